@@ -0,0 +1,133 @@
+package cryptoexchange
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// candlePageSizes 是各交易所单次 K 线请求能返回的最大根数，回补时按它切分时间范围。
+// OKX 的历史接口每次只返回 100 根，按较小值切分
+var candlePageSizes = map[types.ExchangeName]int{
+	types.Binance:     1000,
+	types.BinanceUS:   1000,
+	types.OKX:         100,
+	types.Bitget:      200,
+	types.Kucoin:      1500,
+	types.MEXC:        1000,
+	types.Gate:        1000,
+	types.Kraken:      720,
+	types.Bybit:       1000,
+	types.Huobi:       2000,
+	types.Coinbase:    300,
+	types.BTSE:        300,
+	types.Gemini:      200,
+	types.Upbit:       200,
+	types.CryptoCom:   300,
+	types.BitMart:     200,
+	types.Hyperliquid: 5000,
+}
+
+// defaultCandlePage 用于未登记单次上限的交易所
+const defaultCandlePage = 100
+
+// BackfillCandles 获取 [from, to) 范围内的全部 K 线，按交易所单次上限切分请求，
+// 每取到一批就按时间正序交给 handle。to 为零值或晚于当前时间时取到当前时间；
+// 相邻批次边界上重复的 K 线会被去掉。请求经过交易所限速器，handle 返回错误时停止回补
+func (c *CryptoExchangeClient) BackfillCandles(ctx context.Context, symbol string, interval types.Interval, from, to time.Time, handle func([]types.Candle) error) error {
+	md, err := c.marketData()
+	if err != nil {
+		return err
+	}
+	if !interval.Valid() {
+		return &ExchangeError{Exchange: c.exchange.Name(), Message: fmt.Sprintf("unknown interval %q", interval)}
+	}
+
+	if now := time.Now(); to.IsZero() || to.After(now) {
+		to = now
+	}
+	page, ok := candlePageSizes[c.exchange.Name()]
+	if !ok {
+		page = defaultCandlePage
+	}
+
+	var last time.Time
+	for cursor := interval.Truncate(from); cursor.Before(to); {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkEnd := advanceInterval(interval, cursor, page)
+		if chunkEnd.After(to) {
+			chunkEnd = to
+		}
+
+		candles, err := md.GetCandles(ctx, symbol, interval, cursor, chunkEnd, page)
+		if err != nil {
+			return err
+		}
+
+		batch := make([]types.Candle, 0, len(candles))
+		for _, candle := range candles {
+			if candle.OpenTime.Before(cursor) || !candle.OpenTime.Before(chunkEnd) || !candle.OpenTime.After(last) {
+				continue
+			}
+			batch = append(batch, candle)
+		}
+
+		// 返回的数据没有覆盖到本段末尾，说明交易所的单次上限小于预期：
+		// 丢弃最后一根（可能不完整）并从它开始继续，否则进入下一段
+		next := chunkEnd
+		if n := len(batch); n > 1 && advanceInterval(interval, batch[n-1].OpenTime, 1).Before(chunkEnd) {
+			next = batch[n-1].OpenTime
+			batch = batch[:n-1]
+		}
+
+		if len(batch) > 0 {
+			if err := handle(batch); err != nil {
+				return err
+			}
+			last = batch[len(batch)-1].OpenTime
+		}
+		cursor = next
+	}
+	return nil
+}
+
+// BackfillCandlesChan 与 BackfillCandles 相同，但把 K 线逐根写入返回的通道。
+// 两个通道在回补结束后关闭，错误通道最多收到一个错误
+func (c *CryptoExchangeClient) BackfillCandlesChan(ctx context.Context, symbol string, interval types.Interval, from, to time.Time) (<-chan types.Candle, <-chan error) {
+	candles := make(chan types.Candle)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(candles)
+
+		err := c.BackfillCandles(ctx, symbol, interval, from, to, func(batch []types.Candle) error {
+			for _, candle := range batch {
+				select {
+				case candles <- candle:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	return candles, errs
+}
+
+// advanceInterval 返回 t 之后第 n 个周期的起始时间，月线按自然月计算
+func advanceInterval(interval types.Interval, t time.Time, n int) time.Time {
+	if interval == types.Interval1M {
+		return t.AddDate(0, n, 0)
+	}
+	return t.Add(time.Duration(n) * interval.Duration())
+}
@@ -0,0 +1,65 @@
+package cryptoexchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfillCandles(t *testing.T) {
+	// 模拟单次最多返回 400 根、并且多返回一根起始时间之前 K 线的交易所
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.ParseInt(r.URL.Query().Get("startTime"), 10, 64)
+		end, _ := strconv.ParseInt(r.URL.Query().Get("endTime"), 10, 64)
+		step := int64(time.Minute / time.Millisecond)
+		if r.URL.Query().Get("interval") == "1h" {
+			step = int64(time.Hour / time.Millisecond)
+		}
+
+		rows := [][]interface{}{}
+		for ts := start - step; ts <= end && len(rows) < 400; ts += step {
+			rows = append(rows, []interface{}{ts, "1", "2", "0.5", "1.5", "10", ts + step - 1, "15"})
+		}
+		json.NewEncoder(w).Encode(rows)
+	}))
+	defer server.Close()
+
+	client := NewCryptoExchangeClient()
+	assert.NoError(t, client.AddExchange(types.Binance, types.ExchangeConfig{BaseURL: server.URL, RateLimit: -1}))
+
+	from := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	to := from.Add(2500 * time.Minute)
+
+	var got []types.Candle
+	err := client.BackfillCandles(context.Background(), "BTCUSDT", types.Interval1m, from, to, func(batch []types.Candle) error {
+		got = append(got, batch...)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, got, 2501) {
+		assert.True(t, got[0].OpenTime.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+		for i := 1; i < len(got); i++ {
+			assert.Equal(t, time.Minute, got[i].OpenTime.Sub(got[i-1].OpenTime))
+		}
+	}
+	assert.Greater(t, requests, 3)
+
+	// to 落在 03:00 这根 K 线内，它也会被返回
+	candles, errs := client.BackfillCandlesChan(context.Background(), "BTCUSDT", types.Interval1h, from, from.Add(3*time.Hour))
+	count := 0
+	for range candles {
+		count++
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 4, count)
+}
@@ -7,7 +7,6 @@ import (
 	"net/http"
 	"reflect"
 
-	"github.com/hedeqiang/cryptoexchange/exchanges"
 	"github.com/hedeqiang/cryptoexchange/types"
 )
 
@@ -24,33 +23,17 @@ func NewCryptoExchangeClient() *CryptoExchangeClient {
 }
 
 func (c *CryptoExchangeClient) AddExchange(name types.ExchangeName, config types.ExchangeConfig) error {
-	switch name {
-	case types.Binance:
-		c.exchange = exchanges.NewBinance(config)
-	case types.OKX:
-		c.exchange = exchanges.NewOKX(config)
-	case types.Bitget:
-		c.exchange = exchanges.NewBitget(config)
-	case types.Kucoin:
-		c.exchange = exchanges.NewKucoin(config)
-	case types.MEXC:
-		c.exchange = exchanges.NewMEXC(config)
-	case types.Gate:
-		c.exchange = exchanges.NewGate(config)
-	case types.Kraken:
-		c.exchange = exchanges.NewKraken(config)
-	case types.Bybit:
-		c.exchange = exchanges.NewBybit(config)
-	case types.Huobi:
-		c.exchange = exchanges.NewHuobi(config)
-	case types.Coinbase:
-		c.exchange = exchanges.NewCoinbase(config)
-	case types.BTSE:
-		c.exchange = exchanges.NewBTSE(config)
-	default:
+	factory, ok := registry[name]
+	if !ok {
 		return &ExchangeError{Exchange: name, Message: "unsupported exchanges"}
 	}
 
+	if market := config.MarketOrSpot(); !factory.supports(market) {
+		return &ExchangeError{Exchange: name, Message: fmt.Sprintf("unsupported market %s", market)}
+	}
+
+	c.exchange = factory.newExchange(config)
+
 	return nil
 }
 
@@ -20,6 +20,22 @@ func TestCryptoExchangeClient_AddExchange(t *testing.T) {
 	assert.Equal(t, types.Binance, client.exchange.Name())
 }
 
+func TestCryptoExchangeClient_AddExchangeMarket(t *testing.T) {
+	client := NewCryptoExchangeClient()
+
+	err := client.AddExchange(types.Binance, types.ExchangeConfig{Market: types.USDMFutures})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://fapi.binance.com", client.exchange.GetDefaultBaseURL())
+
+	err = client.AddExchange(types.BinanceUS, types.ExchangeConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, types.BinanceUS, client.exchange.Name())
+	assert.Equal(t, "https://api.binance.us", client.exchange.GetDefaultBaseURL())
+
+	err = client.AddExchange(types.Coinbase, types.ExchangeConfig{Market: types.USDMFutures})
+	assert.Error(t, err)
+}
+
 func TestCryptoExchangeClient_SendRequest(t *testing.T) {
 	client := NewCryptoExchangeClient()
 	err := client.AddExchange(types.Binance, types.ExchangeConfig{
@@ -14,11 +14,5 @@ func (e *ExchangeError) Error() string {
 	return fmt.Sprintf("Exchange %s error: %s", e.Exchange, e.Message)
 }
 
-type APIError struct {
-	StatusCode int
-	Body       string
-}
-
-func (e *APIError) Error() string {
-	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
-}
+// APIError 定义在 types 中，以便各交易所适配器也能返回它
+type APIError = types.APIError
@@ -0,0 +1,81 @@
+package exchanges
+
+import (
+	"context"
+	"sync"
+)
+
+// batchWorkers 是逐个请求时的并发数，实际请求频率仍由限速器控制
+const batchWorkers = 4
+
+// fanOut 并发地对每个 key 调用 get，任一请求失败即取消其余请求并返回该错误
+func fanOut[T any](ctx context.Context, keys []string, get func(ctx context.Context, key string) (T, error)) (map[string]T, error) {
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		result   = make(map[string]T, len(keys))
+		queue    = make(chan string)
+	)
+	for i := 0; i < batchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				v, err := get(batchCtx, key)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				} else if err == nil {
+					result[key] = v
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, key := range keys {
+		select {
+		case queue <- key:
+		case <-batchCtx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// forEach 用 batchWorkers 个协程对 [0, n) 的每个下标调用 fn，全部完成后返回
+func forEach(n int, fn func(i int)) {
+	queue := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < min(batchWorkers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+}
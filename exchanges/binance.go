@@ -13,19 +13,43 @@ import (
 )
 
 type Binance struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	us          bool
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewBinance(config types.ExchangeConfig) *Binance {
 	return &Binance{config: config}
 }
 
+// NewBinanceUS 创建 Binance.US 客户端，接口与签名方式与 Binance 相同，只是域名不同
+func NewBinanceUS(config types.ExchangeConfig) *Binance {
+	return &Binance{config: config, us: true}
+}
+
 func (b *Binance) Name() types.ExchangeName {
+	if b.us {
+		return types.BinanceUS
+	}
 	return types.Binance
 }
 
 func (b *Binance) GetDefaultBaseURL() string {
-	return "https://api.binance.com"
+	if b.us {
+		return "https://api.binance.us"
+	}
+
+	switch b.config.MarketOrSpot() {
+	case types.USDMFutures:
+		return "https://fapi.binance.com"
+	case types.CoinMFutures:
+		return "https://dapi.binance.com"
+	case types.Options:
+		return "https://eapi.binance.com"
+	default:
+		return "https://api.binance.com"
+	}
 }
 
 func (b *Binance) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
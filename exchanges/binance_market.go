@@ -0,0 +1,599 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type binanceTicker24hr struct {
+	Symbol      string      `json:"symbol"`
+	LastPrice   jsonDecimal `json:"lastPrice"`
+	BidPrice    jsonDecimal `json:"bidPrice"`
+	AskPrice    jsonDecimal `json:"askPrice"`
+	Volume      jsonDecimal `json:"volume"`
+	BaseVolume  jsonDecimal `json:"baseVolume"`
+	QuoteVolume jsonDecimal `json:"quoteVolume"`
+	CloseTime   int64       `json:"closeTime"`
+}
+
+type binanceBookTicker struct {
+	Symbol   string      `json:"symbol"`
+	BidPrice jsonDecimal `json:"bidPrice"`
+	AskPrice jsonDecimal `json:"askPrice"`
+}
+
+// pathPrefix 返回当前产品线的接口路径前缀
+func (b *Binance) pathPrefix() string {
+	if b.us {
+		return "/api/v3"
+	}
+
+	switch b.config.MarketOrSpot() {
+	case types.USDMFutures:
+		return "/fapi/v1"
+	case types.CoinMFutures:
+		return "/dapi/v1"
+	case types.Options:
+		return "/eapi/v1"
+	default:
+		return "/api/v3"
+	}
+}
+
+func (t binanceTicker24hr) toTicker(market types.Market) types.Ticker {
+	ticker := types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.LastPrice.Decimal,
+		Bid:         t.BidPrice.Decimal,
+		Ask:         t.AskPrice.Decimal,
+		Volume:      t.Volume.Decimal,
+		QuoteVolume: t.QuoteVolume.Decimal,
+		Timestamp:   msToTime(t.CloseTime),
+	}
+
+	// 币本位合约的 volume 是合约张数，baseVolume 才是基础币数量，成交额不提供
+	if market == types.CoinMFutures {
+		ticker.Volume = t.BaseVolume.Decimal
+		ticker.QuoteVolume = decimal.Zero
+	}
+	return ticker
+}
+
+func (b *Binance) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options {
+		return types.Ticker{}, notSupported(b, "options ticker")
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+
+	var raw json.RawMessage
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/ticker/24hr", params, false, &raw); err != nil {
+		return types.Ticker{}, err
+	}
+
+	var stats binanceTicker24hr
+	if err := unmarshalFirst(raw, &stats); err != nil {
+		return types.Ticker{}, err
+	}
+
+	ticker := stats.toTicker(market)
+	if market == types.Spot {
+		return ticker, nil
+	}
+
+	// 合约的 24hr 统计不含买一卖一，需要再查询 bookTicker
+	raw = nil
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/ticker/bookTicker", params, false, &raw); err != nil {
+		return types.Ticker{}, err
+	}
+
+	var book binanceBookTicker
+	if err := unmarshalFirst(raw, &book); err != nil {
+		return types.Ticker{}, err
+	}
+	ticker.Bid = book.BidPrice.Decimal
+	ticker.Ask = book.AskPrice.Decimal
+
+	return ticker, nil
+}
+
+type binanceDepth struct {
+	LastUpdateID int64           `json:"lastUpdateId"`
+	U            int64           `json:"u"`
+	T            int64           `json:"T"`
+	Bids         [][]jsonDecimal `json:"bids"`
+	Asks         [][]jsonDecimal `json:"asks"`
+}
+
+func (b *Binance) depthLimits() []int {
+	switch b.config.MarketOrSpot() {
+	case types.Spot:
+		return []int{5, 10, 20, 50, 100, 500, 1000, 5000}
+	case types.Options:
+		return []int{10, 20, 50, 100, 500, 1000}
+	default:
+		return []int{5, 10, 20, 50, 100, 500, 1000}
+	}
+}
+
+func (b *Binance) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  nearestDepth(depth, b.depthLimits()),
+	}
+
+	var d binanceDepth
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/depth", params, false, &d); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	// 期权接口使用 u 作为更新 ID，其余使用 lastUpdateId
+	sequence := d.LastUpdateID
+	if sequence == 0 {
+		sequence = d.U
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(d.Bids),
+		Asks:      levelsFromRows(d.Asks),
+		Sequence:  sequence,
+		Timestamp: msToTime(d.T),
+	}, depth), nil
+}
+
+var binanceIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval3m: "3m", types.Interval5m: "5m", types.Interval15m: "15m",
+	types.Interval30m: "30m", types.Interval1h: "1h", types.Interval2h: "2h", types.Interval4h: "4h",
+	types.Interval6h: "6h", types.Interval8h: "8h", types.Interval12h: "12h", types.Interval1d: "1d",
+	types.Interval3d: "3d", types.Interval1w: "1w", types.Interval1M: "1M",
+}
+
+// binanceKlines 解析 [openTime, open, high, low, close, volume, closeTime, quoteVolume, ...]，
+// volumeIndex 和 quoteVolumeIndex 用于处理币本位合约 volume 为合约张数的情况
+func binanceKlines(rows [][]jsonDecimal, volumeIndex, quoteVolumeIndex int) []types.Candle {
+	candles := make([]types.Candle, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 6 {
+			continue
+		}
+		candles = append(candles, candleFromRow(row, msToTime(row[0].IntPart()), 1, 2, 3, 4, volumeIndex, quoteVolumeIndex))
+	}
+	return candles
+}
+
+func (b *Binance) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, b, binanceIntervals, b.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol":   symbol,
+				"interval": venueInterval,
+			}
+			if !start.IsZero() {
+				params["startTime"] = start.UnixMilli()
+			}
+			if !end.IsZero() {
+				params["endTime"] = end.UnixMilli()
+			}
+			if limit > 0 {
+				params["limit"] = clampDepth(limit, 1000)
+			}
+
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/klines", params, false, &rows); err != nil {
+				return nil, err
+			}
+
+			if b.config.MarketOrSpot() == types.CoinMFutures {
+				return binanceKlines(rows, 7, -1), nil
+			}
+			return binanceKlines(rows, 5, 7), nil
+		})
+}
+
+type binanceTrade struct {
+	ID           int64       `json:"id"`
+	Price        jsonDecimal `json:"price"`
+	Qty          jsonDecimal `json:"qty"`
+	BaseQty      jsonDecimal `json:"baseQty"`
+	Time         int64       `json:"time"`
+	IsBuyerMaker bool        `json:"isBuyerMaker"`
+}
+
+func (t binanceTrade) toTrade(coinMargined bool) types.Trade {
+	side := types.Buy
+	if t.IsBuyerMaker {
+		side = types.Sell
+	}
+
+	size := t.Qty.Decimal
+	if coinMargined {
+		size = t.BaseQty.Decimal
+	}
+
+	return types.Trade{
+		ID:    strconv.FormatInt(t.ID, 10),
+		Price: t.Price.Decimal,
+		Size:  size,
+		Side:  side,
+		Time:  msToTime(t.Time),
+	}
+}
+
+func (b *Binance) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(b, "options trades")
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+	if limit > 0 {
+		params["limit"] = clampDepth(limit, 1000)
+	}
+
+	var rows []binanceTrade
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/trades", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, r := range rows {
+		trades = append(trades, r.toTrade(market == types.CoinMFutures))
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// binanceAggTrade 是归集成交：a 为归集 ID，m 表示买方是否为 maker
+type binanceAggTrade struct {
+	A int64       `json:"a"`
+	P jsonDecimal `json:"p"`
+	Q jsonDecimal `json:"q"`
+	T int64       `json:"T"`
+	M bool        `json:"m"`
+}
+
+// binanceAggTradeWindow 是 aggTrades 同时指定 startTime 和 endTime 时允许的最大跨度
+const binanceAggTradeWindow = time.Hour
+
+// GetHistoricalTrades 使用无需 API Key 的 aggTrades 向后翻页，返回的 ID 为归集成交 ID。
+// 指定 Since 时先查询 Since 起的一小时窗口；窗口内没有成交时按归集 ID 二分查找第一笔不早于 Since 的成交，
+// 请求次数与 ID 的位数成正比，不随 Since 的远近增长。之后使用 fromId 翻页。
+func (b *Binance) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options || market == types.CoinMFutures {
+		return types.TradePage{}, notSupported(b, "historical trades")
+	}
+
+	limit := clampDepth(req.Limit, 1000)
+	cursor := req.Cursor
+	if cursor == "" && !req.Since.IsZero() {
+		rows, err := b.aggTrades(ctx, req.Symbol, limit, map[string]interface{}{
+			"startTime": req.Since.UnixMilli(),
+			"endTime":   req.Since.Add(binanceAggTradeWindow).UnixMilli() - 1,
+		})
+		if err != nil {
+			return types.TradePage{}, err
+		}
+		if len(rows) > 0 {
+			// 窗口之后可能还有成交，不足一页时也返回游标
+			page := binanceTradePage(rows, limit)
+			page.Cursor = strconv.FormatInt(rows[len(rows)-1].A+1, 10)
+			return page, nil
+		}
+
+		id, found, err := b.firstAggTrade(ctx, req.Symbol, req.Since)
+		if err != nil || !found {
+			return types.TradePage{}, err
+		}
+		cursor = strconv.FormatInt(id, 10)
+	}
+
+	params := map[string]interface{}{}
+	if cursor != "" {
+		params["fromId"] = cursor
+	}
+	rows, err := b.aggTrades(ctx, req.Symbol, limit, params)
+	if err != nil {
+		return types.TradePage{}, err
+	}
+	return binanceTradePage(rows, limit), nil
+}
+
+func (b *Binance) aggTrades(ctx context.Context, symbol string, limit int, params map[string]interface{}) ([]binanceAggTrade, error) {
+	params["symbol"] = symbol
+	params["limit"] = limit
+
+	var rows []binanceAggTrade
+	err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/aggTrades", params, false, &rows)
+	return rows, err
+}
+
+// firstAggTrade 在 0 到最新归集 ID 之间二分查找第一笔不早于 since 的成交，since 之后没有成交时 found 为 false
+func (b *Binance) firstAggTrade(ctx context.Context, symbol string, since time.Time) (id int64, found bool, err error) {
+	latest, err := b.aggTrades(ctx, symbol, 1, map[string]interface{}{})
+	if err != nil || len(latest) == 0 || msToTime(latest[0].T).Before(since) {
+		return 0, false, err
+	}
+
+	lo, hi := int64(0), latest[0].A
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		rows, err := b.aggTrades(ctx, symbol, 1, map[string]interface{}{"fromId": mid})
+		if err != nil {
+			return 0, false, err
+		}
+		switch {
+		case len(rows) == 0:
+			lo = mid + 1
+		case msToTime(rows[0].T).Before(since):
+			// fromId 返回的是不小于 mid 的第一笔，ID 之间可能有空缺
+			lo = rows[0].A + 1
+		default:
+			hi = mid
+		}
+	}
+	return lo, true, nil
+}
+
+func binanceTradePage(rows []binanceAggTrade, limit int) types.TradePage {
+	page := types.TradePage{Trades: make([]types.Trade, 0, len(rows))}
+	for _, r := range rows {
+		side := types.Buy
+		if r.M {
+			side = types.Sell
+		}
+		page.Trades = append(page.Trades, types.Trade{
+			ID:    strconv.FormatInt(r.A, 10),
+			Price: r.P.Decimal,
+			Size:  r.Q.Decimal,
+			Side:  side,
+			Time:  msToTime(r.T),
+		})
+	}
+
+	// 返回数量达到 limit 说明可能还有后续数据
+	if len(rows) == limit {
+		page.Cursor = strconv.FormatInt(rows[len(rows)-1].A+1, 10)
+	}
+	return page
+}
+
+// binanceFilter 合并了各类过滤器的字段，按 filterType 区分含义
+type binanceFilter struct {
+	FilterType  string      `json:"filterType"`
+	TickSize    jsonDecimal `json:"tickSize"`
+	StepSize    jsonDecimal `json:"stepSize"`
+	MinQty      jsonDecimal `json:"minQty"`
+	MaxQty      jsonDecimal `json:"maxQty"`
+	MinNotional jsonDecimal `json:"minNotional"`
+	Notional    jsonDecimal `json:"notional"`
+}
+
+// binanceSymbol 中现货和 U 本位使用 status，币本位使用 contractStatus
+type binanceSymbol struct {
+	Symbol         string          `json:"symbol"`
+	Status         string          `json:"status"`
+	ContractStatus string          `json:"contractStatus"`
+	BaseAsset      string          `json:"baseAsset"`
+	QuoteAsset     string          `json:"quoteAsset"`
+	ContractSize   jsonDecimal     `json:"contractSize"`
+	Filters        []binanceFilter `json:"filters"`
+}
+
+func binanceStatus(status string) types.InstrumentStatus {
+	switch status {
+	case "TRADING":
+		return types.InstrumentTrading
+	case "PRE_TRADING", "PENDING_TRADING":
+		return types.InstrumentPreTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+func (s binanceSymbol) toInstrument(market types.Market) types.Instrument {
+	inst := types.Instrument{
+		Symbol: s.Symbol,
+		Base:   s.BaseAsset,
+		Quote:  s.QuoteAsset,
+		Status: binanceStatus(s.Status),
+	}
+
+	switch market {
+	case types.USDMFutures:
+		inst.ContractSize = decimal.NewFromInt(1)
+	case types.CoinMFutures:
+		inst.ContractSize = s.ContractSize.Decimal
+		inst.Status = binanceStatus(s.ContractStatus)
+	}
+
+	for _, f := range s.Filters {
+		switch f.FilterType {
+		case "PRICE_FILTER":
+			inst.TickSize = f.TickSize.Decimal
+		case "LOT_SIZE":
+			inst.StepSize = f.StepSize.Decimal
+			inst.MinQty = f.MinQty.Decimal
+			inst.MaxQty = f.MaxQty.Decimal
+		case "MIN_NOTIONAL", "NOTIONAL":
+			// 现货使用 minNotional，U 本位合约使用 notional
+			if f.MinNotional.IsPositive() {
+				inst.MinNotional = f.MinNotional.Decimal
+			} else {
+				inst.MinNotional = f.Notional.Decimal
+			}
+		}
+	}
+	return inst
+}
+
+// GetInstruments 解析 exchangeInfo 中的 PRICE_FILTER、LOT_SIZE 和 (MIN_)NOTIONAL 过滤器，期权暂不支持
+func (b *Binance) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(b, "options instruments")
+	}
+
+	return b.instruments.get(ctx, b.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var info struct {
+			Symbols []binanceSymbol `json:"symbols"`
+		}
+		if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/exchangeInfo", nil, false, &info); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(info.Symbols))
+		for _, s := range info.Symbols {
+			instruments = append(instruments, s.toInstrument(market))
+		}
+		return instruments, nil
+	})
+}
+
+// binancePremiumIndex 同时包含标记价格、指数价格和当前资金费率
+type binancePremiumIndex struct {
+	Symbol          string      `json:"symbol"`
+	MarkPrice       jsonDecimal `json:"markPrice"`
+	IndexPrice      jsonDecimal `json:"indexPrice"`
+	LastFundingRate jsonDecimal `json:"lastFundingRate"`
+	NextFundingTime int64       `json:"nextFundingTime"`
+	Time            int64       `json:"time"`
+}
+
+// premiumIndex 中币本位接口即使指定了 symbol 也返回数组
+func (b *Binance) premiumIndex(ctx context.Context, symbol string) (binancePremiumIndex, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return binancePremiumIndex{}, err
+	}
+
+	var raw json.RawMessage
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/premiumIndex", map[string]interface{}{"symbol": symbol}, false, &raw); err != nil {
+		return binancePremiumIndex{}, err
+	}
+
+	var p binancePremiumIndex
+	err := unmarshalFirst(raw, &p)
+	return p, err
+}
+
+// GetFundingRate 中 U 本位合约的结算间隔来自 fundingInfo，只有调整过间隔的合约会出现在其中，其余为 8 小时
+func (b *Binance) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	p, err := b.premiumIndex(ctx, symbol)
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+
+	interval := defaultFundingInterval
+	if b.config.MarketOrSpot() == types.USDMFutures {
+		var infos []struct {
+			Symbol               string `json:"symbol"`
+			FundingIntervalHours int    `json:"fundingIntervalHours"`
+		}
+		if err := sendRequest(ctx, b, "GET", "/fapi/v1/fundingInfo", nil, false, &infos); err != nil {
+			return types.FundingRate{}, err
+		}
+		for _, info := range infos {
+			if info.Symbol == symbol && info.FundingIntervalHours > 0 {
+				interval = time.Duration(info.FundingIntervalHours) * time.Hour
+			}
+		}
+	}
+
+	return types.FundingRate{
+		Symbol:          p.Symbol,
+		Rate:            p.LastFundingRate.Decimal,
+		NextFundingTime: msToTime(p.NextFundingTime),
+		Interval:        interval,
+		Timestamp:       msToTime(p.Time),
+	}, nil
+}
+
+// GetFundingHistory 单次最多返回 1000 条
+func (b *Binance) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  clampDepth(limit, 1000),
+	}
+	if !start.IsZero() {
+		params["startTime"] = start.UnixMilli()
+	}
+	if !end.IsZero() {
+		params["endTime"] = end.UnixMilli()
+	}
+
+	var rows []struct {
+		Symbol      string      `json:"symbol"`
+		FundingRate jsonDecimal `json:"fundingRate"`
+		FundingTime int64       `json:"fundingTime"`
+	}
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/fundingRate", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(rows))
+	for _, r := range rows {
+		records = append(records, types.FundingRecord{Symbol: r.Symbol, Rate: r.FundingRate.Decimal, Time: msToTime(r.FundingTime)})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+func (b *Binance) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.premiumIndex(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.MarkPrice.Decimal, Timestamp: msToTime(p.Time)}, nil
+}
+
+func (b *Binance) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.premiumIndex(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.IndexPrice.Decimal, Timestamp: msToTime(p.Time)}, nil
+}
+
+// GetAllTickers 使用不带 symbol 的 24hr 接口，合约再合并全量 bookTicker 的买一卖一
+func (b *Binance) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(b, "options ticker")
+	}
+
+	var stats []binanceTicker24hr
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/ticker/24hr", nil, false, &stats); err != nil {
+		return nil, err
+	}
+
+	var books []binanceBookTicker
+	if market != types.Spot {
+		if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/ticker/bookTicker", nil, false, &books); err != nil {
+			return nil, err
+		}
+	}
+	bookBySymbol := make(map[string]binanceBookTicker, len(books))
+	for _, book := range books {
+		bookBySymbol[book.Symbol] = book
+	}
+
+	tickers := make([]types.Ticker, 0, len(stats))
+	for _, st := range stats {
+		ticker := st.toTicker(market)
+		if book, ok := bookBySymbol[st.Symbol]; ok {
+			ticker.Bid = book.BidPrice.Decimal
+			ticker.Ask = book.AskPrice.Decimal
+		}
+		tickers = append(tickers, ticker)
+	}
+	return keyTickers(ctx, b, tickers)
+}
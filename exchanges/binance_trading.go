@@ -0,0 +1,776 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type binanceTradeFee struct {
+	Symbol          string      `json:"symbol"`
+	MakerCommission jsonDecimal `json:"makerCommission"`
+	TakerCommission jsonDecimal `json:"takerCommission"`
+}
+
+type binanceCommissionRate struct {
+	Symbol              string      `json:"symbol"`
+	MakerCommissionRate jsonDecimal `json:"makerCommissionRate"`
+	TakerCommissionRate jsonDecimal `json:"takerCommissionRate"`
+}
+
+// GetTradingFees 中现货使用 /sapi/v1/asset/tradeFee（Binance.US 为 /sapi/v1/asset/query/trading-fee）；
+// 合约只能按交易对查询 commissionRate，symbols 为空时会在限速内逐个查询全部合约
+func (b *Binance) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	switch b.config.MarketOrSpot() {
+	case types.Options:
+		return nil, notSupported(b, "options trading fees")
+	case types.USDMFutures, types.CoinMFutures:
+		if len(symbols) == 0 {
+			var err error
+			if symbols, err = instrumentSymbols(ctx, b); err != nil {
+				return nil, err
+			}
+		}
+		return fanOut(ctx, symbols, func(ctx context.Context, symbol string) (types.TradingFee, error) {
+			var rate binanceCommissionRate
+			if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/commissionRate", map[string]interface{}{"symbol": symbol}, true, &rate); err != nil {
+				return types.TradingFee{}, err
+			}
+			return types.TradingFee{Symbol: symbol, Maker: rate.MakerCommissionRate.Decimal, Taker: rate.TakerCommissionRate.Decimal}, nil
+		})
+	}
+
+	endpoint := "/sapi/v1/asset/tradeFee"
+	if b.us {
+		endpoint = "/sapi/v1/asset/query/trading-fee"
+	}
+	params := map[string]interface{}{}
+	if len(symbols) == 1 {
+		params["symbol"] = symbols[0]
+	}
+
+	var rows []binanceTradeFee
+	if err := sendRequest(ctx, b, "GET", endpoint, params, true, &rows); err != nil {
+		return nil, err
+	}
+
+	all := make(map[string]types.TradingFee, len(rows))
+	for _, row := range rows {
+		all[row.Symbol] = types.TradingFee{Symbol: row.Symbol, Maker: row.MakerCommission.Decimal, Taker: row.TakerCommission.Decimal}
+	}
+	return selectFees(b, all, symbols)
+}
+
+// binanceOrder 是现货与合约订单接口的共同字段，合约没有 origQuoteOrderQty，现货没有 reduceOnly；
+// 现货的成交金额为 cummulativeQuoteQty，合约为 cumQuote 并直接给出 avgPrice
+type binanceOrder struct {
+	Symbol              string      `json:"symbol"`
+	OrderID             int64       `json:"orderId"`
+	ClientOrderID       string      `json:"clientOrderId"`
+	Price               jsonDecimal `json:"price"`
+	StopPrice           jsonDecimal `json:"stopPrice"`
+	OrigQty             jsonDecimal `json:"origQty"`
+	OrigQuoteOrderQty   jsonDecimal `json:"origQuoteOrderQty"`
+	ExecutedQty         jsonDecimal `json:"executedQty"`
+	CummulativeQuoteQty jsonDecimal `json:"cummulativeQuoteQty"`
+	CumQuote            jsonDecimal `json:"cumQuote"`
+	AvgPrice            jsonDecimal `json:"avgPrice"`
+	Status              string      `json:"status"`
+	TimeInForce         string      `json:"timeInForce"`
+	Type                string      `json:"type"`
+	Side                string      `json:"side"`
+	ReduceOnly          bool        `json:"reduceOnly"`
+	Time                int64       `json:"time"`
+	TransactTime        int64       `json:"transactTime"`
+	UpdateTime          int64       `json:"updateTime"`
+}
+
+// binanceStatuses 中 PENDING_CANCEL 已不再使用，EXPIRED_IN_MATCH 是被自成交保护取消的订单
+var binanceStatuses = map[string]types.OrderStatus{
+	"NEW":              types.OrderNew,
+	"PENDING_NEW":      types.OrderNew,
+	"PARTIALLY_FILLED": types.OrderPartiallyFilled,
+	"FILLED":           types.OrderFilled,
+	"CANCELED":         types.OrderCanceled,
+	"REJECTED":         types.OrderRejected,
+	"EXPIRED":          types.OrderExpired,
+	"EXPIRED_IN_MATCH": types.OrderExpired,
+}
+
+// binanceTypes 把现货与合约的条件单类型映射为统一类型。TAKE_PROFIT 在现货是市价单、在合约是限价单，由 toOrder 按价格区分
+var binanceTypes = map[string]types.OrderType{
+	"STOP_LOSS":          types.StopMarketOrder,
+	"STOP_MARKET":        types.StopMarketOrder,
+	"STOP_LOSS_LIMIT":    types.StopLimitOrder,
+	"STOP":               types.StopLimitOrder,
+	"TAKE_PROFIT_MARKET": types.TakeProfitMarketOrder,
+	"TAKE_PROFIT_LIMIT":  types.TakeProfitLimitOrder,
+}
+
+// binanceSpotTypes 与 binanceFuturesTypes 是下单时统一条件单类型对应的现货与合约类型
+var (
+	binanceSpotTypes = map[types.OrderType]string{
+		types.StopMarketOrder:       "STOP_LOSS",
+		types.StopLimitOrder:        "STOP_LOSS_LIMIT",
+		types.TakeProfitMarketOrder: "TAKE_PROFIT",
+		types.TakeProfitLimitOrder:  "TAKE_PROFIT_LIMIT",
+	}
+	binanceFuturesTypes = map[types.OrderType]string{
+		types.StopMarketOrder:       "STOP_MARKET",
+		types.StopLimitOrder:        "STOP",
+		types.TakeProfitMarketOrder: "TAKE_PROFIT_MARKET",
+		types.TakeProfitLimitOrder:  "TAKE_PROFIT",
+	}
+)
+
+// binanceOrderNotFound 是订单不存在时的错误码
+const binanceOrderNotFound = `"code":-2013`
+
+func (o binanceOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:            strconv.FormatInt(o.OrderID, 10),
+		ClientOrderID: o.ClientOrderID,
+		Symbol:        o.Symbol,
+		Side:          parseSide(o.Side),
+		Type:          types.OrderType(o.Type),
+		TimeInForce:   types.TimeInForce(o.TimeInForce),
+		Price:         o.Price.Decimal,
+		TriggerPrice:  o.StopPrice.Decimal,
+		Quantity:      o.OrigQty.Decimal,
+		QuoteQuantity: o.OrigQuoteOrderQty.Decimal,
+		ReduceOnly:    o.ReduceOnly,
+
+		FilledQuantity: o.ExecutedQty.Decimal,
+		AveragePrice:   o.AvgPrice.Decimal,
+		Raw:            o.Status,
+		UpdatedAt:      msToTime(o.UpdateTime),
+	}
+	if o.Status != "" {
+		order.Status = orderStatus(binanceStatuses, o.Status)
+	}
+	if order.AveragePrice.IsZero() {
+		order.AveragePrice = averagePrice(o.CummulativeQuoteQty.Add(o.CumQuote.Decimal), o.ExecutedQty.Decimal)
+	}
+
+	// LIMIT_MAKER 与 GTX 都是只做 maker 的限价单
+	switch o.Type {
+	case "LIMIT_MAKER":
+		order.Type, order.TimeInForce = types.LimitOrder, types.GTC
+	case "TAKE_PROFIT":
+		order.Type = types.TakeProfitLimitOrder
+		if o.Price.IsZero() {
+			order.Type = types.TakeProfitMarketOrder
+		}
+	default:
+		if t, ok := binanceTypes[o.Type]; ok {
+			order.Type = t
+		}
+	}
+	if !order.Type.Priced() {
+		order.TimeInForce = ""
+	}
+	if o.TimeInForce == "GTX" {
+		order.TimeInForce = types.GTC
+	}
+
+	for _, ts := range []int64{o.Time, o.TransactTime, o.UpdateTime} {
+		if ts > 0 {
+			order.CreatedAt = msToTime(ts)
+			break
+		}
+	}
+	return order
+}
+
+// orderParams 把统一的下单请求转换为 Binance 参数：现货的 post-only 使用 LIMIT_MAKER，合约使用 GTX
+func (b *Binance) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	market := b.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(b, "options orders")
+	}
+
+	if req.Type == types.OCOOrder {
+		return nil, notSupported(b, "OCO orders in batches")
+	}
+
+	orderTypes := binanceFuturesTypes
+	if market == types.Spot {
+		orderTypes = binanceSpotTypes
+	}
+	params := map[string]interface{}{
+		"symbol":           req.Symbol,
+		"side":             string(req.Side),
+		"type":             string(req.Type),
+		"newOrderRespType": "RESULT",
+	}
+	if t, ok := orderTypes[req.Type]; ok {
+		params["type"] = t
+		params["stopPrice"] = req.TriggerPrice.String()
+	}
+	if req.ClientOrderID != "" {
+		params["newClientOrderId"] = req.ClientOrderID
+	}
+	if req.Quantity.IsPositive() {
+		params["quantity"] = req.Quantity.String()
+	}
+	if req.Type.Priced() {
+		params["price"] = req.Price.String()
+		params["timeInForce"] = string(timeInForce(req))
+	}
+
+	if market == types.Spot {
+		if req.ReduceOnly {
+			return nil, notSupported(b, "reduce-only spot orders")
+		}
+		if req.Type.Conditional() && req.QuoteQuantity.IsPositive() {
+			return nil, notSupported(b, "conditional spot orders by quote quantity")
+		}
+		if req.QuoteQuantity.IsPositive() {
+			params["quoteOrderQty"] = req.QuoteQuantity.String()
+		}
+		if req.PostOnly {
+			params["type"] = "LIMIT_MAKER"
+			delete(params, "timeInForce")
+		}
+		return params, nil
+	}
+
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(b, "futures orders by quote quantity")
+	}
+	if req.PostOnly {
+		params["timeInForce"] = "GTX"
+	}
+	if req.ReduceOnly {
+		params["reduceOnly"] = "true"
+	}
+	return params, nil
+}
+
+// binanceBatchSize 是合约 batchOrders 一次最多下单的数量，binanceCancelBatchSize 是一次最多撤单的数量
+const (
+	binanceBatchSize       = 5
+	binanceCancelBatchSize = 10
+)
+
+// binanceBatchItem 是合约批量接口中单个订单的结果，失败时只有 code 和 msg
+type binanceBatchItem struct {
+	binanceOrder
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func (i binanceBatchItem) err() error {
+	if i.Code == 0 {
+		return nil
+	}
+	return batchError(strconv.Itoa(i.Code), i.Msg)
+}
+
+// placer 中合约使用 batchOrders 批量下单，现货没有批量下单接口
+func (b *Binance) placer() placer {
+	p := placer{t: b, config: b.config, idLength: clientOrderIDLength, uniqueIDs: true, place: b.placeOrder}
+	if market := b.config.MarketOrSpot(); market == types.USDMFutures || market == types.CoinMFutures {
+		p.batch, p.batchSize = b.placeBatch, binanceBatchSize
+	}
+	return p
+}
+
+func (b *Binance) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return b.placer().placeOrder(ctx, req)
+}
+
+func (b *Binance) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if b.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(b, "options orders")
+	}
+	return b.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeBatch 通过合约的 batchOrders 提交一批订单，batchOrders 参数是订单参数组成的 JSON 数组
+func (b *Binance) placeBatch(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return buildBatch(ctx, reqs, b.orderParams, func(ctx context.Context, items []map[string]interface{}, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+		batch, err := json.Marshal(items)
+		if err != nil {
+			return nil, err
+		}
+
+		var rows []binanceBatchItem
+		if err := sendRequest(ctx, b, "POST", b.pathPrefix()+"/batchOrders", map[string]interface{}{"batchOrders": string(batch)}, true, &rows); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.OrderResult, len(reqs))
+		for i := range reqs {
+			if i >= len(rows) {
+				results[i].Err = errMissingResult
+			} else if err := rows[i].err(); err != nil {
+				results[i].Err = err
+			} else {
+				results[i].Order = rows[i].toOrder()
+			}
+		}
+		return results, nil
+	})
+}
+
+func (b *Binance) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if req.Type == types.OCOOrder {
+		return b.placeOCO(ctx, req)
+	}
+
+	params, err := b.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var order binanceOrder
+	if err := sendRequest(ctx, b, "POST", b.pathPrefix()+"/order", params, true, &order); err != nil {
+		return types.Order{}, err
+	}
+	return order.toOrder(), nil
+}
+
+// placeOCO 通过现货的 orderList/oco 下单：止盈部分是 LIMIT_MAKER，止损部分是 STOP_LOSS 或 STOP_LOSS_LIMIT。
+// 止盈单使用请求的自定义订单号并作为结果返回，撤销其中一个订单会撤销整个订单列表
+func (b *Binance) placeOCO(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(b, "futures OCO orders")
+	}
+	if req.ReduceOnly {
+		return types.Order{}, notSupported(b, "reduce-only spot orders")
+	}
+
+	limit := map[string]interface{}{"Type": "LIMIT_MAKER", "Price": req.Price.String(), "ClientOrderId": req.ClientOrderID}
+	stop := map[string]interface{}{"Type": "STOP_LOSS", "StopPrice": req.TriggerPrice.String()}
+	if req.StopLimitPrice.IsPositive() {
+		stop["Type"], stop["Price"], stop["TimeInForce"] = "STOP_LOSS_LIMIT", req.StopLimitPrice.String(), "GTC"
+	}
+
+	// 卖单的止盈价高于触发价，作为 above；买单相反
+	above, below := limit, stop
+	if req.Side == types.Buy {
+		above, below = stop, limit
+	}
+	params := map[string]interface{}{
+		"symbol":           req.Symbol,
+		"side":             string(req.Side),
+		"quantity":         req.Quantity.String(),
+		"newOrderRespType": "RESULT",
+	}
+	for k, v := range above {
+		params["above"+k] = v
+	}
+	for k, v := range below {
+		params["below"+k] = v
+	}
+
+	var resp struct {
+		OrderReports []binanceOrder `json:"orderReports"`
+	}
+	if err := sendRequest(ctx, b, "POST", "/api/v3/orderList/oco", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	for _, report := range resp.OrderReports {
+		if report.ClientOrderID == req.ClientOrderID {
+			order := report.toOrder()
+			order.Type, order.TriggerPrice = types.OCOOrder, req.TriggerPrice
+			return order, nil
+		}
+	}
+	return types.Order{}, fmt.Errorf("binance OCO order %s returned no take-profit order", req.Symbol)
+}
+
+// refParams 返回按订单号或自定义订单号定位订单的参数
+func (b *Binance) refParams(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"symbol": symbol}
+	if ref.ID != "" {
+		params["orderId"] = ref.ID
+	} else {
+		params["origClientOrderId"] = ref.ClientOrderID
+	}
+	return params
+}
+
+func (b *Binance) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if b.config.MarketOrSpot() == types.Options {
+		return notSupported(b, "options orders")
+	}
+
+	var order binanceOrder
+	return sendRequest(ctx, b, "DELETE", b.pathPrefix()+"/order", b.refParams(symbol, ref), true, &order)
+}
+
+// AmendOrder 中合约使用 PUT order 原地修改订单，订单号不变；现货使用 order/cancelReplace 在一次请求中撤单并下新单，
+// 新订单按原订单的剩余数量下单。两者都需要原订单的方向，因此先查询订单
+func (b *Binance) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+	if b.config.MarketOrSpot() == types.Options {
+		return types.AmendResult{}, notSupported(b, "options orders")
+	}
+
+	orig, err := b.GetOrder(ctx, req.Symbol, req.Ref)
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	if err := amendable(orig); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	price, quantity := req.Price, req.Quantity
+	if price.IsZero() {
+		price = orig.Price
+	}
+	if quantity.IsZero() {
+		quantity = orig.Quantity
+	}
+
+	if b.config.MarketOrSpot() != types.Spot {
+		params := b.refParams(req.Symbol, req.Ref)
+		params["side"] = string(orig.Side)
+		params["price"] = price.String()
+		params["quantity"] = quantity.String()
+
+		var order binanceOrder
+		if err := sendRequest(ctx, b, "PUT", b.pathPrefix()+"/order", params, true, &order); err != nil {
+			return types.AmendResult{}, err
+		}
+		return types.AmendResult{Order: order.toOrder()}, nil
+	}
+
+	remaining := quantity.Sub(orig.FilledQuantity)
+	if !remaining.IsPositive() {
+		return types.AmendResult{}, fmt.Errorf("%w: order %s has already filled %s", types.ErrInvalidOrder, orig.ID, orig.FilledQuantity)
+	}
+	clientOrderID := req.NewClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = newClientOrderID(clientOrderIDLength)
+	}
+	params, err := b.orderParams(types.OrderRequest{
+		Symbol:        req.Symbol,
+		Side:          orig.Side,
+		Type:          types.LimitOrder,
+		TimeInForce:   orig.TimeInForce,
+		Price:         price,
+		Quantity:      remaining,
+		PostOnly:      req.PostOnly,
+		ClientOrderID: clientOrderID,
+	})
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	params["cancelReplaceMode"] = "STOP_ON_FAILURE"
+	params["cancelOrderId"] = orig.ID
+
+	var resp struct {
+		NewOrderResponse binanceOrder `json:"newOrderResponse"`
+	}
+	if err := sendRequest(ctx, b, "POST", "/api/v3/order/cancelReplace", params, true, &resp); err != nil {
+		return types.AmendResult{}, err
+	}
+	return types.AmendResult{Order: resp.NewOrderResponse.toOrder(), Replaced: true}, nil
+}
+
+// CancelOrders 在合约上使用 batchOrders 批量撤单，现货逐个撤单
+func (b *Binance) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	switch b.config.MarketOrSpot() {
+	case types.Options:
+		return nil, notSupported(b, "options orders")
+	case types.Spot:
+		return cancelOrders(ctx, symbol, refs, 0, nil, b.CancelOrder), nil
+	}
+	return cancelOrders(ctx, symbol, refs, binanceCancelBatchSize, func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		return b.cancelBatch(ctx, symbol, refs)
+	}, nil), nil
+}
+
+// cancelBatch 撤销 symbol 上的一批订单。orderIdList 与 origClientOrderIdList 不能同时使用，
+// 有订单号的与只有自定义订单号的订单分别提交
+func (b *Binance) cancelBatch(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	var (
+		ids, clientIDs         []string
+		idIndex, clientIDIndex []int
+	)
+	for i, ref := range refs {
+		if ref.ID != "" {
+			ids, idIndex = append(ids, ref.ID), append(idIndex, i)
+		} else {
+			clientIDs, clientIDIndex = append(clientIDs, ref.ClientOrderID), append(clientIDIndex, i)
+		}
+	}
+
+	results := make([]types.CancelResult, len(refs))
+	for _, group := range []struct {
+		param string
+		list  string
+		index []int
+	}{
+		{"orderIdList", "[" + strings.Join(ids, ",") + "]", idIndex},
+		{"origClientOrderIdList", jsonList(clientIDs), clientIDIndex},
+	} {
+		if len(group.index) == 0 {
+			continue
+		}
+
+		var rows []binanceBatchItem
+		params := map[string]interface{}{"symbol": symbol, group.param: group.list}
+		if err := sendRequest(ctx, b, "DELETE", b.pathPrefix()+"/batchOrders", params, true, &rows); err != nil {
+			return nil, err
+		}
+		for i, idx := range group.index {
+			err := errMissingResult
+			if i < len(rows) {
+				err = rows[i].err()
+			}
+			results[idx] = refResult(symbol, refs[idx], err)
+		}
+	}
+	return results, nil
+}
+
+// openOrders 返回当前挂单，symbol 为空时返回全部交易对的挂单
+func (b *Binance) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	var rows []binanceOrder
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/openOrders", params, true, &rows); err != nil {
+		return nil, err
+	}
+
+	orders := make([]types.Order, 0, len(rows))
+	for _, row := range rows {
+		orders = append(orders, row.toOrder())
+	}
+	return orders, nil
+}
+
+// binanceCanceled 是现货撤销全部挂单的结果，OCO 订单以 orderReports 列出其中的每个订单
+type binanceCanceled struct {
+	binanceOrder
+	OrderReports []binanceOrder `json:"orderReports"`
+}
+
+// cancelSymbol 使用现货的 DELETE openOrders 撤销一个交易对的全部挂单
+func (b *Binance) cancelSymbol(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	var rows []binanceCanceled
+	if err := sendRequest(ctx, b, "DELETE", b.pathPrefix()+"/openOrders", map[string]interface{}{"symbol": symbol}, true, &rows); err != nil {
+		return nil, err
+	}
+
+	var results []types.CancelResult
+	for _, row := range rows {
+		reports := row.OrderReports
+		if len(reports) == 0 {
+			reports = []binanceOrder{row.binanceOrder}
+		}
+		for _, report := range reports {
+			results = append(results, cancelResult(report.toOrder(), nil))
+		}
+	}
+	return results, nil
+}
+
+// CancelAllOrders 在现货上按交易对调用 DELETE openOrders；合约的 allOpenOrders 不返回被撤销的订单，
+// 因此改为列出挂单后逐个撤销
+func (b *Binance) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if b.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(b, "options orders")
+	}
+
+	if b.config.MarketOrSpot() == types.Spot && symbol != "" {
+		return b.cancelSymbol(ctx, symbol)
+	}
+
+	orders, err := b.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.config.MarketOrSpot() != types.Spot {
+		return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+			return b.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+		}), nil
+	}
+
+	// 现货未指定交易对时，对每个有挂单的交易对分别撤销
+	return cancelBySymbol(ctx, orders, b.cancelSymbol), nil
+}
+
+func (b *Binance) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if b.config.MarketOrSpot() == types.Options {
+		return types.Order{}, notSupported(b, "options orders")
+	}
+
+	var order binanceOrder
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/order", b.refParams(symbol, ref), true, &order); err != nil {
+		return types.Order{}, orderNotFound(err, binanceOrderNotFound)
+	}
+	return order.toOrder(), nil
+}
+
+func (b *Binance) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if b.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(b, "options orders")
+	}
+	return b.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 allOrders，必须指定交易对；单次最多返回 1000 个订单，其中的挂单会被过滤掉
+func (b *Binance) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if b.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(b, "options orders")
+	}
+	if req.Symbol == "" {
+		return nil, notSupported(b, "order history without a symbol")
+	}
+
+	params := map[string]interface{}{"symbol": req.Symbol, "limit": 1000}
+	if !req.Start.IsZero() {
+		params["startTime"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["endTime"] = req.End.UnixMilli()
+	}
+
+	var rows []binanceOrder
+	if err := sendRequest(ctx, b, "GET", b.pathPrefix()+"/allOrders", params, true, &rows); err != nil {
+		return nil, err
+	}
+
+	orders := make([]types.Order, 0, len(rows))
+	for _, row := range rows {
+		orders = append(orders, row.toOrder())
+	}
+	return orderHistory(orders, req), nil
+}
+
+// binanceFill 是现货 myTrades 与合约 userTrades 的成交，现货用 isBuyer、isMaker，合约用 side、maker
+type binanceFill struct {
+	Symbol          string      `json:"symbol"`
+	ID              int64       `json:"id"`
+	OrderID         int64       `json:"orderId"`
+	Side            string      `json:"side"`
+	Price           jsonDecimal `json:"price"`
+	Qty             jsonDecimal `json:"qty"`
+	Commission      jsonDecimal `json:"commission"`
+	CommissionAsset string      `json:"commissionAsset"`
+	Time            int64       `json:"time"`
+	IsBuyer         bool        `json:"isBuyer"`
+	IsMaker         bool        `json:"isMaker"`
+	Maker           bool        `json:"maker"`
+}
+
+func (f binanceFill) toFill() types.Fill {
+	side := parseSide(f.Side)
+	if side == "" {
+		side = types.Sell
+		if f.IsBuyer {
+			side = types.Buy
+		}
+	}
+	return types.Fill{
+		Symbol:    f.Symbol,
+		OrderID:   strconv.FormatInt(f.OrderID, 10),
+		TradeID:   strconv.FormatInt(f.ID, 10),
+		Side:      side,
+		Price:     f.Price.Decimal,
+		Quantity:  f.Qty.Decimal,
+		Fee:       f.Commission.Decimal,
+		FeeAsset:  f.CommissionAsset,
+		Liquidity: liquidity(f.IsMaker || f.Maker),
+		Time:      msToTime(f.Time),
+	}
+}
+
+// binanceFillPage 是成交接口每页的最大数量；binanceFillWindow 是合约 userTrades 一次查询的最长时间范围，
+// binanceSpotFillWindow 是现货 myTrades 的
+const (
+	binanceFillPage       = 1000
+	binanceFillWindow     = 7 * 24 * time.Hour
+	binanceSpotFillWindow = 24 * time.Hour
+)
+
+// GetFills 先定位 since 之后的第一页，再用 fromId 向后翻页，直到 fromId 返回的页不满为止；since 为零值时从 fromId 0 开始。
+// 按时间窗口查到的页不满只说明该窗口已读完，之后的窗口可能还有成交，因此仍继续用 fromId 翻页
+func (b *Binance) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	if b.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(b, "options fills")
+	}
+	if symbol == "" {
+		return nil, notSupported(b, "fills without a symbol")
+	}
+
+	futures := !b.us && b.config.MarketOrSpot() != types.Spot
+	endpoint := b.pathPrefix() + "/myTrades"
+	if futures {
+		endpoint = b.pathPrefix() + "/userTrades"
+	}
+
+	rows, err := b.firstFills(ctx, endpoint, symbol, since, futures)
+	if err != nil {
+		return nil, err
+	}
+
+	var fills []types.Fill
+	for windowed := !since.IsZero(); ; windowed = false {
+		for _, row := range rows {
+			fills = append(fills, row.toFill())
+		}
+		if len(rows) == 0 || (!windowed && len(rows) < binanceFillPage) {
+			break
+		}
+
+		params := map[string]interface{}{"symbol": symbol, "limit": binanceFillPage, "fromId": rows[len(rows)-1].ID + 1}
+		rows = nil
+		if err := sendRequest(ctx, b, "GET", endpoint, params, true, &rows); err != nil {
+			return nil, err
+		}
+	}
+	return fillHistory(fills, since), nil
+}
+
+// firstFills 返回 since 之后的第一页成交。按时间查询时现货接口只返回 startTime 起 24 小时内的成交，合约接口为 7 天，
+// 窗口内没有成交时继续查询下一个窗口，直到当前时间
+func (b *Binance) firstFills(ctx context.Context, endpoint, symbol string, since time.Time, futures bool) ([]binanceFill, error) {
+	params := map[string]interface{}{"symbol": symbol, "limit": binanceFillPage}
+	if since.IsZero() {
+		params["fromId"] = 0
+
+		var rows []binanceFill
+		err := sendRequest(ctx, b, "GET", endpoint, params, true, &rows)
+		return rows, err
+	}
+
+	window := binanceSpotFillWindow
+	if futures {
+		window = binanceFillWindow
+	}
+	for start := since; start.Before(time.Now()); start = start.Add(window) {
+		params["startTime"], params["endTime"] = start.UnixMilli(), start.Add(window).UnixMilli()-1
+
+		var rows []binanceFill
+		if err := sendRequest(ctx, b, "GET", endpoint, params, true, &rows); err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			return rows, nil
+		}
+	}
+	return nil, nil
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"github.com/hedeqiang/cryptoexchange/types"
 	"net/http"
@@ -15,7 +14,9 @@ import (
 )
 
 type Bitget struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewBitget(config types.ExchangeConfig) *Bitget {
@@ -49,7 +50,7 @@ func (b *Bitget) PrepareRequest(method, endpoint string, params map[string]inter
 		}
 		u.RawQuery = q.Encode()
 	} else {
-		body, err = json.Marshal(params)
+		body, err = jsonBody(params)
 		if err != nil {
 			return nil, err
 		}
@@ -62,7 +63,8 @@ func (b *Bitget) PrepareRequest(method, endpoint string, params map[string]inter
 
 	if signed {
 		timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-		message := timestamp + method + endpoint
+		// GET 请求的签名路径包含查询字符串
+		message := timestamp + method + u.RequestURI()
 		if method != "GET" {
 			message += string(body)
 		}
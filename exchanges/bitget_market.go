@@ -0,0 +1,425 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type bitgetResponse[T any] struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+func (r *bitgetResponse[T]) apiError() error {
+	if r.Code != "00000" {
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.Code, Message: r.Msg}
+	}
+	return nil
+}
+
+type bitgetTicker struct {
+	Symbol      string      `json:"symbol"`
+	LastPr      jsonDecimal `json:"lastPr"`
+	BidPr       jsonDecimal `json:"bidPr"`
+	AskPr       jsonDecimal `json:"askPr"`
+	BaseVolume  jsonDecimal `json:"baseVolume"`
+	QuoteVolume jsonDecimal `json:"quoteVolume"`
+	Ts          jsonInt     `json:"ts"`
+}
+
+// productType 返回 v2 合约接口需要的 productType，现货返回空字符串
+func (b *Bitget) productType() string {
+	switch b.config.MarketOrSpot() {
+	case types.USDMFutures:
+		return "USDT-FUTURES"
+	case types.CoinMFutures:
+		return "COIN-FUTURES"
+	default:
+		return ""
+	}
+}
+
+func (b *Bitget) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	endpoint := "/api/v2/spot/market/tickers"
+	params := map[string]interface{}{"symbol": symbol}
+	if productType := b.productType(); productType != "" {
+		endpoint = "/api/v2/mix/market/ticker"
+		params["productType"] = productType
+	}
+
+	var resp bitgetResponse[[]bitgetTicker]
+	if err := sendRequest(ctx, b, "GET", endpoint, params, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.Ticker{}, fmt.Errorf("bitget ticker %s not found", symbol)
+	}
+
+	return resp.Data[0].toTicker(), nil
+}
+
+func (t bitgetTicker) toTicker() types.Ticker {
+	return types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.LastPr.Decimal,
+		Bid:         t.BidPr.Decimal,
+		Ask:         t.AskPr.Decimal,
+		Volume:      t.BaseVolume.Decimal,
+		QuoteVolume: t.QuoteVolume.Decimal,
+		Timestamp:   msToTime(int64(t.Ts)),
+	}
+}
+
+type bitgetBook struct {
+	Asks [][]jsonDecimal `json:"asks"`
+	Bids [][]jsonDecimal `json:"bids"`
+	Ts   jsonInt         `json:"ts"`
+}
+
+func (b *Bitget) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	endpoint := "/api/v2/spot/market/orderbook"
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"type":   "step0",
+		"limit":  clampDepth(depth, 150),
+	}
+
+	// 合约的 merge-depth 只接受 1/5/15/50/max
+	if productType := b.productType(); productType != "" {
+		endpoint = "/api/v2/mix/market/merge-depth"
+		params = map[string]interface{}{
+			"symbol":      symbol,
+			"productType": productType,
+			"limit":       "max",
+		}
+		if depth > 0 && depth <= 50 {
+			params["limit"] = nearestDepth(depth, []int{1, 5, 15, 50})
+		}
+	}
+
+	var resp bitgetResponse[bitgetBook]
+	if err := sendRequest(ctx, b, "GET", endpoint, params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(resp.Data.Bids),
+		Asks:      levelsFromRows(resp.Data.Asks),
+		Timestamp: msToTime(int64(resp.Data.Ts)),
+	}, depth), nil
+}
+
+var bitgetSpotIntervals = map[types.Interval]string{
+	types.Interval1m: "1min", types.Interval3m: "3min", types.Interval5m: "5min", types.Interval15m: "15min",
+	types.Interval30m: "30min", types.Interval1h: "1h", types.Interval4h: "4h", types.Interval6h: "6Hutc",
+	types.Interval12h: "12Hutc", types.Interval1d: "1Dutc", types.Interval3d: "3Dutc", types.Interval1w: "1Wutc",
+	types.Interval1M: "1Mutc",
+}
+
+var bitgetMixIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval3m: "3m", types.Interval5m: "5m", types.Interval15m: "15m",
+	types.Interval30m: "30m", types.Interval1h: "1H", types.Interval4h: "4H", types.Interval6h: "6Hutc",
+	types.Interval12h: "12Hutc", types.Interval1d: "1Dutc", types.Interval3d: "3Dutc", types.Interval1w: "1Wutc",
+	types.Interval1M: "1Mutc",
+}
+
+// GetCandles 中现货返回 [ts, o, h, l, c, baseVol, usdtVol, quoteVol]，合约返回 [ts, o, h, l, c, baseVol, quoteVol]
+func (b *Bitget) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	intervals, endpoint, quoteIndex := bitgetSpotIntervals, "/api/v2/spot/market/candles", 7
+	productType := b.productType()
+	if productType != "" {
+		intervals, endpoint, quoteIndex = bitgetMixIntervals, "/api/v2/mix/market/candles", 6
+	}
+
+	return fetchCandles(ctx, b, intervals, b.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol":      symbol,
+				"granularity": venueInterval,
+			}
+			if productType != "" {
+				params["productType"] = productType
+			}
+			if !start.IsZero() {
+				params["startTime"] = start.UnixMilli()
+			}
+			if !end.IsZero() {
+				params["endTime"] = end.UnixMilli()
+			}
+			if limit > 0 {
+				params["limit"] = clampDepth(limit, 1000)
+			}
+
+			var resp bitgetResponse[[][]jsonDecimal]
+			if err := sendRequest(ctx, b, "GET", endpoint, params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Data))
+			for _, row := range resp.Data {
+				if len(row) <= quoteIndex {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, msToTime(row[0].IntPart()), 1, 2, 3, 4, 5, quoteIndex))
+			}
+			return candles, nil
+		})
+}
+
+type bitgetTrade struct {
+	TradeID string      `json:"tradeId"`
+	Price   jsonDecimal `json:"price"`
+	Size    jsonDecimal `json:"size"`
+	Side    string      `json:"side"`
+	Ts      jsonInt     `json:"ts"`
+}
+
+// GetRecentTrades 现货单次最多返回 500 笔，合约最多 100 笔
+func (b *Bitget) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	endpoint, maxLimit := "/api/v2/spot/market/fills", 500
+	params := map[string]interface{}{"symbol": symbol}
+	if productType := b.productType(); productType != "" {
+		endpoint, maxLimit = "/api/v2/mix/market/fills", 100
+		params["productType"] = productType
+	}
+	if limit > 0 {
+		params["limit"] = clampDepth(limit, maxLimit)
+	}
+
+	var resp bitgetResponse[[]bitgetTrade]
+	if err := sendRequest(ctx, b, "GET", endpoint, params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		trades = append(trades, types.Trade{
+			ID:    t.TradeID,
+			Price: t.Price.Decimal,
+			Size:  t.Size.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  msToTime(int64(t.Ts)),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// bitgetSpotSymbol 只提供价格和数量的小数位数
+type bitgetSpotSymbol struct {
+	Symbol            string      `json:"symbol"`
+	BaseCoin          string      `json:"baseCoin"`
+	QuoteCoin         string      `json:"quoteCoin"`
+	MinTradeAmount    jsonDecimal `json:"minTradeAmount"`
+	MaxTradeAmount    jsonDecimal `json:"maxTradeAmount"`
+	PricePrecision    jsonInt     `json:"pricePrecision"`
+	QuantityPrecision jsonInt     `json:"quantityPrecision"`
+	MinTradeUSDT      jsonDecimal `json:"minTradeUSDT"`
+	Status            string      `json:"status"`
+}
+
+// bitgetContract 的价格步长为 priceEndStep * 10^-pricePlace，数量步长为 sizeMultiplier
+type bitgetContract struct {
+	Symbol         string      `json:"symbol"`
+	BaseCoin       string      `json:"baseCoin"`
+	QuoteCoin      string      `json:"quoteCoin"`
+	MinTradeNum    jsonDecimal `json:"minTradeNum"`
+	MaxOrderQty    jsonDecimal `json:"maxOrderQty"`
+	PricePlace     jsonInt     `json:"pricePlace"`
+	PriceEndStep   jsonDecimal `json:"priceEndStep"`
+	SizeMultiplier jsonDecimal `json:"sizeMultiplier"`
+	MinTradeUSDT   jsonDecimal `json:"minTradeUSDT"`
+	SymbolStatus   string      `json:"symbolStatus"`
+}
+
+func bitgetStatus(status string) types.InstrumentStatus {
+	switch status {
+	case "online", "normal":
+		return types.InstrumentTrading
+	case "gray", "listed":
+		return types.InstrumentPreTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+// GetInstruments 中 Bitget 的最小下单金额以 USDT 计（minTradeUSDT）
+func (b *Bitget) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return b.instruments.get(ctx, b.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		productType := b.productType()
+		if productType == "" {
+			var resp bitgetResponse[[]bitgetSpotSymbol]
+			if err := sendRequest(ctx, b, "GET", "/api/v2/spot/public/symbols", nil, false, &resp); err != nil {
+				return nil, err
+			}
+
+			instruments := make([]types.Instrument, 0, len(resp.Data))
+			for _, s := range resp.Data {
+				instruments = append(instruments, types.Instrument{
+					Symbol:      s.Symbol,
+					Base:        s.BaseCoin,
+					Quote:       s.QuoteCoin,
+					TickSize:    precisionStep(int(s.PricePrecision)),
+					StepSize:    precisionStep(int(s.QuantityPrecision)),
+					MinQty:      s.MinTradeAmount.Decimal,
+					MaxQty:      s.MaxTradeAmount.Decimal,
+					MinNotional: s.MinTradeUSDT.Decimal,
+					Status:      bitgetStatus(s.Status),
+				})
+			}
+			return instruments, nil
+		}
+
+		var resp bitgetResponse[[]bitgetContract]
+		if err := sendRequest(ctx, b, "GET", "/api/v2/mix/market/contracts", map[string]interface{}{"productType": productType}, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Data))
+		for _, c := range resp.Data {
+			instruments = append(instruments, types.Instrument{
+				Symbol:       c.Symbol,
+				Base:         c.BaseCoin,
+				Quote:        c.QuoteCoin,
+				TickSize:     c.PriceEndStep.Mul(precisionStep(int(c.PricePlace))),
+				StepSize:     c.SizeMultiplier.Decimal,
+				MinQty:       c.MinTradeNum.Decimal,
+				MaxQty:       c.MaxOrderQty.Decimal,
+				MinNotional:  c.MinTradeUSDT.Decimal,
+				ContractSize: decimal.NewFromInt(1),
+				Status:       bitgetStatus(c.SymbolStatus),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// GetFundingRate 中 fundingRateInterval 以小时表示，nextUpdate 为下次结算时间
+func (b *Bitget) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": b.productType(),
+	}
+
+	var resp bitgetResponse[[]struct {
+		Symbol              string      `json:"symbol"`
+		FundingRate         jsonDecimal `json:"fundingRate"`
+		FundingRateInterval jsonInt     `json:"fundingRateInterval"`
+		NextUpdate          jsonInt     `json:"nextUpdate"`
+	}]
+	if err := sendRequest(ctx, b, "GET", "/api/v2/mix/market/current-fund-rate", params, false, &resp); err != nil {
+		return types.FundingRate{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.FundingRate{}, fmt.Errorf("bitget funding rate %s not found", symbol)
+	}
+
+	f := resp.Data[0]
+	return types.FundingRate{
+		Symbol:          f.Symbol,
+		Rate:            f.FundingRate.Decimal,
+		NextFundingTime: msToTime(int64(f.NextUpdate)),
+		Interval:        time.Duration(f.FundingRateInterval) * time.Hour,
+	}, nil
+}
+
+// GetFundingHistory 中 Bitget 只能按页查询最近的记录（每页最多 100 条），时间范围在本地过滤
+func (b *Bitget) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": b.productType(),
+		"pageSize":    clampDepth(limit, 100),
+	}
+
+	var resp bitgetResponse[[]struct {
+		Symbol      string      `json:"symbol"`
+		FundingRate jsonDecimal `json:"fundingRate"`
+		FundingTime jsonInt     `json:"fundingTime"`
+	}]
+	if err := sendRequest(ctx, b, "GET", "/api/v2/mix/market/history-fund-rate", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(resp.Data))
+	for _, r := range resp.Data {
+		records = append(records, types.FundingRecord{Symbol: r.Symbol, Rate: r.FundingRate.Decimal, Time: msToTime(int64(r.FundingTime))})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+type bitgetSymbolPrice struct {
+	Symbol     string      `json:"symbol"`
+	MarkPrice  jsonDecimal `json:"markPrice"`
+	IndexPrice jsonDecimal `json:"indexPrice"`
+	Ts         jsonInt     `json:"ts"`
+}
+
+func (b *Bitget) symbolPrice(ctx context.Context, symbol string) (bitgetSymbolPrice, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return bitgetSymbolPrice{}, err
+	}
+
+	params := map[string]interface{}{
+		"symbol":      symbol,
+		"productType": b.productType(),
+	}
+
+	var resp bitgetResponse[[]bitgetSymbolPrice]
+	if err := sendRequest(ctx, b, "GET", "/api/v2/mix/market/symbol-price", params, false, &resp); err != nil {
+		return bitgetSymbolPrice{}, err
+	}
+	if len(resp.Data) == 0 {
+		return bitgetSymbolPrice{}, fmt.Errorf("bitget symbol price %s not found", symbol)
+	}
+	return resp.Data[0], nil
+}
+
+func (b *Bitget) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.symbolPrice(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.MarkPrice.Decimal, Timestamp: msToTime(int64(p.Ts))}, nil
+}
+
+func (b *Bitget) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.symbolPrice(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.IndexPrice.Decimal, Timestamp: msToTime(int64(p.Ts))}, nil
+}
+
+func (b *Bitget) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	endpoint := "/api/v2/spot/market/tickers"
+	params := map[string]interface{}{}
+	if productType := b.productType(); productType != "" {
+		endpoint = "/api/v2/mix/market/tickers"
+		params["productType"] = productType
+	}
+
+	var resp bitgetResponse[[]bitgetTicker]
+	if err := sendRequest(ctx, b, "GET", endpoint, params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		tickers = append(tickers, t.toTicker())
+	}
+	return keyTickers(ctx, b, tickers)
+}
@@ -0,0 +1,49 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitget_PrepareRequest(t *testing.T) {
+	bitget := NewBitget(types.ExchangeConfig{
+		APIKey:        "test_key",
+		APISecret:     "test_secret",
+		APIPassphrase: "test_passphrase",
+	})
+
+	// ACCESS-SIGN = base64(HMAC-SHA256(timestamp + method + 路径和查询字符串 + 请求体))
+	sign := func(timestamp, method, requestURI, body string) string {
+		mac := hmac.New(sha256.New, []byte("test_secret"))
+		mac.Write([]byte(timestamp + method + requestURI + body))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req, err := bitget.PrepareRequest("GET", "/api/v2/spot/trade/orderInfo", map[string]interface{}{"orderId": "1", "clientOid": "a"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "clientOid=a&orderId=1", req.URL.RawQuery)
+		assert.Equal(t, "test_key", req.Header.Get("ACCESS-KEY"))
+		assert.Equal(t, "test_passphrase", req.Header.Get("ACCESS-PASSPHRASE"))
+
+		timestamp := req.Header.Get("ACCESS-TIMESTAMP")
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, sign(timestamp, "GET", "/api/v2/spot/trade/orderInfo?clientOid=a&orderId=1", ""), req.Header.Get("ACCESS-SIGN"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := bitget.PrepareRequest("POST", "/api/v2/spot/trade/place-order", map[string]interface{}{"symbol": "BTCUSDT", "size": "0.1"}, true)
+		assert.NoError(t, err)
+		assert.Empty(t, req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.JSONEq(t, `{"size":"0.1","symbol":"BTCUSDT"}`, string(body))
+		assert.Equal(t, sign(req.Header.Get("ACCESS-TIMESTAMP"), "POST", "/api/v2/spot/trade/place-order", string(body)), req.Header.Get("ACCESS-SIGN"))
+	})
+}
@@ -0,0 +1,539 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type bitgetTradeRate struct {
+	MakerFeeRate jsonDecimal `json:"makerFeeRate"`
+	TakerFeeRate jsonDecimal `json:"takerFeeRate"`
+}
+
+// GetTradingFees 使用 /api/v2/common/trade-rate，该接口只能按交易对查询，
+// symbols 为空时逐个查询当前产品线的全部交易对，请求数与交易对数量相同
+func (b *Bitget) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	businessType := "spot"
+	if b.productType() != "" {
+		businessType = "mix"
+	}
+
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = instrumentSymbols(ctx, b); err != nil {
+			return nil, err
+		}
+	}
+	return fanOut(ctx, symbols, func(ctx context.Context, symbol string) (types.TradingFee, error) {
+		var resp bitgetResponse[bitgetTradeRate]
+		params := map[string]interface{}{"symbol": symbol, "businessType": businessType}
+		if err := sendRequest(ctx, b, "GET", "/api/v2/common/trade-rate", params, true, &resp); err != nil {
+			return types.TradingFee{}, err
+		}
+		return types.TradingFee{Symbol: symbol, Maker: resp.Data.MakerFeeRate.Decimal, Taker: resp.Data.TakerFeeRate.Decimal}, nil
+	})
+}
+
+// bitgetOrderAck 是下单、撤单接口返回的订单号
+type bitgetOrderAck struct {
+	OrderID   string `json:"orderId"`
+	ClientOid string `json:"clientOid"`
+}
+
+// bitgetForce 返回限价单的 force，post-only 也通过它表达
+func bitgetForce(req types.OrderRequest) string {
+	if req.PostOnly {
+		return "post_only"
+	}
+	switch timeInForce(req) {
+	case types.IOC:
+		return "ioc"
+	case types.FOK:
+		return "fok"
+	default:
+		return "gtc"
+	}
+}
+
+// marginCoin 返回合约的保证金币种：U 本位合约为 USDT，币本位合约为交易对的基础币
+func (b *Bitget) marginCoin(ctx context.Context, symbol string) (string, error) {
+	if b.config.MarketOrSpot() == types.USDMFutures {
+		return "USDT", nil
+	}
+	s, err := canonicalSymbol(ctx, b, symbol)
+	if err != nil {
+		return "", err
+	}
+	return s.Base, nil
+}
+
+// orderParams 把统一的下单请求转换为 v2 下单参数。现货市价买单的 size 是计价币金额，只能按 QuoteQuantity 下单；
+// 合约按单向持仓、全仓模式下单，size 以基础币计
+func (b *Bitget) orderParams(ctx context.Context, req types.OrderRequest) (map[string]interface{}, error) {
+	if req.Type.Conditional() {
+		return nil, notSupported(b, "conditional orders")
+	}
+
+	params := map[string]interface{}{
+		"symbol":    req.Symbol,
+		"side":      lowerSide(req.Side),
+		"orderType": "limit",
+		"clientOid": req.ClientOrderID,
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		params["force"] = bitgetForce(req)
+	} else {
+		params["orderType"] = "market"
+	}
+
+	if b.config.MarketOrSpot() == types.Spot {
+		if req.ReduceOnly {
+			return nil, notSupported(b, "reduce-only spot orders")
+		}
+		params["size"] = req.Quantity.String()
+		if req.Type == types.MarketOrder && req.Side == types.Buy {
+			if !req.QuoteQuantity.IsPositive() {
+				return nil, notSupported(b, "spot market buys by base quantity")
+			}
+			params["size"] = req.QuoteQuantity.String()
+		} else if req.QuoteQuantity.IsPositive() {
+			return nil, notSupported(b, "spot market sells by quote quantity")
+		}
+		return params, nil
+	}
+
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(b, "futures orders by quote quantity")
+	}
+	marginCoin, err := b.marginCoin(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	params["productType"] = b.productType()
+	params["marginMode"] = "crossed"
+	params["marginCoin"] = marginCoin
+	params["size"] = req.Quantity.String()
+	params["reduceOnly"] = "NO"
+	if req.ReduceOnly {
+		params["reduceOnly"] = "YES"
+	}
+	return params, nil
+}
+
+func (b *Bitget) placer() placer {
+	return placer{t: b, config: b.config, idLength: clientOrderIDLength, place: b.placeOrder}
+}
+
+// PlaceOrder 中现货下单到 /api/v2/spot，合约下单到 /api/v2/mix；条件单返回包装了 ErrNotSupported 的错误
+func (b *Bitget) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return b.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单，Bitget 的批量下单接口只接受同一交易对的订单
+func (b *Bitget) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return b.placer().placeOrders(ctx, reqs), nil
+}
+
+func (b *Bitget) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := b.orderParams(ctx, req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	endpoint := "/api/v2/spot/trade/place-order"
+	if b.config.MarketOrSpot() != types.Spot {
+		endpoint = "/api/v2/mix/order/place-order"
+	}
+
+	var resp bitgetResponse[bitgetOrderAck]
+	if err := sendRequest(ctx, b, "POST", endpoint, params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, resp.Data.OrderID), nil
+}
+
+// AmendOrder 现货使用 cancel-replace-order，按剩余数量下新订单；合约使用 modify-order，数量为修改后的总数量。
+// 两个接口都要求同时提供价格和数量，并为新订单分配新的订单号，因此先查询原订单补全未修改的字段
+func (b *Bitget) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	orig, err := b.GetOrder(ctx, req.Symbol, req.Ref)
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	if err := amendable(orig); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	price, quantity := req.Price, req.Quantity
+	if price.IsZero() {
+		price = orig.Price
+	}
+	if quantity.IsZero() {
+		quantity = orig.Quantity
+	}
+	clientOrderID := req.NewClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = newClientOrderID(clientOrderIDLength)
+	}
+
+	params, err := b.symbolParams(ctx, req.Symbol)
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	for k, v := range bitgetRef(types.OrderRef{ID: orig.ID}) {
+		params[k] = v
+	}
+
+	if b.config.MarketOrSpot() != types.Spot {
+		params["newClientOid"] = clientOrderID
+		params["newPrice"] = price.String()
+		params["newSize"] = quantity.String()
+
+		var resp bitgetResponse[bitgetOrderAck]
+		if err := sendRequest(ctx, b, "POST", "/api/v2/mix/order/modify-order", params, true, &resp); err != nil {
+			return types.AmendResult{}, orderNotFound(err, bitgetOrderNotFound...)
+		}
+		order := amendedOrder(req, resp.Data.OrderID, clientOrderID)
+		order.Side, order.TimeInForce, order.Price, order.Quantity = orig.Side, orig.TimeInForce, price, quantity
+		return types.AmendResult{Order: order, Replaced: true}, nil
+	}
+
+	remaining := quantity.Sub(orig.FilledQuantity)
+	if !remaining.IsPositive() {
+		return types.AmendResult{}, fmt.Errorf("%w: order %s has already filled %s", types.ErrInvalidOrder, orig.ID, orig.FilledQuantity)
+	}
+	params["newClientOid"] = clientOrderID
+	params["price"] = price.String()
+	params["size"] = remaining.String()
+
+	// success 为 failure 时原订单已撤销或成交，没有下出新订单
+	var resp bitgetResponse[struct {
+		bitgetOrderAck
+		Success string `json:"success"`
+		Msg     string `json:"msg"`
+	}]
+	if err := sendRequest(ctx, b, "POST", "/api/v2/spot/trade/cancel-replace-order", params, true, &resp); err != nil {
+		return types.AmendResult{}, orderNotFound(err, bitgetOrderNotFound...)
+	}
+	if resp.Data.Success == "failure" {
+		return types.AmendResult{}, fmt.Errorf("bitget cancel-replace of order %s failed: %s", orig.ID, resp.Data.Msg)
+	}
+	order := amendedOrder(req, resp.Data.OrderID, clientOrderID)
+	order.Side, order.TimeInForce, order.Price, order.Quantity = orig.Side, orig.TimeInForce, price, remaining
+	return types.AmendResult{Order: order, Replaced: true}, nil
+}
+
+// bitgetOrderNotFound 是现货与合约订单不存在时的错误码
+var bitgetOrderNotFound = []string{"43001", "40768"}
+
+// bitgetBatchSize 是批量撤单一次最多包含的订单数，bitgetPageSize 是订单列表每页的最大数量
+const (
+	bitgetBatchSize = 50
+	bitgetPageSize  = 100
+)
+
+// symbolParams 返回指定交易对的参数，合约还需要 productType 与 marginCoin
+func (b *Bitget) symbolParams(ctx context.Context, symbol string) (map[string]interface{}, error) {
+	params := map[string]interface{}{"symbol": symbol}
+	if b.config.MarketOrSpot() == types.Spot {
+		return params, nil
+	}
+	marginCoin, err := b.marginCoin(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	params["productType"] = b.productType()
+	params["marginCoin"] = marginCoin
+	return params, nil
+}
+
+// bitgetRef 返回按订单号或自定义订单号指定订单的参数
+func bitgetRef(ref types.OrderRef) map[string]interface{} {
+	if ref.ID != "" {
+		return map[string]interface{}{"orderId": ref.ID}
+	}
+	return map[string]interface{}{"clientOid": ref.ClientOrderID}
+}
+
+func (b *Bitget) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	params, err := b.symbolParams(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	for k, v := range bitgetRef(ref) {
+		params[k] = v
+	}
+
+	endpoint := "/api/v2/spot/trade/cancel-order"
+	if b.config.MarketOrSpot() != types.Spot {
+		endpoint = "/api/v2/mix/order/cancel-order"
+	}
+	var resp bitgetResponse[bitgetOrderAck]
+	return orderNotFound(sendRequest(ctx, b, "POST", endpoint, params, true, &resp), bitgetOrderNotFound...)
+}
+
+// bitgetBatchAck 是批量接口的结果，失败的订单带有错误码和原因
+type bitgetBatchAck struct {
+	SuccessList []bitgetOrderAck `json:"successList"`
+	FailureList []struct {
+		bitgetOrderAck
+		ErrorCode string `json:"errorCode"`
+		ErrorMsg  string `json:"errorMsg"`
+	} `json:"failureList"`
+}
+
+// result 按订单号或自定义订单号找到 ref 的结果
+func (a bitgetBatchAck) result(ref types.OrderRef) error {
+	matches := func(ack bitgetOrderAck) bool {
+		return (ref.ID != "" && ack.OrderID == ref.ID) || (ref.ID == "" && ack.ClientOid == ref.ClientOrderID)
+	}
+	for _, failure := range a.FailureList {
+		if matches(failure.bitgetOrderAck) {
+			return orderNotFound(batchError(failure.ErrorCode, failure.ErrorMsg), bitgetOrderNotFound...)
+		}
+	}
+	for _, success := range a.SuccessList {
+		if matches(success) {
+			return nil
+		}
+	}
+	return errMissingResult
+}
+
+// CancelOrders 通过 batch-cancel-order（合约为 batch-cancel-orders）批量撤单，每批最多 50 个订单
+func (b *Bitget) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, bitgetBatchSize, func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		params, err := b.symbolParams(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]map[string]interface{}, len(refs))
+		for i, ref := range refs {
+			items[i] = bitgetRef(ref)
+		}
+
+		endpoint := "/api/v2/spot/trade/batch-cancel-order"
+		params["orderList"] = items
+		if b.config.MarketOrSpot() != types.Spot {
+			endpoint = "/api/v2/mix/order/batch-cancel-orders"
+			delete(params, "orderList")
+			params["orderIdList"] = items
+		}
+
+		var resp bitgetResponse[bitgetBatchAck]
+		if err := sendRequest(ctx, b, "POST", endpoint, params, true, &resp); err != nil {
+			return nil, err
+		}
+		results := make([]types.CancelResult, len(refs))
+		for i, ref := range refs {
+			results[i] = refResult(symbol, ref, resp.Data.result(ref))
+		}
+		return results, nil
+	}, nil), nil
+}
+
+// CancelAllOrders 列出挂单后逐个撤销；现货的 cancel-symbol-order 与合约的 cancel-all-orders 不返回被撤销的订单
+func (b *Bitget) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	orders, err := b.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+		return b.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+	}), nil
+}
+
+// bitgetOrder 是现货与合约订单接口的订单。合约详情接口用 state 表示状态，其余接口用 status；
+// 现货市价买单的 size 是计价币金额。合约的 fee 为负数表示支出，现货的手续费在 feeDetail 中，不解析
+type bitgetOrder struct {
+	Symbol     string      `json:"symbol"`
+	OrderID    string      `json:"orderId"`
+	ClientOid  string      `json:"clientOid"`
+	Price      jsonDecimal `json:"price"`
+	Size       jsonDecimal `json:"size"`
+	OrderType  string      `json:"orderType"`
+	Side       string      `json:"side"`
+	Force      string      `json:"force"`
+	Status     string      `json:"status"`
+	State      string      `json:"state"`
+	PriceAvg   jsonDecimal `json:"priceAvg"`
+	BaseVolume jsonDecimal `json:"baseVolume"`
+	Fee        jsonDecimal `json:"fee"`
+	MarginCoin string      `json:"marginCoin"`
+	ReduceOnly string      `json:"reduceOnly"`
+	CTime      jsonInt     `json:"cTime"`
+	UTime      jsonInt     `json:"uTime"`
+}
+
+var bitgetStatuses = map[string]types.OrderStatus{
+	"init":             types.OrderNew,
+	"new":              types.OrderNew,
+	"live":             types.OrderNew,
+	"partially_filled": types.OrderPartiallyFilled,
+	"filled":           types.OrderFilled,
+	"cancelled":        types.OrderCanceled,
+	"canceled":         types.OrderCanceled,
+}
+
+// bitgetTimeInForce 把 force 映射为统一的有效方式，post_only 是只做 maker 的 GTC 限价单
+var bitgetTimeInForce = map[string]types.TimeInForce{
+	"gtc":       types.GTC,
+	"post_only": types.GTC,
+	"ioc":       types.IOC,
+	"fok":       types.FOK,
+}
+
+func (o bitgetOrder) toOrder(spot bool) types.Order {
+	raw := o.Status
+	if raw == "" {
+		raw = o.State
+	}
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClientOid,
+		Symbol:         o.Symbol,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    bitgetTimeInForce[o.Force],
+		Price:          o.Price.Decimal,
+		Quantity:       o.Size.Decimal,
+		ReduceOnly:     o.ReduceOnly == "YES",
+		Status:         orderStatus(bitgetStatuses, raw),
+		FilledQuantity: o.BaseVolume.Decimal,
+		AveragePrice:   o.PriceAvg.Decimal,
+		Fee:            o.Fee.Neg(),
+		FeeAsset:       o.MarginCoin,
+		Raw:            raw,
+		CreatedAt:      msToTime(int64(o.CTime)),
+		UpdatedAt:      msToTime(int64(o.UTime)),
+	}
+	if o.OrderType == "market" {
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+		if spot && order.Side == types.Buy {
+			order.QuoteQuantity, order.Quantity = order.Quantity, decimal.Zero
+		}
+	}
+	return order
+}
+
+// listOrders 按 idLessThan 向前翻页读取 endpoint 的订单，limit 为 0 时读取全部。
+// 现货接口直接返回订单数组，合约接口把订单放在 entrustedList 中
+func (b *Bitget) listOrders(ctx context.Context, endpoint string, params map[string]interface{}, limit int) ([]types.Order, error) {
+	spot := b.config.MarketOrSpot() == types.Spot
+	if !spot {
+		params["productType"] = b.productType()
+	}
+	params["limit"] = bitgetPageSize
+
+	var orders []types.Order
+	for limit <= 0 || len(orders) < limit {
+		var rows []bitgetOrder
+		if spot {
+			var resp bitgetResponse[[]bitgetOrder]
+			if err := sendRequest(ctx, b, "GET", endpoint, params, true, &resp); err != nil {
+				return nil, err
+			}
+			rows = resp.Data
+		} else {
+			var resp bitgetResponse[struct {
+				EntrustedList []bitgetOrder `json:"entrustedList"`
+			}]
+			if err := sendRequest(ctx, b, "GET", endpoint, params, true, &resp); err != nil {
+				return nil, err
+			}
+			rows = resp.Data.EntrustedList
+		}
+
+		for _, row := range rows {
+			orders = append(orders, row.toOrder(spot))
+		}
+		if len(rows) < bitgetPageSize {
+			break
+		}
+		params["idLessThan"] = rows[len(rows)-1].OrderID
+	}
+	return orders, nil
+}
+
+func (b *Bitget) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+	endpoint := "/api/v2/spot/trade/unfilled-orders"
+	if b.config.MarketOrSpot() != types.Spot {
+		endpoint = "/api/v2/mix/order/orders-pending"
+	}
+	return b.listOrders(ctx, endpoint, params, 0)
+}
+
+// GetOrder 现货使用 orderInfo，找不到订单时返回空数组；合约使用 detail
+func (b *Bitget) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	params, err := b.symbolParams(ctx, symbol)
+	if err != nil {
+		return types.Order{}, err
+	}
+	for k, v := range bitgetRef(ref) {
+		params[k] = v
+	}
+
+	if b.config.MarketOrSpot() != types.Spot {
+		delete(params, "marginCoin")
+		var resp bitgetResponse[bitgetOrder]
+		if err := sendRequest(ctx, b, "GET", "/api/v2/mix/order/detail", params, true, &resp); err != nil {
+			return types.Order{}, orderNotFound(err, bitgetOrderNotFound...)
+		}
+		return resp.Data.toOrder(false), nil
+	}
+
+	delete(params, "symbol")
+	var resp bitgetResponse[[]bitgetOrder]
+	if err := sendRequest(ctx, b, "GET", "/api/v2/spot/trade/orderInfo", params, true, &resp); err != nil {
+		return types.Order{}, orderNotFound(err, bitgetOrderNotFound...)
+	}
+	if len(resp.Data) == 0 {
+		return types.Order{}, fmt.Errorf("bitget order %s: %w", symbol, types.ErrOrderNotFound)
+	}
+	return resp.Data[0].toOrder(true), nil
+}
+
+func (b *Bitget) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return b.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 history-orders（合约为 orders-history），只能查询最近 90 天的订单
+func (b *Bitget) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if req.Symbol != "" {
+		params["symbol"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["startTime"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["endTime"] = req.End.UnixMilli()
+	}
+
+	endpoint := "/api/v2/spot/trade/history-orders"
+	if b.config.MarketOrSpot() != types.Spot {
+		endpoint = "/api/v2/mix/order/orders-history"
+	}
+	orders, err := b.listOrders(ctx, endpoint, params, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return orderHistory(orders, req), nil
+}
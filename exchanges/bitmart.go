@@ -0,0 +1,91 @@
+package exchanges
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type BitMart struct {
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
+}
+
+func NewBitMart(config types.ExchangeConfig) *BitMart {
+	return &BitMart{config: config}
+}
+
+func (b *BitMart) Name() types.ExchangeName {
+	return types.BitMart
+}
+
+func (b *BitMart) GetDefaultBaseURL() string {
+	if b.config.MarketOrSpot() != types.Spot {
+		return "https://api-cloud-v2.bitmart.com"
+	}
+	return "https://api-cloud.bitmart.com"
+}
+
+func (b *BitMart) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	baseURL := b.config.BaseURL
+	if baseURL == "" {
+		baseURL = b.GetDefaultBaseURL()
+	}
+
+	u, err := url.Parse(baseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	body := []byte{}
+	if method == "GET" {
+		q := u.Query()
+		for k, v := range params {
+			q.Set(k, fmt.Sprint(v))
+		}
+		u.RawQuery = q.Encode()
+	} else {
+		body, err = json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-BM-KEY", b.config.APIKey)
+
+	if signed {
+		timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+		message := u.RawQuery
+		if method != "GET" {
+			message = string(body)
+		}
+
+		req.Header.Set("X-BM-SIGN", b.sign(timestamp, message))
+		req.Header.Set("X-BM-TIMESTAMP", timestamp)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (b *BitMart) sign(timestamp, message string) string {
+	mac := hmac.New(sha256.New, []byte(b.config.APISecret))
+	mac.Write([]byte(timestamp + "#" + b.config.APIMemo + "#" + message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,269 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type bitMartResponse[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+func (r *bitMartResponse[T]) apiError() error {
+	if r.Code != 1000 {
+		return &types.APIError{StatusCode: http.StatusOK, Code: strconv.Itoa(r.Code), Message: r.Message}
+	}
+	return nil
+}
+
+type bitMartTicker struct {
+	Symbol string      `json:"symbol"`
+	Last   jsonDecimal `json:"last"`
+	V24h   jsonDecimal `json:"v_24h"`
+	QV24h  jsonDecimal `json:"qv_24h"`
+	BidPx  jsonDecimal `json:"bid_px"`
+	AskPx  jsonDecimal `json:"ask_px"`
+	Ts     jsonInt     `json:"ts"`
+}
+
+func (b *BitMart) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.Ticker{}, notSupported(b, "futures ticker")
+	}
+
+	var resp bitMartResponse[bitMartTicker]
+	if err := sendRequest(ctx, b, "GET", "/spot/quotation/v3/ticker", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+
+	return resp.Data.toTicker(), nil
+}
+
+func (t bitMartTicker) toTicker() types.Ticker {
+	return types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.Last.Decimal,
+		Bid:         t.BidPx.Decimal,
+		Ask:         t.AskPx.Decimal,
+		Volume:      t.V24h.Decimal,
+		QuoteVolume: t.QV24h.Decimal,
+		Timestamp:   msToTime(int64(t.Ts)),
+	}
+}
+
+// bitMartTickerRow 是 /spot/quotation/v3/tickers 返回的数组形式：
+// [symbol, last, v_24h, qv_24h, open_24h, high_24h, low_24h, fluctuation, bid_px, bid_sz, ask_px, ask_sz, ts]
+type bitMartTickerRow bitMartTicker
+
+func (r *bitMartTickerRow) UnmarshalJSON(b []byte) error {
+	var row []json.RawMessage
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	if len(row) < 13 {
+		return fmt.Errorf("bitmart ticker row has %d fields", len(row))
+	}
+
+	fields := map[int]interface{}{0: &r.Symbol, 1: &r.Last, 2: &r.V24h, 3: &r.QV24h, 8: &r.BidPx, 10: &r.AskPx, 12: &r.Ts}
+	for i, field := range fields {
+		if err := json.Unmarshal(row[i], field); err != nil {
+			return fmt.Errorf("bitmart ticker field %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+type bitMartBook struct {
+	Ts   jsonInt         `json:"ts"`
+	Bids [][]jsonDecimal `json:"bids"`
+	Asks [][]jsonDecimal `json:"asks"`
+}
+
+func (b *BitMart) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.OrderBook{}, notSupported(b, "futures order book")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  clampDepth(depth, 50),
+	}
+
+	var resp bitMartResponse[bitMartBook]
+	if err := sendRequest(ctx, b, "GET", "/spot/quotation/v3/books", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(resp.Data.Bids),
+		Asks:      levelsFromRows(resp.Data.Asks),
+		Timestamp: msToTime(int64(resp.Data.Ts)),
+	}, depth), nil
+}
+
+// BitMart 的 step 以分钟表示，43200 分钟不是自然月，因此不映射 1M
+var bitMartIntervals = map[types.Interval]string{
+	types.Interval1m: "1", types.Interval3m: "3", types.Interval5m: "5", types.Interval15m: "15",
+	types.Interval30m: "30", types.Interval1h: "60", types.Interval2h: "120", types.Interval4h: "240",
+	types.Interval1d: "1440", types.Interval1w: "10080",
+}
+
+// GetCandles 中 BitMart 返回 [t(秒), open, high, low, close, volume, quoteVolume]，单次最多 200 根
+func (b *BitMart) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures candles")
+	}
+
+	return fetchCandles(ctx, b, bitMartIntervals, b.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol": symbol,
+				"step":   venueInterval,
+				"limit":  clampDepth(limit, 200),
+			}
+			if !start.IsZero() {
+				params["after"] = start.Unix() - 1
+			}
+			if !end.IsZero() {
+				params["before"] = end.Unix() + 1
+			}
+
+			var resp bitMartResponse[[][]jsonDecimal]
+			if err := sendRequest(ctx, b, "GET", "/spot/quotation/v3/klines", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Data))
+			for _, row := range resp.Data {
+				if len(row) < 7 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, unixTime(row[0], time.Second), 1, 2, 3, 4, 5, 6))
+			}
+			return candles, nil
+		})
+}
+
+// GetRecentTrades 中 BitMart 返回 [symbol, ts(毫秒), price, size, side]，不提供成交 ID，单次最多 50 笔
+func (b *BitMart) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures trades")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  clampDepth(limit, 50),
+	}
+
+	var resp bitMartResponse[[][]string]
+	if err := sendRequest(ctx, b, "GET", "/spot/quotation/v3/trades", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		if len(row) < 5 {
+			continue
+		}
+
+		ts, err := strconv.ParseInt(row[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bitmart trade timestamp %q: %w", row[1], err)
+		}
+		price, err := decimal.NewFromString(row[2])
+		if err != nil {
+			return nil, fmt.Errorf("bitmart trade price %q: %w", row[2], err)
+		}
+		size, err := decimal.NewFromString(row[3])
+		if err != nil {
+			return nil, fmt.Errorf("bitmart trade size %q: %w", row[3], err)
+		}
+
+		trades = append(trades, types.Trade{
+			Price: price,
+			Size:  size,
+			Side:  parseSide(row[4]),
+			Time:  msToTime(ts),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// bitMartSymbol 中 quote_increment 实际是数量步长，价格只提供小数位数
+type bitMartSymbol struct {
+	Symbol            string      `json:"symbol"`
+	BaseCurrency      string      `json:"base_currency"`
+	QuoteCurrency     string      `json:"quote_currency"`
+	QuoteIncrement    jsonDecimal `json:"quote_increment"`
+	BaseMinSize       jsonDecimal `json:"base_min_size"`
+	BaseMaxSize       jsonDecimal `json:"base_max_size"`
+	PriceMaxPrecision int         `json:"price_max_precision"`
+	MinBuyAmount      jsonDecimal `json:"min_buy_amount"`
+	TradeStatus       string      `json:"trade_status"`
+}
+
+func (b *BitMart) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures instruments")
+	}
+
+	return b.instruments.get(ctx, b.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp bitMartResponse[struct {
+			Symbols []bitMartSymbol `json:"symbols"`
+		}]
+		if err := sendRequest(ctx, b, "GET", "/spot/v1/symbols/details", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Data.Symbols))
+		for _, s := range resp.Data.Symbols {
+			status := types.InstrumentHalted
+			switch s.TradeStatus {
+			case "trading":
+				status = types.InstrumentTrading
+			case "pre-trade":
+				status = types.InstrumentPreTrading
+			}
+
+			instruments = append(instruments, types.Instrument{
+				Symbol:      s.Symbol,
+				Base:        s.BaseCurrency,
+				Quote:       s.QuoteCurrency,
+				TickSize:    precisionStep(s.PriceMaxPrecision),
+				StepSize:    s.QuoteIncrement.Decimal,
+				MinQty:      s.BaseMinSize.Decimal,
+				MaxQty:      s.BaseMaxSize.Decimal,
+				MinNotional: s.MinBuyAmount.Decimal,
+				Status:      status,
+			})
+		}
+		return instruments, nil
+	})
+}
+
+func (b *BitMart) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures ticker")
+	}
+
+	var resp bitMartResponse[[]bitMartTickerRow]
+	if err := sendRequest(ctx, b, "GET", "/spot/quotation/v3/tickers", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Data))
+	for _, row := range resp.Data {
+		tickers = append(tickers, bitMartTicker(row).toTicker())
+	}
+	return keyTickers(ctx, b, tickers)
+}
@@ -0,0 +1,35 @@
+package exchanges
+
+import (
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitMart_Sign(t *testing.T) {
+	bitMart := NewBitMart(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+		APIMemo:   "test_memo",
+	})
+
+	assert.Equal(t, "a0826d52bb55be3d7bdd3a8b663ed435ed29047025836644b96455b9c31db0b7",
+		bitMart.sign("1589793795969", `{"size":"0.1","symbol":"BTC_USDT"}`))
+}
+
+func TestBitMart_PrepareRequest(t *testing.T) {
+	bitMart := NewBitMart(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+		APIMemo:   "test_memo",
+	})
+
+	req, err := bitMart.PrepareRequest("GET", "/spot/v1/wallet", map[string]interface{}{"currency": "USDT"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_key", req.Header.Get("X-BM-KEY"))
+
+	timestamp := req.Header.Get("X-BM-TIMESTAMP")
+	assert.NotEmpty(t, timestamp)
+	assert.Equal(t, bitMart.sign(timestamp, "currency=USDT"), req.Header.Get("X-BM-SIGN"))
+}
@@ -0,0 +1,299 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// orderParams 把统一的下单请求转换为现货 v2 下单参数。post-only、IOC 分别使用 limit_maker、ioc 类型，BitMart 没有 FOK；
+// 市价买单只能按计价币金额 notional 下单，市价卖单按基础币数量下单。合约交易暂不支持
+func (b *BitMart) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(b, "reduce-only spot orders")
+	}
+	if req.Type.Conditional() {
+		return nil, notSupported(b, "conditional orders")
+	}
+
+	params := map[string]interface{}{
+		"symbol":          req.Symbol,
+		"side":            lowerSide(req.Side),
+		"type":            "limit",
+		"client_order_id": req.ClientOrderID,
+	}
+	if req.Type == types.LimitOrder {
+		switch {
+		case req.PostOnly:
+			params["type"] = "limit_maker"
+		case req.TimeInForce == types.IOC:
+			params["type"] = "ioc"
+		case req.TimeInForce == types.FOK:
+			return nil, notSupported(b, "fill-or-kill orders")
+		}
+		params["size"] = req.Quantity.String()
+		params["price"] = req.Price.String()
+		return params, nil
+	}
+
+	params["type"] = "market"
+	switch {
+	case req.Side == types.Buy && !req.QuoteQuantity.IsPositive():
+		return nil, notSupported(b, "market buys by base quantity")
+	case req.Side == types.Sell && req.QuoteQuantity.IsPositive():
+		return nil, notSupported(b, "market sells by quote quantity")
+	case req.Side == types.Buy:
+		params["notional"] = req.QuoteQuantity.String()
+	default:
+		params["size"] = req.Quantity.String()
+	}
+	return params, nil
+}
+
+func (b *BitMart) placer() placer {
+	return placer{t: b, config: b.config, idLength: clientOrderIDLength, place: b.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为现货交易对，例如 BTC_USDT
+func (b *BitMart) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return b.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单，BitMart 的批量下单接口只接受同一交易对的订单
+func (b *BitMart) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	return b.placer().placeOrders(ctx, reqs), nil
+}
+
+func (b *BitMart) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := b.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp bitMartResponse[struct {
+		OrderID string `json:"order_id"`
+	}]
+	if err := sendRequest(ctx, b, "POST", "/spot/v2/submit_order", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, resp.Data.OrderID), nil
+}
+
+func (b *BitMart) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, b, req)
+}
+
+// bitMartOrderNotFound 是订单不存在时的错误码
+const bitMartOrderNotFound = "50005"
+
+// bitMartPageSize 是 v4 订单列表每页的最大数量
+const bitMartPageSize = 200
+
+// bitMartOrder 是 v4 订单接口的订单。type 中 limit_maker、ioc 分别表示 post-only 与 IOC 限价单，
+// 市价买单用 notional 表示计价币金额
+type bitMartOrder struct {
+	OrderID        string      `json:"orderId"`
+	ClientOrderID  string      `json:"clientOrderId"`
+	Symbol         string      `json:"symbol"`
+	Side           string      `json:"side"`
+	Type           string      `json:"type"`
+	State          string      `json:"state"`
+	Price          jsonDecimal `json:"price"`
+	PriceAvg       jsonDecimal `json:"priceAvg"`
+	Size           jsonDecimal `json:"size"`
+	Notional       jsonDecimal `json:"notional"`
+	FilledSize     jsonDecimal `json:"filledSize"`
+	FilledNotional jsonDecimal `json:"filledNotional"`
+	CreateTime     int64       `json:"createTime"`
+	UpdateTime     int64       `json:"updateTime"`
+}
+
+var bitMartStatuses = map[string]types.OrderStatus{
+	"new":                types.OrderNew,
+	"partially_filled":   types.OrderPartiallyFilled,
+	"filled":             types.OrderFilled,
+	"canceled":           types.OrderCanceled,
+	"partially_canceled": types.OrderCanceled,
+}
+
+func (o bitMartOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClientOrderID,
+		Symbol:         o.Symbol,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.GTC,
+		Price:          o.Price.Decimal,
+		Quantity:       o.Size.Decimal,
+		Status:         orderStatus(bitMartStatuses, o.State),
+		FilledQuantity: o.FilledSize.Decimal,
+		AveragePrice:   o.PriceAvg.Decimal,
+		Raw:            o.State,
+		CreatedAt:      msToTime(o.CreateTime),
+		UpdatedAt:      msToTime(o.UpdateTime),
+	}
+	switch o.Type {
+	case "ioc":
+		order.TimeInForce = types.IOC
+	case "market":
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+		if order.Side == types.Buy {
+			order.QuoteQuantity, order.Quantity = o.Notional.Decimal, decimal.Zero
+		}
+	}
+	return order
+}
+
+func (b *BitMart) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if b.config.MarketOrSpot() != types.Spot {
+		return notSupported(b, "futures orders")
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+	if ref.ID != "" {
+		params["order_id"] = ref.ID
+	} else {
+		params["client_order_id"] = ref.ClientOrderID
+	}
+	var resp bitMartResponse[struct {
+		Result bool `json:"result"`
+	}]
+	if err := sendRequest(ctx, b, "POST", "/spot/v3/cancel_order", params, true, &resp); err != nil {
+		return orderNotFound(err, bitMartOrderNotFound)
+	}
+	// result 为 false 表示订单已成交或已撤销，无法再撤销
+	if !resp.Data.Result {
+		return fmt.Errorf("bitmart order %s is already closed", ref.ID+ref.ClientOrderID)
+	}
+	return nil
+}
+
+// CancelOrders 并发地逐个撤单
+func (b *BitMart) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	return cancelOrders(ctx, symbol, refs, 0, nil, b.CancelOrder), nil
+}
+
+// CancelAllOrders 调用 /spot/v4/cancel_all，它不返回被撤销的订单，结果取自撤销前的挂单快照
+func (b *BitMart) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	orders, err := b.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+	var resp bitMartResponse[json.RawMessage]
+	if err := sendRequest(ctx, b, "POST", "/spot/v4/cancel_all", params, true, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]types.CancelResult, 0, len(orders))
+	for _, order := range orders {
+		results = append(results, cancelResult(order, nil))
+	}
+	return results, nil
+}
+
+// listOrders 调用 v4 的订单列表接口，params 为空时查询全部现货交易对
+func (b *BitMart) listOrders(ctx context.Context, endpoint string, params map[string]interface{}) ([]types.Order, error) {
+	params["orderMode"] = "spot"
+	params["limit"] = bitMartPageSize
+
+	var resp bitMartResponse[[]bitMartOrder]
+	if err := sendRequest(ctx, b, "POST", endpoint, params, true, &resp); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(resp.Data))
+	for i, row := range resp.Data {
+		orders[i] = row.toOrder()
+	}
+	return orders, nil
+}
+
+func (b *BitMart) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+	return b.listOrders(ctx, "/spot/v4/query/open-orders", params)
+}
+
+// GetOrder 按订单号查询 /spot/v4/query/order，按自定义订单号查询 /spot/v4/query/client-order
+func (b *BitMart) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(b, "futures orders")
+	}
+
+	endpoint, params := "/spot/v4/query/client-order", map[string]interface{}{"clientOrderId": ref.ClientOrderID}
+	if ref.ID != "" {
+		endpoint, params = "/spot/v4/query/order", map[string]interface{}{"orderId": ref.ID}
+	}
+	var resp bitMartResponse[bitMartOrder]
+	if err := sendRequest(ctx, b, "POST", endpoint, params, true, &resp); err != nil {
+		return types.Order{}, orderNotFound(err, bitMartOrderNotFound)
+	}
+	if resp.Data.OrderID == "" {
+		return types.Order{}, fmt.Errorf("bitmart order %s: %w", ref.ID+ref.ClientOrderID, types.ErrOrderNotFound)
+	}
+	return resp.Data.toOrder(), nil
+}
+
+func (b *BitMart) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return b.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 /spot/v4/query/history-orders，按 endTime 从新到旧翻页
+func (b *BitMart) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+
+	params := map[string]interface{}{}
+	if req.Symbol != "" {
+		params["symbol"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["startTime"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["endTime"] = req.End.UnixMilli()
+	}
+
+	var orders []types.Order
+	for req.Limit <= 0 || len(orders) < req.Limit {
+		page, err := b.listOrders(ctx, "/spot/v4/query/history-orders", params)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page...)
+		if len(page) < bitMartPageSize {
+			break
+		}
+		params["endTime"] = page[len(page)-1].CreatedAt.UnixMilli() - 1
+	}
+	return orderHistory(orders, req), nil
+}
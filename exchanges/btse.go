@@ -15,7 +15,9 @@ import (
 )
 
 type BTSE struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewBTSE(config types.ExchangeConfig) *BTSE {
@@ -27,6 +29,9 @@ func (b *BTSE) Name() types.ExchangeName {
 }
 
 func (b *BTSE) GetDefaultBaseURL() string {
+	if b.config.MarketOrSpot() != types.Spot {
+		return "https://api.btse.com/futures"
+	}
 	return "https://api.btse.com/spot"
 }
 
@@ -46,7 +51,7 @@ func (b *BTSE) PrepareRequest(method, endpoint string, params map[string]interfa
 	}
 
 	var bodyStr string
-	if method == "GET" && params != nil {
+	if (method == "GET" || method == "DELETE") && params != nil {
 		q := u.Query()
 		for k, v := range params {
 			q.Set(k, fmt.Sprint(v))
@@ -0,0 +1,309 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// btseMarketSummary 中 size 为 24 小时基础币成交量，volume 为计价币成交额
+type btseMarketSummary struct {
+	Symbol     string      `json:"symbol"`
+	Last       jsonDecimal `json:"last"`
+	LowestAsk  jsonDecimal `json:"lowestAsk"`
+	HighestBid jsonDecimal `json:"highestBid"`
+	Volume     jsonDecimal `json:"volume"`
+	Size       jsonDecimal `json:"size"`
+
+	Base              string      `json:"base"`
+	Quote             string      `json:"quote"`
+	Active            bool        `json:"active"`
+	MinPriceIncrement jsonDecimal `json:"minPriceIncrement"`
+	MinSizeIncrement  jsonDecimal `json:"minSizeIncrement"`
+	MinOrderSize      jsonDecimal `json:"minOrderSize"`
+	MaxOrderSize      jsonDecimal `json:"maxOrderSize"`
+	ContractSize      jsonDecimal `json:"contractSize"`
+	FundingRate       jsonDecimal `json:"fundingRate"`
+}
+
+// apiVersion 返回接口路径中的版本，现货与合约的版本号不同
+func (b *BTSE) apiVersion() string {
+	if b.config.MarketOrSpot() != types.Spot {
+		return "/api/v2.1"
+	}
+	return "/api/v3.2"
+}
+
+func (b *BTSE) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var summaries []btseMarketSummary
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/market_summary", map[string]interface{}{"symbol": symbol}, false, &summaries); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(summaries) == 0 {
+		return types.Ticker{}, fmt.Errorf("btse ticker %s not found", symbol)
+	}
+
+	return summaries[0].toTicker(), nil
+}
+
+func (t btseMarketSummary) toTicker() types.Ticker {
+	return types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.Last.Decimal,
+		Bid:         t.HighestBid.Decimal,
+		Ask:         t.LowestAsk.Decimal,
+		Volume:      t.Size.Decimal,
+		QuoteVolume: t.Volume.Decimal,
+	}
+}
+
+type btseQuote struct {
+	Price jsonDecimal `json:"price"`
+	Size  jsonDecimal `json:"size"`
+}
+
+type btseOrderBook struct {
+	BuyQuote  []btseQuote `json:"buyQuote"`
+	SellQuote []btseQuote `json:"sellQuote"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+func btseLevels(quotes []btseQuote) []types.PriceLevel {
+	levels := make([]types.PriceLevel, 0, len(quotes))
+	for _, q := range quotes {
+		levels = append(levels, types.PriceLevel{Price: q.Price.Decimal, Size: q.Size.Decimal})
+	}
+	return levels
+}
+
+// GetOrderBook 仅支持现货，合约订单簿的数量单位是合约张数
+func (b *BTSE) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.OrderBook{}, notSupported(b, "futures order book")
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+	if depth > 0 {
+		params["depth"] = depth
+	}
+
+	var book btseOrderBook
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/orderbook/L2", params, false, &book); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      btseLevels(book.BuyQuote),
+		Asks:      btseLevels(book.SellQuote),
+		Timestamp: msToTime(book.Timestamp),
+	}, depth), nil
+}
+
+// BTSE 的 43200 分钟不是自然月，因此不映射 1M
+var btseIntervals = map[types.Interval]string{
+	types.Interval1m: "1", types.Interval5m: "5", types.Interval15m: "15", types.Interval30m: "30",
+	types.Interval1h: "60", types.Interval4h: "240", types.Interval6h: "360", types.Interval1d: "1440",
+	types.Interval1w: "10080",
+}
+
+// GetCandles 中 BTSE 返回 [time(秒), open, high, low, close, volume]，不提供成交额
+func (b *BTSE) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures candles")
+	}
+
+	return fetchCandles(ctx, b, btseIntervals, b.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol":     symbol,
+				"resolution": venueInterval,
+			}
+			if !start.IsZero() {
+				params["start"] = start.Unix()
+			}
+			if !end.IsZero() {
+				params["end"] = end.Unix()
+			}
+
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/ohlcv", params, false, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, row := range rows {
+				if len(row) < 6 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, unixTime(row[0], time.Second), 1, 2, 3, 4, 5, -1))
+			}
+			return candles, nil
+		})
+}
+
+type btseTrade struct {
+	SerialID  int64       `json:"serialId"`
+	Price     jsonDecimal `json:"price"`
+	Size      jsonDecimal `json:"size"`
+	Side      string      `json:"side"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// GetRecentTrades 仅支持现货，单次最多返回 500 笔
+func (b *BTSE) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures trades")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"count":  clampDepth(limit, 500),
+	}
+
+	var rows []btseTrade
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/trades", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, types.Trade{
+			ID:    strconv.FormatInt(t.SerialID, 10),
+			Price: t.Price.Decimal,
+			Size:  t.Size.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  msToTime(t.Timestamp),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// GetInstruments 使用不带 symbol 的 market_summary，合约的数量单位为张
+func (b *BTSE) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return b.instruments.get(ctx, b.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var summaries []btseMarketSummary
+		if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/market_summary", nil, false, &summaries); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(summaries))
+		for _, s := range summaries {
+			inst := types.Instrument{
+				Symbol:   s.Symbol,
+				Base:     s.Base,
+				Quote:    s.Quote,
+				TickSize: s.MinPriceIncrement.Decimal,
+				StepSize: s.MinSizeIncrement.Decimal,
+				MinQty:   s.MinOrderSize.Decimal,
+				MaxQty:   s.MaxOrderSize.Decimal,
+				Status:   tradingStatus(s.Active),
+			}
+			if b.config.MarketOrSpot() != types.Spot {
+				inst.ContractSize = s.ContractSize.Decimal
+			}
+			instruments = append(instruments, inst)
+		}
+		return instruments, nil
+	})
+}
+
+// GetFundingRate 的 symbol 为 BTSE 永续合约代码，例如 BTCPFC。
+// BTSE 不返回下次结算时间和结算间隔，NextFundingTime 与 Interval 为零值
+func (b *BTSE) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	var summaries []btseMarketSummary
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/market_summary", map[string]interface{}{"symbol": symbol}, false, &summaries); err != nil {
+		return types.FundingRate{}, err
+	}
+	if len(summaries) == 0 {
+		return types.FundingRate{}, fmt.Errorf("btse funding rate %s not found", symbol)
+	}
+
+	return types.FundingRate{Symbol: summaries[0].Symbol, Rate: summaries[0].FundingRate.Decimal}, nil
+}
+
+// GetFundingHistory 中 BTSE 只支持指定返回数量，时间范围在本地过滤
+func (b *BTSE) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+	if limit > 0 {
+		params["count"] = limit
+	}
+
+	var resp map[string][]struct {
+		Time   int64       `json:"time"`
+		Rate   jsonDecimal `json:"rate"`
+		Symbol string      `json:"symbol"`
+	}
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/funding_history", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	var records []types.FundingRecord
+	for _, rows := range resp {
+		for _, r := range rows {
+			records = append(records, types.FundingRecord{Symbol: r.Symbol, Rate: r.Rate.Decimal, Time: time.Unix(r.Time, 0)})
+		}
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+type btsePrice struct {
+	Symbol     string      `json:"symbol"`
+	MarkPrice  jsonDecimal `json:"markPrice"`
+	IndexPrice jsonDecimal `json:"indexPrice"`
+}
+
+func (b *BTSE) price(ctx context.Context, symbol string) (btsePrice, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return btsePrice{}, err
+	}
+
+	var prices []btsePrice
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/price", map[string]interface{}{"symbol": symbol}, false, &prices); err != nil {
+		return btsePrice{}, err
+	}
+	if len(prices) == 0 {
+		return btsePrice{}, fmt.Errorf("btse price %s not found", symbol)
+	}
+	return prices[0], nil
+}
+
+func (b *BTSE) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.price(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.MarkPrice.Decimal}, nil
+}
+
+func (b *BTSE) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := b.price(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.IndexPrice.Decimal}, nil
+}
+
+func (b *BTSE) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	var summaries []btseMarketSummary
+	if err := sendRequest(ctx, b, "GET", b.apiVersion()+"/market_summary", nil, false, &summaries); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(summaries))
+	for _, t := range summaries {
+		tickers = append(tickers, t.toTicker())
+	}
+	return keyTickers(ctx, b, tickers)
+}
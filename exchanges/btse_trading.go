@@ -0,0 +1,302 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// BTSE 的订单状态码，下单、撤单和订单查询接口共用
+const (
+	btseOrderInserted  = 2
+	btseOrderFilled    = 4
+	btseOrderPartial   = 5
+	btseOrderCancelled = 6
+	btseOrderNotFound  = 16
+)
+
+// btseOrderAck 是下单、撤单接口中每个订单的处理结果，status 是订单状态码，失败时 message 是原因
+type btseOrderAck struct {
+	Status    int    `json:"status"`
+	Symbol    string `json:"symbol"`
+	OrderID   string `json:"orderID"`
+	ClOrderID string `json:"clOrderID"`
+	Message   string `json:"message"`
+}
+
+// err 在状态码不属于 accepted 时返回该订单的错误
+func (a btseOrderAck) err(accepted ...int) error {
+	for _, status := range accepted {
+		if a.Status == status {
+			return nil
+		}
+	}
+	return batchError(strconv.Itoa(a.Status), a.Message)
+}
+
+// orderParams 把统一的下单请求转换为现货 v3.2 参数，只支持按基础币数量下单；合约交易暂不支持
+func (b *BTSE) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(b, "reduce-only spot orders")
+	}
+	if req.Type.Conditional() {
+		return nil, notSupported(b, "conditional orders")
+	}
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(b, "orders by quote quantity")
+	}
+
+	params := map[string]interface{}{
+		"symbol":    req.Symbol,
+		"side":      string(req.Side),
+		"type":      string(req.Type),
+		"txType":    "LIMIT",
+		"size":      req.Quantity.String(),
+		"clOrderID": req.ClientOrderID,
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		params["time_in_force"] = string(timeInForce(req))
+		params["postOnly"] = req.PostOnly
+	}
+	return params, nil
+}
+
+func (b *BTSE) placer() placer {
+	return placer{t: b, config: b.config, idLength: clientOrderIDLength, place: b.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为现货交易对，例如 BTC-USDT
+func (b *BTSE) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return b.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单
+func (b *BTSE) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	return b.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeOrder 中 BTSE 以 HTTP 200 返回被拒绝的订单，需要检查状态码
+func (b *BTSE) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := b.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var acks []btseOrderAck
+	if err := sendRequest(ctx, b, "POST", "/api/v3.2/order", params, true, &acks); err != nil {
+		return types.Order{}, err
+	}
+	if len(acks) == 0 {
+		return types.Order{}, fmt.Errorf("btse returned no order result")
+	}
+	if err := acks[0].err(btseOrderInserted, btseOrderFilled, btseOrderPartial); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, acks[0].OrderID), nil
+}
+
+func (b *BTSE) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, b, req)
+}
+
+// cancelErr 把撤单结果转换为错误，状态码 16 表示订单不存在
+func (a btseOrderAck) cancelErr() error {
+	if a.Status == btseOrderNotFound {
+		return fmt.Errorf("%w: %w", types.ErrOrderNotFound, a.err())
+	}
+	return a.err(btseOrderCancelled)
+}
+
+// btseRef 返回按订单号或自定义订单号指定订单的参数
+func btseRef(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"symbol": symbol}
+	if ref.ID != "" {
+		params["orderID"] = ref.ID
+	} else {
+		params["clOrderID"] = ref.ClientOrderID
+	}
+	return params
+}
+
+// cancel 调用 DELETE /api/v3.2/order，params 不指定订单时撤销该交易对的全部挂单
+func (b *BTSE) cancel(ctx context.Context, params map[string]interface{}) ([]btseOrderAck, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	var acks []btseOrderAck
+	if err := sendRequest(ctx, b, "DELETE", "/api/v3.2/order", params, true, &acks); err != nil {
+		return nil, err
+	}
+	return acks, nil
+}
+
+func (b *BTSE) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	acks, err := b.cancel(ctx, btseRef(symbol, ref))
+	if err != nil {
+		return err
+	}
+	if len(acks) == 0 {
+		return fmt.Errorf("btse returned no cancel result")
+	}
+	return acks[0].cancelErr()
+}
+
+// CancelOrders 没有批量撤单接口，并发地逐个撤单
+func (b *BTSE) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	return cancelOrders(ctx, symbol, refs, 0, nil, b.CancelOrder), nil
+}
+
+// CancelAllOrders 不指定订单号时 DELETE /api/v3.2/order 撤销一个交易对的全部挂单；
+// 未指定交易对时先列出挂单，再对涉及的每个交易对调用一次
+func (b *BTSE) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if symbol != "" {
+		return b.cancelSymbol(ctx, symbol)
+	}
+
+	orders, err := b.openOrders(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return cancelBySymbol(ctx, orders, b.cancelSymbol), nil
+}
+
+func (b *BTSE) cancelSymbol(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	acks, err := b.cancel(ctx, map[string]interface{}{"symbol": symbol})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]types.CancelResult, len(acks))
+	for i, ack := range acks {
+		results[i] = types.CancelResult{Symbol: symbol, OrderID: ack.OrderID, ClientOrderID: ack.ClOrderID, Err: ack.cancelErr()}
+	}
+	return results, nil
+}
+
+// btseOrder 是订单查询与挂单接口的订单。orderType 76 是限价单、77 是市价单；
+// 订单查询接口用 status 状态码表示状态，挂单接口没有 status
+type btseOrder struct {
+	OrderID      string      `json:"orderID"`
+	ClOrderID    string      `json:"clOrderID"`
+	Symbol       string      `json:"symbol"`
+	OrderType    int         `json:"orderType"`
+	Side         string      `json:"side"`
+	Price        jsonDecimal `json:"price"`
+	Size         jsonDecimal `json:"size"`
+	FilledSize   jsonDecimal `json:"filledSize"`
+	AvgFillPrice jsonDecimal `json:"avgFillPrice"`
+	Status       int         `json:"status"`
+	TimeInForce  string      `json:"timeInForce"`
+	Timestamp    int64       `json:"timestamp"`
+}
+
+// btseMarketOrder 是市价单的 orderType
+const btseMarketOrder = 77
+
+var btseStatuses = map[int]types.OrderStatus{
+	btseOrderInserted:  types.OrderNew,
+	btseOrderFilled:    types.OrderFilled,
+	btseOrderPartial:   types.OrderPartiallyFilled,
+	btseOrderCancelled: types.OrderCanceled,
+	8:                  types.OrderRejected,
+	15:                 types.OrderRejected,
+}
+
+func (o btseOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClOrderID,
+		Symbol:         o.Symbol,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.TimeInForce(o.TimeInForce),
+		Price:          o.Price.Decimal,
+		Quantity:       o.Size.Decimal,
+		Status:         openStatus(o.FilledSize.Decimal),
+		FilledQuantity: o.FilledSize.Decimal,
+		AveragePrice:   o.AvgFillPrice.Decimal,
+		CreatedAt:      msToTime(o.Timestamp),
+	}
+	if o.Status != 0 {
+		order.Raw = strconv.Itoa(o.Status)
+		order.Status = types.OrderStatusUnknown
+		if status, ok := btseStatuses[o.Status]; ok {
+			order.Status = status
+		}
+	}
+	if order.TimeInForce == "" {
+		order.TimeInForce = types.GTC
+	}
+	if o.OrderType == btseMarketOrder {
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+	}
+	return order
+}
+
+func (b *BTSE) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if b.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(b, "futures orders")
+	}
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	var rows []btseOrder
+	if err := sendRequest(ctx, b, "GET", "/api/v3.2/user/open_orders", params, true, &rows); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(rows))
+	for i, row := range rows {
+		orders[i] = row.toOrder()
+	}
+	return orders, nil
+}
+
+// GetOrder 查询 GET /api/v3.2/order，找不到订单时返回空数组
+func (b *BTSE) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if b.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(b, "futures orders")
+	}
+
+	params := btseRef(symbol, ref)
+	delete(params, "symbol")
+	var raw json.RawMessage
+	if err := sendRequest(ctx, b, "GET", "/api/v3.2/order", params, true, &raw); err != nil {
+		return types.Order{}, err
+	}
+	var order btseOrder
+	if err := unmarshalFirst(raw, &order); err != nil || order.OrderID == "" {
+		return types.Order{}, fmt.Errorf("btse order %s: %w", ref.ID+ref.ClientOrderID, types.ErrOrderNotFound)
+	}
+	return order.toOrder(), nil
+}
+
+func (b *BTSE) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return b.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 不受支持：BTSE 只提供成交记录 trade_history，没有查询已结束订单的接口，
+// 单个订单仍可以通过 GetOrder 查询
+func (b *BTSE) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	return nil, notSupported(b, "order history")
+}
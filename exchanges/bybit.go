@@ -4,7 +4,9 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"sort"
@@ -16,7 +18,9 @@ import (
 )
 
 type Bybit struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewBybit(config types.ExchangeConfig) *Bybit {
@@ -42,6 +46,10 @@ func (b *Bybit) PrepareRequest(method, endpoint string, params map[string]interf
 		return nil, err
 	}
 
+	if strings.HasPrefix(endpoint, "/v5/") {
+		return b.prepareV5Request(method, u, params, signed)
+	}
+
 	if params == nil {
 		params = make(map[string]interface{})
 	}
@@ -77,6 +85,53 @@ func (b *Bybit) PrepareRequest(method, endpoint string, params map[string]interf
 	return req, nil
 }
 
+// bybitRecvWindow 是 v5 签名请求的有效时间（毫秒）
+const bybitRecvWindow = "5000"
+
+// prepareV5Request 处理 v5 接口：GET 参数放在查询字符串，POST 使用 JSON 请求体；
+// 签名放在请求头中，X-BAPI-SIGN = HMAC-SHA256(timestamp + apiKey + recvWindow + 查询字符串或请求体)
+func (b *Bybit) prepareV5Request(method string, u *url.URL, params map[string]interface{}, signed bool) (*http.Request, error) {
+	var payload string
+	if method == "GET" || method == "DELETE" {
+		u.RawQuery = b.buildQueryString(params)
+		payload = u.RawQuery
+	} else {
+		if params == nil {
+			params = make(map[string]interface{})
+		}
+		body, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		payload = string(body)
+	}
+
+	var body io.Reader
+	if method != "GET" && method != "DELETE" {
+		body = strings.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(b.config.APISecret))
+		mac.Write([]byte(timestamp + b.config.APIKey + bybitRecvWindow + payload))
+
+		req.Header.Set("X-BAPI-API-KEY", b.config.APIKey)
+		req.Header.Set("X-BAPI-TIMESTAMP", timestamp)
+		req.Header.Set("X-BAPI-RECV-WINDOW", bybitRecvWindow)
+		req.Header.Set("X-BAPI-SIGN", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
 func (b *Bybit) generateSignature(params map[string]interface{}) string {
 	keys := make([]string, 0, len(params))
 	for k := range params {
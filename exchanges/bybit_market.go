@@ -0,0 +1,421 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type bybitResponse[T any] struct {
+	RetCode int    `json:"retCode"`
+	RetMsg  string `json:"retMsg"`
+	Result  T      `json:"result"`
+	Time    int64  `json:"time"`
+}
+
+func (r *bybitResponse[T]) apiError() error {
+	if r.RetCode != 0 {
+		return &types.APIError{StatusCode: http.StatusOK, Code: strconv.Itoa(r.RetCode), Message: r.RetMsg}
+	}
+	return nil
+}
+
+type bybitList[T any] struct {
+	Category       string `json:"category"`
+	List           []T    `json:"list"`
+	NextPageCursor string `json:"nextPageCursor"`
+}
+
+type bybitTicker struct {
+	Symbol      string      `json:"symbol"`
+	LastPrice   jsonDecimal `json:"lastPrice"`
+	Bid1Price   jsonDecimal `json:"bid1Price"`
+	Ask1Price   jsonDecimal `json:"ask1Price"`
+	Volume24h   jsonDecimal `json:"volume24h"`
+	Turnover24h jsonDecimal `json:"turnover24h"`
+}
+
+// category 返回 v5 接口的 category 参数
+func (b *Bybit) category() string {
+	switch b.config.MarketOrSpot() {
+	case types.USDMFutures:
+		return "linear"
+	case types.CoinMFutures:
+		return "inverse"
+	case types.Options:
+		return "option"
+	default:
+		return "spot"
+	}
+}
+
+func (b *Bybit) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+	}
+
+	var resp bybitResponse[bybitList[bybitTicker]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/tickers", params, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(resp.Result.List) == 0 {
+		return types.Ticker{}, fmt.Errorf("bybit ticker %s not found", symbol)
+	}
+
+	return resp.Result.List[0].toTicker(b.category(), resp.Time), nil
+}
+
+func (t bybitTicker) toTicker(category string, ts int64) types.Ticker {
+	ticker := types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.LastPrice.Decimal,
+		Bid:         t.Bid1Price.Decimal,
+		Ask:         t.Ask1Price.Decimal,
+		Volume:      t.Volume24h.Decimal,
+		QuoteVolume: t.Turnover24h.Decimal,
+		Timestamp:   msToTime(ts),
+	}
+
+	// 反向合约的 volume24h 以美元张数计，turnover24h 以币计
+	if category == "inverse" {
+		ticker.Volume, ticker.QuoteVolume = t.Turnover24h.Decimal, t.Volume24h.Decimal
+	}
+	return ticker
+}
+
+type bybitOrderBook struct {
+	S   string          `json:"s"`
+	B   [][]jsonDecimal `json:"b"`
+	A   [][]jsonDecimal `json:"a"`
+	Ts  int64           `json:"ts"`
+	U   int64           `json:"u"`
+	Seq int64           `json:"seq"`
+}
+
+func (b *Bybit) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	maxDepth := 500
+	switch b.category() {
+	case "spot":
+		maxDepth = 200
+	case "option":
+		maxDepth = 25
+	}
+
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+		"limit":    clampDepth(depth, maxDepth),
+	}
+
+	var resp bybitResponse[bybitOrderBook]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/orderbook", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    resp.Result.S,
+		Bids:      levelsFromRows(resp.Result.B),
+		Asks:      levelsFromRows(resp.Result.A),
+		Sequence:  resp.Result.U,
+		Timestamp: msToTime(resp.Result.Ts),
+	}, depth), nil
+}
+
+var bybitIntervals = map[types.Interval]string{
+	types.Interval1m: "1", types.Interval3m: "3", types.Interval5m: "5", types.Interval15m: "15",
+	types.Interval30m: "30", types.Interval1h: "60", types.Interval2h: "120", types.Interval4h: "240",
+	types.Interval6h: "360", types.Interval12h: "720", types.Interval1d: "D", types.Interval1w: "W",
+	types.Interval1M: "M",
+}
+
+// GetCandles 中 Bybit 返回 [startTime, open, high, low, close, volume, turnover]，单次最多 1000 根
+func (b *Bybit) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, b, bybitIntervals, b.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"category": b.category(),
+				"symbol":   symbol,
+				"interval": venueInterval,
+			}
+			if !start.IsZero() {
+				params["start"] = start.UnixMilli()
+			}
+			if !end.IsZero() {
+				params["end"] = end.UnixMilli()
+			}
+			if limit > 0 {
+				params["limit"] = clampDepth(limit, 1000)
+			}
+
+			var resp bybitResponse[bybitList[[]jsonDecimal]]
+			if err := sendRequest(ctx, b, "GET", "/v5/market/kline", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			// 反向合约的 volume 为美元张数，turnover 为币数量
+			volumeIndex, quoteIndex := 5, 6
+			if b.category() == "inverse" {
+				volumeIndex, quoteIndex = 6, 5
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Result.List))
+			for _, row := range resp.Result.List {
+				if len(row) < 7 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, msToTime(row[0].IntPart()), 1, 2, 3, 4, volumeIndex, quoteIndex))
+			}
+			return candles, nil
+		})
+}
+
+type bybitTrade struct {
+	ExecID string      `json:"execId"`
+	Price  jsonDecimal `json:"price"`
+	Size   jsonDecimal `json:"size"`
+	Side   string      `json:"side"`
+	Time   jsonInt     `json:"time"`
+}
+
+// GetRecentTrades 中现货最多返回 60 笔，期权 100 笔，其他合约 1000 笔
+func (b *Bybit) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	maxLimit := 1000
+	switch b.category() {
+	case "spot":
+		maxLimit = 60
+	case "option":
+		maxLimit = 100
+	}
+
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+		"limit":    clampDepth(limit, maxLimit),
+	}
+
+	var resp bybitResponse[bybitList[bybitTrade]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/recent-trade", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Result.List))
+	for _, t := range resp.Result.List {
+		trades = append(trades, types.Trade{
+			ID:    t.ExecID,
+			Price: t.Price.Decimal,
+			Size:  t.Size.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  msToTime(int64(t.Time)),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// bybitInstrument 中现货的数量步长为 basePrecision、最小金额为 minOrderAmt，合约为 qtyStep、minNotionalValue
+type bybitInstrument struct {
+	Symbol        string `json:"symbol"`
+	BaseCoin      string `json:"baseCoin"`
+	QuoteCoin     string `json:"quoteCoin"`
+	Status        string `json:"status"`
+	LotSizeFilter struct {
+		BasePrecision    jsonDecimal `json:"basePrecision"`
+		QtyStep          jsonDecimal `json:"qtyStep"`
+		MinOrderQty      jsonDecimal `json:"minOrderQty"`
+		MaxOrderQty      jsonDecimal `json:"maxOrderQty"`
+		MinOrderAmt      jsonDecimal `json:"minOrderAmt"`
+		MinNotionalValue jsonDecimal `json:"minNotionalValue"`
+	} `json:"lotSizeFilter"`
+	PriceFilter struct {
+		TickSize jsonDecimal `json:"tickSize"`
+	} `json:"priceFilter"`
+}
+
+func bybitStatus(status string) types.InstrumentStatus {
+	switch status {
+	case "Trading":
+		return types.InstrumentTrading
+	case "PreLaunch":
+		return types.InstrumentPreTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+// GetInstruments 按 nextPageCursor 翻页取得全部交易对
+func (b *Bybit) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return b.instruments.get(ctx, b.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		params := map[string]interface{}{
+			"category": b.category(),
+			"limit":    1000,
+		}
+
+		var instruments []types.Instrument
+		for {
+			var resp bybitResponse[bybitList[bybitInstrument]]
+			if err := sendRequest(ctx, b, "GET", "/v5/market/instruments-info", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			for _, i := range resp.Result.List {
+				lot := i.LotSizeFilter
+				inst := types.Instrument{
+					Symbol:      i.Symbol,
+					Base:        i.BaseCoin,
+					Quote:       i.QuoteCoin,
+					TickSize:    i.PriceFilter.TickSize.Decimal,
+					StepSize:    lot.QtyStep.Decimal,
+					MinQty:      lot.MinOrderQty.Decimal,
+					MaxQty:      lot.MaxOrderQty.Decimal,
+					MinNotional: lot.MinNotionalValue.Decimal,
+					Status:      bybitStatus(i.Status),
+				}
+				if b.category() == "spot" {
+					inst.StepSize = lot.BasePrecision.Decimal
+					inst.MinNotional = lot.MinOrderAmt.Decimal
+				} else {
+					inst.ContractSize = decimal.NewFromInt(1)
+				}
+				instruments = append(instruments, inst)
+			}
+
+			if resp.Result.NextPageCursor == "" {
+				return instruments, nil
+			}
+			params["cursor"] = resp.Result.NextPageCursor
+		}
+	})
+}
+
+// bybitDerivTicker 是合约 tickers 中与资金费率和参考价格相关的字段
+type bybitDerivTicker struct {
+	Symbol          string      `json:"symbol"`
+	MarkPrice       jsonDecimal `json:"markPrice"`
+	IndexPrice      jsonDecimal `json:"indexPrice"`
+	FundingRate     jsonDecimal `json:"fundingRate"`
+	NextFundingTime jsonInt     `json:"nextFundingTime"`
+}
+
+func (b *Bybit) derivTicker(ctx context.Context, symbol string) (bybitDerivTicker, int64, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return bybitDerivTicker{}, 0, err
+	}
+
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+	}
+
+	var resp bybitResponse[bybitList[bybitDerivTicker]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/tickers", params, false, &resp); err != nil {
+		return bybitDerivTicker{}, 0, err
+	}
+	if len(resp.Result.List) == 0 {
+		return bybitDerivTicker{}, 0, fmt.Errorf("bybit ticker %s not found", symbol)
+	}
+	return resp.Result.List[0], resp.Time, nil
+}
+
+// GetFundingRate 的结算间隔来自 instruments-info 的 fundingInterval（分钟）
+func (b *Bybit) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	t, ts, err := b.derivTicker(ctx, symbol)
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+	}
+
+	var resp bybitResponse[bybitList[struct {
+		FundingInterval int `json:"fundingInterval"`
+	}]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/instruments-info", params, false, &resp); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	rate := types.FundingRate{
+		Symbol:          t.Symbol,
+		Rate:            t.FundingRate.Decimal,
+		NextFundingTime: msToTime(int64(t.NextFundingTime)),
+		Timestamp:       msToTime(ts),
+	}
+	if len(resp.Result.List) > 0 {
+		rate.Interval = time.Duration(resp.Result.List[0].FundingInterval) * time.Minute
+	}
+	return rate, nil
+}
+
+// GetFundingHistory 单次最多返回 200 条；Bybit 要求 startTime 与 endTime 同时出现，只指定 start 时 end 取当前时间
+func (b *Bybit) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(b, b.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"category": b.category(),
+		"symbol":   symbol,
+		"limit":    clampDepth(limit, 200),
+	}
+	if !start.IsZero() {
+		params["startTime"] = start.UnixMilli()
+		if end.IsZero() {
+			params["endTime"] = time.Now().UnixMilli()
+		}
+	}
+	if !end.IsZero() {
+		params["endTime"] = end.UnixMilli()
+	}
+
+	var resp bybitResponse[bybitList[struct {
+		Symbol               string      `json:"symbol"`
+		FundingRate          jsonDecimal `json:"fundingRate"`
+		FundingRateTimestamp jsonInt     `json:"fundingRateTimestamp"`
+	}]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/funding/history", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(resp.Result.List))
+	for _, r := range resp.Result.List {
+		records = append(records, types.FundingRecord{Symbol: r.Symbol, Rate: r.FundingRate.Decimal, Time: msToTime(int64(r.FundingRateTimestamp))})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+func (b *Bybit) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	t, ts, err := b.derivTicker(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: t.Symbol, Price: t.MarkPrice.Decimal, Timestamp: msToTime(ts)}, nil
+}
+
+func (b *Bybit) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	t, ts, err := b.derivTicker(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: t.Symbol, Price: t.IndexPrice.Decimal, Timestamp: msToTime(ts)}, nil
+}
+
+func (b *Bybit) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	category := b.category()
+
+	var resp bybitResponse[bybitList[bybitTicker]]
+	if err := sendRequest(ctx, b, "GET", "/v5/market/tickers", map[string]interface{}{"category": category}, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Result.List))
+	for _, t := range resp.Result.List {
+		tickers = append(tickers, t.toTicker(category, resp.Time))
+	}
+	return keyTickers(ctx, b, tickers)
+}
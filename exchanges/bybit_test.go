@@ -0,0 +1,55 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBybit_PrepareV5Request(t *testing.T) {
+	bybit := NewBybit(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+	})
+
+	// X-BAPI-SIGN = hex(HMAC-SHA256(timestamp + apiKey + recvWindow + 查询字符串或请求体))
+	sign := func(timestamp, payload string) string {
+		mac := hmac.New(sha256.New, []byte("test_secret"))
+		mac.Write([]byte(timestamp + "test_key" + "5000" + payload))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req, err := bybit.PrepareRequest("GET", "/v5/account/fee-rate", map[string]interface{}{"category": "spot", "symbol": "BTCUSDT"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "category=spot&symbol=BTCUSDT", req.URL.RawQuery)
+		assert.Equal(t, "test_key", req.Header.Get("X-BAPI-API-KEY"))
+		assert.Equal(t, "5000", req.Header.Get("X-BAPI-RECV-WINDOW"))
+
+		timestamp := req.Header.Get("X-BAPI-TIMESTAMP")
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, sign(timestamp, "category=spot&symbol=BTCUSDT"), req.Header.Get("X-BAPI-SIGN"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := bybit.PrepareRequest("POST", "/v5/order/create", map[string]interface{}{"category": "spot", "symbol": "BTCUSDT", "qty": "0.1"}, true)
+		assert.NoError(t, err)
+		assert.Empty(t, req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.JSONEq(t, `{"category":"spot","symbol":"BTCUSDT","qty":"0.1"}`, string(body))
+		assert.Equal(t, sign(req.Header.Get("X-BAPI-TIMESTAMP"), string(body)), req.Header.Get("X-BAPI-SIGN"))
+	})
+
+	t.Run("legacy endpoints keep query signing", func(t *testing.T) {
+		req, err := bybit.PrepareRequest("GET", "/spot/v3/private/account", nil, true)
+		assert.NoError(t, err)
+		assert.Empty(t, req.Header.Get("X-BAPI-SIGN"))
+		assert.NotEmpty(t, req.URL.Query().Get("sign"))
+	})
+}
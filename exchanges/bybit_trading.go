@@ -0,0 +1,565 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type bybitFeeRate struct {
+	Symbol       string      `json:"symbol"`
+	MakerFeeRate jsonDecimal `json:"makerFeeRate"`
+	TakerFeeRate jsonDecimal `json:"takerFeeRate"`
+}
+
+func (b *Bybit) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	params := map[string]interface{}{"category": b.category()}
+	if len(symbols) == 1 {
+		params["symbol"] = symbols[0]
+	}
+
+	var resp bybitResponse[bybitList[bybitFeeRate]]
+	if err := sendRequest(ctx, b, "GET", "/v5/account/fee-rate", params, true, &resp); err != nil {
+		return nil, err
+	}
+
+	all := make(map[string]types.TradingFee, len(resp.Result.List))
+	for _, f := range resp.Result.List {
+		all[f.Symbol] = types.TradingFee{Symbol: f.Symbol, Maker: f.MakerFeeRate.Decimal, Taker: f.TakerFeeRate.Decimal}
+	}
+	return selectFees(b, all, symbols)
+}
+
+var (
+	bybitSides      = map[types.Side]string{types.Buy: "Buy", types.Sell: "Sell"}
+	bybitOrderTypes = map[types.OrderType]string{types.LimitOrder: "Limit", types.MarketOrder: "Market"}
+)
+
+type bybitOrderAck struct {
+	OrderID     string `json:"orderId"`
+	OrderLinkID string `json:"orderLinkId"`
+}
+
+// bybitTimeInForce 返回 v5 的 timeInForce，post-only 是单独的取值
+func bybitTimeInForce(req types.OrderRequest) string {
+	if req.PostOnly {
+		return "PostOnly"
+	}
+	return string(timeInForce(req))
+}
+
+// orderParams 把统一的下单请求转换为 v5 参数，现货市价单用 marketUnit 指明数量单位
+func (b *Bybit) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if req.Type == types.OCOOrder {
+		return nil, notSupported(b, "OCO orders")
+	}
+
+	category := b.category()
+	params := map[string]interface{}{
+		"category":  category,
+		"symbol":    req.Symbol,
+		"side":      bybitSides[req.Side],
+		"orderType": bybitOrderTypes[req.TriggerOrder().Type],
+		"qty":       req.Quantity.String(),
+	}
+	// 条件单用 triggerDirection 指明触发方向：1 为上涨触发，2 为下跌触发；现货条件单需要 orderFilter
+	if req.Type.Conditional() {
+		params["triggerPrice"] = req.TriggerPrice.String()
+		params["triggerDirection"] = 2
+		if req.TriggersOnRise() {
+			params["triggerDirection"] = 1
+		}
+		if category == "spot" {
+			params["orderFilter"] = "StopOrder"
+		}
+	}
+	if req.Type.Priced() {
+		params["price"] = req.Price.String()
+		params["timeInForce"] = bybitTimeInForce(req)
+	}
+	if req.ClientOrderID != "" {
+		params["orderLinkId"] = req.ClientOrderID
+	}
+
+	if category == "spot" {
+		if req.ReduceOnly {
+			return nil, notSupported(b, "reduce-only spot orders")
+		}
+		if !req.Type.Priced() {
+			params["marketUnit"] = "baseCoin"
+			if req.QuoteQuantity.IsPositive() {
+				params["qty"] = req.QuoteQuantity.String()
+				params["marketUnit"] = "quoteCoin"
+			}
+		}
+		return params, nil
+	}
+
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(b, "derivative orders by quote quantity")
+	}
+	if req.ReduceOnly {
+		params["reduceOnly"] = true
+	}
+	return params, nil
+}
+
+// bybitBatchResponse 中 result.list 与 retExtInfo.list 都按请求顺序排列，retExtInfo 的 code 非 0 表示该订单失败
+type bybitBatchResponse struct {
+	bybitResponse[bybitList[bybitOrderAck]]
+	RetExtInfo struct {
+		List []struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		} `json:"list"`
+	} `json:"retExtInfo"`
+}
+
+// at 返回批量请求中第 i 个订单的结果
+func (r *bybitBatchResponse) at(i int) (bybitOrderAck, error) {
+	if i < len(r.RetExtInfo.List) && r.RetExtInfo.List[i].Code != 0 {
+		return bybitOrderAck{}, batchError(strconv.Itoa(r.RetExtInfo.List[i].Code), r.RetExtInfo.List[i].Msg)
+	}
+	if i >= len(r.Result.List) {
+		return bybitOrderAck{}, errMissingResult
+	}
+	return r.Result.List[i], nil
+}
+
+// batchSize 返回批量接口一次最多处理的订单数，现货为 10，其余为 20
+func (b *Bybit) batchSize() int {
+	if b.category() == "spot" {
+		return 10
+	}
+	return 20
+}
+
+func (b *Bybit) placer() placer {
+	return placer{t: b, config: b.config, idLength: clientOrderIDLength, uniqueIDs: true, place: b.placeOrder, batch: b.placeBatch, batchSize: b.batchSize()}
+}
+
+func (b *Bybit) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return b.placer().placeOrder(ctx, req)
+}
+
+func (b *Bybit) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return b.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeBatch 通过 create-batch 提交一批订单，category 放在外层
+func (b *Bybit) placeBatch(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return buildBatch(ctx, reqs, b.orderParams, func(ctx context.Context, items []map[string]interface{}, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+		for _, item := range items {
+			delete(item, "category")
+		}
+
+		var resp bybitBatchResponse
+		if err := sendRequest(ctx, b, "POST", "/v5/order/create-batch", map[string]interface{}{"category": b.category(), "request": items}, true, &resp); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.OrderResult, len(reqs))
+		for i, req := range reqs {
+			ack, err := resp.at(i)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Order = orderFromRequest(req, ack.OrderID)
+		}
+		return results, nil
+	})
+}
+
+func (b *Bybit) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := b.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp bybitResponse[bybitOrderAck]
+	if err := sendRequest(ctx, b, "POST", "/v5/order/create", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+
+	order := orderFromRequest(req, resp.Result.OrderID)
+	order.ClientOrderID = resp.Result.OrderLinkID
+	return order, nil
+}
+
+// bybitSettleCoins 是 U 本位合约的结算币，未指定交易对撤单时需要按结算币分别撤销
+var bybitSettleCoins = []string{"USDT", "USDC"}
+
+func (b *Bybit) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	params := b.refParams(symbol, ref)
+	params["category"] = b.category()
+
+	var resp bybitResponse[bybitOrderAck]
+	return sendRequest(ctx, b, "POST", "/v5/order/cancel", params, true, &resp)
+}
+
+// refParams 返回按订单号或自定义订单号定位订单的参数
+func (b *Bybit) refParams(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"symbol": symbol}
+	if ref.ID != "" {
+		params["orderId"] = ref.ID
+	} else {
+		params["orderLinkId"] = ref.ClientOrderID
+	}
+	return params
+}
+
+// AmendOrder 使用 order/amend 原地修改订单，订单号不变
+func (b *Bybit) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	params := b.refParams(req.Symbol, req.Ref)
+	params["category"] = b.category()
+	if req.Price.IsPositive() {
+		params["price"] = req.Price.String()
+	}
+	if req.Quantity.IsPositive() {
+		params["qty"] = req.Quantity.String()
+	}
+
+	var resp bybitResponse[bybitOrderAck]
+	if err := sendRequest(ctx, b, "POST", "/v5/order/amend", params, true, &resp); err != nil {
+		return types.AmendResult{}, err
+	}
+	return types.AmendResult{Order: amendedOrder(req, resp.Result.OrderID, resp.Result.OrderLinkID)}, nil
+}
+
+// CancelOrders 通过 cancel-batch 批量撤单
+func (b *Bybit) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, b.batchSize(), func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		items := make([]map[string]interface{}, len(refs))
+		for i, ref := range refs {
+			items[i] = b.refParams(symbol, ref)
+		}
+
+		var resp bybitBatchResponse
+		if err := sendRequest(ctx, b, "POST", "/v5/order/cancel-batch", map[string]interface{}{"category": b.category(), "request": items}, true, &resp); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.CancelResult, len(refs))
+		for i, ref := range refs {
+			_, err := resp.at(i)
+			results[i] = refResult(symbol, ref, err)
+		}
+		return results, nil
+	}, nil), nil
+}
+
+// scopeParams 返回按交易对或全部交易对操作订单时的参数：U 本位合约未指定交易对时按结算币分别请求，币本位合约必须指定交易对
+func (b *Bybit) scopeParams(symbol string) ([]map[string]interface{}, error) {
+	category := b.category()
+	if symbol != "" {
+		return []map[string]interface{}{{"category": category, "symbol": symbol}}, nil
+	}
+
+	switch category {
+	case "linear":
+		scopes := make([]map[string]interface{}, 0, len(bybitSettleCoins))
+		for _, coin := range bybitSettleCoins {
+			scopes = append(scopes, map[string]interface{}{"category": category, "settleCoin": coin})
+		}
+		return scopes, nil
+	case "inverse":
+		return nil, notSupported(b, "inverse orders without a symbol")
+	default:
+		return []map[string]interface{}{{"category": category}}, nil
+	}
+}
+
+// CancelAllOrders 使用原生的 cancel-all
+func (b *Bybit) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	scopes, err := b.scopeParams(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []types.CancelResult
+	for _, params := range scopes {
+		var resp bybitResponse[bybitList[bybitOrderAck]]
+		if err := sendRequest(ctx, b, "POST", "/v5/order/cancel-all", params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, ack := range resp.Result.List {
+			results = append(results, types.CancelResult{Symbol: symbol, OrderID: ack.OrderID, ClientOrderID: ack.OrderLinkID})
+		}
+	}
+	return results, nil
+}
+
+// bybitOrder 是 realtime 与 history 接口返回的订单
+type bybitOrder struct {
+	OrderID      string      `json:"orderId"`
+	OrderLinkID  string      `json:"orderLinkId"`
+	Symbol       string      `json:"symbol"`
+	Price        jsonDecimal `json:"price"`
+	Qty          jsonDecimal `json:"qty"`
+	Side         string      `json:"side"`
+	OrderType    string      `json:"orderType"`
+	TimeInForce  string      `json:"timeInForce"`
+	OrderStatus  string      `json:"orderStatus"`
+	CumExecQty   jsonDecimal `json:"cumExecQty"`
+	CumExecValue jsonDecimal `json:"cumExecValue"`
+	AvgPrice     jsonDecimal `json:"avgPrice"`
+	CumExecFee   jsonDecimal `json:"cumExecFee"`
+	// TriggerPrice 与 TriggerDirection 只在条件单上有值，方向 1 为上涨触发，2 为下跌触发
+	TriggerPrice     jsonDecimal `json:"triggerPrice"`
+	TriggerDirection int         `json:"triggerDirection"`
+	ReduceOnly       bool        `json:"reduceOnly"`
+	CreatedTime      jsonInt     `json:"createdTime"`
+	UpdatedTime      jsonInt     `json:"updatedTime"`
+}
+
+// bybitStatuses 中 Untriggered、Triggered 是条件单的状态，Deactivated 是被撤销的条件单
+var bybitStatuses = map[string]types.OrderStatus{
+	"New":                     types.OrderNew,
+	"Untriggered":             types.OrderNew,
+	"Triggered":               types.OrderNew,
+	"PartiallyFilled":         types.OrderPartiallyFilled,
+	"Filled":                  types.OrderFilled,
+	"Cancelled":               types.OrderCanceled,
+	"PartiallyFilledCanceled": types.OrderCanceled,
+	"Deactivated":             types.OrderCanceled,
+	"Rejected":                types.OrderRejected,
+}
+
+func (o bybitOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:            o.OrderID,
+		ClientOrderID: o.OrderLinkID,
+		Symbol:        o.Symbol,
+		Side:          parseSide(o.Side),
+		Type:          types.OrderType(strings.ToUpper(o.OrderType)),
+		Price:         o.Price.Decimal,
+		Quantity:      o.Qty.Decimal,
+		ReduceOnly:    o.ReduceOnly,
+
+		Status:         orderStatus(bybitStatuses, o.OrderStatus),
+		FilledQuantity: o.CumExecQty.Decimal,
+		AveragePrice:   o.AvgPrice.Decimal,
+		Fee:            o.CumExecFee.Decimal,
+		Raw:            o.OrderStatus,
+		CreatedAt:      msToTime(int64(o.CreatedTime)),
+		UpdatedAt:      msToTime(int64(o.UpdatedTime)),
+	}
+	if order.AveragePrice.IsZero() {
+		order.AveragePrice = averagePrice(o.CumExecValue.Decimal, o.CumExecQty.Decimal)
+	}
+
+	if order.Type == types.LimitOrder {
+		order.TimeInForce = types.TimeInForce(o.TimeInForce)
+		if o.TimeInForce == "PostOnly" {
+			order.TimeInForce = types.GTC
+		}
+	}
+
+	if o.TriggerPrice.IsPositive() && o.TriggerDirection != 0 {
+		order.TriggerPrice = o.TriggerPrice.Decimal
+		order.Type = triggerType(order.Side, o.TriggerDirection == 1, order.Type == types.LimitOrder)
+	}
+	return order
+}
+
+// listOrders 按游标翻页读取 endpoint 的订单，limit 为 0 时读取全部
+func (b *Bybit) listOrders(ctx context.Context, endpoint string, params map[string]interface{}, limit int) ([]types.Order, error) {
+	params["limit"] = 50
+
+	var orders []types.Order
+	for limit <= 0 || len(orders) < limit {
+		var resp bybitResponse[bybitList[bybitOrder]]
+		if err := sendRequest(ctx, b, "GET", endpoint, params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, row := range resp.Result.List {
+			orders = append(orders, row.toOrder())
+		}
+		if resp.Result.NextPageCursor == "" || len(resp.Result.List) == 0 {
+			break
+		}
+		params["cursor"] = resp.Result.NextPageCursor
+	}
+	return orders, nil
+}
+
+// GetOrder 先查询 realtime，现货已结束的订单不在其中，再查询 history
+func (b *Bybit) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	for _, endpoint := range []string{"/v5/order/realtime", "/v5/order/history"} {
+		params := map[string]interface{}{"category": b.category(), "symbol": symbol}
+		if ref.ID != "" {
+			params["orderId"] = ref.ID
+		} else {
+			params["orderLinkId"] = ref.ClientOrderID
+		}
+
+		var resp bybitResponse[bybitList[bybitOrder]]
+		if err := sendRequest(ctx, b, "GET", endpoint, params, true, &resp); err != nil {
+			return types.Order{}, err
+		}
+		if len(resp.Result.List) > 0 {
+			return resp.Result.List[0].toOrder(), nil
+		}
+	}
+	return types.Order{}, fmt.Errorf("bybit order %s: %w", symbol, types.ErrOrderNotFound)
+}
+
+func (b *Bybit) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	scopes, err := b.scopeParams(symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []types.Order
+	for _, params := range scopes {
+		page, err := b.listOrders(ctx, "/v5/order/realtime", params, 0)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, page...)
+	}
+	return orders, nil
+}
+
+// GetOrderHistory 使用 history 接口，时间范围最长 7 天，未指定时为最近 7 天
+func (b *Bybit) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{"category": b.category()}
+	if req.Symbol != "" {
+		params["symbol"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["startTime"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["endTime"] = req.End.UnixMilli()
+	}
+
+	orders, err := b.listOrders(ctx, "/v5/order/history", params, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return orderHistory(orders, req), nil
+}
+
+// bybitFill 是 execution/list 的成交记录，execType 为 Trade 以外的记录是资金费、交割等，不是成交；
+// feeCurrency 只有现货返回，合约的手续费以结算币计
+type bybitFill struct {
+	Symbol      string      `json:"symbol"`
+	OrderID     string      `json:"orderId"`
+	OrderLinkID string      `json:"orderLinkId"`
+	ExecID      string      `json:"execId"`
+	Side        string      `json:"side"`
+	ExecPrice   jsonDecimal `json:"execPrice"`
+	ExecQty     jsonDecimal `json:"execQty"`
+	ExecFee     jsonDecimal `json:"execFee"`
+	FeeCurrency string      `json:"feeCurrency"`
+	ExecType    string      `json:"execType"`
+	IsMaker     bool        `json:"isMaker"`
+	ExecTime    jsonInt     `json:"execTime"`
+}
+
+func (f bybitFill) toFill() types.Fill {
+	return types.Fill{
+		Symbol:        f.Symbol,
+		OrderID:       f.OrderID,
+		ClientOrderID: f.OrderLinkID,
+		TradeID:       f.ExecID,
+		Side:          parseSide(f.Side),
+		Price:         f.ExecPrice.Decimal,
+		Quantity:      f.ExecQty.Decimal,
+		Fee:           f.ExecFee.Decimal,
+		FeeAsset:      f.FeeCurrency,
+		Liquidity:     liquidity(f.IsMaker),
+		Time:          msToTime(int64(f.ExecTime)),
+	}
+}
+
+// bybitFillWindow 是 execution/list 一次查询的最长时间范围
+const bybitFillWindow = 7 * 24 * time.Hour
+
+// GetFills 按 7 天的窗口从 since 查询到当前时间，每个窗口内按 cursor 翻页；since 为零值时只返回最近 7 天的成交
+func (b *Bybit) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	params := map[string]interface{}{"category": b.category(), "limit": 100}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	var fills []types.Fill
+	for start := since; ; start = start.Add(bybitFillWindow) {
+		if !since.IsZero() {
+			params["startTime"], params["endTime"] = start.UnixMilli(), start.Add(bybitFillWindow).UnixMilli()-1
+		}
+		delete(params, "cursor")
+
+		for {
+			var resp bybitResponse[bybitList[bybitFill]]
+			if err := sendRequest(ctx, b, "GET", "/v5/execution/list", params, true, &resp); err != nil {
+				return nil, err
+			}
+			for _, row := range resp.Result.List {
+				if row.ExecType == "" || row.ExecType == "Trade" {
+					fills = append(fills, row.toFill())
+				}
+			}
+			if resp.Result.NextPageCursor == "" || len(resp.Result.List) == 0 {
+				break
+			}
+			params["cursor"] = resp.Result.NextPageCursor
+		}
+
+		if since.IsZero() || !start.Add(bybitFillWindow).Before(time.Now()) {
+			break
+		}
+	}
+
+	if err := b.fillFeeAssets(ctx, fills); err != nil {
+		return nil, err
+	}
+	return fillHistory(fills, since), nil
+}
+
+// fillFeeAssets 为没有 feeCurrency 的合约成交补上结算币：反向合约以基础币结算，其余以计价币结算
+func (b *Bybit) fillFeeAssets(ctx context.Context, fills []types.Fill) error {
+	missing := false
+	for _, fill := range fills {
+		missing = missing || fill.FeeAsset == ""
+	}
+	if !missing {
+		return nil
+	}
+
+	instruments, err := b.GetInstruments(ctx)
+	if err != nil {
+		return err
+	}
+	settle := make(map[string]string, len(instruments))
+	for _, in := range instruments {
+		settle[in.Symbol] = in.Quote
+		if b.category() == "inverse" {
+			settle[in.Symbol] = in.Base
+		}
+	}
+	for i := range fills {
+		if fills[i].FeeAsset == "" {
+			fills[i].FeeAsset = settle[fills[i].Symbol]
+		}
+	}
+	return nil
+}
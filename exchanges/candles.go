@@ -0,0 +1,163 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// candleFetcher 请求交易所原生支持的周期 interval，venueInterval 为交易所自己的周期写法
+type candleFetcher func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error)
+
+// fetchCandles 把统一周期映射为交易所周期后请求 K 线。
+// 交易所不支持该周期时，若开启了 resample，则用能整除它的最大细周期请求后聚合，否则返回 ErrNotSupported。
+func fetchCandles(ctx context.Context, e types.Exchange, intervals map[types.Interval]string, resample bool,
+	interval types.Interval, start, end time.Time, limit int, fetch candleFetcher) ([]types.Candle, error) {
+	if !interval.Valid() {
+		return nil, fmt.Errorf("invalid interval %q", interval)
+	}
+
+	if venueInterval, ok := intervals[interval]; ok {
+		candles, err := fetch(ctx, interval, venueInterval, start, end, limit)
+		if err != nil {
+			return nil, err
+		}
+		return trimCandles(candles, start, end, limit), nil
+	}
+
+	base, ok := resampleBase(intervals, interval)
+	if !ok || !resample {
+		return nil, fmt.Errorf("%s interval %s: %w", e.Name(), interval, types.ErrNotSupported)
+	}
+
+	// 起点对齐到目标周期，保证第一根合成 K 线是完整的；未指定起点时按 limit 往前推
+	fineStart := start
+	if fineStart.IsZero() && limit > 0 {
+		to := end
+		if to.IsZero() {
+			to = time.Now()
+		}
+		fineStart = to.Add(-time.Duration(limit) * interval.Duration())
+	}
+	if !fineStart.IsZero() {
+		fineStart = interval.Truncate(fineStart)
+	}
+
+	fineLimit := 0
+	if limit > 0 {
+		fineLimit = limit * int(interval.Duration()/base.Duration())
+	}
+
+	fine, err := fetch(ctx, base, intervals[base], fineStart, end, fineLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return trimCandles(resampleCandles(fine, interval), start, end, limit), nil
+}
+
+// resampleBase 在交易所支持的周期中找出能整除 interval 的最大周期
+func resampleBase(intervals map[types.Interval]string, interval types.Interval) (types.Interval, bool) {
+	var best types.Interval
+	for candidate := range intervals {
+		if !divides(candidate, interval) {
+			continue
+		}
+		if best == "" || candidate.Duration() > best.Duration() {
+			best = candidate
+		}
+	}
+	return best, best != ""
+}
+
+func divides(fine, coarse types.Interval) bool {
+	f, c := fine.Duration(), coarse.Duration()
+	if f == 0 || f >= c {
+		return false
+	}
+
+	// 周线从周一对齐、月线的天数不固定，都只能由整除 1 天的周期合成
+	if coarse == types.Interval1w || coarse == types.Interval1M {
+		return (24*time.Hour)%f == 0
+	}
+	return c%f == 0
+}
+
+// resampleCandles 把细周期 K 线按目标周期聚合
+func resampleCandles(candles []types.Candle, interval types.Interval) []types.Candle {
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+
+	var result []types.Candle
+	for _, c := range candles {
+		bucket := interval.Truncate(c.OpenTime)
+		if n := len(result); n > 0 && result[n-1].OpenTime.Equal(bucket) {
+			last := &result[n-1]
+			if c.High.GreaterThan(last.High) {
+				last.High = c.High
+			}
+			if c.Low.LessThan(last.Low) {
+				last.Low = c.Low
+			}
+			last.Close = c.Close
+			last.Volume = last.Volume.Add(c.Volume)
+			last.QuoteVolume = last.QuoteVolume.Add(c.QuoteVolume)
+			continue
+		}
+
+		c.OpenTime = bucket
+		result = append(result, c)
+	}
+	return result
+}
+
+// trimCandles 升序排列并按时间范围过滤；指定了 start 时保留最早的 limit 根，否则保留最近的 limit 根
+func trimCandles(candles []types.Candle, start, end time.Time, limit int) []types.Candle {
+	sort.Slice(candles, func(i, j int) bool { return candles[i].OpenTime.Before(candles[j].OpenTime) })
+
+	result := candles[:0]
+	for _, c := range candles {
+		if !start.IsZero() && c.OpenTime.Before(start) {
+			continue
+		}
+		if !end.IsZero() && c.OpenTime.After(end) {
+			continue
+		}
+		result = append(result, c)
+	}
+
+	if limit > 0 && len(result) > limit {
+		if !start.IsZero() {
+			return result[:limit]
+		}
+		return result[len(result)-limit:]
+	}
+	return result
+}
+
+// candleFromRow 按给定下标从数组形式的 K 线中取值，下标为负表示交易所不提供该字段
+func candleFromRow(row []jsonDecimal, openTime time.Time, open, high, low, close, volume, quoteVolume int) types.Candle {
+	get := func(i int) jsonDecimal {
+		if i < 0 || i >= len(row) {
+			return jsonDecimal{}
+		}
+		return row[i]
+	}
+
+	return types.Candle{
+		OpenTime:    openTime,
+		Open:        get(open).Decimal,
+		High:        get(high).Decimal,
+		Low:         get(low).Decimal,
+		Close:       get(close).Decimal,
+		Volume:      get(volume).Decimal,
+		QuoteVolume: get(quoteVolume).Decimal,
+	}
+}
+
+// unixTime 把数组中的时间字段转换为 time.Time，unit 为该字段的单位
+func unixTime(v jsonDecimal, unit time.Duration) time.Time {
+	return time.Unix(0, v.Mul(decimalFromDuration(unit)).IntPart()).UTC()
+}
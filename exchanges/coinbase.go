@@ -1,6 +1,7 @@
 package exchanges
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -15,7 +16,9 @@ import (
 )
 
 type Coinbase struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewCoinbase(config types.ExchangeConfig) *Coinbase {
@@ -42,7 +45,7 @@ func (c *Coinbase) PrepareRequest(method, endpoint string, params map[string]int
 	}
 
 	var body []byte
-	if method == "GET" {
+	if method == "GET" || method == "DELETE" {
 		q := u.Query()
 		for k, v := range params {
 			q.Set(k, fmt.Sprint(v))
@@ -55,19 +58,20 @@ func (c *Coinbase) PrepareRequest(method, endpoint string, params map[string]int
 		}
 	}
 
-	req, err := http.NewRequest(method, u.String(), nil)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 
 	if signed {
 		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-		message := timestamp + method + endpoint
-		if method != "GET" {
-			message += string(body)
-		}
+		// 签名路径包含查询字符串
+		message := timestamp + method + u.RequestURI() + string(body)
 
-		signature := c.sign(message)
+		signature, err := c.sign(message)
+		if err != nil {
+			return nil, err
+		}
 
 		req.Header.Set("CB-ACCESS-KEY", c.config.APIKey)
 		req.Header.Set("CB-ACCESS-SIGN", signature)
@@ -80,8 +84,14 @@ func (c *Coinbase) PrepareRequest(method, endpoint string, params map[string]int
 	return req, nil
 }
 
-func (c *Coinbase) sign(message string) string {
-	h := hmac.New(sha256.New, []byte(c.config.APISecret))
+// sign 使用 base64 解码后的 API Secret 计算 HMAC-SHA256
+func (c *Coinbase) sign(message string) (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(c.config.APISecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode api secret: %v", err)
+	}
+
+	h := hmac.New(sha256.New, secret)
 	h.Write([]byte(message))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
 }
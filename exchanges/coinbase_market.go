@@ -0,0 +1,206 @@
+package exchanges
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type coinbaseTicker struct {
+	Price  jsonDecimal `json:"price"`
+	Bid    jsonDecimal `json:"bid"`
+	Ask    jsonDecimal `json:"ask"`
+	Volume jsonDecimal `json:"volume"`
+	Time   time.Time   `json:"time"`
+}
+
+// GetTicker 的 symbol 为 Coinbase product_id，例如 BTC-USD；接口不提供成交额
+func (c *Coinbase) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var t coinbaseTicker
+	if err := sendRequest(ctx, c, "GET", "/products/"+symbol+"/ticker", nil, false, &t); err != nil {
+		return types.Ticker{}, err
+	}
+
+	return types.Ticker{
+		Symbol:    symbol,
+		Last:      t.Price.Decimal,
+		Bid:       t.Bid.Decimal,
+		Ask:       t.Ask.Decimal,
+		Volume:    t.Volume.Decimal,
+		Timestamp: t.Time,
+	}, nil
+}
+
+type coinbaseBook struct {
+	Sequence int64           `json:"sequence"`
+	Bids     [][]jsonDecimal `json:"bids"`
+	Asks     [][]jsonDecimal `json:"asks"`
+	Time     time.Time       `json:"time"`
+}
+
+// GetOrderBook 使用 level=2 的聚合订单簿，Coinbase 不支持指定档位，返回后再截取
+func (c *Coinbase) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	var b coinbaseBook
+	if err := sendRequest(ctx, c, "GET", "/products/"+symbol+"/book", map[string]interface{}{"level": 2}, false, &b); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(b.Bids),
+		Asks:      levelsFromRows(b.Asks),
+		Sequence:  b.Sequence,
+		Timestamp: b.Time,
+	}, depth), nil
+}
+
+var coinbaseIntervals = map[types.Interval]string{
+	types.Interval1m: "60", types.Interval5m: "300", types.Interval15m: "900",
+	types.Interval1h: "3600", types.Interval6h: "21600", types.Interval1d: "86400",
+}
+
+// GetCandles 中 Coinbase 返回 [time(秒), low, high, open, close, volume]，单次最多 300 根，不提供成交额
+func (c *Coinbase) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, c, coinbaseIntervals, c.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{"granularity": venueInterval}
+			if !start.IsZero() {
+				params["start"] = start.UTC().Format(time.RFC3339)
+			}
+			if !end.IsZero() {
+				params["end"] = end.UTC().Format(time.RFC3339)
+			}
+
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, c, "GET", "/products/"+symbol+"/candles", params, false, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, row := range rows {
+				if len(row) < 6 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, unixTime(row[0], time.Second), 3, 2, 1, 4, 5, -1))
+			}
+			return candles, nil
+		})
+}
+
+// coinbaseTrade 的 side 是 maker 方向，需要取反得到 taker 方向
+type coinbaseTrade struct {
+	TradeID int64       `json:"trade_id"`
+	Price   jsonDecimal `json:"price"`
+	Size    jsonDecimal `json:"size"`
+	Side    string      `json:"side"`
+	Time    time.Time   `json:"time"`
+}
+
+func (c *Coinbase) coinbaseTrades(ctx context.Context, symbol string, after string, limit int) ([]coinbaseTrade, error) {
+	params := map[string]interface{}{"limit": clampDepth(limit, 1000)}
+	if after != "" {
+		params["after"] = after
+	}
+
+	var rows []coinbaseTrade
+	if err := sendRequest(ctx, c, "GET", "/products/"+symbol+"/trades", params, false, &rows); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (t coinbaseTrade) toTrade() types.Trade {
+	return types.Trade{
+		ID:    strconv.FormatInt(t.TradeID, 10),
+		Price: t.Price.Decimal,
+		Size:  t.Size.Decimal,
+		Side:  opposite(parseSide(t.Side)),
+		Time:  t.Time,
+	}
+}
+
+func (c *Coinbase) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	rows, err := c.coinbaseTrades(ctx, symbol, "", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, t.toTrade())
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// GetHistoricalTrades 按 trade_id 由新到旧翻页，游标为本页最早的 trade_id。
+// 指定 Since 时丢弃早于 Since 的成交，并在越过 Since 后结束翻页。
+func (c *Coinbase) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	limit := clampDepth(req.Limit, 1000)
+	rows, err := c.coinbaseTrades(ctx, req.Symbol, req.Cursor, limit)
+	if err != nil {
+		return types.TradePage{}, err
+	}
+
+	page := types.TradePage{Trades: make([]types.Trade, 0, len(rows))}
+	reachedSince := false
+	for _, t := range rows {
+		if !req.Since.IsZero() && t.Time.Before(req.Since) {
+			reachedSince = true
+			continue
+		}
+		page.Trades = append(page.Trades, t.toTrade())
+	}
+	sortTrades(page.Trades, 0)
+
+	if len(rows) == limit && !reachedSince {
+		page.Cursor = strconv.FormatInt(rows[len(rows)-1].TradeID, 10)
+	}
+	return page, nil
+}
+
+type coinbaseProduct struct {
+	ID              string      `json:"id"`
+	BaseCurrency    string      `json:"base_currency"`
+	QuoteCurrency   string      `json:"quote_currency"`
+	QuoteIncrement  jsonDecimal `json:"quote_increment"`
+	BaseIncrement   jsonDecimal `json:"base_increment"`
+	MinMarketFunds  jsonDecimal `json:"min_market_funds"`
+	Status          string      `json:"status"`
+	TradingDisabled bool        `json:"trading_disabled"`
+	CancelOnly      bool        `json:"cancel_only"`
+}
+
+// GetInstruments 中 Coinbase 已不再提供最小、最大下单数量，只有最小下单金额
+func (c *Coinbase) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return c.instruments.get(ctx, c.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var products []coinbaseProduct
+		if err := sendRequest(ctx, c, "GET", "/products", nil, false, &products); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(products))
+		for _, p := range products {
+			instruments = append(instruments, types.Instrument{
+				Symbol:      p.ID,
+				Base:        p.BaseCurrency,
+				Quote:       p.QuoteCurrency,
+				TickSize:    p.QuoteIncrement.Decimal,
+				StepSize:    p.BaseIncrement.Decimal,
+				MinNotional: p.MinMarketFunds.Decimal,
+				Status:      tradingStatus(p.Status == "online" && !p.TradingDisabled && !p.CancelOnly),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// GetAllTickers 中 Coinbase 没有批量行情接口，按可交易的交易对在限速内逐个查询
+func (c *Coinbase) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	instruments, err := c.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return batchTickers(ctx, symbolIndex(instruments, true), c.GetTicker)
+}
@@ -0,0 +1,337 @@
+package exchanges
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// coinbaseFees 是 /fees 接口的响应，费率由最近 30 天的成交额决定
+type coinbaseFees struct {
+	MakerFeeRate jsonDecimal `json:"maker_fee_rate"`
+	TakerFeeRate jsonDecimal `json:"taker_fee_rate"`
+}
+
+// GetTradingFees 中 Coinbase 只提供账户级别的费率，统一适用于全部交易对
+func (c *Coinbase) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	var fees coinbaseFees
+	if err := sendRequest(ctx, c, "GET", "/fees", nil, true, &fees); err != nil {
+		return nil, err
+	}
+	return uniformFees(ctx, c, symbols, fees.MakerFeeRate.Decimal, fees.TakerFeeRate.Decimal)
+}
+
+// coinbaseFill 是 fills 接口的成交，liquidity 为 M 表示 maker、T 表示 taker；手续费以计价币计
+type coinbaseFill struct {
+	TradeID   int64       `json:"trade_id"`
+	ProductID string      `json:"product_id"`
+	OrderID   string      `json:"order_id"`
+	Liquidity string      `json:"liquidity"`
+	Price     jsonDecimal `json:"price"`
+	Size      jsonDecimal `json:"size"`
+	Fee       jsonDecimal `json:"fee"`
+	Side      string      `json:"side"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (f coinbaseFill) toFill() types.Fill {
+	_, quote, _ := strings.Cut(f.ProductID, "-")
+	return types.Fill{
+		Symbol:    f.ProductID,
+		OrderID:   f.OrderID,
+		TradeID:   strconv.FormatInt(f.TradeID, 10),
+		Side:      parseSide(f.Side),
+		Price:     f.Price.Decimal,
+		Quantity:  f.Size.Decimal,
+		Fee:       f.Fee.Decimal,
+		FeeAsset:  quote,
+		Liquidity: liquidity(f.Liquidity == "M"),
+		Time:      f.CreatedAt,
+	}
+}
+
+// coinbaseFillPage 是 fills 每页的最大数量
+const coinbaseFillPage = 1000
+
+// GetFills 从最新的成交按 trade_id 向前翻页，直到早于 since；fills 接口要求指定交易对
+func (c *Coinbase) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	if symbol == "" {
+		return nil, notSupported(c, "fills without a symbol")
+	}
+
+	params := map[string]interface{}{"product_id": symbol, "limit": coinbaseFillPage}
+	var fills []types.Fill
+	for {
+		var rows []coinbaseFill
+		if err := sendRequest(ctx, c, "GET", "/fills", params, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			fills = append(fills, row.toFill())
+		}
+		if len(rows) < coinbaseFillPage || (!since.IsZero() && rows[len(rows)-1].CreatedAt.Before(since)) {
+			break
+		}
+		params["after"] = strconv.FormatInt(rows[len(rows)-1].TradeID, 10)
+	}
+	return fillHistory(fills, since), nil
+}
+
+// orderParams 把统一的下单请求转换为 Coinbase 参数，按计价币金额下的市价单使用 funds
+func (c *Coinbase) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if req.ReduceOnly {
+		return nil, notSupported(c, "reduce-only spot orders")
+	}
+	if req.Type.Conditional() {
+		return nil, notSupported(c, "conditional orders")
+	}
+
+	params := map[string]interface{}{
+		"product_id": req.Symbol,
+		"side":       lowerSide(req.Side),
+		"type":       strings.ToLower(string(req.Type)),
+		"client_oid": req.ClientOrderID,
+	}
+	if req.Quantity.IsPositive() {
+		params["size"] = req.Quantity.String()
+	}
+	if req.QuoteQuantity.IsPositive() {
+		params["funds"] = req.QuoteQuantity.String()
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		params["time_in_force"] = string(timeInForce(req))
+		params["post_only"] = req.PostOnly
+	}
+	return params, nil
+}
+
+// withClientOID 在缺少自定义订单号时生成一个 UUID，Coinbase 的 client_oid 必须是 UUID 格式
+func withClientOID(req types.OrderRequest) (types.OrderRequest, error) {
+	if req.ClientOrderID != "" {
+		return req, nil
+	}
+	id, err := newUUID()
+	if err != nil {
+		return req, err
+	}
+	req.ClientOrderID = id
+	return req, nil
+}
+
+func (c *Coinbase) placer() placer {
+	return placer{t: c, config: c.config, idLength: clientOrderIDLength, place: c.placeOrder}
+}
+
+func (c *Coinbase) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	req, err := withClientOID(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+	return c.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单，Coinbase 没有批量下单接口
+func (c *Coinbase) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	prepared := make([]types.OrderRequest, len(reqs))
+	for i, req := range reqs {
+		var err error
+		if prepared[i], err = withClientOID(req); err != nil {
+			return nil, err
+		}
+	}
+	return c.placer().placeOrders(ctx, prepared), nil
+}
+
+func (c *Coinbase) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := c.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var ack struct {
+		ID string `json:"id"`
+	}
+	if err := sendRequest(ctx, c, "POST", "/orders", params, true, &ack); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, ack.ID), nil
+}
+
+// AmendOrder 通过撤单再下单实现，Coinbase Exchange 的订单不能修改
+func (c *Coinbase) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, c, req)
+}
+
+// coinbaseOrderNotFound 是订单不存在时 404 响应中的消息
+var coinbaseOrderNotFound = []string{"NotFound", "not found"}
+
+// coinbaseOrderEndpoint 返回按订单号或自定义订单号指定订单的路径，自定义订单号使用 client: 前缀
+func coinbaseOrderEndpoint(ref types.OrderRef) string {
+	if ref.ID != "" {
+		return "/orders/" + url.PathEscape(ref.ID)
+	}
+	return "/orders/client:" + url.PathEscape(ref.ClientOrderID)
+}
+
+func (c *Coinbase) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["product_id"] = symbol
+	}
+	var id string
+	return orderNotFound(sendRequest(ctx, c, "DELETE", coinbaseOrderEndpoint(ref), params, true, &id), coinbaseOrderNotFound...)
+}
+
+// CancelOrders 没有批量撤单接口，并发地逐个撤单
+func (c *Coinbase) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, 0, nil, c.CancelOrder), nil
+}
+
+// CancelAllOrders 使用原生的 DELETE /orders，结果中只有订单号
+func (c *Coinbase) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["product_id"] = symbol
+	}
+
+	var ids []string
+	if err := sendRequest(ctx, c, "DELETE", "/orders", params, true, &ids); err != nil {
+		return nil, err
+	}
+	results := make([]types.CancelResult, len(ids))
+	for i, id := range ids {
+		results[i] = types.CancelResult{Symbol: symbol, OrderID: id}
+	}
+	return results, nil
+}
+
+// coinbaseOrder 是订单接口的订单。status 为 done 时由 done_reason 区分成交与撤销；
+// 按计价币金额下的市价单用 funds 表示金额，executed_value 是已成交的计价币金额
+type coinbaseOrder struct {
+	ID            string      `json:"id"`
+	ClientOID     string      `json:"client_oid"`
+	ProductID     string      `json:"product_id"`
+	Side          string      `json:"side"`
+	Type          string      `json:"type"`
+	TimeInForce   string      `json:"time_in_force"`
+	Price         jsonDecimal `json:"price"`
+	Size          jsonDecimal `json:"size"`
+	Funds         jsonDecimal `json:"specified_funds"`
+	FilledSize    jsonDecimal `json:"filled_size"`
+	ExecutedValue jsonDecimal `json:"executed_value"`
+	FillFees      jsonDecimal `json:"fill_fees"`
+	Status        string      `json:"status"`
+	DoneReason    string      `json:"done_reason"`
+	CreatedAt     time.Time   `json:"created_at"`
+	DoneAt        time.Time   `json:"done_at"`
+}
+
+var coinbaseDoneReasons = map[string]types.OrderStatus{
+	"filled":   types.OrderFilled,
+	"canceled": types.OrderCanceled,
+	"rejected": types.OrderRejected,
+}
+
+func (o coinbaseOrder) toOrder() types.Order {
+	_, quote, _ := strings.Cut(o.ProductID, "-")
+	order := types.Order{
+		ID:             o.ID,
+		ClientOrderID:  o.ClientOID,
+		Symbol:         o.ProductID,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.TimeInForce(o.TimeInForce),
+		Price:          o.Price.Decimal,
+		Quantity:       o.Size.Decimal,
+		Status:         openStatus(o.FilledSize.Decimal),
+		FilledQuantity: o.FilledSize.Decimal,
+		AveragePrice:   averagePrice(o.ExecutedValue.Decimal, o.FilledSize.Decimal),
+		Fee:            o.FillFees.Decimal,
+		FeeAsset:       quote,
+		Raw:            o.Status,
+		CreatedAt:      o.CreatedAt,
+		UpdatedAt:      o.DoneAt,
+	}
+	switch o.Status {
+	case "done":
+		order.Status = orderStatus(coinbaseDoneReasons, o.DoneReason)
+	case "rejected":
+		order.Status = types.OrderRejected
+	}
+	if o.Type == "market" {
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+		order.QuoteQuantity = o.Funds.Decimal
+	}
+	return order
+}
+
+func (c *Coinbase) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	var order coinbaseOrder
+	if err := sendRequest(ctx, c, "GET", coinbaseOrderEndpoint(ref), nil, true, &order); err != nil {
+		return types.Order{}, orderNotFound(err, coinbaseOrderNotFound...)
+	}
+	return order.toOrder(), nil
+}
+
+// GetOpenOrders 不指定 status，Coinbase 默认返回 open、pending 与 active 的订单
+func (c *Coinbase) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["product_id"] = symbol
+	}
+	return c.listOrders(ctx, params, 0)
+}
+
+// GetOrderHistory 查询 status 为 done 的订单，Coinbase 只保留已撤销且没有成交的订单 24 小时
+func (c *Coinbase) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{"status": "done"}
+	if req.Symbol != "" {
+		params["product_id"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["start_date"] = req.Start.UTC().Format(time.RFC3339)
+	}
+	if !req.End.IsZero() {
+		params["end_date"] = req.End.UTC().Format(time.RFC3339)
+	}
+
+	orders, err := c.listOrders(ctx, params, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return orderHistory(orders, req), nil
+}
+
+// listOrders 按创建时间从新到旧读取订单，用 after 向前翻页，limit 为 0 时读取全部
+func (c *Coinbase) listOrders(ctx context.Context, params map[string]interface{}, limit int) ([]types.Order, error) {
+	params["limit"] = coinbaseFillPage
+	var orders []types.Order
+	for limit <= 0 || len(orders) < limit {
+		var rows []coinbaseOrder
+		if err := sendRequest(ctx, c, "GET", "/orders", params, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			orders = append(orders, row.toOrder())
+		}
+		if len(rows) < coinbaseFillPage {
+			break
+		}
+		params["after"] = rows[len(rows)-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return orders, nil
+}
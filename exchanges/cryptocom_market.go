@@ -0,0 +1,238 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type cryptoComResponse[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Result  T      `json:"result"`
+}
+
+func (r *cryptoComResponse[T]) apiError() error {
+	if r.Code != 0 {
+		return &types.APIError{StatusCode: http.StatusOK, Code: strconv.Itoa(r.Code), Message: r.Message}
+	}
+	return nil
+}
+
+type cryptoComData[T any] struct {
+	Data []T `json:"data"`
+}
+
+// cryptoComTicker 使用交易所的缩写字段：a 最新价，b 买一，k 卖一，v 基础币成交量，vv 成交额
+type cryptoComTicker struct {
+	I  string      `json:"i"`
+	A  jsonDecimal `json:"a"`
+	B  jsonDecimal `json:"b"`
+	K  jsonDecimal `json:"k"`
+	V  jsonDecimal `json:"v"`
+	VV jsonDecimal `json:"vv"`
+	T  int64       `json:"t"`
+}
+
+// GetTicker 的 symbol 为 instrument_name，例如 BTC_USDT 或 BTCUSD-PERP
+func (c *CryptoCom) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var resp cryptoComResponse[cryptoComData[cryptoComTicker]]
+	if err := sendRequest(ctx, c, "GET", "public/get-tickers", map[string]interface{}{"instrument_name": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(resp.Result.Data) == 0 {
+		return types.Ticker{}, fmt.Errorf("crypto.com ticker %s not found", symbol)
+	}
+
+	return resp.Result.Data[0].toTicker(), nil
+}
+
+func (t cryptoComTicker) toTicker() types.Ticker {
+	return types.Ticker{
+		Symbol:      t.I,
+		Last:        t.A.Decimal,
+		Bid:         t.B.Decimal,
+		Ask:         t.K.Decimal,
+		Volume:      t.V.Decimal,
+		QuoteVolume: t.VV.Decimal,
+		Timestamp:   msToTime(t.T),
+	}
+}
+
+type cryptoComBook struct {
+	Bids [][]jsonDecimal `json:"bids"`
+	Asks [][]jsonDecimal `json:"asks"`
+	T    int64           `json:"t"`
+	U    int64           `json:"u"`
+}
+
+func (c *CryptoCom) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	params := map[string]interface{}{
+		"instrument_name": symbol,
+		"depth":           clampDepth(depth, 50),
+	}
+
+	var resp cryptoComResponse[cryptoComData[cryptoComBook]]
+	if err := sendRequest(ctx, c, "GET", "public/get-book", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+	if len(resp.Result.Data) == 0 {
+		return types.OrderBook{}, fmt.Errorf("crypto.com order book %s not found", symbol)
+	}
+
+	b := resp.Result.Data[0]
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(b.Bids),
+		Asks:      levelsFromRows(b.Asks),
+		Sequence:  b.U,
+		Timestamp: msToTime(b.T),
+	}, depth), nil
+}
+
+var cryptoComIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval5m: "5m", types.Interval15m: "15m", types.Interval30m: "30m",
+	types.Interval1h: "1h", types.Interval2h: "2h", types.Interval4h: "4h", types.Interval12h: "12h",
+	types.Interval1d: "1D", types.Interval1w: "7D", types.Interval1M: "1M",
+}
+
+type cryptoComCandle struct {
+	T int64       `json:"t"`
+	O jsonDecimal `json:"o"`
+	H jsonDecimal `json:"h"`
+	L jsonDecimal `json:"l"`
+	C jsonDecimal `json:"c"`
+	V jsonDecimal `json:"v"`
+}
+
+// GetCandles 中 Crypto.com 单次最多 300 根，只返回基础币成交量
+func (c *CryptoCom) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, c, cryptoComIntervals, c.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"instrument_name": symbol,
+				"timeframe":       venueInterval,
+				"count":           clampDepth(limit, 300),
+			}
+			if !start.IsZero() {
+				params["start_ts"] = start.UnixMilli()
+			}
+			if !end.IsZero() {
+				params["end_ts"] = end.UnixMilli()
+			}
+
+			var resp cryptoComResponse[cryptoComData[cryptoComCandle]]
+			if err := sendRequest(ctx, c, "GET", "public/get-candlestick", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Result.Data))
+			for _, k := range resp.Result.Data {
+				candles = append(candles, types.Candle{
+					OpenTime: msToTime(k.T),
+					Open:     k.O.Decimal,
+					High:     k.H.Decimal,
+					Low:      k.L.Decimal,
+					Close:    k.C.Decimal,
+					Volume:   k.V.Decimal,
+				})
+			}
+			return candles, nil
+		})
+}
+
+// cryptoComTrade 中 d 为成交 ID，s 为 taker 方向，p 价格，q 数量，t 毫秒时间戳
+type cryptoComTrade struct {
+	D string      `json:"d"`
+	S string      `json:"s"`
+	P jsonDecimal `json:"p"`
+	Q jsonDecimal `json:"q"`
+	T int64       `json:"t"`
+}
+
+// GetRecentTrades 单次最多返回 150 笔
+func (c *CryptoCom) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	params := map[string]interface{}{
+		"instrument_name": symbol,
+		"count":           clampDepth(limit, 150),
+	}
+
+	var resp cryptoComResponse[cryptoComData[cryptoComTrade]]
+	if err := sendRequest(ctx, c, "GET", "public/get-trades", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Result.Data))
+	for _, t := range resp.Result.Data {
+		trades = append(trades, types.Trade{
+			ID:    t.D,
+			Price: t.P.Decimal,
+			Size:  t.Q.Decimal,
+			Side:  parseSide(t.S),
+			Time:  msToTime(t.T),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+type cryptoComInstrument struct {
+	Symbol        string      `json:"symbol"`
+	InstType      string      `json:"inst_type"`
+	BaseCcy       string      `json:"base_ccy"`
+	QuoteCcy      string      `json:"quote_ccy"`
+	PriceTickSize jsonDecimal `json:"price_tick_size"`
+	QtyTickSize   jsonDecimal `json:"qty_tick_size"`
+	ContractSize  jsonDecimal `json:"contract_size"`
+	Tradable      bool        `json:"tradable"`
+}
+
+// GetInstruments 中现货对应 CCY_PAIR，合约对应 PERPETUAL_SWAP 与 FUTURE；最小下单数量为 qty_tick_size
+func (c *CryptoCom) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return c.instruments.get(ctx, c.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp cryptoComResponse[cryptoComData[cryptoComInstrument]]
+		if err := sendRequest(ctx, c, "GET", "public/get-instruments", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		spot := c.config.MarketOrSpot() == types.Spot
+		instruments := make([]types.Instrument, 0, len(resp.Result.Data))
+		for _, i := range resp.Result.Data {
+			if spot != (i.InstType == "CCY_PAIR") {
+				continue
+			}
+
+			inst := types.Instrument{
+				Symbol:   i.Symbol,
+				Base:     i.BaseCcy,
+				Quote:    i.QuoteCcy,
+				TickSize: i.PriceTickSize.Decimal,
+				StepSize: i.QtyTickSize.Decimal,
+				MinQty:   i.QtyTickSize.Decimal,
+				Status:   tradingStatus(i.Tradable),
+			}
+			if !spot {
+				inst.ContractSize = i.ContractSize.Decimal
+			}
+			instruments = append(instruments, inst)
+		}
+		return instruments, nil
+	})
+}
+
+// GetAllTickers 使用不带 instrument_name 的 get-tickers，现货与永续混在一起返回，按交易对元数据筛选当前产品线
+func (c *CryptoCom) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	var resp cryptoComResponse[cryptoComData[cryptoComTicker]]
+	if err := sendRequest(ctx, c, "GET", "public/get-tickers", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Result.Data))
+	for _, t := range resp.Result.Data {
+		tickers = append(tickers, t.toTicker())
+	}
+	return keyTickers(ctx, c, tickers)
+}
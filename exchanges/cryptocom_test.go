@@ -0,0 +1,64 @@
+package exchanges
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCryptoCom_Sign(t *testing.T) {
+	cryptoCom := NewCryptoCom(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+	})
+
+	params := map[string]interface{}{
+		"instrument_name": "BTC_USDT",
+		"side":            "BUY",
+		"type":            "LIMIT",
+		"price":           50000.5,
+		"quantity":        "0.01",
+		"notional":        nil,
+	}
+	assert.Equal(t, "126efe70b53b1b659be483e2ef0744c796782e03218ea37eb19ebcbb3a4796a0",
+		cryptoCom.sign("private/create-order", 11, 1587846358253, params))
+
+	nested := map[string]interface{}{
+		"contingency_type": "LIST",
+		"order_list": []interface{}{
+			map[string]interface{}{"instrument_name": "ETH_USDT", "order_id": "1"},
+			map[string]interface{}{"instrument_name": "ETH_USDT", "order_id": "2"},
+		},
+	}
+	assert.Equal(t, "a83cc67714a170cf41c1a32c1dd255d884be5d445ef9e3b83a7bb281ce07eba7",
+		cryptoCom.sign("private/cancel-order-list", 12, 1587846358254, nested))
+}
+
+func TestCryptoCom_PrepareRequest(t *testing.T) {
+	cryptoCom := NewCryptoCom(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+	})
+
+	req, err := cryptoCom.PrepareRequest("POST", "private/user-balance", nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.crypto.com/exchange/v1/private/user-balance", req.URL.String())
+
+	body, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+
+	var payload struct {
+		ID     int64  `json:"id"`
+		Method string `json:"method"`
+		APIKey string `json:"api_key"`
+		Nonce  int64  `json:"nonce"`
+		Sig    string `json:"sig"`
+	}
+	assert.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, "private/user-balance", payload.Method)
+	assert.Equal(t, "test_key", payload.APIKey)
+	assert.Equal(t, cryptoCom.sign(payload.Method, payload.ID, payload.Nonce, map[string]interface{}{}), payload.Sig)
+}
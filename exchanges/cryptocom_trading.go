@@ -0,0 +1,276 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// cryptoComTimeInForce 把统一的有效方式转换为 Crypto.com 的全称
+var cryptoComTimeInForce = map[types.TimeInForce]string{
+	types.GTC: "GOOD_TILL_CANCEL",
+	types.IOC: "IMMEDIATE_OR_CANCEL",
+	types.FOK: "FILL_OR_KILL",
+}
+
+// orderParams 把统一的下单请求转换为 private/create-order 参数。现货与永续合约共用同一接口，
+// 按计价币金额下的市价单使用 notional；条件单暂不支持
+func (c *CryptoCom) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if req.Type.Conditional() {
+		return nil, notSupported(c, "conditional orders")
+	}
+	if req.ReduceOnly && c.config.MarketOrSpot() == types.Spot {
+		return nil, notSupported(c, "reduce-only spot orders")
+	}
+
+	params := map[string]interface{}{
+		"instrument_name": req.Symbol,
+		"side":            string(req.Side),
+		"type":            string(req.Type),
+		"client_oid":      req.ClientOrderID,
+	}
+	if req.QuoteQuantity.IsPositive() {
+		params["notional"] = req.QuoteQuantity.String()
+	} else {
+		params["quantity"] = req.Quantity.String()
+	}
+
+	var execInst []string
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		params["time_in_force"] = cryptoComTimeInForce[timeInForce(req)]
+		if req.PostOnly {
+			execInst = append(execInst, "POST_ONLY")
+		}
+	}
+	if req.ReduceOnly {
+		execInst = append(execInst, "REDUCE_ONLY")
+	}
+	if execInst != nil {
+		params["exec_inst"] = execInst
+	}
+	return params, nil
+}
+
+func (c *CryptoCom) placer() placer {
+	return placer{t: c, config: c.config, idLength: clientOrderIDLength, place: c.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为 Crypto.com 的 instrument_name，例如现货 BTC_USD、永续合约 BTCUSD-PERP
+func (c *CryptoCom) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return c.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单
+func (c *CryptoCom) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return c.placer().placeOrders(ctx, reqs), nil
+}
+
+func (c *CryptoCom) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := c.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp cryptoComResponse[struct {
+		OrderID string `json:"order_id"`
+	}]
+	if err := sendRequest(ctx, c, "POST", "private/create-order", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, resp.Result.OrderID), nil
+}
+
+func (c *CryptoCom) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, c, req)
+}
+
+// cryptoComOrderNotFound 是订单不存在时的错误信息
+const cryptoComOrderNotFound = "INVALID_ORDERID"
+
+// cryptoComOrder 是订单接口的订单，exec_inst 中的 REDUCE_ONLY 表示只减仓
+type cryptoComOrder struct {
+	OrderID            string      `json:"order_id"`
+	ClientOID          string      `json:"client_oid"`
+	InstrumentName     string      `json:"instrument_name"`
+	OrderType          string      `json:"order_type"`
+	Side               string      `json:"side"`
+	TimeInForce        string      `json:"time_in_force"`
+	ExecInst           []string    `json:"exec_inst"`
+	Quantity           jsonDecimal `json:"quantity"`
+	LimitPrice         jsonDecimal `json:"limit_price"`
+	OrderValue         jsonDecimal `json:"order_value"`
+	AvgPrice           jsonDecimal `json:"avg_price"`
+	CumulativeQuantity jsonDecimal `json:"cumulative_quantity"`
+	CumulativeFee      jsonDecimal `json:"cumulative_fee"`
+	FeeInstrumentName  string      `json:"fee_instrument_name"`
+	Status             string      `json:"status"`
+	CreateTime         int64       `json:"create_time"`
+	CreateTimeNs       string      `json:"create_time_ns"`
+	UpdateTime         int64       `json:"update_time"`
+}
+
+var cryptoComStatuses = map[string]types.OrderStatus{
+	"FILLED":   types.OrderFilled,
+	"CANCELED": types.OrderCanceled,
+	"REJECTED": types.OrderRejected,
+	"EXPIRED":  types.OrderExpired,
+}
+
+// cryptoComTimeInForceNames 是 cryptoComTimeInForce 的反向映射
+var cryptoComTimeInForceNames = map[string]types.TimeInForce{
+	"GOOD_TILL_CANCEL":    types.GTC,
+	"IMMEDIATE_OR_CANCEL": types.IOC,
+	"FILL_OR_KILL":        types.FOK,
+}
+
+func (o cryptoComOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClientOID,
+		Symbol:         o.InstrumentName,
+		Side:           parseSide(o.Side),
+		Type:           types.OrderType(o.OrderType),
+		TimeInForce:    cryptoComTimeInForceNames[o.TimeInForce],
+		Price:          o.LimitPrice.Decimal,
+		Quantity:       o.Quantity.Decimal,
+		Status:         orderStatus(cryptoComStatuses, o.Status),
+		FilledQuantity: o.CumulativeQuantity.Decimal,
+		AveragePrice:   o.AvgPrice.Decimal,
+		Fee:            o.CumulativeFee.Decimal,
+		FeeAsset:       o.FeeInstrumentName,
+		Raw:            o.Status,
+		CreatedAt:      msToTime(o.CreateTime),
+		UpdatedAt:      msToTime(o.UpdateTime),
+	}
+	switch o.Status {
+	case "NEW", "PENDING", "ACTIVE":
+		order.Status = openStatus(o.CumulativeQuantity.Decimal)
+	}
+	if order.Type == types.MarketOrder {
+		order.TimeInForce = ""
+		if o.Quantity.IsZero() {
+			order.QuoteQuantity = o.OrderValue.Decimal
+		}
+	}
+	for _, inst := range o.ExecInst {
+		if inst == "REDUCE_ONLY" {
+			order.ReduceOnly = true
+		}
+	}
+	return order
+}
+
+func (c *CryptoCom) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{"client_oid": ref.ClientOrderID}
+	if ref.ID != "" {
+		params = map[string]interface{}{"order_id": ref.ID}
+	}
+	var resp cryptoComResponse[json.RawMessage]
+	return orderNotFound(sendRequest(ctx, c, "POST", "private/cancel-order", params, true, &resp), cryptoComOrderNotFound)
+}
+
+// CancelOrders 并发地逐个撤单
+func (c *CryptoCom) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, 0, nil, c.CancelOrder), nil
+}
+
+// CancelAllOrders 调用 private/cancel-all-orders，它只确认请求已受理，结果取自撤销前的挂单快照
+func (c *CryptoCom) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	orders, err := c.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["instrument_name"] = symbol
+	}
+	var resp cryptoComResponse[json.RawMessage]
+	if err := sendRequest(ctx, c, "POST", "private/cancel-all-orders", params, true, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]types.CancelResult, 0, len(orders))
+	for _, order := range orders {
+		results = append(results, cancelResult(order, nil))
+	}
+	return results, nil
+}
+
+func (c *CryptoCom) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["instrument_name"] = symbol
+	}
+
+	var resp cryptoComResponse[cryptoComData[cryptoComOrder]]
+	if err := sendRequest(ctx, c, "POST", "private/get-open-orders", params, true, &resp); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(resp.Result.Data))
+	for i, row := range resp.Result.Data {
+		orders[i] = row.toOrder()
+	}
+	return orders, nil
+}
+
+// cryptoComHistoryPage 是 get-order-history 每页的最大数量
+const cryptoComHistoryPage = 100
+
+func (c *CryptoCom) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	params := map[string]interface{}{"client_oid": ref.ClientOrderID}
+	if ref.ID != "" {
+		params = map[string]interface{}{"order_id": ref.ID}
+	}
+	var resp cryptoComResponse[cryptoComOrder]
+	if err := sendRequest(ctx, c, "POST", "private/get-order-detail", params, true, &resp); err != nil {
+		return types.Order{}, orderNotFound(err, cryptoComOrderNotFound)
+	}
+	return resp.Result.toOrder(), nil
+}
+
+func (c *CryptoCom) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return c.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 get-order-history，按 end_time 从新到旧翻页；不指定 Start 时交易所只返回最近 24 小时的订单
+func (c *CryptoCom) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{"limit": cryptoComHistoryPage}
+	if req.Symbol != "" {
+		params["instrument_name"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["start_time"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["end_time"] = req.End.UnixMilli()
+	}
+
+	var orders []types.Order
+	for req.Limit <= 0 || len(orders) < req.Limit {
+		var resp cryptoComResponse[cryptoComData[cryptoComOrder]]
+		if err := sendRequest(ctx, c, "POST", "private/get-order-history", params, true, &resp); err != nil {
+			return nil, err
+		}
+		rows := resp.Result.Data
+		for _, row := range rows {
+			orders = append(orders, row.toOrder())
+		}
+		if len(rows) < cryptoComHistoryPage {
+			break
+		}
+		// end_time 不包含边界，使用纳秒时间戳避免跳过同一毫秒内的订单
+		params["end_time"] = rows[len(rows)-1].CreateTimeNs
+	}
+	return orderHistory(orders, req), nil
+}
@@ -0,0 +1,406 @@
+package exchanges
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// ConditionalEmulator 包装 types.Trading，在交易所不支持条件单时由客户端按最新价模拟：
+// 条件单保存在内存中，Run 轮询行情，触及触发价后提交对应的市价或限价单。OCO 的限价单直接挂到交易所，
+// 止损单在客户端等待触发，触发时先撤销限价单再按剩余数量下单。
+// 模拟的条件单只存在于当前进程，进程退出后不会触发；交易所原生支持的条件单照常提交
+type ConditionalEmulator struct {
+	types.Trading
+	market   types.MarketData
+	interval time.Duration
+
+	// OnTrigger 在模拟的条件单触发后调用，order 是提交的订单，err 是下单失败的原因
+	OnTrigger func(req types.OrderRequest, order types.Order, err error)
+
+	mu        sync.Mutex
+	pending   map[string]*emulatedOrder
+	triggered map[string]triggeredOrder
+}
+
+// triggeredOrder 是已触发条件单提交到交易所的订单，订单结束后从 triggered 中移除
+type triggeredOrder struct {
+	symbol string
+	ref    types.OrderRef
+}
+
+// emulatedOrder 是等待触发的条件单。clientOrderID 是调用方指定的自定义订单号，触发后提交的订单沿用它，
+// 未指定时由交易所生成，以免模拟器生成的订单号不符合交易所的格式；limit 是 OCO 已挂到交易所的限价单，
+// remaining 是限价单撤销后止损单应下的数量，limitCanceled 为 true 时重试不再撤销限价单
+type emulatedOrder struct {
+	req           types.OrderRequest
+	clientOrderID string
+	createdAt     time.Time
+	limit         types.OrderRef
+	limitCanceled bool
+	remaining     decimal.Decimal
+}
+
+func (o *emulatedOrder) toOrder() types.Order {
+	return types.Order{
+		ClientOrderID: o.req.ClientOrderID,
+		Symbol:        o.req.Symbol,
+		Side:          o.req.Side,
+		Type:          o.req.Type,
+		TimeInForce:   o.req.TimeInForce,
+		Price:         o.req.Price,
+		TriggerPrice:  o.req.TriggerPrice,
+		Quantity:      o.req.Quantity,
+		Status:        types.OrderNew,
+		Raw:           "EMULATED",
+		CreatedAt:     o.createdAt,
+	}
+}
+
+// NewConditionalEmulator 创建条件单模拟器，market 提供触发判断用的最新价，interval 是轮询行情的间隔
+func NewConditionalEmulator(t types.Trading, market types.MarketData, interval time.Duration) *ConditionalEmulator {
+	return &ConditionalEmulator{
+		Trading:   t,
+		market:    market,
+		interval:  interval,
+		pending:   make(map[string]*emulatedOrder),
+		triggered: make(map[string]triggeredOrder),
+	}
+}
+
+// PlaceOrder 先按原生条件单提交，交易所返回 ErrNotSupported 时改为在客户端模拟。
+// 模拟的条件单没有交易所订单号，之后用 ClientOrderID 查询和撤销
+func (e *ConditionalEmulator) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	order, err := e.Trading.PlaceOrder(ctx, req)
+	if !req.Type.Conditional() || !errors.Is(err, types.ErrNotSupported) {
+		return order, err
+	}
+	return e.emulate(ctx, req)
+}
+
+func (e *ConditionalEmulator) emulate(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if err := req.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	pending := &emulatedOrder{req: req, clientOrderID: req.ClientOrderID, createdAt: time.Now()}
+	if req.ClientOrderID == "" {
+		pending.req.ClientOrderID = newClientOrderID(clientOrderIDLength)
+	}
+	if req.Type == types.OCOOrder {
+		limit := req
+		limit.Type, limit.ClientOrderID = types.LimitOrder, ""
+		limit.TriggerPrice, limit.StopLimitPrice = decimal.Zero, decimal.Zero
+		order, err := e.Trading.PlaceOrder(ctx, limit)
+		if err != nil {
+			return types.Order{}, err
+		}
+		pending.limit = types.OrderRef{ID: order.ID, ClientOrderID: order.ClientOrderID}
+	}
+
+	e.mu.Lock()
+	e.pending[pending.req.ClientOrderID] = pending
+	e.mu.Unlock()
+	return pending.toOrder(), nil
+}
+
+// PlaceOrders 逐个提交条件单，其余订单交给交易所的批量下单
+func (e *ConditionalEmulator) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	results := make([]types.OrderResult, len(reqs))
+	var plain []types.OrderRequest
+	var index []int
+	for i, req := range reqs {
+		if req.Type.Conditional() {
+			results[i].Order, results[i].Err = e.PlaceOrder(ctx, req)
+			continue
+		}
+		plain, index = append(plain, req), append(index, i)
+	}
+	if len(plain) == 0 {
+		return results, nil
+	}
+
+	placed, err := e.Trading.PlaceOrders(ctx, plain)
+	if err != nil {
+		return nil, err
+	}
+	for j, result := range placed {
+		results[index[j]] = result
+	}
+	return results, nil
+}
+
+// take 取出 ref 对应的待触发条件单
+func (e *ConditionalEmulator) take(ref types.OrderRef) *emulatedOrder {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	pending := e.pending[ref.ClientOrderID]
+	delete(e.pending, ref.ClientOrderID)
+	return pending
+}
+
+// restore 把触发后因可重试的错误没有提交的条件单放回，留到下一轮
+func (e *ConditionalEmulator) restore(pending *emulatedOrder) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pending[pending.req.ClientOrderID] = pending
+}
+
+// venueRef 把已触发条件单的 ref 换成交易所订单的 ref
+func (e *ConditionalEmulator) venueRef(ref types.OrderRef) types.OrderRef {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if venue, ok := e.triggered[ref.ClientOrderID]; ok && ref.ID == "" {
+		return venue.ref
+	}
+	return ref
+}
+
+// forget 在已触发条件单提交的订单结束后移除它的记录
+func (e *ConditionalEmulator) forget(ref types.OrderRef) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.triggered, ref.ClientOrderID)
+}
+
+func (e *ConditionalEmulator) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if pending := e.take(ref); pending != nil {
+		if pending.req.Type == types.OCOOrder && !pending.limitCanceled {
+			return e.Trading.CancelOrder(ctx, symbol, pending.limit)
+		}
+		return nil
+	}
+	if err := e.Trading.CancelOrder(ctx, symbol, e.venueRef(ref)); err != nil {
+		return err
+	}
+	e.forget(ref)
+	return nil
+}
+
+func (e *ConditionalEmulator) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	results := make([]types.CancelResult, len(refs))
+	var venue []types.OrderRef
+	var index []int
+	for i, ref := range refs {
+		if pending := e.take(ref); pending != nil {
+			results[i] = types.CancelResult{Symbol: symbol, ClientOrderID: ref.ClientOrderID}
+			if pending.req.Type == types.OCOOrder && !pending.limitCanceled {
+				results[i].Err = e.Trading.CancelOrder(ctx, symbol, pending.limit)
+			}
+			continue
+		}
+		venue, index = append(venue, e.venueRef(ref)), append(index, i)
+	}
+	if len(venue) == 0 {
+		return results, nil
+	}
+
+	cancelled, err := e.Trading.CancelOrders(ctx, symbol, venue)
+	if err != nil {
+		return nil, err
+	}
+	for j, result := range cancelled {
+		results[index[j]] = result
+	}
+	return results, nil
+}
+
+// CancelAllOrders 撤销交易所上的挂单并丢弃 symbol 上全部待触发的条件单
+func (e *ConditionalEmulator) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	results, err := e.Trading.CancelAllOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, pending := range e.pending {
+		if symbol == "" || pending.req.Symbol == symbol {
+			delete(e.pending, id)
+			results = append(results, types.CancelResult{Symbol: pending.req.Symbol, ClientOrderID: id})
+		}
+	}
+	return results, nil
+}
+
+// GetOrder 返回待触发的条件单，已触发的条件单返回触发后提交的订单
+func (e *ConditionalEmulator) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	e.mu.Lock()
+	pending := e.pending[ref.ClientOrderID]
+	e.mu.Unlock()
+	if pending != nil {
+		return pending.toOrder(), nil
+	}
+	order, err := e.Trading.GetOrder(ctx, symbol, e.venueRef(ref))
+	if err == nil && order.Status.Final() {
+		e.forget(ref)
+	}
+	return order, err
+}
+
+// GetOpenOrders 返回交易所上的挂单和待触发的条件单，OCO 的限价单只以 OCO 条件单的形式出现
+func (e *ConditionalEmulator) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	orders, err := e.Trading.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	legs := make(map[string]bool)
+	for _, pending := range e.pending {
+		if symbol != "" && pending.req.Symbol != symbol {
+			continue
+		}
+		if pending.limit.ID != "" {
+			legs[pending.limit.ID] = true
+		}
+		orders = append(orders, pending.toOrder())
+	}
+
+	open := orders[:0]
+	for _, order := range orders {
+		if !legs[order.ID] || order.Type == types.OCOOrder {
+			open = append(open, order)
+		}
+	}
+	return open, nil
+}
+
+// Run 每隔 interval 检查一次待触发的条件单，直到 ctx 结束
+func (e *ConditionalEmulator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			e.check(ctx)
+		}
+	}
+}
+
+// check 按交易对查询一次最新价并提交已触发的条件单，行情查询失败的交易对留到下一轮；
+// 同时查询已触发条件单提交的订单，订单结束后不再记录
+func (e *ConditionalEmulator) check(ctx context.Context) {
+	e.mu.Lock()
+	bySymbol := make(map[string][]*emulatedOrder)
+	for _, pending := range e.pending {
+		bySymbol[pending.req.Symbol] = append(bySymbol[pending.req.Symbol], pending)
+	}
+	triggered := make(map[string]triggeredOrder, len(e.triggered))
+	for id, venue := range e.triggered {
+		triggered[id] = venue
+	}
+	e.mu.Unlock()
+
+	for id, venue := range triggered {
+		if order, err := e.Trading.GetOrder(ctx, venue.symbol, venue.ref); err == nil && order.Status.Final() {
+			e.forget(types.OrderRef{ClientOrderID: id})
+		}
+	}
+
+	for symbol, orders := range bySymbol {
+		ticker, err := e.market.GetTicker(ctx, symbol)
+		if err != nil {
+			continue
+		}
+		for _, pending := range orders {
+			if pending.req.Triggered(ticker.Last) {
+				e.trigger(ctx, pending)
+			} else if pending.req.Type == types.OCOOrder && !pending.limitCanceled {
+				e.checkLimit(ctx, pending)
+			}
+		}
+	}
+}
+
+// checkLimit 在 OCO 的限价单已结束（成交或被撤销）时丢弃止损单
+func (e *ConditionalEmulator) checkLimit(ctx context.Context, pending *emulatedOrder) {
+	order, err := e.Trading.GetOrder(ctx, pending.req.Symbol, pending.limit)
+	if err != nil || !order.Status.Final() {
+		return
+	}
+	e.take(types.OrderRef{ClientOrderID: pending.req.ClientOrderID})
+}
+
+// trigger 提交已触发的条件单。OCO 先撤销限价单，再按未成交数量提交止损单，限价单已全部成交时不再下单。
+// 撤单或下单遇到可重试的错误时放回条件单，下一轮仍满足触发条件时再次提交
+func (e *ConditionalEmulator) trigger(ctx context.Context, pending *emulatedOrder) {
+	if e.take(types.OrderRef{ClientOrderID: pending.req.ClientOrderID}) == nil {
+		return
+	}
+
+	req := pending.req.TriggerOrder()
+	req.ClientOrderID = pending.clientOrderID
+	if pending.req.Type == types.OCOOrder {
+		if !pending.limitCanceled {
+			remaining, err := e.cancelLimit(ctx, pending)
+			if retryable(err) {
+				e.restore(pending)
+				return
+			}
+			if err != nil {
+				e.notify(pending.req, types.Order{}, err)
+				return
+			}
+			pending.limitCanceled, pending.remaining = true, remaining
+		}
+		if !pending.remaining.IsPositive() {
+			e.notify(pending.req, types.Order{}, nil)
+			return
+		}
+		req.Quantity = pending.remaining
+	}
+
+	order, err := e.Trading.PlaceOrder(ctx, req)
+	if retryable(err) {
+		e.restore(pending)
+		return
+	}
+	if err == nil {
+		e.mu.Lock()
+		e.triggered[pending.req.ClientOrderID] = triggeredOrder{
+			symbol: req.Symbol,
+			ref:    types.OrderRef{ID: order.ID, ClientOrderID: order.ClientOrderID},
+		}
+		e.mu.Unlock()
+	}
+	e.notify(pending.req, order, err)
+}
+
+// retryable 判断触发后的撤单或下单错误能否留到下一轮重试：请求超时、网络错误、5xx 或被限频。
+// 下单后按自定义订单号也查不到的订单（ErrOrderStatusUnknown）可能已经提交，不再重试
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, types.ErrOrderStatusUnknown) {
+		return false
+	}
+	var apiErr *types.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return ambiguous(err)
+}
+
+// cancelLimit 撤销 OCO 的限价单并返回其未成交数量
+func (e *ConditionalEmulator) cancelLimit(ctx context.Context, pending *emulatedOrder) (remaining decimal.Decimal, err error) {
+	if err := e.Trading.CancelOrder(ctx, pending.req.Symbol, pending.limit); err != nil && !errors.Is(err, types.ErrOrderNotFound) {
+		return remaining, err
+	}
+	order, err := e.Trading.GetOrder(ctx, pending.req.Symbol, pending.limit)
+	if err != nil {
+		return remaining, err
+	}
+	return pending.req.Quantity.Sub(order.FilledQuantity), nil
+}
+
+func (e *ConditionalEmulator) notify(req types.OrderRequest, order types.Order, err error) {
+	if e.OnTrigger != nil {
+		e.OnTrigger(req, order, err)
+	}
+}
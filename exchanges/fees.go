@@ -0,0 +1,55 @@
+package exchanges
+
+import (
+	"context"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// instrumentSymbols 返回当前产品线全部交易对的原生名称
+func instrumentSymbols(ctx context.Context, e instrumentSource) ([]string, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]string, 0, len(instruments))
+	for _, inst := range instruments {
+		symbols = append(symbols, inst.Symbol)
+	}
+	return symbols, nil
+}
+
+// uniformFees 把账户级别的统一费率展开到 symbols，symbols 为空时展开到当前产品线全部交易对
+func uniformFees(ctx context.Context, e instrumentSource, symbols []string, maker, taker decimal.Decimal) (map[string]types.TradingFee, error) {
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = instrumentSymbols(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+
+	fees := make(map[string]types.TradingFee, len(symbols))
+	for _, symbol := range symbols {
+		fees[symbol] = types.TradingFee{Symbol: symbol, Maker: maker, Taker: taker}
+	}
+	return fees, nil
+}
+
+// selectFees 从交易所返回的全量费率中取出 symbols，symbols 为空时全部返回
+func selectFees(e types.Exchange, all map[string]types.TradingFee, symbols []string) (map[string]types.TradingFee, error) {
+	if len(symbols) == 0 {
+		return all, nil
+	}
+
+	fees := make(map[string]types.TradingFee, len(symbols))
+	for _, symbol := range symbols {
+		fee, ok := all[symbol]
+		if !ok {
+			return nil, symbolNotFound(e, symbol)
+		}
+		fees[symbol] = fee
+	}
+	return fees, nil
+}
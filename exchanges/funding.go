@@ -0,0 +1,43 @@
+package exchanges
+
+import (
+	"sort"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// defaultFundingInterval 是大多数交易所默认的资金费率结算间隔
+const defaultFundingInterval = 8 * time.Hour
+
+// requirePerpetual 在非合约产品线下返回不支持的错误
+func requirePerpetual(e types.Exchange, market types.Market) error {
+	if market != types.USDMFutures && market != types.CoinMFutures {
+		return notSupported(e, "perpetuals on "+string(market))
+	}
+	return nil
+}
+
+// trimFunding 升序排列并按时间范围过滤；指定了 start 时保留最早的 limit 条，否则保留最近的 limit 条
+func trimFunding(records []types.FundingRecord, start, end time.Time, limit int) []types.FundingRecord {
+	sort.Slice(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	result := records[:0]
+	for _, r := range records {
+		if !start.IsZero() && r.Time.Before(start) {
+			continue
+		}
+		if !end.IsZero() && r.Time.After(end) {
+			continue
+		}
+		result = append(result, r)
+	}
+
+	if limit > 0 && len(result) > limit {
+		if !start.IsZero() {
+			return result[:limit]
+		}
+		return result[len(result)-limit:]
+	}
+	return result
+}
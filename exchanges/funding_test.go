@@ -0,0 +1,84 @@
+package exchanges
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFundingRate(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v5/public/funding-rate": `{"code":"0","msg":"","data":[{"instId":"BTC-USDT-SWAP","fundingRate":"0.0001","fundingTime":"1700006400000","nextFundingTime":"1700020800000","ts":"1700000000000"}]}`,
+	})
+
+	rate, err := NewOKX(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).GetFundingRate(context.Background(), "BTC-USDT-SWAP")
+	assert.NoError(t, err)
+	assert.True(t, dec("0.0001").Equal(rate.Rate))
+	assert.Equal(t, int64(1700006400000), rate.NextFundingTime.UnixMilli())
+	assert.Equal(t, 4*time.Hour, rate.Interval)
+
+	_, err = NewOKX(types.ExchangeConfig{BaseURL: server.URL}).GetFundingRate(context.Background(), "BTC-USDT")
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+
+	// HTX 的结算间隔由本期结算时间与最近一次结算时间之差得出
+	server = newTestServer(t, map[string]string{
+		"/linear-swap-api/v1/swap_funding_rate":            `{"status":"ok","data":{"contract_code":"BTC-USDT","funding_rate":"0.0002","funding_time":"1700006400000"},"ts":1700000000000}`,
+		"/linear-swap-api/v1/swap_historical_funding_rate": `{"status":"ok","data":{"data":[{"contract_code":"BTC-USDT","funding_rate":"0.0001","funding_time":"1699992000000"}]}}`,
+	})
+	rate, err = NewHuobi(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).GetFundingRate(context.Background(), "BTC-USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1700006400000), rate.NextFundingTime.UnixMilli())
+	assert.Equal(t, 4*time.Hour, rate.Interval)
+
+	// BTSE 不返回结算时间和间隔
+	server = newTestServer(t, map[string]string{
+		"/api/v2.1/market_summary": `[{"symbol":"BTCPFC","fundingRate":0.0001}]`,
+	})
+	rate, err = NewBTSE(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).GetFundingRate(context.Background(), "BTCPFC")
+	assert.NoError(t, err)
+	assert.True(t, dec("0.0001").Equal(rate.Rate))
+	assert.True(t, rate.NextFundingTime.IsZero())
+	assert.Zero(t, rate.Interval)
+}
+
+func TestGetFundingHistory(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v4/futures/usdt/funding_rate": `[{"t":1700028800,"r":"0.0003"},{"t":1700000000,"r":"-0.0001"},{"t":1700014400,"r":"0.0002"}]`,
+	})
+	gate := NewGate(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+
+	records, err := gate.GetFundingHistory(context.Background(), "BTC_USDT", time.Time{}, time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, int64(1700014400), records[0].Time.Unix())
+	assert.True(t, dec("0.0003").Equal(records[1].Rate))
+
+	records, err = gate.GetFundingHistory(context.Background(), "BTC_USDT", time.Unix(1700000000, 0), time.Time{}, 2)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.True(t, dec("-0.0001").Equal(records[0].Rate))
+	assert.Equal(t, "BTC_USDT", records[0].Symbol)
+}
+
+func TestGetMarkAndIndexPrice(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/dapi/v1/premiumIndex": `[{"symbol":"BTCUSD_PERP","pair":"BTCUSD","markPrice":"60010.5","indexPrice":"60000.1","lastFundingRate":"0.0001","nextFundingTime":1700006400000,"time":1700000000000}]`,
+	})
+	binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, Market: types.CoinMFutures})
+
+	mark, err := binance.GetMarkPrice(context.Background(), "BTCUSD_PERP")
+	assert.NoError(t, err)
+	assert.True(t, dec("60010.5").Equal(mark.Price))
+	assert.Equal(t, int64(1700000000000), mark.Timestamp.UnixMilli())
+
+	index, err := binance.GetIndexPrice(context.Background(), "BTCUSD_PERP")
+	assert.NoError(t, err)
+	assert.True(t, dec("60000.1").Equal(index.Price))
+
+	rate, err := binance.GetFundingRate(context.Background(), "BTCUSD_PERP")
+	assert.NoError(t, err)
+	assert.Equal(t, 8*time.Hour, rate.Interval)
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"github.com/hedeqiang/cryptoexchange/types"
 	"net/http"
@@ -15,7 +14,9 @@ import (
 )
 
 type Gate struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewGate(config types.ExchangeConfig) *Gate {
@@ -42,14 +43,14 @@ func (g *Gate) PrepareRequest(method, endpoint string, params map[string]interfa
 	}
 
 	body := []byte{}
-	if method == "GET" {
+	if method == "GET" || method == "DELETE" {
 		q := u.Query()
 		for k, v := range params {
 			q.Set(k, fmt.Sprint(v))
 		}
 		u.RawQuery = q.Encode()
 	} else {
-		body, err = json.Marshal(params)
+		body, err = jsonBody(params)
 		if err != nil {
 			return nil, err
 		}
@@ -62,13 +63,9 @@ func (g *Gate) PrepareRequest(method, endpoint string, params map[string]interfa
 
 	if signed {
 		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-		payloadToSign := method + "\n" + endpoint + "\n" + u.RawQuery + "\n"
-		if method != "GET" {
-			hash := sha512.New()
-			hash.Write(body)
-			payloadToSign += hex.EncodeToString(hash.Sum(nil)) + "\n"
-		}
-		payloadToSign += timestamp
+		// 没有请求体时对空字符串求哈希
+		bodyHash := sha512.Sum512(body)
+		payloadToSign := method + "\n" + u.Path + "\n" + u.RawQuery + "\n" + hex.EncodeToString(bodyHash[:]) + "\n" + timestamp
 
 		mac := hmac.New(sha512.New, []byte(g.config.APISecret))
 		mac.Write([]byte(payloadToSign))
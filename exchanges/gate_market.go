@@ -0,0 +1,479 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type gateTicker struct {
+	CurrencyPair   string      `json:"currency_pair"`
+	Contract       string      `json:"contract"`
+	Last           jsonDecimal `json:"last"`
+	LowestAsk      jsonDecimal `json:"lowest_ask"`
+	HighestBid     jsonDecimal `json:"highest_bid"`
+	BaseVolume     jsonDecimal `json:"base_volume"`
+	QuoteVolume    jsonDecimal `json:"quote_volume"`
+	Volume24hBase  jsonDecimal `json:"volume_24h_base"`
+	Volume24hQuote jsonDecimal `json:"volume_24h_quote"`
+}
+
+// settle 返回合约接口路径中的结算币种，现货返回空字符串
+func (g *Gate) settle() string {
+	switch g.config.MarketOrSpot() {
+	case types.USDMFutures:
+		return "usdt"
+	case types.CoinMFutures:
+		return "btc"
+	default:
+		return ""
+	}
+}
+
+func (g *Gate) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	endpoint := "/api/v4/spot/tickers"
+	params := map[string]interface{}{"currency_pair": symbol}
+
+	switch g.config.MarketOrSpot() {
+	case types.Options:
+		return types.Ticker{}, notSupported(g, "options ticker")
+	case types.USDMFutures, types.CoinMFutures:
+		endpoint = "/api/v4/futures/" + g.settle() + "/tickers"
+		params = map[string]interface{}{"contract": symbol}
+	}
+
+	var tickers []gateTicker
+	if err := sendRequest(ctx, g, "GET", endpoint, params, false, &tickers); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(tickers) == 0 {
+		return types.Ticker{}, fmt.Errorf("gate ticker %s not found", symbol)
+	}
+
+	return tickers[0].toTicker(), nil
+}
+
+func (t gateTicker) toTicker() types.Ticker {
+	if t.Contract != "" {
+		return types.Ticker{
+			Symbol:      t.Contract,
+			Last:        t.Last.Decimal,
+			Bid:         t.HighestBid.Decimal,
+			Ask:         t.LowestAsk.Decimal,
+			Volume:      t.Volume24hBase.Decimal,
+			QuoteVolume: t.Volume24hQuote.Decimal,
+		}
+	}
+
+	return types.Ticker{
+		Symbol:      t.CurrencyPair,
+		Last:        t.Last.Decimal,
+		Bid:         t.HighestBid.Decimal,
+		Ask:         t.LowestAsk.Decimal,
+		Volume:      t.BaseVolume.Decimal,
+		QuoteVolume: t.QuoteVolume.Decimal,
+	}
+}
+
+type gateOrderBook struct {
+	ID      int64           `json:"id"`
+	Current int64           `json:"current"`
+	Asks    [][]jsonDecimal `json:"asks"`
+	Bids    [][]jsonDecimal `json:"bids"`
+}
+
+// gateFuturesOrderBook 的档位为 {p: 价格, s: 张数}，current 为带小数的秒
+type gateFuturesOrderBook struct {
+	ID      int64       `json:"id"`
+	Current jsonDecimal `json:"current"`
+	Asks    []gateLevel `json:"asks"`
+	Bids    []gateLevel `json:"bids"`
+}
+
+type gateLevel struct {
+	P jsonDecimal `json:"p"`
+	S jsonDecimal `json:"s"`
+}
+
+func gateLevels(rows []gateLevel) []types.PriceLevel {
+	levels := make([]types.PriceLevel, 0, len(rows))
+	for _, row := range rows {
+		levels = append(levels, types.PriceLevel{Price: row.P.Decimal, Size: row.S.Decimal})
+	}
+	return levels
+}
+
+// GetOrderBook 中合约订单簿的数量单位是合约张数
+func (g *Gate) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if g.config.MarketOrSpot() != types.Spot {
+		params := map[string]interface{}{
+			"contract": symbol,
+			"limit":    clampDepth(depth, 300),
+			"with_id":  true,
+		}
+
+		var b gateFuturesOrderBook
+		if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/order_book", params, false, &b); err != nil {
+			return types.OrderBook{}, err
+		}
+
+		return finalizeOrderBook(types.OrderBook{
+			Symbol:    symbol,
+			Bids:      gateLevels(b.Bids),
+			Asks:      gateLevels(b.Asks),
+			Sequence:  b.ID,
+			Timestamp: unixTime(b.Current, time.Second),
+		}, depth), nil
+	}
+
+	params := map[string]interface{}{
+		"currency_pair": symbol,
+		"limit":         clampDepth(depth, 100),
+		"with_id":       true,
+	}
+
+	var b gateOrderBook
+	if err := sendRequest(ctx, g, "GET", "/api/v4/spot/order_book", params, false, &b); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(b.Bids),
+		Asks:      levelsFromRows(b.Asks),
+		Sequence:  b.ID,
+		Timestamp: msToTime(b.Current),
+	}, depth), nil
+}
+
+// Gate 的 30d 不是自然月，因此不映射 1M
+var gateIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval5m: "5m", types.Interval15m: "15m", types.Interval30m: "30m",
+	types.Interval1h: "1h", types.Interval4h: "4h", types.Interval8h: "8h", types.Interval1d: "1d",
+	types.Interval1w: "7d",
+}
+
+// gateFuturesCandle 中 t 为秒，v 为合约张数，sum 为以计价币计的成交额
+type gateFuturesCandle struct {
+	T   jsonDecimal `json:"t"`
+	V   jsonDecimal `json:"v"`
+	C   jsonDecimal `json:"c"`
+	H   jsonDecimal `json:"h"`
+	L   jsonDecimal `json:"l"`
+	O   jsonDecimal `json:"o"`
+	Sum jsonDecimal `json:"sum"`
+}
+
+// GetCandles 中 Gate 现货返回 [t(秒), 成交额, close, high, low, open, 成交量, 是否完结]，合约返回对象且成交量为合约张数；
+// 指定时间范围时不能同时传 limit
+func (g *Gate) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	spot := g.config.MarketOrSpot() == types.Spot
+
+	return fetchCandles(ctx, g, gateIntervals, g.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{"interval": venueInterval}
+			if !start.IsZero() {
+				params["from"] = start.Unix()
+			}
+			if !end.IsZero() {
+				params["to"] = end.Unix()
+			}
+			if start.IsZero() && end.IsZero() && limit > 0 {
+				params["limit"] = clampDepth(limit, 1000)
+			}
+
+			if !spot {
+				params["contract"] = symbol
+
+				var rows []gateFuturesCandle
+				if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/candlesticks", params, false, &rows); err != nil {
+					return nil, err
+				}
+
+				candles := make([]types.Candle, 0, len(rows))
+				for _, c := range rows {
+					candles = append(candles, types.Candle{
+						OpenTime:    unixTime(c.T, time.Second),
+						Open:        c.O.Decimal,
+						High:        c.H.Decimal,
+						Low:         c.L.Decimal,
+						Close:       c.C.Decimal,
+						Volume:      c.V.Decimal,
+						QuoteVolume: c.Sum.Decimal,
+					})
+				}
+				return candles, nil
+			}
+
+			params["currency_pair"] = symbol
+
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, g, "GET", "/api/v4/spot/candlesticks", params, false, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, row := range rows {
+				if len(row) < 7 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, unixTime(row[0], time.Second), 5, 3, 4, 2, 6, 1))
+			}
+			return candles, nil
+		})
+}
+
+type gateTrade struct {
+	ID           string      `json:"id"`
+	CreateTimeMs jsonDecimal `json:"create_time_ms"`
+	Side         string      `json:"side"`
+	Amount       jsonDecimal `json:"amount"`
+	Price        jsonDecimal `json:"price"`
+}
+
+// gateFuturesTrade 的 size 为合约张数，负数表示 taker 卖出
+type gateFuturesTrade struct {
+	ID           int64       `json:"id"`
+	CreateTimeMs jsonDecimal `json:"create_time_ms"`
+	Size         jsonDecimal `json:"size"`
+	Price        jsonDecimal `json:"price"`
+}
+
+// GetRecentTrades 单次最多返回 1000 笔，合约的 Size 为合约张数
+func (g *Gate) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	params := map[string]interface{}{}
+	if limit > 0 {
+		params["limit"] = clampDepth(limit, 1000)
+	}
+
+	if g.config.MarketOrSpot() != types.Spot {
+		params["contract"] = symbol
+
+		var rows []gateFuturesTrade
+		if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/trades", params, false, &rows); err != nil {
+			return nil, err
+		}
+
+		trades := make([]types.Trade, 0, len(rows))
+		for _, t := range rows {
+			side := types.Buy
+			if t.Size.IsNegative() {
+				side = types.Sell
+			}
+			trades = append(trades, types.Trade{
+				ID:    strconv.FormatInt(t.ID, 10),
+				Price: t.Price.Decimal,
+				Size:  t.Size.Abs(),
+				Side:  side,
+				Time:  unixTime(t.CreateTimeMs, time.Millisecond),
+			})
+		}
+		return sortTrades(trades, limit), nil
+	}
+
+	params["currency_pair"] = symbol
+
+	var rows []gateTrade
+	if err := sendRequest(ctx, g, "GET", "/api/v4/spot/trades", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, types.Trade{
+			ID:    t.ID,
+			Price: t.Price.Decimal,
+			Size:  t.Amount.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  unixTime(t.CreateTimeMs, time.Millisecond),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+type gateCurrencyPair struct {
+	ID              string      `json:"id"`
+	Base            string      `json:"base"`
+	Quote           string      `json:"quote"`
+	MinBaseAmount   jsonDecimal `json:"min_base_amount"`
+	MinQuoteAmount  jsonDecimal `json:"min_quote_amount"`
+	MaxBaseAmount   jsonDecimal `json:"max_base_amount"`
+	AmountPrecision int         `json:"amount_precision"`
+	Precision       int         `json:"precision"`
+	TradeStatus     string      `json:"trade_status"`
+}
+
+// gateContract 的数量单位为张，quanto_multiplier 为每张对应的基础币数量
+type gateContract struct {
+	Name             string      `json:"name"`
+	QuantoMultiplier jsonDecimal `json:"quanto_multiplier"`
+	OrderPriceRound  jsonDecimal `json:"order_price_round"`
+	OrderSizeMin     jsonDecimal `json:"order_size_min"`
+	OrderSizeMax     jsonDecimal `json:"order_size_max"`
+	InDelisting      bool        `json:"in_delisting"`
+}
+
+// GetInstruments 中现货只有 tradable 视为可交易，只允许买入或卖出的交易对视为暂停
+func (g *Gate) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return g.instruments.get(ctx, g.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		if g.config.MarketOrSpot() == types.Spot {
+			var pairs []gateCurrencyPair
+			if err := sendRequest(ctx, g, "GET", "/api/v4/spot/currency_pairs", nil, false, &pairs); err != nil {
+				return nil, err
+			}
+
+			instruments := make([]types.Instrument, 0, len(pairs))
+			for _, p := range pairs {
+				instruments = append(instruments, types.Instrument{
+					Symbol:      p.ID,
+					Base:        p.Base,
+					Quote:       p.Quote,
+					TickSize:    precisionStep(p.Precision),
+					StepSize:    precisionStep(p.AmountPrecision),
+					MinQty:      p.MinBaseAmount.Decimal,
+					MaxQty:      p.MaxBaseAmount.Decimal,
+					MinNotional: p.MinQuoteAmount.Decimal,
+					Status:      tradingStatus(p.TradeStatus == "tradable"),
+				})
+			}
+			return instruments, nil
+		}
+
+		var contracts []gateContract
+		if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/contracts", nil, false, &contracts); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(contracts))
+		for _, c := range contracts {
+			base, quote, _ := strings.Cut(c.Name, "_")
+			// 反向合约的 quanto_multiplier 为 0，每张合约为 1 美元
+			contractSize := c.QuantoMultiplier.Decimal
+			if !contractSize.IsPositive() {
+				contractSize = decimal.NewFromInt(1)
+			}
+
+			instruments = append(instruments, types.Instrument{
+				Symbol:       c.Name,
+				Base:         base,
+				Quote:        quote,
+				TickSize:     c.OrderPriceRound.Decimal,
+				StepSize:     decimal.NewFromInt(1),
+				MinQty:       c.OrderSizeMin.Decimal,
+				MaxQty:       c.OrderSizeMax.Decimal,
+				ContractSize: contractSize,
+				Status:       tradingStatus(!c.InDelisting),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// gateContractDetail 中时间与间隔均以秒为单位
+type gateContractDetail struct {
+	Name             string      `json:"name"`
+	FundingRate      jsonDecimal `json:"funding_rate"`
+	FundingNextApply int64       `json:"funding_next_apply"`
+	FundingInterval  int64       `json:"funding_interval"`
+	MarkPrice        jsonDecimal `json:"mark_price"`
+	IndexPrice       jsonDecimal `json:"index_price"`
+}
+
+func (g *Gate) contract(ctx context.Context, symbol string) (gateContractDetail, error) {
+	if err := requirePerpetual(g, g.config.MarketOrSpot()); err != nil {
+		return gateContractDetail{}, err
+	}
+
+	var c gateContractDetail
+	err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/contracts/"+symbol, nil, false, &c)
+	return c, err
+}
+
+// GetFundingRate 的 symbol 为 Gate 合约名称，例如 BTC_USDT
+func (g *Gate) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	c, err := g.contract(ctx, symbol)
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+
+	return types.FundingRate{
+		Symbol:          c.Name,
+		Rate:            c.FundingRate.Decimal,
+		NextFundingTime: time.Unix(c.FundingNextApply, 0),
+		Interval:        time.Duration(c.FundingInterval) * time.Second,
+	}, nil
+}
+
+// GetFundingHistory 单次最多返回 1000 条，from、to 以秒为单位
+func (g *Gate) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(g, g.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"contract": symbol,
+		"limit":    clampDepth(limit, 1000),
+	}
+	if !start.IsZero() {
+		params["from"] = start.Unix()
+	}
+	if !end.IsZero() {
+		params["to"] = end.Unix()
+	}
+
+	var rows []struct {
+		T int64       `json:"t"`
+		R jsonDecimal `json:"r"`
+	}
+	if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/funding_rate", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(rows))
+	for _, r := range rows {
+		records = append(records, types.FundingRecord{Symbol: symbol, Rate: r.R.Decimal, Time: time.Unix(r.T, 0)})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+func (g *Gate) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	c, err := g.contract(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: c.Name, Price: c.MarkPrice.Decimal}, nil
+}
+
+func (g *Gate) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	c, err := g.contract(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: c.Name, Price: c.IndexPrice.Decimal}, nil
+}
+
+func (g *Gate) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	endpoint := "/api/v4/spot/tickers"
+	switch g.config.MarketOrSpot() {
+	case types.Options:
+		return nil, notSupported(g, "options ticker")
+	case types.USDMFutures, types.CoinMFutures:
+		endpoint = "/api/v4/futures/" + g.settle() + "/tickers"
+	}
+
+	var rows []gateTicker
+	if err := sendRequest(ctx, g, "GET", endpoint, nil, false, &rows); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(rows))
+	for _, t := range rows {
+		tickers = append(tickers, t.toTicker())
+	}
+	return keyTickers(ctx, g, tickers)
+}
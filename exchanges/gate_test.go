@@ -0,0 +1,58 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate_PrepareRequest(t *testing.T) {
+	gate := NewGate(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+	})
+
+	// SIGN = HMAC-SHA512(method\npath\nquery\nhex(SHA512(body))\ntimestamp)，没有请求体时对空字符串求哈希
+	sign := func(timestamp, method, path, query, body string) string {
+		bodyHash := sha512.Sum512([]byte(body))
+		mac := hmac.New(sha512.New, []byte("test_secret"))
+		mac.Write([]byte(method + "\n" + path + "\n" + query + "\n" + hex.EncodeToString(bodyHash[:]) + "\n" + timestamp))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req, err := gate.PrepareRequest("GET", "/api/v4/spot/orders", map[string]interface{}{"currency_pair": "BTC_USDT", "status": "open"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "currency_pair=BTC_USDT&status=open", req.URL.RawQuery)
+		assert.Equal(t, "test_key", req.Header.Get("KEY"))
+
+		timestamp := req.Header.Get("Timestamp")
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, sign(timestamp, "GET", "/api/v4/spot/orders", "currency_pair=BTC_USDT&status=open", ""), req.Header.Get("SIGN"))
+	})
+
+	t.Run("DELETE sends params in the query", func(t *testing.T) {
+		req, err := gate.PrepareRequest("DELETE", "/api/v4/spot/orders/123", map[string]interface{}{"currency_pair": "BTC_USDT"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "currency_pair=BTC_USDT", req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.Empty(t, body)
+		assert.Equal(t, sign(req.Header.Get("Timestamp"), "DELETE", "/api/v4/spot/orders/123", "currency_pair=BTC_USDT", ""), req.Header.Get("SIGN"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := gate.PrepareRequest("POST", "/api/v4/spot/orders", map[string]interface{}{"currency_pair": "BTC_USDT", "amount": "0.1"}, true)
+		assert.NoError(t, err)
+		assert.Empty(t, req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.JSONEq(t, `{"currency_pair":"BTC_USDT","amount":"0.1"}`, string(body))
+		assert.Equal(t, sign(req.Header.Get("Timestamp"), "POST", "/api/v4/spot/orders", "", string(body)), req.Header.Get("SIGN"))
+	})
+}
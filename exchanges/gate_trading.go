@@ -0,0 +1,843 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type gateWalletFee struct {
+	MakerFee        jsonDecimal `json:"maker_fee"`
+	TakerFee        jsonDecimal `json:"taker_fee"`
+	FuturesMakerFee jsonDecimal `json:"futures_maker_fee"`
+	FuturesTakerFee jsonDecimal `json:"futures_taker_fee"`
+}
+
+func (g *Gate) walletFee(ctx context.Context, params map[string]interface{}) (gateWalletFee, error) {
+	var fee gateWalletFee
+	err := sendRequest(ctx, g, "GET", "/api/v4/wallet/fee", params, true, &fee)
+	return fee, err
+}
+
+// GetTradingFees 中现货按交易对逐个查询 /wallet/fee；合约费率取决于账户等级，统一适用于全部合约
+func (g *Gate) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	switch g.config.MarketOrSpot() {
+	case types.Options:
+		return nil, notSupported(g, "options trading fees")
+	case types.USDMFutures, types.CoinMFutures:
+		fee, err := g.walletFee(ctx, map[string]interface{}{"settle": g.settle()})
+		if err != nil {
+			return nil, err
+		}
+		return uniformFees(ctx, g, symbols, fee.FuturesMakerFee.Decimal, fee.FuturesTakerFee.Decimal)
+	}
+
+	if len(symbols) == 0 {
+		fee, err := g.walletFee(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		return uniformFees(ctx, g, symbols, fee.MakerFee.Decimal, fee.TakerFee.Decimal)
+	}
+
+	return fanOut(ctx, symbols, func(ctx context.Context, symbol string) (types.TradingFee, error) {
+		fee, err := g.walletFee(ctx, map[string]interface{}{"currency_pair": symbol})
+		if err != nil {
+			return types.TradingFee{}, err
+		}
+		return types.TradingFee{Symbol: symbol, Maker: fee.MakerFee.Decimal, Taker: fee.TakerFee.Decimal}, nil
+	})
+}
+
+// gateSpotOrder 是现货订单，text 为自定义订单号；市价买单的 amount、left 以计价币计，
+// filled_amount 始终是基础币的成交数量
+type gateSpotOrder struct {
+	ID           string      `json:"id"`
+	Text         string      `json:"text"`
+	CreateTimeMs jsonDecimal `json:"create_time_ms"`
+	UpdateTimeMs jsonDecimal `json:"update_time_ms"`
+	CurrencyPair string      `json:"currency_pair"`
+	Status       string      `json:"status"`
+	FinishAs     string      `json:"finish_as"`
+	Type         string      `json:"type"`
+	Side         string      `json:"side"`
+	Amount       jsonDecimal `json:"amount"`
+	Price        jsonDecimal `json:"price"`
+	TimeInForce  string      `json:"time_in_force"`
+	Left         jsonDecimal `json:"left"`
+	FilledAmount jsonDecimal `json:"filled_amount"`
+	FilledTotal  jsonDecimal `json:"filled_total"`
+	AvgDealPrice jsonDecimal `json:"avg_deal_price"`
+	Fee          jsonDecimal `json:"fee"`
+	FeeCurrency  string      `json:"fee_currency"`
+}
+
+// gateExpired 是因有效方式未能成交而结束的 finish_as 取值
+var gateExpired = map[string]bool{"ioc": true, "fok": true, "poc": true}
+
+// gateStatus 把 open 按成交数量细分，已撤销的订单再按 finish_as 区分有效方式导致的过期
+func gateStatus(status, finishAs string, filled decimal.Decimal) types.OrderStatus {
+	switch status {
+	case "open":
+		return openStatus(filled)
+	case "closed":
+		return types.OrderFilled
+	case "cancelled", "finished":
+		if finishAs == "filled" {
+			return types.OrderFilled
+		}
+		if gateExpired[finishAs] {
+			return types.OrderExpired
+		}
+		return types.OrderCanceled
+	}
+	return types.OrderStatusUnknown
+}
+
+func (o gateSpotOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:            o.ID,
+		ClientOrderID: o.Text,
+		Symbol:        o.CurrencyPair,
+		Side:          parseSide(o.Side),
+		Type:          types.OrderType(strings.ToUpper(o.Type)),
+		Price:         o.Price.Decimal,
+		Quantity:      o.Amount.Decimal,
+
+		FilledQuantity: o.FilledAmount.Decimal,
+		AveragePrice:   o.AvgDealPrice.Decimal,
+		Fee:            o.Fee.Decimal,
+		FeeAsset:       o.FeeCurrency,
+		Raw:            o.Status,
+		CreatedAt:      msToTime(o.CreateTimeMs.IntPart()),
+		UpdatedAt:      msToTime(o.UpdateTimeMs.IntPart()),
+	}
+
+	// 市价买单的 amount 是计价币金额
+	if order.Type == types.MarketOrder {
+		order.Price = decimal.Zero
+		if order.Side == types.Buy {
+			order.Quantity, order.QuoteQuantity = decimal.Zero, o.Amount.Decimal
+		}
+	} else {
+		order.TimeInForce = gateTimeInForce(o.TimeInForce)
+		if order.FilledQuantity.IsZero() {
+			order.FilledQuantity = o.Amount.Sub(o.Left.Decimal)
+		}
+	}
+	if order.AveragePrice.IsZero() {
+		order.AveragePrice = averagePrice(o.FilledTotal.Decimal, order.FilledQuantity)
+	}
+	if o.Status != "" {
+		order.Status = gateStatus(o.Status, o.FinishAs, order.FilledQuantity)
+	}
+	return order
+}
+
+// gateFuturesOrder 是合约订单，size、left 为张数，负数表示卖出；fill_price 是成交均价
+type gateFuturesOrder struct {
+	ID           int64       `json:"id"`
+	Contract     string      `json:"contract"`
+	Size         int64       `json:"size"`
+	Left         int64       `json:"left"`
+	Price        jsonDecimal `json:"price"`
+	FillPrice    jsonDecimal `json:"fill_price"`
+	Tif          string      `json:"tif"`
+	Text         string      `json:"text"`
+	Status       string      `json:"status"`
+	FinishAs     string      `json:"finish_as"`
+	IsReduceOnly bool        `json:"is_reduce_only"`
+	CreateTime   jsonDecimal `json:"create_time"`
+	FinishTime   jsonDecimal `json:"finish_time"`
+}
+
+func (o gateFuturesOrder) toOrder() types.Order {
+	filled := decimal.NewFromInt(o.Size - o.Left).Abs()
+	order := types.Order{
+		ID:            strconv.FormatInt(o.ID, 10),
+		ClientOrderID: o.Text,
+		Symbol:        o.Contract,
+		Side:          types.Buy,
+		Type:          types.LimitOrder,
+		TimeInForce:   gateTimeInForce(o.Tif),
+		Price:         o.Price.Decimal,
+		Quantity:      decimal.NewFromInt(o.Size).Abs(),
+		ReduceOnly:    o.IsReduceOnly,
+
+		FilledQuantity: filled,
+		AveragePrice:   o.FillPrice.Decimal,
+		Raw:            o.Status,
+		CreatedAt:      unixTime(o.CreateTime, time.Second),
+	}
+	if o.Size < 0 {
+		order.Side = types.Sell
+	}
+	// 价格为 0 的 IOC 订单是市价单
+	if o.Price.IsZero() {
+		order.Type, order.TimeInForce = types.MarketOrder, ""
+	}
+	if o.Status != "" {
+		order.Status = gateStatus(o.Status, o.FinishAs, filled)
+	}
+	if o.FinishTime.IsPositive() {
+		order.UpdatedAt = unixTime(o.FinishTime, time.Second)
+	}
+	return order
+}
+
+// gateTimeInForce 把 gtc/ioc/fok/poc 转换为统一取值，poc（只做 maker）视为 GTC
+func gateTimeInForce(tif string) types.TimeInForce {
+	switch tif {
+	case "ioc":
+		return types.IOC
+	case "fok":
+		return types.FOK
+	default:
+		return types.GTC
+	}
+}
+
+// gateTif 返回下单使用的 time_in_force，post-only 为 poc，市价单为 ioc
+func gateTif(req types.OrderRequest) string {
+	if req.Type == types.MarketOrder {
+		return "ioc"
+	}
+	if req.PostOnly {
+		return "poc"
+	}
+	return strings.ToLower(string(timeInForce(req)))
+}
+
+// gateTextLength 是自动生成的自定义订单号长度，Gate 要求去掉 t- 前缀后不超过 28 个字符
+const gateTextLength = 28
+
+// gateText 返回 Gate 要求的自定义订单号，必须以 t- 开头，缺少时自动补上
+func gateText(clientOrderID string) string {
+	if clientOrderID == "" || strings.HasPrefix(clientOrderID, "t-") {
+		return clientOrderID
+	}
+	return "t-" + clientOrderID
+}
+
+// orderParams 返回下单接口与参数。现货市价买单只能按计价币金额下单，市价卖单只能按基础币数量下单；
+// 合约数量为整数张数，卖出用负数表示，市价单的价格为 0
+func (g *Gate) orderParams(req types.OrderRequest) (string, map[string]interface{}, error) {
+	// 价格触发单走独立的 price_orders 接口，订单号与普通订单不互通，暂不支持
+	if req.Type.Conditional() {
+		return "", nil, notSupported(g, "conditional orders")
+	}
+	switch g.config.MarketOrSpot() {
+	case types.Options:
+		return "", nil, notSupported(g, "options orders")
+	case types.USDMFutures, types.CoinMFutures:
+		if req.QuoteQuantity.IsPositive() {
+			return "", nil, notSupported(g, "futures orders by quote quantity")
+		}
+		if !isInteger(req.Quantity) {
+			return "", nil, fmt.Errorf("%w: gate futures size must be a whole number of contracts", types.ErrInvalidOrder)
+		}
+
+		size := req.Quantity.IntPart()
+		if req.Side == types.Sell {
+			size = -size
+		}
+		params := map[string]interface{}{
+			"contract":    req.Symbol,
+			"size":        size,
+			"price":       "0",
+			"tif":         gateTif(req),
+			"reduce_only": req.ReduceOnly,
+		}
+		if req.Type == types.LimitOrder {
+			params["price"] = req.Price.String()
+		}
+		if text := gateText(req.ClientOrderID); text != "" {
+			params["text"] = text
+		}
+		return "/api/v4/futures/" + g.settle() + "/orders", params, nil
+	}
+
+	if req.ReduceOnly {
+		return "", nil, notSupported(g, "reduce-only spot orders")
+	}
+
+	amount := req.Quantity
+	if req.Type == types.MarketOrder {
+		if req.Side == types.Buy && !req.QuoteQuantity.IsPositive() {
+			return "", nil, notSupported(g, "market buy orders by base quantity")
+		}
+		if req.Side == types.Sell && req.QuoteQuantity.IsPositive() {
+			return "", nil, notSupported(g, "market sell orders by quote quantity")
+		}
+		if req.Side == types.Buy {
+			amount = req.QuoteQuantity
+		}
+	}
+
+	params := map[string]interface{}{
+		"currency_pair": req.Symbol,
+		"side":          lowerSide(req.Side),
+		"type":          strings.ToLower(string(req.Type)),
+		"account":       "spot",
+		"amount":        amount.String(),
+		"time_in_force": gateTif(req),
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+	}
+	if text := gateText(req.ClientOrderID); text != "" {
+		params["text"] = text
+	}
+	return "/api/v4/spot/orders", params, nil
+}
+
+// gateBatchSize 是 batch_orders 一次最多提交的订单数，gateCancelBatchSize 是批量撤单的上限
+const (
+	gateBatchSize       = 10
+	gateCancelBatchSize = 20
+)
+
+// gateBatchStatus 是批量接口中单个订单的处理结果，succeeded 为 false 时 label 和 message 是失败原因
+type gateBatchStatus struct {
+	Succeeded bool   `json:"succeeded"`
+	Label     string `json:"label"`
+	Message   string `json:"message"`
+}
+
+func (s gateBatchStatus) err() error {
+	if s.Succeeded {
+		return nil
+	}
+	return batchError(s.Label, s.Message)
+}
+
+func (g *Gate) placer() placer {
+	return placer{t: g, config: g.config, idLength: gateTextLength, place: g.placeOrder, batch: g.placeBatch, batchSize: gateBatchSize}
+}
+
+func (g *Gate) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return g.placer().placeOrder(ctx, req)
+}
+
+func (g *Gate) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if g.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(g, "options orders")
+	}
+	return g.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeBatch 通过现货或合约的 batch_orders 提交一批订单，请求体是订单参数组成的数组
+func (g *Gate) placeBatch(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	build := func(req types.OrderRequest) (map[string]interface{}, error) {
+		_, params, err := g.orderParams(req)
+		return params, err
+	}
+	return buildBatch(ctx, reqs, build, func(ctx context.Context, items []map[string]interface{}, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+		endpoint := strings.TrimSuffix(g.orderPath(), "orders") + "batch_orders"
+		results := make([]types.OrderResult, len(reqs))
+
+		if g.config.MarketOrSpot() == types.Spot {
+			var rows []struct {
+				gateSpotOrder
+				gateBatchStatus
+			}
+			if err := sendRequest(ctx, g, "POST", endpoint, map[string]interface{}{bodyParam: items}, true, &rows); err != nil {
+				return nil, err
+			}
+			for i := range reqs {
+				if i >= len(rows) {
+					results[i].Err = errMissingResult
+				} else if results[i].Err = rows[i].err(); results[i].Err == nil {
+					results[i].Order = rows[i].toOrder()
+				}
+			}
+			return results, nil
+		}
+
+		var rows []struct {
+			gateFuturesOrder
+			gateBatchStatus
+		}
+		if err := sendRequest(ctx, g, "POST", endpoint, map[string]interface{}{bodyParam: items}, true, &rows); err != nil {
+			return nil, err
+		}
+		for i := range reqs {
+			if i >= len(rows) {
+				results[i].Err = errMissingResult
+			} else if results[i].Err = rows[i].err(); results[i].Err == nil {
+				results[i].Order = rows[i].toOrder()
+			}
+		}
+		return results, nil
+	})
+}
+
+func (g *Gate) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	endpoint, params, err := g.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	if g.config.MarketOrSpot() == types.Spot {
+		var order gateSpotOrder
+		if err := sendRequest(ctx, g, "POST", endpoint, params, true, &order); err != nil {
+			return types.Order{}, err
+		}
+		return order.toOrder(), nil
+	}
+
+	var order gateFuturesOrder
+	if err := sendRequest(ctx, g, "POST", endpoint, params, true, &order); err != nil {
+		return types.Order{}, err
+	}
+	return order.toOrder(), nil
+}
+
+// orderPath 返回订单接口路径，现货与合约分别为 /spot/orders 和 /futures/{settle}/orders
+func (g *Gate) orderPath() string {
+	if g.config.MarketOrSpot() == types.Spot {
+		return "/api/v4/spot/orders"
+	}
+	return "/api/v4/futures/" + g.settle() + "/orders"
+}
+
+// CancelOrder 中 Gate 的订单号位置也接受以 t- 开头的自定义订单号
+func (g *Gate) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if g.config.MarketOrSpot() == types.Options {
+		return notSupported(g, "options orders")
+	}
+
+	id := ref.ID
+	if id == "" {
+		id = gateText(ref.ClientOrderID)
+	}
+	endpoint := g.orderPath() + "/" + url.PathEscape(id)
+
+	if g.config.MarketOrSpot() == types.Spot {
+		var order gateSpotOrder
+		return sendRequest(ctx, g, "DELETE", endpoint, map[string]interface{}{"currency_pair": symbol}, true, &order)
+	}
+	var order gateFuturesOrder
+	return sendRequest(ctx, g, "DELETE", endpoint, nil, true, &order)
+}
+
+// AmendOrder 中现货使用 PATCH、合约使用 PUT 原地修改订单，订单号不变。
+// 合约数量带方向，修改数量时先查询原订单的方向
+func (g *Gate) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	id := req.Ref.ID
+	if id == "" {
+		id = gateText(req.Ref.ClientOrderID)
+	}
+	endpoint := g.orderPath() + "/" + url.PathEscape(id)
+
+	params := map[string]interface{}{}
+	if req.Price.IsPositive() {
+		params["price"] = req.Price.String()
+	}
+
+	switch g.config.MarketOrSpot() {
+	case types.Options:
+		return types.AmendResult{}, notSupported(g, "options orders")
+	case types.Spot:
+		params["currency_pair"] = req.Symbol
+		if req.Quantity.IsPositive() {
+			params["amount"] = req.Quantity.String()
+		}
+
+		var order gateSpotOrder
+		if err := sendRequest(ctx, g, "PATCH", endpoint, params, true, &order); err != nil {
+			return types.AmendResult{}, err
+		}
+		return types.AmendResult{Order: order.toOrder()}, nil
+	}
+
+	if req.Quantity.IsPositive() {
+		if !isInteger(req.Quantity) {
+			return types.AmendResult{}, fmt.Errorf("%w: gate futures size must be a whole number of contracts", types.ErrInvalidOrder)
+		}
+		orig, err := g.GetOrder(ctx, req.Symbol, req.Ref)
+		if err != nil {
+			return types.AmendResult{}, err
+		}
+		size := req.Quantity.IntPart()
+		if orig.Side == types.Sell {
+			size = -size
+		}
+		params["size"] = size
+	}
+
+	var order gateFuturesOrder
+	if err := sendRequest(ctx, g, "PUT", endpoint, params, true, &order); err != nil {
+		return types.AmendResult{}, err
+	}
+	return types.AmendResult{Order: order.toOrder()}, nil
+}
+
+// CancelOrders 中现货使用 cancel_batch_orders，合约使用 batch_cancel_orders
+func (g *Gate) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if g.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(g, "options orders")
+	}
+
+	return cancelOrders(ctx, symbol, refs, gateCancelBatchSize, func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		ids := make([]string, len(refs))
+		for i, ref := range refs {
+			ids[i] = ref.ID
+			if ids[i] == "" {
+				ids[i] = gateText(ref.ClientOrderID)
+			}
+		}
+
+		endpoint := "/api/v4/futures/" + g.settle() + "/batch_cancel_orders"
+		var body interface{} = ids
+		if g.config.MarketOrSpot() == types.Spot {
+			items := make([]map[string]interface{}, len(ids))
+			for i, id := range ids {
+				items[i] = map[string]interface{}{"currency_pair": symbol, "id": id}
+			}
+			endpoint, body = "/api/v4/spot/cancel_batch_orders", items
+		}
+
+		var rows []gateBatchStatus
+		if err := sendRequest(ctx, g, "POST", endpoint, map[string]interface{}{bodyParam: body}, true, &rows); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.CancelResult, len(refs))
+		for i, ref := range refs {
+			err := errMissingResult
+			if i < len(rows) {
+				err = rows[i].err()
+			}
+			results[i] = refResult(symbol, ref, err)
+		}
+		return results, nil
+	}, nil), nil
+}
+
+// gateOrderPage 是订单列表每页的订单数量上限
+const gateOrderPage = 100
+
+// openOrders 返回当前挂单，symbol 为空时返回当前产品线的全部挂单
+func (g *Gate) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	var orders []types.Order
+	if g.config.MarketOrSpot() != types.Spot {
+		params := map[string]interface{}{"status": "open", "limit": gateOrderPage}
+		if symbol != "" {
+			params["contract"] = symbol
+		}
+		for offset := 0; ; offset += gateOrderPage {
+			params["offset"] = offset
+			var rows []gateFuturesOrder
+			if err := sendRequest(ctx, g, "GET", g.orderPath(), params, true, &rows); err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				orders = append(orders, row.toOrder())
+			}
+			if len(rows) < gateOrderPage {
+				return orders, nil
+			}
+		}
+	}
+
+	if symbol != "" {
+		params := map[string]interface{}{"currency_pair": symbol, "status": "open", "limit": gateOrderPage}
+		for page := 1; ; page++ {
+			params["page"] = page
+			var rows []gateSpotOrder
+			if err := sendRequest(ctx, g, "GET", g.orderPath(), params, true, &rows); err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				orders = append(orders, row.toOrder())
+			}
+			if len(rows) < gateOrderPage {
+				return orders, nil
+			}
+		}
+	}
+
+	// 现货的 open_orders 按交易对分组返回，每个交易对最多返回一页订单
+	var groups []struct {
+		CurrencyPair string          `json:"currency_pair"`
+		Orders       []gateSpotOrder `json:"orders"`
+	}
+	if err := sendRequest(ctx, g, "GET", "/api/v4/spot/open_orders", map[string]interface{}{"limit": gateOrderPage}, true, &groups); err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		for _, row := range group.Orders {
+			orders = append(orders, row.toOrder())
+		}
+	}
+	return orders, nil
+}
+
+// cancelSymbol 使用原生接口撤销一个交易对的全部挂单，现货需要指定 currency_pair，合约需要指定 contract
+func (g *Gate) cancelSymbol(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	var results []types.CancelResult
+	if g.config.MarketOrSpot() == types.Spot {
+		var rows []gateSpotOrder
+		if err := sendRequest(ctx, g, "DELETE", g.orderPath(), map[string]interface{}{"currency_pair": symbol, "account": "spot"}, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			results = append(results, cancelResult(row.toOrder(), nil))
+		}
+		return results, nil
+	}
+
+	var rows []gateFuturesOrder
+	if err := sendRequest(ctx, g, "DELETE", g.orderPath(), map[string]interface{}{"contract": symbol}, true, &rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		results = append(results, cancelResult(row.toOrder(), nil))
+	}
+	return results, nil
+}
+
+// CancelAllOrders 的原生接口必须指定交易对，未指定时先列出挂单，再对每个交易对分别撤销
+func (g *Gate) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if g.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(g, "options orders")
+	}
+	if symbol != "" {
+		return g.cancelSymbol(ctx, symbol)
+	}
+
+	orders, err := g.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelBySymbol(ctx, orders, g.cancelSymbol), nil
+}
+
+// gateOrderNotFound 是订单不存在时的错误标签
+const gateOrderNotFound = "ORDER_NOT_FOUND"
+
+func (g *Gate) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if g.config.MarketOrSpot() == types.Options {
+		return types.Order{}, notSupported(g, "options orders")
+	}
+
+	id := ref.ID
+	if id == "" {
+		id = gateText(ref.ClientOrderID)
+	}
+	endpoint := g.orderPath() + "/" + url.PathEscape(id)
+
+	if g.config.MarketOrSpot() == types.Spot {
+		var order gateSpotOrder
+		if err := sendRequest(ctx, g, "GET", endpoint, map[string]interface{}{"currency_pair": symbol}, true, &order); err != nil {
+			return types.Order{}, orderNotFound(err, gateOrderNotFound)
+		}
+		return order.toOrder(), nil
+	}
+
+	var order gateFuturesOrder
+	if err := sendRequest(ctx, g, "GET", endpoint, nil, true, &order); err != nil {
+		return types.Order{}, orderNotFound(err, gateOrderNotFound)
+	}
+	return order.toOrder(), nil
+}
+
+func (g *Gate) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if g.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(g, "options orders")
+	}
+	return g.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 查询 status 为 finished 的订单；现货必须指定交易对，合约不支持按时间查询，在本地过滤
+func (g *Gate) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	market := g.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(g, "options orders")
+	}
+	if market == types.Spot && req.Symbol == "" {
+		return nil, notSupported(g, "spot order history without a symbol")
+	}
+
+	params := map[string]interface{}{"status": "finished", "limit": gateOrderPage}
+	var orders []types.Order
+	if market == types.Spot {
+		params["currency_pair"] = req.Symbol
+		if !req.Start.IsZero() {
+			params["from"] = req.Start.Unix()
+		}
+		if !req.End.IsZero() {
+			params["to"] = req.End.Unix()
+		}
+
+		for page := 1; req.Limit <= 0 || len(orders) < req.Limit; page++ {
+			params["page"] = page
+			var rows []gateSpotOrder
+			if err := sendRequest(ctx, g, "GET", g.orderPath(), params, true, &rows); err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				orders = append(orders, row.toOrder())
+			}
+			if len(rows) < gateOrderPage {
+				break
+			}
+		}
+		return orderHistory(orders, req), nil
+	}
+
+	if req.Symbol != "" {
+		params["contract"] = req.Symbol
+	}
+	for offset := 0; req.Limit <= 0 || len(orders) < req.Limit; offset += gateOrderPage {
+		params["offset"] = offset
+		var rows []gateFuturesOrder
+		if err := sendRequest(ctx, g, "GET", g.orderPath(), params, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			orders = append(orders, row.toOrder())
+		}
+		// 结果按时间倒序，早于开始时间后不再翻页
+		if len(rows) < gateOrderPage || (!req.Start.IsZero() && orders[len(orders)-1].CreatedAt.Before(req.Start)) {
+			break
+		}
+	}
+	return orderHistory(orders, req), nil
+}
+
+// gateSpotFill 是现货 my_trades 的成交，role 为 maker 或 taker，create_time_ms 是带小数的毫秒时间戳
+type gateSpotFill struct {
+	ID           string      `json:"id"`
+	CurrencyPair string      `json:"currency_pair"`
+	OrderID      string      `json:"order_id"`
+	Text         string      `json:"text"`
+	Side         string      `json:"side"`
+	Role         string      `json:"role"`
+	Amount       jsonDecimal `json:"amount"`
+	Price        jsonDecimal `json:"price"`
+	Fee          jsonDecimal `json:"fee"`
+	FeeCurrency  string      `json:"fee_currency"`
+	CreateTimeMs jsonDecimal `json:"create_time_ms"`
+}
+
+func (f gateSpotFill) toFill() types.Fill {
+	return types.Fill{
+		Symbol:        f.CurrencyPair,
+		OrderID:       f.OrderID,
+		ClientOrderID: f.Text,
+		TradeID:       f.ID,
+		Side:          parseSide(f.Side),
+		Price:         f.Price.Decimal,
+		Quantity:      f.Amount.Decimal,
+		Fee:           f.Fee.Decimal,
+		FeeAsset:      f.FeeCurrency,
+		Liquidity:     liquidity(f.Role == "maker"),
+		Time:          msToTime(f.CreateTimeMs.IntPart()),
+	}
+}
+
+// gateFuturesFill 是合约 my_trades_timerange 的成交，size 为带符号的张数，卖出为负数；手续费以结算币计
+type gateFuturesFill struct {
+	TradeID    string      `json:"trade_id"`
+	Contract   string      `json:"contract"`
+	OrderID    string      `json:"order_id"`
+	Text       string      `json:"text"`
+	Size       jsonDecimal `json:"size"`
+	Price      jsonDecimal `json:"price"`
+	Role       string      `json:"role"`
+	Fee        jsonDecimal `json:"fee"`
+	CreateTime jsonDecimal `json:"create_time"`
+}
+
+func (f gateFuturesFill) toFill(settle string) types.Fill {
+	side := types.Buy
+	if f.Size.IsNegative() {
+		side = types.Sell
+	}
+	return types.Fill{
+		Symbol:        f.Contract,
+		OrderID:       f.OrderID,
+		ClientOrderID: f.Text,
+		TradeID:       f.TradeID,
+		Side:          side,
+		Price:         f.Price.Decimal,
+		Quantity:      f.Size.Abs(),
+		Fee:           f.Fee.Decimal,
+		FeeAsset:      strings.ToUpper(settle),
+		Liquidity:     liquidity(f.Role == "maker"),
+		Time:          unixTime(f.CreateTime, time.Second),
+	}
+}
+
+// gateFillPage 是成交接口每页的最大数量
+const gateFillPage = 1000
+
+// GetFills 现货使用 my_trades 按 page 翻页，合约使用 my_trades_timerange 按 offset 翻页
+func (g *Gate) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	market := g.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(g, "options fills")
+	}
+
+	params := map[string]interface{}{"limit": gateFillPage}
+	if !since.IsZero() {
+		params["from"] = since.Unix()
+	}
+
+	var fills []types.Fill
+	if market == types.Spot {
+		if symbol != "" {
+			params["currency_pair"] = symbol
+		}
+		for page := 1; ; page++ {
+			params["page"] = page
+			var rows []gateSpotFill
+			if err := sendRequest(ctx, g, "GET", "/api/v4/spot/my_trades", params, true, &rows); err != nil {
+				return nil, err
+			}
+			for _, row := range rows {
+				fills = append(fills, row.toFill())
+			}
+			if len(rows) < gateFillPage {
+				break
+			}
+		}
+		return fillHistory(fills, since), nil
+	}
+
+	if symbol != "" {
+		params["contract"] = symbol
+	}
+	for offset := 0; ; offset += gateFillPage {
+		params["offset"] = offset
+		var rows []gateFuturesFill
+		if err := sendRequest(ctx, g, "GET", "/api/v4/futures/"+g.settle()+"/my_trades_timerange", params, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			fills = append(fills, row.toFill(g.settle()))
+		}
+		if len(rows) < gateFillPage {
+			break
+		}
+	}
+	return fillHistory(fills, since), nil
+}
@@ -0,0 +1,92 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type Gemini struct {
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
+	nonces      nonceClock
+}
+
+func NewGemini(config types.ExchangeConfig) *Gemini {
+	return &Gemini{config: config}
+}
+
+func (g *Gemini) Name() types.ExchangeName {
+	return types.Gemini
+}
+
+func (g *Gemini) GetDefaultBaseURL() string {
+	if g.config.Sandbox {
+		return "https://api.sandbox.gemini.com"
+	}
+	return "https://api.gemini.com"
+}
+
+func (g *Gemini) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	baseURL := g.config.BaseURL
+	if baseURL == "" {
+		baseURL = g.GetDefaultBaseURL()
+	}
+
+	u, err := url.Parse(baseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if !signed {
+		q := u.Query()
+		for k, v := range params {
+			q.Set(k, fmt.Sprint(v))
+		}
+		u.RawQuery = q.Encode()
+
+		return http.NewRequest(method, u.String(), nil)
+	}
+
+	// Gemini 的私有接口不使用请求体，参数连同 request 和 nonce 一起放进 base64 编码的 payload 头
+	payload := make(map[string]interface{}, len(params)+2)
+	for k, v := range params {
+		payload[k] = v
+	}
+	payload["request"] = endpoint
+	payload["nonce"] = g.nonces.next()
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	encodedPayload := base64.StdEncoding.EncodeToString(payloadJSON)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Content-Length", "0")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("X-GEMINI-APIKEY", g.config.APIKey)
+	req.Header.Set("X-GEMINI-PAYLOAD", encodedPayload)
+	req.Header.Set("X-GEMINI-SIGNATURE", g.sign(encodedPayload))
+
+	return req, nil
+}
+
+func (g *Gemini) sign(payload string) string {
+	h := hmac.New(sha512.New384, []byte(g.config.APISecret))
+	h.Write([]byte(payload))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,175 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// geminiTicker 的 volume 以币种为 key，例如 {"BTC": "...", "USD": "...", "timestamp": 1483018200000}
+type geminiTicker struct {
+	Bid    jsonDecimal                `json:"bid"`
+	Ask    jsonDecimal                `json:"ask"`
+	Last   jsonDecimal                `json:"last"`
+	Volume map[string]json.RawMessage `json:"volume"`
+}
+
+// GetTicker 的 symbol 为 Gemini 交易对，例如 btcusd
+func (g *Gemini) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var t geminiTicker
+	if err := sendRequest(ctx, g, "GET", "/v1/pubticker/"+symbol, nil, false, &t); err != nil {
+		return types.Ticker{}, err
+	}
+
+	ticker := types.Ticker{
+		Symbol: symbol,
+		Last:   t.Last.Decimal,
+		Bid:    t.Bid.Decimal,
+		Ask:    t.Ask.Decimal,
+	}
+
+	upper := strings.ToUpper(symbol)
+	for currency, raw := range t.Volume {
+		if currency == "timestamp" {
+			var ts jsonInt
+			if err := json.Unmarshal(raw, &ts); err != nil {
+				return types.Ticker{}, err
+			}
+			ticker.Timestamp = msToTime(int64(ts))
+			continue
+		}
+
+		var v jsonDecimal
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return types.Ticker{}, err
+		}
+		if strings.HasPrefix(upper, currency) {
+			ticker.Volume = v.Decimal
+		} else if strings.HasSuffix(upper, currency) {
+			ticker.QuoteVolume = v.Decimal
+		}
+	}
+
+	return ticker, nil
+}
+
+type geminiBookEntry struct {
+	Price     jsonDecimal `json:"price"`
+	Amount    jsonDecimal `json:"amount"`
+	Timestamp jsonInt     `json:"timestamp"`
+}
+
+type geminiBook struct {
+	Bids []geminiBookEntry `json:"bids"`
+	Asks []geminiBookEntry `json:"asks"`
+}
+
+// GetOrderBook 中 depth <= 0 时返回完整订单簿；Gemini 每档的 timestamp 已废弃，不作为快照时间
+func (g *Gemini) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	limit := depth
+	if limit < 0 {
+		limit = 0
+	}
+	params := map[string]interface{}{
+		"limit_bids": limit,
+		"limit_asks": limit,
+	}
+
+	var b geminiBook
+	if err := sendRequest(ctx, g, "GET", "/v1/book/"+symbol, params, false, &b); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	book := types.OrderBook{Symbol: symbol}
+	for _, e := range b.Bids {
+		book.Bids = append(book.Bids, types.PriceLevel{Price: e.Price.Decimal, Size: e.Amount.Decimal})
+	}
+	for _, e := range b.Asks {
+		book.Asks = append(book.Asks, types.PriceLevel{Price: e.Price.Decimal, Size: e.Amount.Decimal})
+	}
+
+	return finalizeOrderBook(book, depth), nil
+}
+
+var geminiIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval5m: "5m", types.Interval15m: "15m", types.Interval30m: "30m",
+	types.Interval1h: "1hr", types.Interval6h: "6hr", types.Interval1d: "1day",
+}
+
+// GetCandles 中 Gemini 不支持按时间查询，返回固定数量的最近 K 线，时间范围在本地过滤；
+// 行格式为 [time(毫秒), open, high, low, close, volume]
+func (g *Gemini) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, g, geminiIntervals, g.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, g, "GET", "/v2/candles/"+symbol+"/"+venueInterval, nil, false, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, row := range rows {
+				if len(row) < 6 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, msToTime(row[0].IntPart()), 1, 2, 3, 4, 5, -1))
+			}
+			return candles, nil
+		})
+}
+
+// geminiTrade 的 type 为 taker 方向
+type geminiTrade struct {
+	TID         int64       `json:"tid"`
+	Price       jsonDecimal `json:"price"`
+	Amount      jsonDecimal `json:"amount"`
+	Type        string      `json:"type"`
+	TimestampMs int64       `json:"timestampms"`
+}
+
+// GetRecentTrades 单次最多返回 500 笔
+func (g *Gemini) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	params := map[string]interface{}{"limit_trades": clampDepth(limit, 500)}
+
+	var rows []geminiTrade
+	if err := sendRequest(ctx, g, "GET", "/v1/trades/"+symbol, params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, types.Trade{
+			ID:    strconv.FormatInt(t.TID, 10),
+			Price: t.Price.Decimal,
+			Size:  t.Amount.Decimal,
+			Side:  parseSide(t.Type),
+			Time:  msToTime(t.TimestampMs),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// GetInstruments 暂不支持：Gemini 只能通过 /v1/symbols/details/{symbol} 逐个查询交易对，
+// 全量拉取会超出公共接口的频率限制
+func (g *Gemini) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return nil, notSupported(g, "instrument list")
+}
+
+// GetAllTickers 中 Gemini 没有批量行情接口，按 /v1/symbols 列出的交易对在限速内逐个查询
+func (g *Gemini) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	var natives []string
+	if err := sendRequest(ctx, g, "GET", "/v1/symbols", nil, false, &natives); err != nil {
+		return nil, err
+	}
+
+	symbols := make(map[string]types.Symbol, len(natives))
+	for _, native := range natives {
+		if symbol, err := g.CanonicalSymbol(ctx, native); err == nil {
+			symbols[native] = symbol
+		}
+	}
+	return batchTickers(ctx, symbols, g.GetTicker)
+}
@@ -0,0 +1,41 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGemini_PrepareRequest(t *testing.T) {
+	gemini := NewGemini(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+		Sandbox:   true,
+	})
+
+	req, err := gemini.PrepareRequest("POST", "/v1/balances", map[string]interface{}{"account": "primary"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.sandbox.gemini.com/v1/balances", req.URL.String())
+	assert.Nil(t, req.Body)
+
+	encodedPayload := req.Header.Get("X-GEMINI-PAYLOAD")
+	payloadJSON, err := base64.StdEncoding.DecodeString(encodedPayload)
+	assert.NoError(t, err)
+
+	var payload map[string]interface{}
+	assert.NoError(t, json.Unmarshal(payloadJSON, &payload))
+	assert.Equal(t, "/v1/balances", payload["request"])
+	assert.Equal(t, "primary", payload["account"])
+	assert.NotZero(t, payload["nonce"])
+
+	mac := hmac.New(sha512.New384, []byte("test_secret"))
+	mac.Write([]byte(encodedPayload))
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get("X-GEMINI-SIGNATURE"))
+	assert.Equal(t, "test_key", req.Header.Get("X-GEMINI-APIKEY"))
+}
@@ -0,0 +1,286 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// geminiOptions 返回限价单的执行选项，post-only、IOC、FOK 分别对应 maker-or-cancel、immediate-or-cancel、fill-or-kill
+func geminiOptions(req types.OrderRequest) []string {
+	switch {
+	case req.PostOnly:
+		return []string{"maker-or-cancel"}
+	case req.TimeInForce == types.IOC:
+		return []string{"immediate-or-cancel"}
+	case req.TimeInForce == types.FOK:
+		return []string{"fill-or-kill"}
+	default:
+		return nil
+	}
+}
+
+// orderParams 把统一的下单请求转换为 /v1/order/new 参数。Gemini 的 API 只接受限价单，市价单和条件单暂不支持
+func (g *Gemini) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if req.Type != types.LimitOrder {
+		return nil, notSupported(g, string(req.Type)+" orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(g, "reduce-only spot orders")
+	}
+
+	params := map[string]interface{}{
+		"client_order_id": req.ClientOrderID,
+		"symbol":          req.Symbol,
+		"amount":          req.Quantity.String(),
+		"price":           req.Price.String(),
+		"side":            lowerSide(req.Side),
+		"type":            "exchange limit",
+	}
+	if options := geminiOptions(req); options != nil {
+		params["options"] = options
+	}
+	return params, nil
+}
+
+func (g *Gemini) placer() placer {
+	return placer{t: g, config: g.config, idLength: clientOrderIDLength, place: g.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为 Gemini 交易对，例如 btcusd
+func (g *Gemini) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return g.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 按顺序逐个下单：Gemini 要求 nonce 递增，并发的请求可能乱序到达而被拒绝
+func (g *Gemini) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	p := g.placer()
+	results := make([]types.OrderResult, len(reqs))
+	for i, req := range reqs {
+		order, err := p.placeOrder(ctx, req)
+		results[i] = types.OrderResult{Order: order, Err: err}
+	}
+	return results, nil
+}
+
+func (g *Gemini) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := g.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var ack struct {
+		OrderID string `json:"order_id"`
+	}
+	if err := sendRequest(ctx, g, "POST", "/v1/order/new", params, true, &ack); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, ack.OrderID), nil
+}
+
+func (g *Gemini) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, g, req)
+}
+
+// geminiOrderNotFound 是订单不存在时错误响应中的 reason
+const geminiOrderNotFound = "OrderNotFound"
+
+// geminiOrder 是订单接口的订单，状态由 is_live 与 is_cancelled 表示，两者都为 false 时订单已全部成交
+type geminiOrder struct {
+	OrderID           string      `json:"order_id"`
+	ClientOrderID     string      `json:"client_order_id"`
+	Symbol            string      `json:"symbol"`
+	Side              string      `json:"side"`
+	Type              string      `json:"type"`
+	Options           []string    `json:"options"`
+	Price             jsonDecimal `json:"price"`
+	OriginalAmount    jsonDecimal `json:"original_amount"`
+	ExecutedAmount    jsonDecimal `json:"executed_amount"`
+	AvgExecutionPrice jsonDecimal `json:"avg_execution_price"`
+	IsLive            bool        `json:"is_live"`
+	IsCancelled       bool        `json:"is_cancelled"`
+	Timestampms       int64       `json:"timestampms"`
+}
+
+func (o geminiOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClientOrderID,
+		Symbol:         o.Symbol,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.GTC,
+		Price:          o.Price.Decimal,
+		Quantity:       o.OriginalAmount.Decimal,
+		Status:         types.OrderFilled,
+		FilledQuantity: o.ExecutedAmount.Decimal,
+		AveragePrice:   o.AvgExecutionPrice.Decimal,
+		CreatedAt:      msToTime(o.Timestampms),
+	}
+	switch {
+	case o.IsLive:
+		order.Status = openStatus(o.ExecutedAmount.Decimal)
+	case o.IsCancelled:
+		order.Status = types.OrderCanceled
+	}
+	for _, option := range o.Options {
+		switch option {
+		case "immediate-or-cancel":
+			order.TimeInForce = types.IOC
+		case "fill-or-kill":
+			order.TimeInForce = types.FOK
+		}
+	}
+	if o.Type != "exchange limit" {
+		order.Type, order.TimeInForce = types.MarketOrder, ""
+	}
+	return order
+}
+
+// orderStatus 按订单号或自定义订单号查询订单，按自定义订单号查询时返回数组
+func (g *Gemini) orderStatus(ctx context.Context, ref types.OrderRef) (types.Order, error) {
+	params := map[string]interface{}{"include_trades": false}
+	if ref.ID != "" {
+		id, err := strconv.ParseInt(ref.ID, 10, 64)
+		if err != nil {
+			return types.Order{}, fmt.Errorf("%w: gemini order id must be numeric: %v", types.ErrInvalidOrder, err)
+		}
+		params["order_id"] = id
+	} else {
+		params["client_order_id"] = ref.ClientOrderID
+	}
+
+	var raw json.RawMessage
+	if err := sendRequest(ctx, g, "POST", "/v1/order/status", params, true, &raw); err != nil {
+		return types.Order{}, orderNotFound(err, geminiOrderNotFound)
+	}
+	if strings.TrimSpace(string(raw)) == "[]" {
+		return types.Order{}, fmt.Errorf("gemini order %s: %w", ref.ClientOrderID, types.ErrOrderNotFound)
+	}
+	var order geminiOrder
+	if err := unmarshalFirst(raw, &order); err != nil {
+		return types.Order{}, err
+	}
+	return order.toOrder(), nil
+}
+
+// CancelOrder 只接受数字订单号，按自定义订单号撤单时先查询订单
+func (g *Gemini) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	orderID := ref.ID
+	if orderID == "" {
+		order, err := g.orderStatus(ctx, ref)
+		if err != nil {
+			return err
+		}
+		orderID = order.ID
+	}
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: gemini order id must be numeric: %v", types.ErrInvalidOrder, err)
+	}
+
+	var order geminiOrder
+	return orderNotFound(sendRequest(ctx, g, "POST", "/v1/order/cancel", map[string]interface{}{"order_id": id}, true, &order), geminiOrderNotFound)
+}
+
+// CancelOrders 按顺序逐个撤单，原因同 PlaceOrders
+func (g *Gemini) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	results := make([]types.CancelResult, len(refs))
+	for i, ref := range refs {
+		results[i] = refResult(symbol, ref, g.CancelOrder(ctx, symbol, ref))
+	}
+	return results, nil
+}
+
+// CancelAllOrders 未指定交易对时使用原生的 cancel/all，结果中只有订单号；指定交易对时列出挂单后按顺序逐个撤销
+func (g *Gemini) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if symbol == "" {
+		var resp struct {
+			Details struct {
+				CancelledOrders []int64 `json:"cancelledOrders"`
+				CancelRejects   []int64 `json:"cancelRejects"`
+			} `json:"details"`
+		}
+		if err := sendRequest(ctx, g, "POST", "/v1/order/cancel/all", nil, true, &resp); err != nil {
+			return nil, err
+		}
+
+		var results []types.CancelResult
+		for _, id := range resp.Details.CancelledOrders {
+			results = append(results, types.CancelResult{OrderID: strconv.FormatInt(id, 10)})
+		}
+		for _, id := range resp.Details.CancelRejects {
+			results = append(results, types.CancelResult{OrderID: strconv.FormatInt(id, 10), Err: fmt.Errorf("gemini rejected the cancel of order %d", id)})
+		}
+		return results, nil
+	}
+
+	orders, err := g.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]types.CancelResult, len(orders))
+	for i, order := range orders {
+		results[i] = cancelResult(order, g.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID}))
+	}
+	return results, nil
+}
+
+// openOrders 读取全部挂单，/v1/orders 不能按交易对过滤，指定交易对时在本地过滤
+func (g *Gemini) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	var rows []geminiOrder
+	if err := sendRequest(ctx, g, "POST", "/v1/orders", nil, true, &rows); err != nil {
+		return nil, err
+	}
+
+	var orders []types.Order
+	for _, row := range rows {
+		if symbol == "" || strings.EqualFold(row.Symbol, symbol) {
+			orders = append(orders, row.toOrder())
+		}
+	}
+	return orders, nil
+}
+
+// geminiHistoryLimit 是 /v1/orders/history 一次返回的最大订单数
+const geminiHistoryLimit = 500
+
+func (g *Gemini) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	return g.orderStatus(ctx, ref)
+}
+
+func (g *Gemini) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return g.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 /v1/orders/history，只返回 Start 之后最新的 500 个已结束订单
+func (g *Gemini) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{"limit_orders": geminiHistoryLimit}
+	if req.Symbol != "" {
+		params["symbol"] = strings.ToLower(req.Symbol)
+	}
+	if !req.Start.IsZero() {
+		params["timestamp"] = req.Start.UnixMilli()
+	}
+
+	var rows []geminiOrder
+	if err := sendRequest(ctx, g, "POST", "/v1/orders/history", params, true, &rows); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(rows))
+	for i, row := range rows {
+		orders[i] = row.toOrder()
+	}
+	return orderHistory(orders, req), nil
+}
@@ -1,6 +1,7 @@
 package exchanges
 
 import (
+	"bytes"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
@@ -9,13 +10,20 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hedeqiang/cryptoexchange/types"
 )
 
 type Huobi struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
+
+	// accountID 缓存现货账户的 account-id，下单和查询挂单需要它
+	accountMu sync.Mutex
+	accountID string
 }
 
 func NewHuobi(config types.ExchangeConfig) *Huobi {
@@ -27,6 +35,9 @@ func (h *Huobi) Name() types.ExchangeName {
 }
 
 func (h *Huobi) GetDefaultBaseURL() string {
+	if h.config.MarketOrSpot() != types.Spot {
+		return "https://api.hbdm.com"
+	}
 	return "https://api.huobi.pro"
 }
 
@@ -45,22 +56,32 @@ func (h *Huobi) PrepareRequest(method, endpoint string, params map[string]interf
 		params = make(map[string]interface{})
 	}
 
+	// POST 的业务参数放在 JSON 请求体中，不参与签名，查询字符串只有签名参数
+	var body []byte
+	query := params
+	if method == "POST" {
+		if body, err = jsonBody(params); err != nil {
+			return nil, err
+		}
+		query = make(map[string]interface{})
+	}
+
 	if signed {
 		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-		params["AccessKeyId"] = h.config.APIKey
-		params["SignatureMethod"] = "HmacSHA256"
-		params["SignatureVersion"] = "2"
-		params["Timestamp"] = timestamp
+		query["AccessKeyId"] = h.config.APIKey
+		query["SignatureMethod"] = "HmacSHA256"
+		query["SignatureVersion"] = "2"
+		query["Timestamp"] = timestamp
 
-		payload := h.buildPayload(method, u.Host, u.Path, params)
+		payload := h.buildPayload(method, u.Host, u.Path, query)
 		signature := h.sign(payload)
-		params["Signature"] = signature
+		query["Signature"] = signature
 	}
 
-	queryString := h.buildQueryString(params)
+	queryString := h.buildQueryString(query)
 	u.RawQuery = queryString
 
-	req, err := http.NewRequest(method, u.String(), nil)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,420 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// huobiStatus 中现货接口使用 err-code、err-msg，合约接口使用 err_code、err_msg
+type huobiStatus struct {
+	Status      string  `json:"status"`
+	ErrCode     string  `json:"err-code"`
+	ErrMsg      string  `json:"err-msg"`
+	SwapErrCode jsonInt `json:"err_code"`
+	SwapErrMsg  string  `json:"err_msg"`
+	Ts          int64   `json:"ts"`
+}
+
+func (r *huobiStatus) apiError() error {
+	if r.Status != "" && r.Status != "ok" {
+		if r.ErrCode == "" && r.SwapErrCode != 0 {
+			return &types.APIError{StatusCode: http.StatusOK, Code: strconv.FormatInt(int64(r.SwapErrCode), 10), Message: r.SwapErrMsg}
+		}
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.ErrCode, Message: r.ErrMsg}
+	}
+	return nil
+}
+
+// huobiResponse 用于把结果放在 data 中的接口
+type huobiResponse[T any] struct {
+	huobiStatus
+	Data T `json:"data"`
+}
+
+// huobiTickResponse 用于行情类把结果放在 tick 中的接口
+type huobiTickResponse[T any] struct {
+	huobiStatus
+	Tick T `json:"tick"`
+}
+
+// huobiMergedTick 中 bid/ask 为 [价格, 数量]，amount 为基础币成交量，vol 为计价币成交额
+type huobiMergedTick struct {
+	Close  jsonDecimal   `json:"close"`
+	Bid    []jsonDecimal `json:"bid"`
+	Ask    []jsonDecimal `json:"ask"`
+	Amount jsonDecimal   `json:"amount"`
+	Vol    jsonDecimal   `json:"vol"`
+}
+
+// GetTicker 的 symbol 为小写交易对，例如 btcusdt
+func (h *Huobi) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return types.Ticker{}, notSupported(h, "futures ticker")
+	}
+
+	var resp huobiTickResponse[huobiMergedTick]
+	if err := sendRequest(ctx, h, "GET", "/market/detail/merged", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+
+	t := resp.Tick
+	ticker := types.Ticker{
+		Symbol:      symbol,
+		Last:        t.Close.Decimal,
+		Volume:      t.Amount.Decimal,
+		QuoteVolume: t.Vol.Decimal,
+		Timestamp:   msToTime(resp.Ts),
+	}
+	if len(t.Bid) > 0 {
+		ticker.Bid = t.Bid[0].Decimal
+	}
+	if len(t.Ask) > 0 {
+		ticker.Ask = t.Ask[0].Decimal
+	}
+
+	return ticker, nil
+}
+
+type huobiDepthTick struct {
+	Bids    [][]jsonDecimal `json:"bids"`
+	Asks    [][]jsonDecimal `json:"asks"`
+	Version int64           `json:"version"`
+	Ts      int64           `json:"ts"`
+}
+
+// GetOrderBook 的 depth 可取 5/10/20，超过 20 时不传 depth，返回 step0 的全部 150 档
+func (h *Huobi) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return types.OrderBook{}, notSupported(h, "futures order book")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"type":   "step0",
+	}
+	if d := nearestDepth(depth, []int{5, 10, 20, 150}); d < 150 {
+		params["depth"] = d
+	}
+
+	var resp huobiTickResponse[huobiDepthTick]
+	if err := sendRequest(ctx, h, "GET", "/market/depth", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(resp.Tick.Bids),
+		Asks:      levelsFromRows(resp.Tick.Asks),
+		Sequence:  resp.Tick.Version,
+		Timestamp: msToTime(resp.Tick.Ts),
+	}, depth), nil
+}
+
+var huobiIntervals = map[types.Interval]string{
+	types.Interval1m: "1min", types.Interval5m: "5min", types.Interval15m: "15min", types.Interval30m: "30min",
+	types.Interval1h: "60min", types.Interval4h: "4hour", types.Interval1d: "1day", types.Interval1w: "1week",
+	types.Interval1M: "1mon",
+}
+
+type huobiKline struct {
+	ID     int64       `json:"id"`
+	Open   jsonDecimal `json:"open"`
+	Close  jsonDecimal `json:"close"`
+	Low    jsonDecimal `json:"low"`
+	High   jsonDecimal `json:"high"`
+	Amount jsonDecimal `json:"amount"`
+	Vol    jsonDecimal `json:"vol"`
+}
+
+// GetCandles 中 Huobi 不支持按时间查询，只能取最近 size 根（最多 2000），时间范围在本地过滤
+func (h *Huobi) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures candles")
+	}
+
+	return fetchCandles(ctx, h, huobiIntervals, h.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			size := 2000
+			if limit > 0 && start.IsZero() {
+				size = clampDepth(limit, 2000)
+			}
+			params := map[string]interface{}{
+				"symbol": symbol,
+				"period": venueInterval,
+				"size":   size,
+			}
+
+			var resp huobiResponse[[]huobiKline]
+			if err := sendRequest(ctx, h, "GET", "/market/history/kline", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Data))
+			for _, k := range resp.Data {
+				candles = append(candles, types.Candle{
+					OpenTime:    time.Unix(k.ID, 0).UTC(),
+					Open:        k.Open.Decimal,
+					High:        k.High.Decimal,
+					Low:         k.Low.Decimal,
+					Close:       k.Close.Decimal,
+					Volume:      k.Amount.Decimal,
+					QuoteVolume: k.Vol.Decimal,
+				})
+			}
+			return candles, nil
+		})
+}
+
+type huobiTrade struct {
+	TradeID   jsonInt     `json:"trade-id"`
+	Ts        int64       `json:"ts"`
+	Amount    jsonDecimal `json:"amount"`
+	Price     jsonDecimal `json:"price"`
+	Direction string      `json:"direction"`
+}
+
+// GetRecentTrades 使用 /market/history/trade，结果按成交批次分组，单次最多 2000 批
+func (h *Huobi) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures trades")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"size":   clampDepth(limit, 2000),
+	}
+
+	var resp huobiResponse[[]struct {
+		Data []huobiTrade `json:"data"`
+	}]
+	if err := sendRequest(ctx, h, "GET", "/market/history/trade", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	var trades []types.Trade
+	for _, batch := range resp.Data {
+		for _, t := range batch.Data {
+			trades = append(trades, types.Trade{
+				ID:    strconv.FormatInt(int64(t.TradeID), 10),
+				Price: t.Price.Decimal,
+				Size:  t.Amount.Decimal,
+				Side:  parseSide(t.Direction),
+				Time:  msToTime(t.Ts),
+			})
+		}
+	}
+	return sortTrades(trades, limit), nil
+}
+
+type huobiSymbol struct {
+	Symbol          string      `json:"symbol"`
+	BaseCurrency    string      `json:"base-currency"`
+	QuoteCurrency   string      `json:"quote-currency"`
+	PricePrecision  int         `json:"price-precision"`
+	AmountPrecision int         `json:"amount-precision"`
+	MinOrderAmt     jsonDecimal `json:"limit-order-min-order-amt"`
+	MaxOrderAmt     jsonDecimal `json:"limit-order-max-order-amt"`
+	MinOrderValue   jsonDecimal `json:"min-order-value"`
+	State           string      `json:"state"`
+}
+
+func huobiState(state string) types.InstrumentStatus {
+	switch state {
+	case "online":
+		return types.InstrumentTrading
+	case "pre-online":
+		return types.InstrumentPreTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+// GetInstruments 中的数量限制取限价单的限制，Symbol 与币种均为小写
+func (h *Huobi) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures instruments")
+	}
+
+	return h.instruments.get(ctx, h.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp huobiResponse[[]huobiSymbol]
+		if err := sendRequest(ctx, h, "GET", "/v1/common/symbols", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Data))
+		for _, s := range resp.Data {
+			instruments = append(instruments, types.Instrument{
+				Symbol:      s.Symbol,
+				Base:        s.BaseCurrency,
+				Quote:       s.QuoteCurrency,
+				TickSize:    precisionStep(s.PricePrecision),
+				StepSize:    precisionStep(s.AmountPrecision),
+				MinQty:      s.MinOrderAmt.Decimal,
+				MaxQty:      s.MaxOrderAmt.Decimal,
+				MinNotional: s.MinOrderValue.Decimal,
+				Status:      huobiState(s.State),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// swapAPI 返回合约接口的路径前缀，U 本位与币本位永续使用不同的前缀
+func (h *Huobi) swapAPI() string {
+	if h.config.MarketOrSpot() == types.CoinMFutures {
+		return "/swap-api/v1"
+	}
+	return "/linear-swap-api/v1"
+}
+
+// GetFundingRate 的 symbol 为合约代码，例如 BTC-USDT 或币本位的 BTC-USD。HTX 不直接返回结算间隔，
+// 用本期结算时间减去最近一次已结算的时间得出，没有结算记录时为零值
+func (h *Huobi) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	if err := requirePerpetual(h, h.config.MarketOrSpot()); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	var resp huobiResponse[struct {
+		ContractCode string      `json:"contract_code"`
+		FundingRate  jsonDecimal `json:"funding_rate"`
+		FundingTime  jsonInt     `json:"funding_time"`
+	}]
+	if err := sendRequest(ctx, h, "GET", h.swapAPI()+"/swap_funding_rate", map[string]interface{}{"contract_code": symbol}, false, &resp); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	rate := types.FundingRate{
+		Symbol:          resp.Data.ContractCode,
+		Rate:            resp.Data.FundingRate.Decimal,
+		NextFundingTime: msToTime(int64(resp.Data.FundingTime)),
+		Timestamp:       msToTime(resp.Ts),
+	}
+
+	history, err := h.GetFundingHistory(ctx, symbol, time.Time{}, time.Time{}, 1)
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+	if len(history) > 0 && rate.NextFundingTime.After(history[0].Time) {
+		rate.Interval = rate.NextFundingTime.Sub(history[0].Time)
+	}
+	return rate, nil
+}
+
+// GetFundingHistory 中 HTX 只能按页查询最近的记录（每页最多 50 条），时间范围在本地过滤
+func (h *Huobi) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(h, h.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"contract_code": symbol,
+		"page_size":     clampDepth(limit, 50),
+	}
+
+	var resp huobiResponse[struct {
+		Data []struct {
+			ContractCode string      `json:"contract_code"`
+			FundingRate  jsonDecimal `json:"funding_rate"`
+			FundingTime  jsonInt     `json:"funding_time"`
+		} `json:"data"`
+	}]
+	if err := sendRequest(ctx, h, "GET", h.swapAPI()+"/swap_historical_funding_rate", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(resp.Data.Data))
+	for _, r := range resp.Data.Data {
+		records = append(records, types.FundingRecord{Symbol: r.ContractCode, Rate: r.FundingRate.Decimal, Time: msToTime(int64(r.FundingTime))})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+// GetMarkPrice 取 1 分钟标记价格 K 线的最新收盘价
+func (h *Huobi) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	if err := requirePerpetual(h, h.config.MarketOrSpot()); err != nil {
+		return types.ReferencePrice{}, err
+	}
+
+	endpoint := "/index/market/history/linear_swap_mark_price_kline"
+	if h.config.MarketOrSpot() == types.CoinMFutures {
+		endpoint = "/index/market/history/swap_mark_price_kline"
+	}
+	params := map[string]interface{}{
+		"contract_code": symbol,
+		"period":        "1min",
+		"size":          1,
+	}
+
+	var resp huobiResponse[[]struct {
+		Close jsonDecimal `json:"close"`
+	}]
+	if err := sendRequest(ctx, h, "GET", endpoint, params, false, &resp); err != nil {
+		return types.ReferencePrice{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.ReferencePrice{}, fmt.Errorf("huobi mark price %s not found", symbol)
+	}
+
+	return types.ReferencePrice{Symbol: symbol, Price: resp.Data[len(resp.Data)-1].Close.Decimal, Timestamp: msToTime(resp.Ts)}, nil
+}
+
+func (h *Huobi) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	if err := requirePerpetual(h, h.config.MarketOrSpot()); err != nil {
+		return types.ReferencePrice{}, err
+	}
+
+	var resp huobiResponse[[]struct {
+		ContractCode string      `json:"contract_code"`
+		IndexPrice   jsonDecimal `json:"index_price"`
+		IndexTs      int64       `json:"index_ts"`
+	}]
+	if err := sendRequest(ctx, h, "GET", h.swapAPI()+"/swap_index", map[string]interface{}{"contract_code": symbol}, false, &resp); err != nil {
+		return types.ReferencePrice{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.ReferencePrice{}, fmt.Errorf("huobi index price %s not found", symbol)
+	}
+
+	p := resp.Data[0]
+	return types.ReferencePrice{Symbol: p.ContractCode, Price: p.IndexPrice.Decimal, Timestamp: msToTime(p.IndexTs)}, nil
+}
+
+// huobiTicker 是 /market/tickers 中的一项，amount 为基础币成交量，vol 为计价币成交额
+type huobiTicker struct {
+	Symbol string      `json:"symbol"`
+	Close  jsonDecimal `json:"close"`
+	Bid    jsonDecimal `json:"bid"`
+	Ask    jsonDecimal `json:"ask"`
+	Amount jsonDecimal `json:"amount"`
+	Vol    jsonDecimal `json:"vol"`
+}
+
+func (h *Huobi) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures ticker")
+	}
+
+	var resp huobiResponse[[]huobiTicker]
+	if err := sendRequest(ctx, h, "GET", "/market/tickers", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		tickers = append(tickers, types.Ticker{
+			Symbol:      t.Symbol,
+			Last:        t.Close.Decimal,
+			Bid:         t.Bid.Decimal,
+			Ask:         t.Ask.Decimal,
+			Volume:      t.Amount.Decimal,
+			QuoteVolume: t.Vol.Decimal,
+			Timestamp:   msToTime(resp.Ts),
+		})
+	}
+	return keyTickers(ctx, h, tickers)
+}
@@ -0,0 +1,460 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// huobiFeeBatch 是 transact-fee-rate 接口一次最多查询的交易对数量
+const huobiFeeBatch = 10
+
+// huobiV2Response 用于 /v2 接口，成功时 code 为 200
+type huobiV2Response[T any] struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+func (r *huobiV2Response[T]) apiError() error {
+	if r.Code != http.StatusOK {
+		return &types.APIError{StatusCode: http.StatusOK, Code: strconv.Itoa(r.Code), Message: r.Message}
+	}
+	return nil
+}
+
+// huobiFeeRate 中 actualMakerRate、actualTakerRate 是计入点卡等抵扣后的实际费率
+type huobiFeeRate struct {
+	Symbol          string      `json:"symbol"`
+	ActualMakerRate jsonDecimal `json:"actualMakerRate"`
+	ActualTakerRate jsonDecimal `json:"actualTakerRate"`
+}
+
+// GetTradingFees 每次最多查询 huobiFeeBatch 个交易对，symbols 为空时分批查询全部现货交易对
+func (h *Huobi) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures trading fees")
+	}
+
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = instrumentSymbols(ctx, h); err != nil {
+			return nil, err
+		}
+	}
+
+	fees := make(map[string]types.TradingFee, len(symbols))
+	for start := 0; start < len(symbols); start += huobiFeeBatch {
+		batch := symbols[start:min(start+huobiFeeBatch, len(symbols))]
+
+		var resp huobiV2Response[[]huobiFeeRate]
+		if err := sendRequest(ctx, h, "GET", "/v2/reference/transact-fee-rate", map[string]interface{}{"symbols": strings.Join(batch, ",")}, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Data {
+			fees[f.Symbol] = types.TradingFee{Symbol: f.Symbol, Maker: f.ActualMakerRate.Decimal, Taker: f.ActualTakerRate.Decimal}
+		}
+	}
+	return fees, nil
+}
+
+// spotAccount 返回现货账户的 account-id，查询成功后缓存
+func (h *Huobi) spotAccount(ctx context.Context) (string, error) {
+	h.accountMu.Lock()
+	defer h.accountMu.Unlock()
+	if h.accountID != "" {
+		return h.accountID, nil
+	}
+
+	var resp huobiResponse[[]struct {
+		ID   int64  `json:"id"`
+		Type string `json:"type"`
+	}]
+	if err := sendRequest(ctx, h, "GET", "/v1/account/accounts", nil, true, &resp); err != nil {
+		return "", err
+	}
+	for _, account := range resp.Data {
+		if account.Type == "spot" {
+			h.accountID = strconv.FormatInt(account.ID, 10)
+			return h.accountID, nil
+		}
+	}
+	return "", fmt.Errorf("huobi spot account not found")
+}
+
+// huobiOrderType 返回下单的 type，由方向和订单类型拼接，例如 buy-limit、sell-ioc、buy-limit-maker
+func huobiOrderType(req types.OrderRequest) string {
+	orderType := "limit"
+	switch {
+	case req.Type == types.MarketOrder:
+		orderType = "market"
+	case req.PostOnly:
+		orderType = "limit-maker"
+	case req.TimeInForce == types.IOC:
+		orderType = "ioc"
+	case req.TimeInForce == types.FOK:
+		orderType = "limit-fok"
+	}
+	return lowerSide(req.Side) + "-" + orderType
+}
+
+// orderParams 把统一的下单请求转换为现货下单参数，市价买单的 amount 是计价币金额，市价卖单是基础币数量；
+// 合约交易暂不支持
+func (h *Huobi) orderParams(ctx context.Context, req types.OrderRequest) (map[string]interface{}, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(h, "reduce-only spot orders")
+	}
+	if req.Type.Conditional() {
+		return nil, notSupported(h, "conditional orders")
+	}
+
+	amount := req.Quantity
+	if req.Type == types.MarketOrder {
+		switch {
+		case req.Side == types.Buy && !req.QuoteQuantity.IsPositive():
+			return nil, notSupported(h, "market buys by base quantity")
+		case req.Side == types.Sell && req.QuoteQuantity.IsPositive():
+			return nil, notSupported(h, "market sells by quote quantity")
+		case req.Side == types.Buy:
+			amount = req.QuoteQuantity
+		}
+	}
+
+	accountID, err := h.spotAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"account-id":      accountID,
+		"symbol":          req.Symbol,
+		"type":            huobiOrderType(req),
+		"amount":          amount.String(),
+		"client-order-id": req.ClientOrderID,
+		"source":          "spot-api",
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+	}
+	return params, nil
+}
+
+func (h *Huobi) placer() placer {
+	return placer{t: h, config: h.config, idLength: clientOrderIDLength, place: h.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为小写交易对，例如 btcusdt
+func (h *Huobi) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return h.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单
+func (h *Huobi) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+	return h.placer().placeOrders(ctx, reqs), nil
+}
+
+func (h *Huobi) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := h.orderParams(ctx, req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp huobiResponse[string]
+	if err := sendRequest(ctx, h, "POST", "/v1/order/orders/place", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, resp.Data), nil
+}
+
+func (h *Huobi) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, h, req)
+}
+
+// huobiOrderNotFound 是订单不存在时的错误码
+var huobiOrderNotFound = []string{"base-record-invalid", "base-not-found"}
+
+// huobiBatchSize 是批量撤单一次最多包含的订单数，huobiPageSize 是挂单列表每页的最大数量
+const (
+	huobiBatchSize = 50
+	huobiPageSize  = 500
+)
+
+// CancelOrder 按订单号调用 submitcancel，按自定义订单号调用 submitCancelClientOrder
+func (h *Huobi) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if h.config.MarketOrSpot() != types.Spot {
+		return notSupported(h, "futures orders")
+	}
+
+	if ref.ID != "" {
+		var resp huobiResponse[string]
+		err := sendRequest(ctx, h, "POST", "/v1/order/orders/"+url.PathEscape(ref.ID)+"/submitcancel", nil, true, &resp)
+		return orderNotFound(err, huobiOrderNotFound...)
+	}
+
+	// data 是撤单时的订单状态码：0 表示找不到该自定义订单号，-1 与 6 表示订单早已结束或已全部成交
+	var resp huobiResponse[int]
+	err := sendRequest(ctx, h, "POST", "/v1/order/orders/submitCancelClientOrder", map[string]interface{}{"client-order-id": ref.ClientOrderID}, true, &resp)
+	switch {
+	case err != nil:
+		return orderNotFound(err, huobiOrderNotFound...)
+	case resp.Data == 0:
+		return fmt.Errorf("huobi order %s: %w", ref.ClientOrderID, types.ErrOrderNotFound)
+	case resp.Data == -1 || resp.Data == 6:
+		return fmt.Errorf("huobi order %s is already closed", ref.ClientOrderID)
+	}
+	return nil
+}
+
+// huobiBatchCancel 是 batchcancel 的结果，success 中是撤单成功的订单号
+type huobiBatchCancel struct {
+	Success []string `json:"success"`
+	Failed  []struct {
+		OrderID       string `json:"order-id"`
+		ClientOrderID string `json:"client-order-id"`
+		ErrCode       string `json:"err-code"`
+		ErrMsg        string `json:"err-msg"`
+	} `json:"failed"`
+}
+
+// result 查找 ref 的撤单结果，不在 failed 中的订单视为撤单成功
+func (c huobiBatchCancel) result(ref types.OrderRef) error {
+	for _, failed := range c.Failed {
+		if (ref.ID != "" && failed.OrderID == ref.ID) || (ref.ID == "" && failed.ClientOrderID == ref.ClientOrderID) {
+			return orderNotFound(batchError(failed.ErrCode, failed.ErrMsg), huobiOrderNotFound...)
+		}
+	}
+	return nil
+}
+
+// CancelOrders 通过 batchcancel 批量撤单，每批最多 50 个订单，订单号与自定义订单号可以混用
+func (h *Huobi) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+
+	return cancelOrders(ctx, symbol, refs, huobiBatchSize, func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		var ids, clientIDs []string
+		for _, ref := range refs {
+			if ref.ID != "" {
+				ids = append(ids, ref.ID)
+			} else {
+				clientIDs = append(clientIDs, ref.ClientOrderID)
+			}
+		}
+		params := map[string]interface{}{}
+		if ids != nil {
+			params["order-ids"] = ids
+		}
+		if clientIDs != nil {
+			params["client-order-ids"] = clientIDs
+		}
+
+		var resp huobiResponse[huobiBatchCancel]
+		if err := sendRequest(ctx, h, "POST", "/v1/order/orders/batchcancel", params, true, &resp); err != nil {
+			return nil, err
+		}
+		results := make([]types.CancelResult, len(refs))
+		for i, ref := range refs {
+			results[i] = refResult(symbol, ref, resp.Data.result(ref))
+		}
+		return results, nil
+	}, nil), nil
+}
+
+// CancelAllOrders 列出挂单后逐个撤销；batchCancelOpenOrders 只返回撤销数量
+func (h *Huobi) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+
+	orders, err := h.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+		return h.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+	}), nil
+}
+
+// huobiOrder 是现货订单。订单详情使用 field-* 字段，挂单与历史列表使用 filled-* 字段；
+// type 由方向和类型拼接，例如 buy-limit、sell-market，市价买单的 amount 是计价币金额
+type huobiOrder struct {
+	ID               int64       `json:"id"`
+	ClientOrderID    string      `json:"client-order-id"`
+	Symbol           string      `json:"symbol"`
+	Type             string      `json:"type"`
+	Price            jsonDecimal `json:"price"`
+	Amount           jsonDecimal `json:"amount"`
+	State            string      `json:"state"`
+	FieldAmount      jsonDecimal `json:"field-amount"`
+	FieldCashAmount  jsonDecimal `json:"field-cash-amount"`
+	FieldFees        jsonDecimal `json:"field-fees"`
+	FilledAmount     jsonDecimal `json:"filled-amount"`
+	FilledCashAmount jsonDecimal `json:"filled-cash-amount"`
+	FilledFees       jsonDecimal `json:"filled-fees"`
+	CreatedAt        int64       `json:"created-at"`
+	FinishedAt       int64       `json:"finished-at"`
+	CanceledAt       int64       `json:"canceled-at"`
+}
+
+// huobiStatuses 中 created 是尚未进入撮合的订单，submitted 是已挂单；canceling 由 toOrder 按成交数量处理
+var huobiStatuses = map[string]types.OrderStatus{
+	"created":          types.OrderNew,
+	"submitted":        types.OrderNew,
+	"partial-filled":   types.OrderPartiallyFilled,
+	"filled":           types.OrderFilled,
+	"partial-canceled": types.OrderCanceled,
+	"canceled":         types.OrderCanceled,
+}
+
+func (o huobiOrder) toOrder() types.Order {
+	side, kind, _ := strings.Cut(o.Type, "-")
+	filled := o.FieldAmount.Add(o.FilledAmount.Decimal)
+	cash := o.FieldCashAmount.Add(o.FilledCashAmount.Decimal)
+
+	order := types.Order{
+		ID:             strconv.FormatInt(o.ID, 10),
+		ClientOrderID:  o.ClientOrderID,
+		Symbol:         o.Symbol,
+		Side:           parseSide(side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.GTC,
+		Price:          o.Price.Decimal,
+		Quantity:       o.Amount.Decimal,
+		Status:         orderStatus(huobiStatuses, o.State),
+		FilledQuantity: filled,
+		AveragePrice:   averagePrice(cash, filled),
+		Fee:            o.FieldFees.Add(o.FilledFees.Decimal),
+		Raw:            o.State,
+		CreatedAt:      msToTime(o.CreatedAt),
+		UpdatedAt:      msToTime(max(o.FinishedAt, o.CanceledAt)),
+	}
+	if o.State == "canceling" {
+		order.Status = openStatus(filled)
+	}
+
+	switch kind {
+	case "market":
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+		if order.Side == types.Buy {
+			order.QuoteQuantity, order.Quantity = order.Quantity, decimal.Zero
+		}
+	case "ioc":
+		order.TimeInForce = types.IOC
+	case "limit-fok":
+		order.TimeInForce = types.FOK
+	case "stop-limit":
+		order.Type = types.StopLimitOrder
+	}
+	return order
+}
+
+// openOrders 按 from 向后翻页读取现货账户的挂单
+func (h *Huobi) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	accountID, err := h.spotAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{"account-id": accountID, "size": huobiPageSize}
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	var orders []types.Order
+	for {
+		var resp huobiResponse[[]huobiOrder]
+		if err := sendRequest(ctx, h, "GET", "/v1/order/openOrders", params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, row := range resp.Data {
+			orders = append(orders, row.toOrder())
+		}
+		if len(resp.Data) < huobiPageSize {
+			return orders, nil
+		}
+		params["from"] = resp.Data[len(resp.Data)-1].ID
+		params["direct"] = "next"
+	}
+}
+
+// huobiHistoryPageSize 是 /v1/order/history 每页的最大数量
+const huobiHistoryPageSize = 1000
+
+// GetOrder 按订单号查询 /v1/order/orders/{id}，按自定义订单号查询 getClientOrder
+func (h *Huobi) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if h.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(h, "futures orders")
+	}
+
+	endpoint := "/v1/order/orders/getClientOrder"
+	params := map[string]interface{}{"clientOrderId": ref.ClientOrderID}
+	if ref.ID != "" {
+		endpoint, params = "/v1/order/orders/"+url.PathEscape(ref.ID), nil
+	}
+	var resp huobiResponse[huobiOrder]
+	if err := sendRequest(ctx, h, "GET", endpoint, params, true, &resp); err != nil {
+		return types.Order{}, orderNotFound(err, huobiOrderNotFound...)
+	}
+	return resp.Data.toOrder(), nil
+}
+
+func (h *Huobi) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+	return h.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 /v1/order/history，只能查询最近 48 小时内结束的订单，按 next-time 向后翻页
+func (h *Huobi) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(h, "futures orders")
+	}
+
+	params := map[string]interface{}{"direct": "next", "size": huobiHistoryPageSize}
+	if req.Symbol != "" {
+		params["symbol"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["start-time"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["end-time"] = req.End.UnixMilli()
+	}
+
+	var orders []types.Order
+	for req.Limit <= 0 || len(orders) < req.Limit {
+		var resp struct {
+			huobiResponse[[]huobiOrder]
+			NextTime int64 `json:"next-time"`
+		}
+		if err := sendRequest(ctx, h, "GET", "/v1/order/history", params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, row := range resp.Data {
+			orders = append(orders, row.toOrder())
+		}
+		if resp.NextTime == 0 {
+			break
+		}
+		params["start-time"] = resp.NextTime
+	}
+	return orderHistory(orders, req), nil
+}
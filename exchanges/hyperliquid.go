@@ -0,0 +1,259 @@
+package exchanges
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/vmihailenco/msgpack/v5"
+	"golang.org/x/crypto/sha3"
+)
+
+// Hyperliquid 的 /exchange 接口使用钱包私钥对 EIP-712 typed data 签名，
+// PrepareRequest 的 params 需包含 "action"，可选 "vaultAddress"。
+// action 以 msgpack 编码后参与哈希，字段顺序必须与服务端一致，因此应使用下面定义的结构体而不是 map。
+type Hyperliquid struct {
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
+	nonces      nonceClock
+}
+
+type HyperliquidOrderAction struct {
+	Type     string                 `msgpack:"type" json:"type"`
+	Orders   []HyperliquidOrderWire `msgpack:"orders" json:"orders"`
+	Grouping string                 `msgpack:"grouping" json:"grouping"`
+}
+
+type HyperliquidOrderWire struct {
+	Asset      int                  `msgpack:"a" json:"a"`
+	IsBuy      bool                 `msgpack:"b" json:"b"`
+	Price      string               `msgpack:"p" json:"p"`
+	Size       string               `msgpack:"s" json:"s"`
+	ReduceOnly bool                 `msgpack:"r" json:"r"`
+	OrderType  HyperliquidOrderType `msgpack:"t" json:"t"`
+	Cloid      string               `msgpack:"c,omitempty" json:"c,omitempty"`
+}
+
+type HyperliquidOrderType struct {
+	Limit   *HyperliquidLimitOrder   `msgpack:"limit,omitempty" json:"limit,omitempty"`
+	Trigger *HyperliquidTriggerOrder `msgpack:"trigger,omitempty" json:"trigger,omitempty"`
+}
+
+type HyperliquidLimitOrder struct {
+	Tif string `msgpack:"tif" json:"tif"`
+}
+
+type HyperliquidTriggerOrder struct {
+	IsMarket  bool   `msgpack:"isMarket" json:"isMarket"`
+	TriggerPx string `msgpack:"triggerPx" json:"triggerPx"`
+	Tpsl      string `msgpack:"tpsl" json:"tpsl"`
+}
+
+type HyperliquidCancelAction struct {
+	Type    string              `msgpack:"type" json:"type"`
+	Cancels []HyperliquidCancel `msgpack:"cancels" json:"cancels"`
+}
+
+type HyperliquidCancel struct {
+	Asset   int   `msgpack:"a" json:"a"`
+	OrderID int64 `msgpack:"o" json:"o"`
+}
+
+// HyperliquidCancelByCloidAction 按自定义订单号撤单，字段名与按订单号撤单的缩写不同
+type HyperliquidCancelByCloidAction struct {
+	Type    string                     `msgpack:"type" json:"type"`
+	Cancels []HyperliquidCancelByCloid `msgpack:"cancels" json:"cancels"`
+}
+
+type HyperliquidCancelByCloid struct {
+	Asset int    `msgpack:"asset" json:"asset"`
+	Cloid string `msgpack:"cloid" json:"cloid"`
+}
+
+type HyperliquidSignature struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V int    `json:"v"`
+}
+
+func NewHyperliquid(config types.ExchangeConfig) *Hyperliquid {
+	return &Hyperliquid{config: config}
+}
+
+func (h *Hyperliquid) Name() types.ExchangeName {
+	return types.Hyperliquid
+}
+
+func (h *Hyperliquid) GetDefaultBaseURL() string {
+	if h.config.Sandbox {
+		return "https://api.hyperliquid-testnet.xyz"
+	}
+	return "https://api.hyperliquid.xyz"
+}
+
+// PrepareRequest 中 /info 等公开查询直接以 JSON 发送 params，例如 {"type": "allMids"}
+func (h *Hyperliquid) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	baseURL := h.config.BaseURL
+	if baseURL == "" {
+		baseURL = h.GetDefaultBaseURL()
+	}
+
+	u, err := url.Parse(baseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := params
+	if signed {
+		action, ok := params["action"]
+		if !ok {
+			return nil, fmt.Errorf("hyperliquid signed request requires an action")
+		}
+		vaultAddress, _ := params["vaultAddress"].(string)
+		nonce := h.nonces.next()
+
+		signature, err := h.SignL1Action(action, vaultAddress, nonce)
+		if err != nil {
+			return nil, err
+		}
+
+		payload = map[string]interface{}{
+			"action":    action,
+			"nonce":     nonce,
+			"signature": signature,
+		}
+		if vaultAddress != "" {
+			payload["vaultAddress"] = vaultAddress
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// Address 返回配置私钥对应的钱包地址，/info 中的 user 参数需要使用它
+func (h *Hyperliquid) Address() (string, error) {
+	key, err := h.privateKey()
+	if err != nil {
+		return "", err
+	}
+
+	pub := key.PubKey().SerializeUncompressed()
+	return "0x" + hex.EncodeToString(keccak256(pub[1:])[12:]), nil
+}
+
+// SignL1Action 对 action 生成 phantom agent 签名
+func (h *Hyperliquid) SignL1Action(action interface{}, vaultAddress string, nonce int64) (HyperliquidSignature, error) {
+	connectionID, err := hyperliquidActionHash(action, vaultAddress, nonce)
+	if err != nil {
+		return HyperliquidSignature{}, err
+	}
+
+	source := "a"
+	if h.config.Sandbox {
+		source = "b"
+	}
+
+	key, err := h.privateKey()
+	if err != nil {
+		return HyperliquidSignature{}, err
+	}
+
+	compact := ecdsa.SignCompact(key, hyperliquidAgentDigest(source, connectionID), false)
+
+	return HyperliquidSignature{
+		R: "0x" + new(big.Int).SetBytes(compact[1:33]).Text(16),
+		S: "0x" + new(big.Int).SetBytes(compact[33:65]).Text(16),
+		V: int(compact[0]),
+	}, nil
+}
+
+func (h *Hyperliquid) privateKey() (*secp256k1.PrivateKey, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimPrefix(h.config.PrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode private key: %v", err)
+	}
+	if len(keyBytes) != 32 {
+		return nil, fmt.Errorf("private key must be 32 bytes, got %d", len(keyBytes))
+	}
+	return secp256k1.PrivKeyFromBytes(keyBytes), nil
+}
+
+// hyperliquidActionHash 计算 keccak256(msgpack(action) || nonce || vault)，即 phantom agent 的 connectionId
+func hyperliquidActionHash(action interface{}, vaultAddress string, nonce int64) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	enc.SetSortMapKeys(true)
+	if err := enc.Encode(action); err != nil {
+		return nil, err
+	}
+
+	nonceBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonceBytes, uint64(nonce))
+	buf.Write(nonceBytes)
+
+	if vaultAddress == "" {
+		buf.WriteByte(0x00)
+	} else {
+		addr, err := hex.DecodeString(strings.TrimPrefix(vaultAddress, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode vault address: %v", err)
+		}
+		buf.WriteByte(0x01)
+		buf.Write(addr)
+	}
+
+	return keccak256(buf.Bytes()), nil
+}
+
+// hyperliquidAgentDigest 按 EIP-712 计算 Agent(string source,bytes32 connectionId) 的签名摘要
+func hyperliquidAgentDigest(source string, connectionID []byte) []byte {
+	chainID := make([]byte, 32)
+	big.NewInt(1337).FillBytes(chainID)
+
+	domainSeparator := keccak256(
+		keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")),
+		keccak256([]byte("Exchange")),
+		keccak256([]byte("1")),
+		chainID,
+		make([]byte, 32),
+	)
+
+	structHash := keccak256(
+		keccak256([]byte("Agent(string source,bytes32 connectionId)")),
+		keccak256([]byte(source)),
+		connectionID,
+	)
+
+	return keccak256([]byte{0x19, 0x01}, domainSeparator, structHash)
+}
+
+func keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,331 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type hyperliquidMeta struct {
+	Universe []struct {
+		Name       string `json:"name"`
+		SzDecimals int    `json:"szDecimals"`
+		IsDelisted bool   `json:"isDelisted"`
+	} `json:"universe"`
+}
+
+// hyperliquidAssetCtx 中 dayBaseVlm 为基础币成交量，dayNtlVlm 为名义成交额；现货的 ctx 带有 coin 字段
+type hyperliquidAssetCtx struct {
+	Coin       string      `json:"coin"`
+	MarkPx     jsonDecimal `json:"markPx"`
+	MidPx      jsonDecimal `json:"midPx"`
+	DayBaseVlm jsonDecimal `json:"dayBaseVlm"`
+	DayNtlVlm  jsonDecimal `json:"dayNtlVlm"`
+}
+
+type hyperliquidL2Book struct {
+	Coin   string                  `json:"coin"`
+	Time   int64                   `json:"time"`
+	Levels [2][]hyperliquidL2Level `json:"levels"`
+}
+
+type hyperliquidL2Level struct {
+	Px jsonDecimal `json:"px"`
+	Sz jsonDecimal `json:"sz"`
+	N  int         `json:"n"`
+}
+
+func (h *Hyperliquid) info(ctx context.Context, params map[string]interface{}, result interface{}) error {
+	return sendRequest(ctx, h, "POST", "/info", params, false, result)
+}
+
+// assetCtxs 返回全部资产上下文，以 coin 为键；永续合约按 universe 下标对应，现货按 coin 字段对应
+func (h *Hyperliquid) assetCtxs(ctx context.Context) (map[string]hyperliquidAssetCtx, error) {
+	infoType := "metaAndAssetCtxs"
+	if h.config.MarketOrSpot() == types.Spot {
+		infoType = "spotMetaAndAssetCtxs"
+	}
+
+	var raw [2]json.RawMessage
+	if err := h.info(ctx, map[string]interface{}{"type": infoType}, &raw); err != nil {
+		return nil, err
+	}
+
+	var meta hyperliquidMeta
+	if err := json.Unmarshal(raw[0], &meta); err != nil {
+		return nil, err
+	}
+	var ctxs []hyperliquidAssetCtx
+	if err := json.Unmarshal(raw[1], &ctxs); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]hyperliquidAssetCtx, len(ctxs))
+	for i, c := range ctxs {
+		if c.Coin != "" {
+			result[c.Coin] = c
+		} else if i < len(meta.Universe) {
+			result[meta.Universe[i].Name] = c
+		}
+	}
+	return result, nil
+}
+
+func (h *Hyperliquid) assetCtx(ctx context.Context, symbol string) (hyperliquidAssetCtx, error) {
+	ctxs, err := h.assetCtxs(ctx)
+	if err != nil {
+		return hyperliquidAssetCtx{}, err
+	}
+
+	c, ok := ctxs[symbol]
+	if !ok {
+		return hyperliquidAssetCtx{}, fmt.Errorf("hyperliquid asset %s not found", symbol)
+	}
+	return c, nil
+}
+
+// GetTicker 的 symbol 为 coin，例如 BTC 或现货的 PURR/USDC、@107。
+// Hyperliquid 不提供最新成交价，Last 使用标记价格。
+func (h *Hyperliquid) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	assetCtx, err := h.assetCtx(ctx, symbol)
+	if err != nil {
+		return types.Ticker{}, err
+	}
+
+	var book hyperliquidL2Book
+	if err := h.info(ctx, map[string]interface{}{"type": "l2Book", "coin": symbol}, &book); err != nil {
+		return types.Ticker{}, err
+	}
+
+	ticker := types.Ticker{
+		Symbol:      symbol,
+		Last:        assetCtx.MarkPx.Decimal,
+		Volume:      assetCtx.DayBaseVlm.Decimal,
+		QuoteVolume: assetCtx.DayNtlVlm.Decimal,
+		Timestamp:   msToTime(book.Time),
+	}
+	if len(book.Levels[0]) > 0 {
+		ticker.Bid = book.Levels[0][0].Px.Decimal
+	}
+	if len(book.Levels[1]) > 0 {
+		ticker.Ask = book.Levels[1][0].Px.Decimal
+	}
+
+	return ticker, nil
+}
+
+func hyperliquidLevels(levels []hyperliquidL2Level) []types.PriceLevel {
+	result := make([]types.PriceLevel, 0, len(levels))
+	for _, l := range levels {
+		result = append(result, types.PriceLevel{Price: l.Px.Decimal, Size: l.Sz.Decimal})
+	}
+	return result
+}
+
+// GetOrderBook 返回 l2Book 的快照，Hyperliquid 每侧最多 20 档
+func (h *Hyperliquid) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	var book hyperliquidL2Book
+	if err := h.info(ctx, map[string]interface{}{"type": "l2Book", "coin": symbol}, &book); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      hyperliquidLevels(book.Levels[0]),
+		Asks:      hyperliquidLevels(book.Levels[1]),
+		Timestamp: msToTime(book.Time),
+	}, depth), nil
+}
+
+var hyperliquidIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval3m: "3m", types.Interval5m: "5m", types.Interval15m: "15m",
+	types.Interval30m: "30m", types.Interval1h: "1h", types.Interval2h: "2h", types.Interval4h: "4h",
+	types.Interval8h: "8h", types.Interval12h: "12h", types.Interval1d: "1d", types.Interval3d: "3d",
+	types.Interval1w: "1w", types.Interval1M: "1M",
+}
+
+// hyperliquidDefaultCandles 是未指定 start 和 limit 时请求的 K 线数量
+const hyperliquidDefaultCandles = 500
+
+type hyperliquidCandle struct {
+	T int64       `json:"t"`
+	O jsonDecimal `json:"o"`
+	H jsonDecimal `json:"h"`
+	L jsonDecimal `json:"l"`
+	C jsonDecimal `json:"c"`
+	V jsonDecimal `json:"v"`
+}
+
+// GetCandles 使用 candleSnapshot，该接口必须指定 startTime，未指定时按 limit 往前推算
+func (h *Hyperliquid) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, h, hyperliquidIntervals, h.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			if end.IsZero() {
+				end = time.Now()
+			}
+			if start.IsZero() {
+				count := limit
+				if count <= 0 {
+					count = hyperliquidDefaultCandles
+				}
+				start = end.Add(-time.Duration(count) * interval.Duration())
+			}
+
+			params := map[string]interface{}{
+				"type": "candleSnapshot",
+				"req": map[string]interface{}{
+					"coin":      symbol,
+					"interval":  venueInterval,
+					"startTime": start.UnixMilli(),
+					"endTime":   end.UnixMilli(),
+				},
+			}
+
+			var rows []hyperliquidCandle
+			if err := h.info(ctx, params, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, k := range rows {
+				candles = append(candles, types.Candle{
+					OpenTime: msToTime(k.T),
+					Open:     k.O.Decimal,
+					High:     k.H.Decimal,
+					Low:      k.L.Decimal,
+					Close:    k.C.Decimal,
+					Volume:   k.V.Decimal,
+				})
+			}
+			return candles, nil
+		})
+}
+
+// hyperliquidTrade 的 side 为 taker 方向：B 为买入，A 为卖出
+type hyperliquidTrade struct {
+	Px   jsonDecimal `json:"px"`
+	Sz   jsonDecimal `json:"sz"`
+	Side string      `json:"side"`
+	Time int64       `json:"time"`
+	TID  int64       `json:"tid"`
+}
+
+// GetRecentTrades 使用 recentTrades，接口不支持指定数量，返回后再截取
+func (h *Hyperliquid) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	var rows []hyperliquidTrade
+	if err := h.info(ctx, map[string]interface{}{"type": "recentTrades", "coin": symbol}, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, types.Trade{
+			ID:    strconv.FormatInt(t.TID, 10),
+			Price: t.Px.Decimal,
+			Size:  t.Sz.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  msToTime(t.Time),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// hyperliquidSpotMeta 的交易对通过 tokens 下标引用代币
+type hyperliquidSpotMeta struct {
+	Universe []struct {
+		Name   string `json:"name"`
+		Tokens [2]int `json:"tokens"`
+		Index  int    `json:"index"`
+	} `json:"universe"`
+	Tokens []struct {
+		Name       string `json:"name"`
+		SzDecimals int    `json:"szDecimals"`
+		Index      int    `json:"index"`
+	} `json:"tokens"`
+}
+
+// hyperliquidMinNotional 是 Hyperliquid 统一的最小下单金额（USDC）
+var hyperliquidMinNotional = decimal.NewFromInt(10)
+
+// GetInstruments 中 Hyperliquid 的价格最多 5 位有效数字，且小数位不超过 6 - szDecimals（现货为 8 - szDecimals），
+// TickSize 只反映后一条限制；永续合约以 USDC 计价结算
+func (h *Hyperliquid) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return h.instruments.get(ctx, h.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		if h.config.MarketOrSpot() != types.Spot {
+			var meta hyperliquidMeta
+			if err := h.info(ctx, map[string]interface{}{"type": "meta"}, &meta); err != nil {
+				return nil, err
+			}
+
+			instruments := make([]types.Instrument, 0, len(meta.Universe))
+			for _, asset := range meta.Universe {
+				instruments = append(instruments, types.Instrument{
+					Symbol:       asset.Name,
+					Base:         asset.Name,
+					Quote:        "USDC",
+					TickSize:     precisionStep(6 - asset.SzDecimals),
+					StepSize:     precisionStep(asset.SzDecimals),
+					MinNotional:  hyperliquidMinNotional,
+					ContractSize: decimal.NewFromInt(1),
+					Status:       tradingStatus(!asset.IsDelisted),
+				})
+			}
+			return instruments, nil
+		}
+
+		var meta hyperliquidSpotMeta
+		if err := h.info(ctx, map[string]interface{}{"type": "spotMeta"}, &meta); err != nil {
+			return nil, err
+		}
+
+		tokens := make(map[int]int, len(meta.Tokens))
+		for i, t := range meta.Tokens {
+			tokens[t.Index] = i
+		}
+
+		instruments := make([]types.Instrument, 0, len(meta.Universe))
+		for _, pair := range meta.Universe {
+			base, okBase := tokens[pair.Tokens[0]]
+			quote, okQuote := tokens[pair.Tokens[1]]
+			if !okBase || !okQuote {
+				continue
+			}
+
+			szDecimals := meta.Tokens[base].SzDecimals
+			instruments = append(instruments, types.Instrument{
+				Symbol:      pair.Name,
+				Base:        meta.Tokens[base].Name,
+				Quote:       meta.Tokens[quote].Name,
+				TickSize:    precisionStep(8 - szDecimals),
+				StepSize:    precisionStep(szDecimals),
+				MinNotional: hyperliquidMinNotional,
+				Status:      types.InstrumentTrading,
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// GetAllTickers 只需一次资产上下文请求；批量接口不含盘口，Bid、Ask 为零值，Last 同样使用标记价格
+func (h *Hyperliquid) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	ctxs, err := h.assetCtxs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(ctxs))
+	for coin, c := range ctxs {
+		tickers = append(tickers, types.Ticker{
+			Symbol:      coin,
+			Last:        c.MarkPx.Decimal,
+			Volume:      c.DayBaseVlm.Decimal,
+			QuoteVolume: c.DayNtlVlm.Decimal,
+		})
+	}
+	return keyTickers(ctx, h, tickers)
+}
@@ -0,0 +1,45 @@
+package exchanges
+
+import (
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// 测试向量取自 hyperliquid-python-sdk 的 test_l1_action_signing_matches
+func TestHyperliquid_SignL1Action(t *testing.T) {
+	action := struct {
+		Type string `msgpack:"type"`
+		Num  int64  `msgpack:"num"`
+	}{Type: "dummy", Num: 100000000000}
+
+	mainnet := NewHyperliquid(types.ExchangeConfig{
+		PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+	})
+	signature, err := mainnet.SignL1Action(action, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x53749d5b30552aeb2fca34b530185976545bb22d0b3ce6f62e31be961a59298", signature.R)
+	assert.Equal(t, "0x755c40ba9bf05223521753995abb2f73ab3229be8ec921f350cb447e384d8ed8", signature.S)
+	assert.Equal(t, 27, signature.V)
+
+	testnet := NewHyperliquid(types.ExchangeConfig{
+		PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+		Sandbox:    true,
+	})
+	signature, err = testnet.SignL1Action(action, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x542af61ef1f429707e3c76c5293c80d01f74ef853e34b76efffcb57e574f9510", signature.R)
+	assert.Equal(t, "0x17b8b32f086e8cdede991f1e2c529f5dd5297cbe8128500e00cbaf766204a613", signature.S)
+	assert.Equal(t, 28, signature.V)
+}
+
+func TestHyperliquid_Address(t *testing.T) {
+	hyperliquid := NewHyperliquid(types.ExchangeConfig{
+		PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+	})
+
+	address, err := hyperliquid.Address()
+	assert.NoError(t, err)
+	assert.Equal(t, "0x14791697260e4c9a71f18484c9f997b308e59325", address)
+}
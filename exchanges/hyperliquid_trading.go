@@ -0,0 +1,513 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// hyperliquidBatchSize 是一次下单 action 包含的最大订单数
+const hyperliquidBatchSize = 50
+
+// hyperliquidResponse 是 /exchange 的响应，成功时 response.data.statuses 与 action 中的订单一一对应，
+// 失败时 status 为 err，response 是错误消息字符串
+type hyperliquidResponse[T any] struct {
+	Status   string          `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+func (r *hyperliquidResponse[T]) apiError() error {
+	if r.Status == "ok" {
+		return nil
+	}
+	var message string
+	if err := json.Unmarshal(r.Response, &message); err != nil {
+		message = string(r.Response)
+	}
+	return &types.APIError{StatusCode: http.StatusOK, Message: message, Body: string(r.Response)}
+}
+
+// statuses 解析每个订单的处理结果
+func (r *hyperliquidResponse[T]) statuses() ([]T, error) {
+	var resp struct {
+		Data struct {
+			Statuses []T `json:"statuses"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(r.Response, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data.Statuses, nil
+}
+
+// hyperliquidPlaced 是下单结果：挂单返回 resting，立即全部成交返回 filled，被拒绝返回 error。
+// Hyperliquid 的错误没有错误码，APIError 的 Body 也填入消息以便 Error() 输出
+type hyperliquidPlaced struct {
+	Resting *struct {
+		Oid int64 `json:"oid"`
+	} `json:"resting"`
+	Filled *struct {
+		Oid     int64       `json:"oid"`
+		TotalSz jsonDecimal `json:"totalSz"`
+		AvgPx   jsonDecimal `json:"avgPx"`
+	} `json:"filled"`
+	Error string `json:"error"`
+}
+
+// order 用请求字段和下单结果生成订单
+func (p hyperliquidPlaced) order(req types.OrderRequest) (types.Order, error) {
+	switch {
+	case p.Resting != nil:
+		order := orderFromRequest(req, strconv.FormatInt(p.Resting.Oid, 10))
+		order.Status = types.OrderNew
+		return order, nil
+	case p.Filled != nil:
+		order := orderFromRequest(req, strconv.FormatInt(p.Filled.Oid, 10))
+		order.Status = types.OrderFilled
+		order.FilledQuantity = p.Filled.TotalSz.Decimal
+		order.AveragePrice = p.Filled.AvgPx.Decimal
+		return order, nil
+	case p.Error != "":
+		return types.Order{}, &types.APIError{StatusCode: http.StatusOK, Message: p.Error, Body: p.Error}
+	}
+	return types.Order{}, errMissingResult
+}
+
+// assets 返回 coin 到下单时 asset 编号的映射：永续合约为 meta.universe 中的下标，现货为 10000 加 spotMeta.universe 的 index
+func (h *Hyperliquid) assets(ctx context.Context) (map[string]int, error) {
+	if h.config.MarketOrSpot() != types.Spot {
+		var meta hyperliquidMeta
+		if err := h.info(ctx, map[string]interface{}{"type": "meta"}, &meta); err != nil {
+			return nil, err
+		}
+		assets := make(map[string]int, len(meta.Universe))
+		for i, asset := range meta.Universe {
+			assets[asset.Name] = i
+		}
+		return assets, nil
+	}
+
+	var meta hyperliquidSpotMeta
+	if err := h.info(ctx, map[string]interface{}{"type": "spotMeta"}, &meta); err != nil {
+		return nil, err
+	}
+	assets := make(map[string]int, len(meta.Universe))
+	for _, pair := range meta.Universe {
+		assets[pair.Name] = 10000 + pair.Index
+	}
+	return assets, nil
+}
+
+// hyperliquidCloid 检查自定义订单号是否为 0x 加 32 位十六进制，Hyperliquid 只接受这种格式
+func hyperliquidCloid(id string) error {
+	if len(id) != 34 || !strings.HasPrefix(id, "0x") {
+		return fmt.Errorf("%w: hyperliquid client order id must be 0x followed by 32 hex digits", types.ErrInvalidOrder)
+	}
+	if _, err := hex.DecodeString(id[2:]); err != nil {
+		return fmt.Errorf("%w: hyperliquid client order id must be 0x followed by 32 hex digits", types.ErrInvalidOrder)
+	}
+	return nil
+}
+
+// withCloid 在缺少自定义订单号时生成一个 0x 开头的 cloid
+func withCloid(req types.OrderRequest) types.OrderRequest {
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = "0x" + newClientOrderID(clientOrderIDLength)
+	}
+	return req
+}
+
+// orderWire 把统一的下单请求转换为 action 中的订单。Hyperliquid 只有限价单，post-only 对应 Alo；
+// 市价单、FOK 和条件单暂不支持
+func (h *Hyperliquid) orderWire(req types.OrderRequest, assets map[string]int) (HyperliquidOrderWire, error) {
+	if req.Type != types.LimitOrder {
+		return HyperliquidOrderWire{}, notSupported(h, string(req.Type)+" orders")
+	}
+	if req.ReduceOnly && h.config.MarketOrSpot() == types.Spot {
+		return HyperliquidOrderWire{}, notSupported(h, "reduce-only spot orders")
+	}
+	if err := hyperliquidCloid(req.ClientOrderID); err != nil {
+		return HyperliquidOrderWire{}, err
+	}
+	asset, ok := assets[req.Symbol]
+	if !ok {
+		return HyperliquidOrderWire{}, fmt.Errorf("hyperliquid asset %s not found", req.Symbol)
+	}
+
+	tif := "Gtc"
+	switch {
+	case req.PostOnly:
+		tif = "Alo"
+	case req.TimeInForce == types.IOC:
+		tif = "Ioc"
+	case req.TimeInForce == types.FOK:
+		return HyperliquidOrderWire{}, notSupported(h, "fill-or-kill orders")
+	}
+
+	return HyperliquidOrderWire{
+		Asset:      asset,
+		IsBuy:      req.Side == types.Buy,
+		Price:      req.Price.String(),
+		Size:       req.Quantity.String(),
+		ReduceOnly: req.ReduceOnly,
+		OrderType:  HyperliquidOrderType{Limit: &HyperliquidLimitOrder{Tif: tif}},
+		Cloid:      req.ClientOrderID,
+	}, nil
+}
+
+func (h *Hyperliquid) placer() placer {
+	return placer{t: h, config: h.config, idLength: clientOrderIDLength, place: h.placeOrder, batch: h.placeBatch, batchSize: hyperliquidBatchSize}
+}
+
+// PlaceOrder 的 symbol 为 coin，例如 BTC 或现货的 PURR/USDC；ClientOrderID 为空时生成 0x 开头的 cloid
+func (h *Hyperliquid) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return h.placer().placeOrder(ctx, withCloid(req))
+}
+
+// PlaceOrders 把多个订单放进同一个 order action 提交
+func (h *Hyperliquid) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	prepared := make([]types.OrderRequest, len(reqs))
+	for i, req := range reqs {
+		prepared[i] = withCloid(req)
+	}
+	return h.placer().placeOrders(ctx, prepared), nil
+}
+
+func (h *Hyperliquid) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	results, err := h.placeBatch(ctx, []types.OrderRequest{req})
+	if err != nil {
+		return types.Order{}, err
+	}
+	return results[0].Order, results[0].Err
+}
+
+func (h *Hyperliquid) placeBatch(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	assets, err := h.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	build := func(req types.OrderRequest) (HyperliquidOrderWire, error) {
+		return h.orderWire(req, assets)
+	}
+	return buildBatch(ctx, reqs, build, func(ctx context.Context, orders []HyperliquidOrderWire, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+		action := HyperliquidOrderAction{Type: "order", Orders: orders, Grouping: "na"}
+		var resp hyperliquidResponse[hyperliquidPlaced]
+		if err := sendRequest(ctx, h, "POST", "/exchange", map[string]interface{}{"action": action}, true, &resp); err != nil {
+			return nil, err
+		}
+		statuses, err := resp.statuses()
+		if err != nil {
+			return nil, err
+		}
+
+		results := make([]types.OrderResult, len(reqs))
+		for i, req := range reqs {
+			if i >= len(statuses) {
+				results[i].Err = errMissingResult
+				continue
+			}
+			results[i].Order, results[i].Err = statuses[i].order(req)
+		}
+		return results, nil
+	})
+}
+
+// AmendOrder 通过撤单再下单实现。NewClientOrderID 同样必须是 0x 开头的 cloid，在撤单前检查
+func (h *Hyperliquid) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if req.NewClientOrderID != "" {
+		if err := hyperliquidCloid(req.NewClientOrderID); err != nil {
+			return types.AmendResult{}, err
+		}
+	}
+	return replaceOrder(ctx, h, req)
+}
+
+// hyperliquidOrderNotFound 是撤销不存在或已结束的订单时错误消息的一部分
+const hyperliquidOrderNotFound = "never placed"
+
+// hyperliquidCancelErr 解析单个撤单结果：成功时是字符串 success，失败时是 {"error": "..."}
+func hyperliquidCancelErr(status json.RawMessage) error {
+	var ok string
+	if json.Unmarshal(status, &ok) == nil && ok == "success" {
+		return nil
+	}
+	var failed struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(status, &failed); err != nil || failed.Error == "" {
+		return fmt.Errorf("hyperliquid returned unexpected cancel status %s", status)
+	}
+	return orderNotFound(&types.APIError{StatusCode: http.StatusOK, Message: failed.Error, Body: failed.Error}, hyperliquidOrderNotFound)
+}
+
+// hyperliquidOrder 是 frontendOpenOrders 返回的挂单，也是订单查询结果中的订单；sz 是剩余数量，origSz 是下单数量
+type hyperliquidOrder struct {
+	Coin       string      `json:"coin"`
+	Side       string      `json:"side"`
+	LimitPx    jsonDecimal `json:"limitPx"`
+	Sz         jsonDecimal `json:"sz"`
+	OrigSz     jsonDecimal `json:"origSz"`
+	Oid        int64       `json:"oid"`
+	Cloid      string      `json:"cloid"`
+	Tif        string      `json:"tif"`
+	ReduceOnly bool        `json:"reduceOnly"`
+	Timestamp  int64       `json:"timestamp"`
+}
+
+// hyperliquidTimeInForce 把 tif 转换为统一的有效方式，Alo 是 post-only，按 GTC 处理
+var hyperliquidTimeInForce = map[string]types.TimeInForce{
+	"Gtc": types.GTC,
+	"Alo": types.GTC,
+	"Ioc": types.IOC,
+}
+
+func (o hyperliquidOrder) toOrder() types.Order {
+	filled := o.OrigSz.Sub(o.Sz.Decimal)
+	return types.Order{
+		ID:             strconv.FormatInt(o.Oid, 10),
+		ClientOrderID:  o.Cloid,
+		Symbol:         o.Coin,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    hyperliquidTimeInForce[o.Tif],
+		Price:          o.LimitPx.Decimal,
+		Quantity:       o.OrigSz.Decimal,
+		ReduceOnly:     o.ReduceOnly,
+		Status:         openStatus(filled),
+		FilledQuantity: filled,
+		Raw:            "open",
+		CreatedAt:      msToTime(o.Timestamp),
+	}
+}
+
+// cancel 提交撤单 action 并返回每个订单的结果，action 为 HyperliquidCancelAction 或 HyperliquidCancelByCloidAction
+func (h *Hyperliquid) cancel(ctx context.Context, action interface{}, n int) ([]error, error) {
+	var resp hyperliquidResponse[json.RawMessage]
+	if err := sendRequest(ctx, h, "POST", "/exchange", map[string]interface{}{"action": action}, true, &resp); err != nil {
+		return nil, err
+	}
+	statuses, err := resp.statuses()
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, n)
+	for i := range errs {
+		if i >= len(statuses) {
+			errs[i] = errMissingResult
+			continue
+		}
+		errs[i] = hyperliquidCancelErr(statuses[i])
+	}
+	return errs, nil
+}
+
+// cancelBatch 把 refs 按订单号和 cloid 分成两个撤单 action 提交，结果顺序与 refs 一致
+func (h *Hyperliquid) cancelBatch(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	assets, err := h.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	asset, ok := assets[symbol]
+	if !ok {
+		return nil, fmt.Errorf("hyperliquid asset %s not found", symbol)
+	}
+
+	results := make([]types.CancelResult, len(refs))
+	var (
+		byID, byCloid []int
+		idAction      = HyperliquidCancelAction{Type: "cancel"}
+		cloidAction   = HyperliquidCancelByCloidAction{Type: "cancelByCloid"}
+	)
+	for i, ref := range refs {
+		results[i] = refResult(symbol, ref, nil)
+		if ref.ID == "" {
+			byCloid = append(byCloid, i)
+			cloidAction.Cancels = append(cloidAction.Cancels, HyperliquidCancelByCloid{Asset: asset, Cloid: ref.ClientOrderID})
+			continue
+		}
+		oid, err := strconv.ParseInt(ref.ID, 10, 64)
+		if err != nil {
+			results[i].Err = fmt.Errorf("%w: hyperliquid order id must be numeric", types.ErrInvalidOrder)
+			continue
+		}
+		byID = append(byID, i)
+		idAction.Cancels = append(idAction.Cancels, HyperliquidCancel{Asset: asset, OrderID: oid})
+	}
+
+	submit := func(index []int, action interface{}) error {
+		if len(index) == 0 {
+			return nil
+		}
+		errs, err := h.cancel(ctx, action, len(index))
+		if err != nil {
+			return err
+		}
+		for i, err := range errs {
+			results[index[i]].Err = err
+		}
+		return nil
+	}
+	if err := submit(byID, idAction); err != nil {
+		return nil, err
+	}
+	if err := submit(byCloid, cloidAction); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// CancelOrder 的 symbol 与下单时相同，用于确定 asset 编号
+func (h *Hyperliquid) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	results, err := h.cancelBatch(ctx, symbol, []types.OrderRef{ref})
+	if err != nil {
+		return err
+	}
+	return results[0].Err
+}
+
+// CancelOrders 把订单放进同一个撤单 action 提交，按订单号和按 cloid 撤销的订单分两次提交
+func (h *Hyperliquid) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	batch := func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		return h.cancelBatch(ctx, symbol, refs)
+	}
+	return cancelOrders(ctx, symbol, refs, hyperliquidBatchSize, batch, h.CancelOrder), nil
+}
+
+// CancelAllOrders 列出挂单后按交易对批量撤销
+func (h *Hyperliquid) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	orders, err := h.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelBySymbol(ctx, orders, func(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+		var refs []types.OrderRef
+		for _, order := range orders {
+			if order.Symbol == symbol {
+				refs = append(refs, types.OrderRef{ID: order.ID, ClientOrderID: order.ClientOrderID})
+			}
+		}
+		return h.CancelOrders(ctx, symbol, refs)
+	}), nil
+}
+
+// openOrders 读取账户的全部挂单，只保留当前产品线（永续合约或现货）的订单
+func (h *Hyperliquid) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	user, err := h.Address()
+	if err != nil {
+		return nil, err
+	}
+	assets, err := h.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []hyperliquidOrder
+	if err := h.info(ctx, map[string]interface{}{"type": "frontendOpenOrders", "user": user}, &rows); err != nil {
+		return nil, err
+	}
+	var orders []types.Order
+	for _, row := range rows {
+		if _, ok := assets[row.Coin]; !ok || (symbol != "" && row.Coin != symbol) {
+			continue
+		}
+		orders = append(orders, row.toOrder())
+	}
+	return orders, nil
+}
+
+// hyperliquidOrderStatus 是 orderStatus 与 historicalOrders 中的订单和它的状态。status 除 open、filled、
+// triggered 外，撤销类状态以 Canceled 结尾（如 marginCanceled），拒绝类状态以 Rejected 结尾
+type hyperliquidOrderStatus struct {
+	Order           hyperliquidOrder `json:"order"`
+	Status          string           `json:"status"`
+	StatusTimestamp int64            `json:"statusTimestamp"`
+}
+
+func (s hyperliquidOrderStatus) toOrder() types.Order {
+	order := s.Order.toOrder()
+	order.Raw = s.Status
+	order.UpdatedAt = msToTime(s.StatusTimestamp)
+	status := strings.ToLower(s.Status)
+	switch {
+	case status == "filled":
+		order.Status = types.OrderFilled
+	case strings.HasSuffix(status, "canceled"), status == "scheduledcancel":
+		order.Status = types.OrderCanceled
+	case strings.HasSuffix(status, "rejected"):
+		order.Status = types.OrderRejected
+	case status != "open" && status != "triggered":
+		order.Status = types.OrderStatusUnknown
+	}
+	return order
+}
+
+// GetOrder 查询 orderStatus，oid 为数字订单号或 cloid；订单不存在时返回 unknownOid
+func (h *Hyperliquid) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	user, err := h.Address()
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var oid interface{} = ref.ClientOrderID
+	if ref.ID != "" {
+		id, err := strconv.ParseInt(ref.ID, 10, 64)
+		if err != nil {
+			return types.Order{}, fmt.Errorf("%w: hyperliquid order id must be numeric", types.ErrInvalidOrder)
+		}
+		oid = id
+	}
+
+	var resp struct {
+		Status string                 `json:"status"`
+		Order  hyperliquidOrderStatus `json:"order"`
+	}
+	if err := h.info(ctx, map[string]interface{}{"type": "orderStatus", "user": user, "oid": oid}, &resp); err != nil {
+		return types.Order{}, err
+	}
+	if resp.Status != "order" {
+		return types.Order{}, fmt.Errorf("hyperliquid order %s: %w", ref.ID+ref.ClientOrderID, types.ErrOrderNotFound)
+	}
+	return resp.Order.toOrder(), nil
+}
+
+func (h *Hyperliquid) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return h.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 historicalOrders，它只返回账户最近的 2000 个订单，只保留当前产品线的订单
+func (h *Hyperliquid) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	user, err := h.Address()
+	if err != nil {
+		return nil, err
+	}
+	assets, err := h.assets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []hyperliquidOrderStatus
+	if err := h.info(ctx, map[string]interface{}{"type": "historicalOrders", "user": user}, &rows); err != nil {
+		return nil, err
+	}
+	var orders []types.Order
+	for _, row := range rows {
+		if _, ok := assets[row.Order.Coin]; !ok || (req.Symbol != "" && row.Order.Coin != req.Symbol) {
+			continue
+		}
+		orders = append(orders, row.toOrder())
+	}
+	return orderHistory(orders, req), nil
+}
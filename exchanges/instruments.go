@@ -0,0 +1,51 @@
+package exchanges
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// instrumentCache 缓存交易对元数据，由各交易所结构体内嵌为字段，零值即可使用。
+// 请求期间持有锁，并发调用只会触发一次请求。
+type instrumentCache struct {
+	mu        sync.Mutex
+	fetchedAt time.Time
+	items     []types.Instrument
+}
+
+func (c *instrumentCache) get(ctx context.Context, ttl time.Duration, fetch func(context.Context) ([]types.Instrument, error)) ([]types.Instrument, error) {
+	if ttl == 0 {
+		ttl = types.DefaultInstrumentTTL
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl < 0 || c.items == nil || time.Since(c.fetchedAt) >= ttl {
+		items, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.items, c.fetchedAt = items, time.Now()
+	}
+
+	// 返回副本，避免调用方修改缓存内容
+	return append([]types.Instrument(nil), c.items...), nil
+}
+
+// precisionStep 把小数位数转换为最小变动单位，例如 2 -> 0.01
+func precisionStep(places int) decimal.Decimal {
+	return decimal.New(1, -int32(places))
+}
+
+// tradingStatus 用于只提供是否可交易标志的交易所
+func tradingStatus(trading bool) types.InstrumentStatus {
+	if trading {
+		return types.InstrumentTrading
+	}
+	return types.InstrumentHalted
+}
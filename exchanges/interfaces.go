@@ -0,0 +1,101 @@
+package exchanges
+
+import "github.com/hedeqiang/cryptoexchange/types"
+
+var (
+	_ types.MarketData = (*Binance)(nil)
+	_ types.MarketData = (*OKX)(nil)
+	_ types.MarketData = (*Bitget)(nil)
+	_ types.MarketData = (*Kucoin)(nil)
+	_ types.MarketData = (*MEXC)(nil)
+	_ types.MarketData = (*Gate)(nil)
+	_ types.MarketData = (*Kraken)(nil)
+	_ types.MarketData = (*Bybit)(nil)
+	_ types.MarketData = (*Huobi)(nil)
+	_ types.MarketData = (*Coinbase)(nil)
+	_ types.MarketData = (*BTSE)(nil)
+	_ types.MarketData = (*Gemini)(nil)
+	_ types.MarketData = (*Upbit)(nil)
+	_ types.MarketData = (*CryptoCom)(nil)
+	_ types.MarketData = (*BitMart)(nil)
+	_ types.MarketData = (*Hyperliquid)(nil)
+
+	_ types.SymbolMapper = (*Binance)(nil)
+	_ types.SymbolMapper = (*OKX)(nil)
+	_ types.SymbolMapper = (*Bitget)(nil)
+	_ types.SymbolMapper = (*Kucoin)(nil)
+	_ types.SymbolMapper = (*MEXC)(nil)
+	_ types.SymbolMapper = (*Gate)(nil)
+	_ types.SymbolMapper = (*Kraken)(nil)
+	_ types.SymbolMapper = (*Bybit)(nil)
+	_ types.SymbolMapper = (*Huobi)(nil)
+	_ types.SymbolMapper = (*Coinbase)(nil)
+	_ types.SymbolMapper = (*BTSE)(nil)
+	_ types.SymbolMapper = (*Gemini)(nil)
+	_ types.SymbolMapper = (*Upbit)(nil)
+	_ types.SymbolMapper = (*CryptoCom)(nil)
+	_ types.SymbolMapper = (*BitMart)(nil)
+	_ types.SymbolMapper = (*Hyperliquid)(nil)
+
+	_ types.HistoricalTrades = (*Binance)(nil)
+	_ types.HistoricalTrades = (*OKX)(nil)
+	_ types.HistoricalTrades = (*Kraken)(nil)
+	_ types.HistoricalTrades = (*Coinbase)(nil)
+
+	_ types.Perpetuals = (*Binance)(nil)
+	_ types.Perpetuals = (*OKX)(nil)
+	_ types.Perpetuals = (*Bitget)(nil)
+	_ types.Perpetuals = (*Kucoin)(nil)
+	_ types.Perpetuals = (*Gate)(nil)
+	_ types.Perpetuals = (*Bybit)(nil)
+	_ types.Perpetuals = (*Huobi)(nil)
+	_ types.Perpetuals = (*BTSE)(nil)
+
+	_ types.TradingFees = (*Binance)(nil)
+	_ types.TradingFees = (*OKX)(nil)
+	_ types.TradingFees = (*Kucoin)(nil)
+	_ types.TradingFees = (*Gate)(nil)
+	_ types.TradingFees = (*Kraken)(nil)
+	_ types.TradingFees = (*Bybit)(nil)
+	_ types.TradingFees = (*Bitget)(nil)
+	_ types.TradingFees = (*MEXC)(nil)
+	_ types.TradingFees = (*Huobi)(nil)
+	_ types.TradingFees = (*Coinbase)(nil)
+
+	_ types.Trading = (*Binance)(nil)
+	_ types.Trading = (*OKX)(nil)
+	_ types.Trading = (*Kucoin)(nil)
+	_ types.Trading = (*Gate)(nil)
+	_ types.Trading = (*Kraken)(nil)
+	_ types.Trading = (*Bybit)(nil)
+	_ types.Trading = (*Bitget)(nil)
+	_ types.Trading = (*MEXC)(nil)
+	_ types.Trading = (*Huobi)(nil)
+	_ types.Trading = (*Coinbase)(nil)
+	_ types.Trading = (*BTSE)(nil)
+	_ types.Trading = (*Gemini)(nil)
+	_ types.Trading = (*Upbit)(nil)
+	_ types.Trading = (*CryptoCom)(nil)
+	_ types.Trading = (*BitMart)(nil)
+	_ types.Trading = (*Hyperliquid)(nil)
+	_ types.Trading = (*ConditionalEmulator)(nil)
+	_ types.Trading = (*PaperExchange)(nil)
+
+	_ types.Fills = (*Binance)(nil)
+	_ types.Fills = (*OKX)(nil)
+	_ types.Fills = (*Kucoin)(nil)
+	_ types.Fills = (*Gate)(nil)
+	_ types.Fills = (*Kraken)(nil)
+	_ types.Fills = (*Bybit)(nil)
+	_ types.Fills = (*Coinbase)(nil)
+	_ types.Fills = (*PaperExchange)(nil)
+
+	_ types.Exchange    = (*PaperExchange)(nil)
+	_ types.MarketData  = (*PaperExchange)(nil)
+	_ types.TradingFees = (*PaperExchange)(nil)
+	_ types.Balances    = (*PaperExchange)(nil)
+
+	_ types.HistoricalTrades = (*PaperExchange)(nil)
+	_ types.Perpetuals       = (*PaperExchange)(nil)
+	_ types.SymbolMapper     = (*PaperExchange)(nil)
+)
@@ -15,7 +15,9 @@ import (
 )
 
 type Kraken struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewKraken(config types.ExchangeConfig) *Kraken {
@@ -27,6 +29,12 @@ func (k *Kraken) Name() types.ExchangeName {
 }
 
 func (k *Kraken) GetDefaultBaseURL() string {
+	if k.config.MarketOrSpot() != types.Spot {
+		if k.config.Sandbox {
+			return "https://demo-futures.kraken.com"
+		}
+		return "https://futures.kraken.com"
+	}
 	return "https://api.kraken.com"
 }
 
@@ -41,6 +49,10 @@ func (k *Kraken) PrepareRequest(method, endpoint string, params map[string]inter
 		return nil, err
 	}
 
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.prepareFuturesRequest(method, u, endpoint, params, signed)
+	}
+
 	if params == nil {
 		params = make(map[string]interface{})
 	}
@@ -85,6 +97,59 @@ func (k *Kraken) PrepareRequest(method, endpoint string, params map[string]inter
 	return req, nil
 }
 
+// prepareFuturesRequest 处理 Kraken Futures 的请求，其签名方式与现货不同：
+// Authent = base64(HMAC-SHA512(base64decode(secret), SHA256(postData + nonce + endpointPath)))
+func (k *Kraken) prepareFuturesRequest(method string, u *url.URL, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	postData := url.Values{}
+	for key, value := range params {
+		postData.Set(key, fmt.Sprint(value))
+	}
+	encoded := postData.Encode()
+
+	var body string
+	if method == "GET" || method == "DELETE" {
+		u.RawQuery = encoded
+	} else {
+		body = encoded
+	}
+
+	req, err := http.NewRequest(method, u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		nonce := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+		signature, err := k.getFuturesSignature(strings.TrimPrefix(endpoint, "/derivatives"), encoded, nonce)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("APIKey", k.config.APIKey)
+		req.Header.Set("Nonce", nonce)
+		req.Header.Set("Authent", signature)
+	}
+
+	if body != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return req, nil
+}
+
+func (k *Kraken) getFuturesSignature(endpointPath, postData, nonce string) (string, error) {
+	sha256Sum := sha256.Sum256([]byte(postData + nonce + endpointPath))
+
+	decodedSecret, err := base64.StdEncoding.DecodeString(k.config.APISecret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode API secret: %v", err)
+	}
+
+	mac := hmac.New(sha512.New, decodedSecret)
+	mac.Write(sha256Sum[:])
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
 func (k *Kraken) getKrakenSignature(endpoint, postData, nonce string) (string, error) {
 	sha256Sum := sha256.Sum256([]byte(nonce + postData))
 	pathBytes := []byte(endpoint)
@@ -0,0 +1,606 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type krakenResponse[T any] struct {
+	Error  []string `json:"error"`
+	Result T        `json:"result"`
+}
+
+func (r *krakenResponse[T]) apiError() error {
+	if len(r.Error) > 0 {
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.Error[0], Message: strings.Join(r.Error, "; ")}
+	}
+	return nil
+}
+
+// krakenTicker 中各字段都是数组：a/b 为 [价格, 整手数量, 数量]，c 为 [价格, 数量]，v 为 [今日, 最近 24 小时]
+type krakenTicker struct {
+	A []jsonDecimal `json:"a"`
+	B []jsonDecimal `json:"b"`
+	C []jsonDecimal `json:"c"`
+	V []jsonDecimal `json:"v"`
+	P []jsonDecimal `json:"p"`
+}
+
+// GetTicker 的 symbol 可以是 XBTUSD 或 XXBTZUSD，Kraken 返回的 key 始终是后者
+func (k *Kraken) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresTicker(ctx, symbol)
+	}
+
+	var resp krakenResponse[map[string]krakenTicker]
+	if err := sendRequest(ctx, k, "GET", "/0/public/Ticker", map[string]interface{}{"pair": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+
+	for pair, t := range resp.Result {
+		ticker, ok := t.toTicker(pair)
+		if !ok {
+			return types.Ticker{}, fmt.Errorf("kraken ticker %s is malformed", pair)
+		}
+		return ticker, nil
+	}
+
+	return types.Ticker{}, fmt.Errorf("kraken ticker %s not found", symbol)
+}
+
+// toTicker 在字段不完整时返回 false
+func (t krakenTicker) toTicker(pair string) (types.Ticker, bool) {
+	if len(t.A) == 0 || len(t.B) == 0 || len(t.C) == 0 || len(t.V) < 2 || len(t.P) < 2 {
+		return types.Ticker{}, false
+	}
+
+	// Kraken 只提供成交量和 VWAP，成交额按 24 小时 VWAP 估算
+	return types.Ticker{
+		Symbol:      pair,
+		Last:        t.C[0].Decimal,
+		Bid:         t.B[0].Decimal,
+		Ask:         t.A[0].Decimal,
+		Volume:      t.V[1].Decimal,
+		QuoteVolume: t.V[1].Decimal.Mul(t.P[1].Decimal),
+	}, true
+}
+
+// krakenDepth 的档位为 [价格, 数量, 时间戳(秒)]
+type krakenDepth struct {
+	Asks [][]jsonDecimal `json:"asks"`
+	Bids [][]jsonDecimal `json:"bids"`
+}
+
+func (k *Kraken) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresOrderBook(ctx, symbol, depth)
+	}
+
+	params := map[string]interface{}{
+		"pair":  symbol,
+		"count": clampDepth(depth, 500),
+	}
+
+	var resp krakenResponse[map[string]krakenDepth]
+	if err := sendRequest(ctx, k, "GET", "/0/public/Depth", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	for pair, d := range resp.Result {
+		// Kraken 没有快照时间，取各档位中最新的更新时间
+		var latest decimal.Decimal
+		for _, row := range append(d.Bids, d.Asks...) {
+			if len(row) > 2 && row[2].GreaterThan(latest) {
+				latest = row[2].Decimal
+			}
+		}
+
+		return finalizeOrderBook(types.OrderBook{
+			Symbol:    pair,
+			Bids:      levelsFromRows(d.Bids),
+			Asks:      levelsFromRows(d.Asks),
+			Timestamp: msToTime(latest.Mul(decimal.NewFromInt(1000)).IntPart()),
+		}, depth), nil
+	}
+
+	return types.OrderBook{}, fmt.Errorf("kraken order book %s not found", symbol)
+}
+
+var krakenIntervals = map[types.Interval]string{
+	types.Interval1m: "1", types.Interval5m: "5", types.Interval15m: "15", types.Interval30m: "30",
+	types.Interval1h: "60", types.Interval4h: "240", types.Interval1d: "1440", types.Interval1w: "10080",
+}
+
+// GetCandles 中 Kraken 周期以分钟数表示，只返回最近 720 根，since 之前的数据无法获取；
+// 行格式为 [time(秒), open, high, low, close, vwap, volume, count]，成交额按 vwap 估算
+func (k *Kraken) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresCandles(ctx, symbol, interval, start, end, limit)
+	}
+
+	return fetchCandles(ctx, k, krakenIntervals, k.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"pair":     symbol,
+				"interval": venueInterval,
+			}
+			if !start.IsZero() {
+				params["since"] = start.Unix() - 1
+			}
+
+			var resp krakenResponse[map[string]json.RawMessage]
+			if err := sendRequest(ctx, k, "GET", "/0/public/OHLC", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			var candles []types.Candle
+			for key, raw := range resp.Result {
+				if key == "last" {
+					continue
+				}
+
+				var rows [][]jsonDecimal
+				if err := json.Unmarshal(raw, &rows); err != nil {
+					return nil, err
+				}
+				for _, row := range rows {
+					if len(row) < 7 {
+						continue
+					}
+					candle := candleFromRow(row, unixTime(row[0], time.Second), 1, 2, 3, 4, 6, -1)
+					candle.QuoteVolume = row[5].Mul(row[6].Decimal)
+					candles = append(candles, candle)
+				}
+			}
+			return candles, nil
+		})
+}
+
+// krakenTrades 请求 /0/public/Trades，行格式为 [price, volume, time(秒), b/s, m/l, misc, trade_id]，
+// last 为下一页的 since 游标（纳秒）
+func (k *Kraken) krakenTrades(ctx context.Context, symbol string, since string, limit int) ([]types.Trade, string, error) {
+	params := map[string]interface{}{"pair": symbol}
+	if since != "" {
+		params["since"] = since
+	}
+	if limit > 0 {
+		params["count"] = clampDepth(limit, 1000)
+	}
+
+	var resp krakenResponse[map[string]json.RawMessage]
+	if err := sendRequest(ctx, k, "GET", "/0/public/Trades", params, false, &resp); err != nil {
+		return nil, "", err
+	}
+
+	var trades []types.Trade
+	var last string
+	for key, raw := range resp.Result {
+		if key == "last" {
+			if err := json.Unmarshal(raw, &last); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		var rows [][]json.RawMessage
+		if err := json.Unmarshal(raw, &rows); err != nil {
+			return nil, "", err
+		}
+		for _, row := range rows {
+			if len(row) < 4 {
+				continue
+			}
+
+			var price, size, ts jsonDecimal
+			var side string
+			if err := json.Unmarshal(row[0], &price); err != nil {
+				return nil, "", err
+			}
+			if err := json.Unmarshal(row[1], &size); err != nil {
+				return nil, "", err
+			}
+			if err := json.Unmarshal(row[2], &ts); err != nil {
+				return nil, "", err
+			}
+			if err := json.Unmarshal(row[3], &side); err != nil {
+				return nil, "", err
+			}
+
+			trade := types.Trade{
+				Price: price.Decimal,
+				Size:  size.Decimal,
+				Side:  parseSide(side),
+				Time:  unixTime(ts, time.Second),
+			}
+			if len(row) > 6 {
+				var id jsonInt
+				if err := json.Unmarshal(row[6], &id); err == nil {
+					trade.ID = strconv.FormatInt(int64(id), 10)
+				}
+			}
+			trades = append(trades, trade)
+		}
+	}
+	return trades, last, nil
+}
+
+func (k *Kraken) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresTrades(ctx, symbol, limit)
+	}
+
+	trades, _, err := k.krakenTrades(ctx, symbol, "", limit)
+	if err != nil {
+		return nil, err
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// GetHistoricalTrades 从 Since（或游标）开始向后翻页，游标为 Kraken 返回的 last
+func (k *Kraken) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return types.TradePage{}, notSupported(k, "futures historical trades")
+	}
+
+	since := req.Cursor
+	if since == "" && !req.Since.IsZero() {
+		since = strconv.FormatInt(req.Since.UnixNano(), 10)
+	}
+
+	limit := clampDepth(req.Limit, 1000)
+	trades, last, err := k.krakenTrades(ctx, req.Symbol, since, limit)
+	if err != nil {
+		return types.TradePage{}, err
+	}
+
+	page := types.TradePage{Trades: sortTrades(trades, 0)}
+	if len(trades) == limit {
+		page.Cursor = last
+	}
+	return page, nil
+}
+
+// krakenAssetPair 的 base、quote 为 Kraken 内部资产代码（如 XXBT、ZUSD），wsname 为 XBT/USD 形式
+type krakenAssetPair struct {
+	Altname      string      `json:"altname"`
+	WSName       string      `json:"wsname"`
+	Base         string      `json:"base"`
+	Quote        string      `json:"quote"`
+	PairDecimals int         `json:"pair_decimals"`
+	LotDecimals  int         `json:"lot_decimals"`
+	OrderMin     jsonDecimal `json:"ordermin"`
+	CostMin      jsonDecimal `json:"costmin"`
+	TickSize     jsonDecimal `json:"tick_size"`
+	Status       string      `json:"status"`
+}
+
+// krakenStatus 中 limit_only、post_only 仍可下限价单，视为可交易；cancel_only、reduce_only 视为暂停
+func krakenStatus(status string) types.InstrumentStatus {
+	switch status {
+	case "online", "limit_only", "post_only":
+		return types.InstrumentTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+// GetInstruments 的 Symbol 为 AssetPairs 的 key（如 XXBTZUSD），与 GetTicker 返回的一致；
+// Base、Quote 取自 wsname，未做 XBT/BTC 之类的别名转换
+func (k *Kraken) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresInstruments(ctx)
+	}
+
+	return k.instruments.get(ctx, k.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp krakenResponse[map[string]krakenAssetPair]
+		if err := sendRequest(ctx, k, "GET", "/0/public/AssetPairs", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Result))
+		for key, p := range resp.Result {
+			base, quote, ok := strings.Cut(p.WSName, "/")
+			if !ok {
+				base, quote = p.Base, p.Quote
+			}
+
+			tick := p.TickSize.Decimal
+			if !tick.IsPositive() {
+				tick = precisionStep(p.PairDecimals)
+			}
+
+			instruments = append(instruments, types.Instrument{
+				Symbol:      key,
+				Base:        base,
+				Quote:       quote,
+				TickSize:    tick,
+				StepSize:    precisionStep(p.LotDecimals),
+				MinQty:      p.OrderMin.Decimal,
+				MinNotional: p.CostMin.Decimal,
+				Status:      krakenStatus(p.Status),
+			})
+		}
+		sort.Slice(instruments, func(i, j int) bool { return instruments[i].Symbol < instruments[j].Symbol })
+		return instruments, nil
+	})
+}
+
+// GetAllTickers 使用不带 pair 的 Ticker 接口，字段不完整的交易对被跳过
+func (k *Kraken) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return k.futuresAllTickers(ctx)
+	}
+
+	var resp krakenResponse[map[string]krakenTicker]
+	if err := sendRequest(ctx, k, "GET", "/0/public/Ticker", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Result))
+	for pair, t := range resp.Result {
+		if ticker, ok := t.toTicker(pair); ok {
+			tickers = append(tickers, ticker)
+		}
+	}
+	return keyTickers(ctx, k, tickers)
+}
+
+// krakenFuturesResponse 是 Kraken Futures 接口的公共字段，result 为 error 时 error 给出原因
+type krakenFuturesResponse struct {
+	Result     string `json:"result"`
+	Error      string `json:"error"`
+	ServerTime string `json:"serverTime"`
+}
+
+func (r *krakenFuturesResponse) apiError() error {
+	if r.Result == "error" {
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.Error, Message: r.Error}
+	}
+	return nil
+}
+
+// futuresPrefix 返回当前产品线永续合约的代码前缀：PF_ 为线性合约，PI_ 为以 1 美元为一张的反向合约
+func (k *Kraken) futuresPrefix() string {
+	if k.config.MarketOrSpot() == types.CoinMFutures {
+		return "PI_"
+	}
+	return "PF_"
+}
+
+// krakenTime 解析 Kraken Futures 的 RFC 3339 时间，无法解析时返回零值
+func krakenTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// krakenFuturesTicker 中 vol24h 为合约张数，volumeQuote 为以计价币计的成交额
+type krakenFuturesTicker struct {
+	Symbol      string      `json:"symbol"`
+	Last        jsonDecimal `json:"last"`
+	LastTime    string      `json:"lastTime"`
+	Bid         jsonDecimal `json:"bid"`
+	Ask         jsonDecimal `json:"ask"`
+	Vol24h      jsonDecimal `json:"vol24h"`
+	VolumeQuote jsonDecimal `json:"volumeQuote"`
+}
+
+// toTicker 中反向合约的 Volume 按成交额和最新价折算为基础币
+func (t krakenFuturesTicker) toTicker(inverse bool) types.Ticker {
+	volume := t.Vol24h.Decimal
+	if inverse && t.Last.IsPositive() {
+		volume = t.VolumeQuote.Div(t.Last.Decimal)
+	}
+
+	return types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.Last.Decimal,
+		Bid:         t.Bid.Decimal,
+		Ask:         t.Ask.Decimal,
+		Volume:      volume,
+		QuoteVolume: t.VolumeQuote.Decimal,
+		Timestamp:   krakenTime(t.LastTime),
+	}
+}
+
+// futuresTicker 的 symbol 为 Kraken Futures 合约代码，例如 PF_XBTUSD
+func (k *Kraken) futuresTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var resp struct {
+		krakenFuturesResponse
+		Ticker krakenFuturesTicker `json:"ticker"`
+	}
+	if err := sendRequest(ctx, k, "GET", "/derivatives/api/v3/tickers/"+symbol, nil, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+	if resp.Ticker.Symbol == "" {
+		return types.Ticker{}, fmt.Errorf("kraken futures ticker %s not found", symbol)
+	}
+
+	return resp.Ticker.toTicker(k.config.MarketOrSpot() == types.CoinMFutures), nil
+}
+
+// futuresOrderBook 返回完整订单簿，数量单位为合约张数
+func (k *Kraken) futuresOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	var resp struct {
+		krakenFuturesResponse
+		OrderBook krakenDepth `json:"orderBook"`
+	}
+	if err := sendRequest(ctx, k, "GET", "/derivatives/api/v3/orderbook", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(resp.OrderBook.Bids),
+		Asks:      levelsFromRows(resp.OrderBook.Asks),
+		Timestamp: krakenTime(resp.ServerTime),
+	}, depth), nil
+}
+
+var krakenFuturesIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval5m: "5m", types.Interval15m: "15m", types.Interval30m: "30m",
+	types.Interval1h: "1h", types.Interval4h: "4h", types.Interval12h: "12h", types.Interval1d: "1d",
+	types.Interval1w: "1w",
+}
+
+// krakenFuturesCandle 的 time 为毫秒，volume 为合约张数
+type krakenFuturesCandle struct {
+	Time   int64       `json:"time"`
+	Open   jsonDecimal `json:"open"`
+	High   jsonDecimal `json:"high"`
+	Low    jsonDecimal `json:"low"`
+	Close  jsonDecimal `json:"close"`
+	Volume jsonDecimal `json:"volume"`
+}
+
+// futuresCandles 使用 charts 接口，from、to 以秒为单位；未指定 start 时按 limit 从 end 往前推算 from
+func (k *Kraken) futuresCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, k, krakenFuturesIntervals, k.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{}
+			if start.IsZero() && limit > 0 {
+				to := end
+				if to.IsZero() {
+					to = time.Now()
+				}
+				start = to.Add(-time.Duration(limit) * interval.Duration())
+			}
+			if !start.IsZero() {
+				params["from"] = start.Unix()
+			}
+			if !end.IsZero() {
+				params["to"] = end.Unix()
+			}
+
+			var resp struct {
+				Candles []krakenFuturesCandle `json:"candles"`
+			}
+			if err := sendRequest(ctx, k, "GET", "/api/charts/v1/trade/"+symbol+"/"+venueInterval, params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Candles))
+			for _, c := range resp.Candles {
+				candles = append(candles, types.Candle{
+					OpenTime: msToTime(c.Time),
+					Open:     c.Open.Decimal,
+					High:     c.High.Decimal,
+					Low:      c.Low.Decimal,
+					Close:    c.Close.Decimal,
+					Volume:   c.Volume.Decimal,
+				})
+			}
+			return candles, nil
+		})
+}
+
+// krakenFuturesTrade 的 side 为 taker 方向
+type krakenFuturesTrade struct {
+	Time    string      `json:"time"`
+	TradeID int64       `json:"trade_id"`
+	Price   jsonDecimal `json:"price"`
+	Size    jsonDecimal `json:"size"`
+	Side    string      `json:"side"`
+}
+
+// futuresTrades 使用 history 接口，只返回最近 100 笔，Size 为合约张数
+func (k *Kraken) futuresTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	var resp struct {
+		krakenFuturesResponse
+		History []krakenFuturesTrade `json:"history"`
+	}
+	if err := sendRequest(ctx, k, "GET", "/derivatives/api/v3/history", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.History))
+	for _, t := range resp.History {
+		trades = append(trades, types.Trade{
+			ID:    strconv.FormatInt(t.TradeID, 10),
+			Price: t.Price.Decimal,
+			Size:  t.Size.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  krakenTime(t.Time),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// krakenFuturesInstrument 中 contractValueTradePrecision 为下单数量的小数位数，可以为负数
+type krakenFuturesInstrument struct {
+	Symbol                      string      `json:"symbol"`
+	Base                        string      `json:"base"`
+	Quote                       string      `json:"quote"`
+	Tradeable                   bool        `json:"tradeable"`
+	TickSize                    jsonDecimal `json:"tickSize"`
+	ContractSize                jsonDecimal `json:"contractSize"`
+	ContractValueTradePrecision int         `json:"contractValueTradePrecision"`
+}
+
+// futuresInstruments 只返回当前产品线的永续合约。反向合约没有 base、quote 字段，从合约代码（如 PI_XBTUSD）中解析
+func (k *Kraken) futuresInstruments(ctx context.Context) ([]types.Instrument, error) {
+	prefix := k.futuresPrefix()
+	return k.instruments.get(ctx, k.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp struct {
+			krakenFuturesResponse
+			Instruments []krakenFuturesInstrument `json:"instruments"`
+		}
+		if err := sendRequest(ctx, k, "GET", "/derivatives/api/v3/instruments", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		var instruments []types.Instrument
+		for _, i := range resp.Instruments {
+			pair, ok := strings.CutPrefix(i.Symbol, prefix)
+			if !ok {
+				continue
+			}
+
+			base, quote := i.Base, i.Quote
+			if base == "" && len(pair) > 3 {
+				base, quote = pair[:len(pair)-3], pair[len(pair)-3:]
+			}
+			contractSize := i.ContractSize.Decimal
+			if !contractSize.IsPositive() {
+				contractSize = decimal.NewFromInt(1)
+			}
+
+			instruments = append(instruments, types.Instrument{
+				Symbol:       i.Symbol,
+				Base:         base,
+				Quote:        quote,
+				TickSize:     i.TickSize.Decimal,
+				StepSize:     precisionStep(i.ContractValueTradePrecision),
+				ContractSize: contractSize,
+				Status:       tradingStatus(i.Tradeable),
+			})
+		}
+		sort.Slice(instruments, func(i, j int) bool { return instruments[i].Symbol < instruments[j].Symbol })
+		return instruments, nil
+	})
+}
+
+func (k *Kraken) futuresAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	var resp struct {
+		krakenFuturesResponse
+		Tickers []krakenFuturesTicker `json:"tickers"`
+	}
+	if err := sendRequest(ctx, k, "GET", "/derivatives/api/v3/tickers", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	inverse := k.config.MarketOrSpot() == types.CoinMFutures
+	tickers := make([]types.Ticker, 0, len(resp.Tickers))
+	for _, t := range resp.Tickers {
+		tickers = append(tickers, t.toTicker(inverse))
+	}
+	return keyTickers(ctx, k, tickers)
+}
@@ -0,0 +1,536 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type krakenFeeTier struct {
+	Fee jsonDecimal `json:"fee"`
+}
+
+type krakenTradeVolume struct {
+	Fees      map[string]krakenFeeTier `json:"fees"`
+	FeesMaker map[string]krakenFeeTier `json:"fees_maker"`
+}
+
+// GetTradingFees 使用 TradeVolume。Kraken 以百分比返回费率，这里换算为小数；
+// 结果的键是 AssetPairs 的名称（如 XXBTZUSD），即使传入的是 XBTUSD 这类 altname
+func (k *Kraken) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures trading fees")
+	}
+
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = instrumentSymbols(ctx, k); err != nil {
+			return nil, err
+		}
+	}
+
+	var resp krakenResponse[krakenTradeVolume]
+	if err := sendRequest(ctx, k, "POST", "/0/private/TradeVolume", map[string]interface{}{"pair": strings.Join(symbols, ",")}, true, &resp); err != nil {
+		return nil, err
+	}
+
+	hundred := decimal.NewFromInt(100)
+	fees := make(map[string]types.TradingFee, len(resp.Result.Fees))
+	for pair, taker := range resp.Result.Fees {
+		fee := types.TradingFee{Symbol: pair, Maker: taker.Fee.Div(hundred), Taker: taker.Fee.Div(hundred)}
+		if maker, ok := resp.Result.FeesMaker[pair]; ok {
+			fee.Maker = maker.Fee.Div(hundred)
+		}
+		fees[pair] = fee
+	}
+	return fees, nil
+}
+
+type krakenOrderAck struct {
+	Txid []string `json:"txid"`
+}
+
+// orderParams 把统一的下单请求转换为 AddOrder 参数；Kraken 不支持 FOK，也不支持按计价币金额下市价单
+func (k *Kraken) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(k, "orders by quote quantity")
+	}
+
+	ordertype, ok := krakenOrderTypes[req.Type]
+	if !ok {
+		return nil, notSupported(k, strings.ToLower(string(req.Type))+" orders")
+	}
+
+	params := map[string]interface{}{
+		"pair":      req.Symbol,
+		"type":      lowerSide(req.Side),
+		"ordertype": ordertype,
+		"volume":    req.Quantity.String(),
+	}
+	// 条件单的 price 是触发价，限价条件单的 price2 是触发后的委托价
+	if req.Type.Conditional() {
+		params["price"] = req.TriggerPrice.String()
+		if req.Type.Priced() {
+			params["price2"] = req.Price.String()
+		}
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		switch timeInForce(req) {
+		case types.IOC:
+			params["timeinforce"] = "IOC"
+		case types.FOK:
+			return nil, notSupported(k, "fill-or-kill orders")
+		}
+		if req.PostOnly {
+			params["oflags"] = "post"
+		}
+	}
+	if req.ClientOrderID != "" {
+		params["cl_ord_id"] = req.ClientOrderID
+	}
+	// reduce_only 只对保证金交易生效
+	if req.ReduceOnly {
+		params["reduce_only"] = true
+	}
+	return params, nil
+}
+
+func (k *Kraken) placer() placer {
+	return placer{t: k, config: k.config, idLength: clientOrderIDLength, uniqueIDs: true, place: k.placeOrder}
+}
+
+func (k *Kraken) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return k.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单
+func (k *Kraken) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+	return k.placer().placeOrders(ctx, reqs), nil
+}
+
+func (k *Kraken) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := k.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp krakenResponse[krakenOrderAck]
+	if err := sendRequest(ctx, k, "POST", "/0/private/AddOrder", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	if len(resp.Result.Txid) == 0 {
+		return types.Order{}, fmt.Errorf("kraken order %s returned no txid", req.Symbol)
+	}
+	return orderFromRequest(req, resp.Result.Txid[0]), nil
+}
+
+// krakenOrderTypes 是统一订单类型与 Kraken ordertype 的对应关系，Kraken 不支持 OCO
+var krakenOrderTypes = map[types.OrderType]string{
+	types.LimitOrder:            "limit",
+	types.MarketOrder:           "market",
+	types.StopMarketOrder:       "stop-loss",
+	types.StopLimitOrder:        "stop-loss-limit",
+	types.TakeProfitMarketOrder: "take-profit",
+	types.TakeProfitLimitOrder:  "take-profit-limit",
+}
+
+// krakenOrder 是 OpenOrders 等接口中的订单，descr.pair 为 altname（如 XBTUSD），时间为秒级时间戳；
+// price 是成交均价，cost、fee 以计价币计
+type krakenOrder struct {
+	ClOrdID string      `json:"cl_ord_id"`
+	Status  string      `json:"status"`
+	Opentm  jsonDecimal `json:"opentm"`
+	Closetm jsonDecimal `json:"closetm"`
+	Vol     jsonDecimal `json:"vol"`
+	VolExec jsonDecimal `json:"vol_exec"`
+	Cost    jsonDecimal `json:"cost"`
+	Fee     jsonDecimal `json:"fee"`
+	Price   jsonDecimal `json:"price"`
+	Oflags  string      `json:"oflags"`
+	Descr   struct {
+		Pair      string      `json:"pair"`
+		Type      string      `json:"type"`
+		OrderType string      `json:"ordertype"`
+		Price     jsonDecimal `json:"price"`
+		Price2    jsonDecimal `json:"price2"`
+	} `json:"descr"`
+}
+
+func (o krakenOrder) toOrder(txid string) types.Order {
+	order := types.Order{
+		ID:            txid,
+		ClientOrderID: o.ClOrdID,
+		Symbol:        o.Descr.Pair,
+		Side:          parseSide(o.Descr.Type),
+		Type:          types.OrderType(strings.ToUpper(o.Descr.OrderType)),
+		Price:         o.Descr.Price.Decimal,
+		Quantity:      o.Vol.Decimal,
+
+		Status:         orderStatus(krakenStatuses, o.Status),
+		FilledQuantity: o.VolExec.Decimal,
+		AveragePrice:   o.Price.Decimal,
+		Fee:            o.Fee.Decimal,
+		Raw:            o.Status,
+		CreatedAt:      unixTime(o.Opentm, time.Second),
+	}
+	if order.Status == types.OrderNew {
+		order.Status = openStatus(o.VolExec.Decimal)
+	}
+	if order.AveragePrice.IsZero() {
+		order.AveragePrice = averagePrice(o.Cost.Decimal, o.VolExec.Decimal)
+	}
+	if o.Closetm.IsPositive() {
+		order.UpdatedAt = unixTime(o.Closetm, time.Second)
+	}
+	for typ, name := range krakenOrderTypes {
+		if name == o.Descr.OrderType {
+			order.Type = typ
+		}
+	}
+	if order.Type.Conditional() {
+		order.TriggerPrice, order.Price = o.Descr.Price.Decimal, o.Descr.Price2.Decimal
+	}
+	if order.Type == types.LimitOrder {
+		order.TimeInForce = types.GTC
+	}
+	return order
+}
+
+// krakenStatuses 中 closed 表示完全成交，open 按成交数量区分是否部分成交
+var krakenStatuses = map[string]types.OrderStatus{
+	"pending":  types.OrderNew,
+	"open":     types.OrderNew,
+	"closed":   types.OrderFilled,
+	"canceled": types.OrderCanceled,
+	"expired":  types.OrderExpired,
+}
+
+// krakenOrderNotFound 是订单不存在时的错误
+const krakenOrderNotFound = "EOrder:Invalid order"
+
+// krakenCancelAck 只包含撤销的订单数量
+type krakenCancelAck struct {
+	Count int `json:"count"`
+}
+
+func (k *Kraken) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if k.config.MarketOrSpot() != types.Spot {
+		return notSupported(k, "futures orders")
+	}
+
+	params := map[string]interface{}{"txid": ref.ID}
+	if ref.ID == "" {
+		params = map[string]interface{}{"cl_ord_id": ref.ClientOrderID}
+	}
+
+	var resp krakenResponse[krakenCancelAck]
+	return sendRequest(ctx, k, "POST", "/0/private/CancelOrder", params, true, &resp)
+}
+
+// pairNames 返回交易对的 AssetPairs 名称和 altname，挂单中的交易对使用 altname
+func (k *Kraken) pairNames(ctx context.Context, symbol string) (map[string]bool, error) {
+	var resp krakenResponse[map[string]krakenAssetPair]
+	if err := sendRequest(ctx, k, "GET", "/0/public/AssetPairs", map[string]interface{}{"pair": symbol}, false, &resp); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, 2*len(resp.Result))
+	for key, p := range resp.Result {
+		names[key], names[p.Altname] = true, true
+	}
+	return names, nil
+}
+
+// krakenOrders 是 OpenOrders 与 ClosedOrders 的结果，count 是 ClosedOrders 符合条件的订单总数
+type krakenOrders struct {
+	Open   map[string]krakenOrder `json:"open"`
+	Closed map[string]krakenOrder `json:"closed"`
+	Count  int                    `json:"count"`
+}
+
+// krakenOrderList 把以 txid 为键的订单转换为按创建时间升序排列的列表，names 不为空时只保留其中的交易对
+func krakenOrderList(rows map[string]krakenOrder, names map[string]bool) []types.Order {
+	orders := make([]types.Order, 0, len(rows))
+	for txid, row := range rows {
+		if names == nil || names[row.Descr.Pair] {
+			orders = append(orders, row.toOrder(txid))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.Before(orders[j].CreatedAt) })
+	return orders
+}
+
+// symbolNames 在指定了交易对时返回它的全部名称，否则返回 nil 表示不过滤
+func (k *Kraken) symbolNames(ctx context.Context, symbol string) (map[string]bool, error) {
+	if symbol == "" {
+		return nil, nil
+	}
+	return k.pairNames(ctx, symbol)
+}
+
+// openOrders 返回当前挂单，symbol 为空时返回全部交易对的挂单
+func (k *Kraken) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	names, err := k.symbolNames(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp krakenResponse[krakenOrders]
+	if err := sendRequest(ctx, k, "POST", "/0/private/OpenOrders", nil, true, &resp); err != nil {
+		return nil, err
+	}
+	return krakenOrderList(resp.Result.Open, names), nil
+}
+
+// krakenEditAck 是 EditOrder 的结果，txid 是代替原订单的新订单
+type krakenEditAck struct {
+	Txid         string `json:"txid"`
+	OriginalTxid string `json:"originaltxid"`
+}
+
+// AmendOrder 使用 EditOrder，Kraken 在一次请求中撤销原订单并以新的 txid 下单，新订单失去排队位置。
+// EditOrder 只接受 txid，按自定义订单号修改时先查询订单
+func (k *Kraken) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+	if k.config.MarketOrSpot() != types.Spot {
+		return types.AmendResult{}, notSupported(k, "futures orders")
+	}
+
+	txid := req.Ref.ID
+	if txid == "" {
+		orig, err := k.GetOrder(ctx, req.Symbol, req.Ref)
+		if err != nil {
+			return types.AmendResult{}, err
+		}
+		txid = orig.ID
+	}
+
+	params := map[string]interface{}{"txid": txid, "pair": req.Symbol}
+	if req.Price.IsPositive() {
+		params["price"] = req.Price.String()
+	}
+	if req.Quantity.IsPositive() {
+		params["volume"] = req.Quantity.String()
+	}
+
+	var resp krakenResponse[krakenEditAck]
+	if err := sendRequest(ctx, k, "POST", "/0/private/EditOrder", params, true, &resp); err != nil {
+		return types.AmendResult{}, err
+	}
+	return types.AmendResult{Order: amendedOrder(req, resp.Result.Txid, ""), Replaced: true}, nil
+}
+
+// CancelOrders 并发地逐个撤单
+func (k *Kraken) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+	return cancelOrders(ctx, symbol, refs, 0, nil, k.CancelOrder), nil
+}
+
+// CancelAllOrders 未指定交易对时调用原生的 CancelAll，它只返回撤销数量，结果取自撤销前的挂单快照；
+// 指定交易对时列出该交易对的挂单后逐个撤销
+func (k *Kraken) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+
+	orders, err := k.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbol != "" {
+		return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+			return k.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+		}), nil
+	}
+
+	var resp krakenResponse[krakenCancelAck]
+	if err := sendRequest(ctx, k, "POST", "/0/private/CancelAll", nil, true, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]types.CancelResult, 0, len(orders))
+	for _, order := range orders {
+		results = append(results, cancelResult(order, nil))
+	}
+	return results, nil
+}
+
+// GetOrder 中 symbol 不参与查询；按自定义订单号查询时依次查找挂单和已结束的订单
+func (k *Kraken) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if k.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(k, "futures orders")
+	}
+
+	if ref.ID != "" {
+		var resp krakenResponse[map[string]krakenOrder]
+		if err := sendRequest(ctx, k, "POST", "/0/private/QueryOrders", map[string]interface{}{"txid": ref.ID}, true, &resp); err != nil {
+			return types.Order{}, orderNotFound(err, krakenOrderNotFound)
+		}
+		if row, ok := resp.Result[ref.ID]; ok {
+			return row.toOrder(ref.ID), nil
+		}
+		return types.Order{}, fmt.Errorf("kraken order %s: %w", ref.ID, types.ErrOrderNotFound)
+	}
+
+	for _, endpoint := range []string{"/0/private/OpenOrders", "/0/private/ClosedOrders"} {
+		var resp krakenResponse[krakenOrders]
+		if err := sendRequest(ctx, k, "POST", endpoint, map[string]interface{}{"cl_ord_id": ref.ClientOrderID}, true, &resp); err != nil {
+			return types.Order{}, err
+		}
+		for txid, row := range resp.Result.Open {
+			return row.toOrder(txid), nil
+		}
+		for txid, row := range resp.Result.Closed {
+			return row.toOrder(txid), nil
+		}
+	}
+	return types.Order{}, fmt.Errorf("kraken order %s: %w", ref.ClientOrderID, types.ErrOrderNotFound)
+}
+
+func (k *Kraken) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+	return k.openOrders(ctx, symbol)
+}
+
+// krakenClosedPage 是 ClosedOrders 与 TradesHistory 每页返回的记录数量
+const krakenClosedPage = 50
+
+// GetOrderHistory 使用 ClosedOrders 按 ofs 翻页，接口不能按交易对过滤，指定交易对时在本地过滤
+func (k *Kraken) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures orders")
+	}
+
+	names, err := k.symbolNames(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{}
+	if !req.Start.IsZero() {
+		params["start"] = req.Start.Unix()
+	}
+	if !req.End.IsZero() {
+		params["end"] = req.End.Unix()
+	}
+
+	var orders []types.Order
+	for offset := 0; req.Limit <= 0 || len(orders) < req.Limit; offset += krakenClosedPage {
+		params["ofs"] = offset
+
+		var resp krakenResponse[krakenOrders]
+		if err := sendRequest(ctx, k, "POST", "/0/private/ClosedOrders", params, true, &resp); err != nil {
+			return nil, err
+		}
+		orders = append(orders, krakenOrderList(resp.Result.Closed, names)...)
+		if len(resp.Result.Closed) < krakenClosedPage || offset+krakenClosedPage >= resp.Result.Count {
+			break
+		}
+	}
+	return orderHistory(orders, req), nil
+}
+
+// krakenFill 是 TradesHistory 中的成交，pair 为 AssetPairs 的 key，time 为秒级时间戳；
+// 手续费默认以计价币收取，接口不返回币种
+type krakenFill struct {
+	OrderTxID string      `json:"ordertxid"`
+	Pair      string      `json:"pair"`
+	Time      jsonDecimal `json:"time"`
+	Type      string      `json:"type"`
+	Price     jsonDecimal `json:"price"`
+	Fee       jsonDecimal `json:"fee"`
+	Vol       jsonDecimal `json:"vol"`
+	Maker     bool        `json:"maker"`
+}
+
+func (f krakenFill) toFill(txid string) types.Fill {
+	return types.Fill{
+		Symbol:    f.Pair,
+		OrderID:   f.OrderTxID,
+		TradeID:   txid,
+		Side:      parseSide(f.Type),
+		Price:     f.Price.Decimal,
+		Quantity:  f.Vol.Decimal,
+		Fee:       f.Fee.Decimal,
+		Liquidity: liquidity(f.Maker),
+		Time:      unixTime(f.Time, time.Second),
+	}
+}
+
+// krakenTrades 是 TradesHistory 的结果，count 是符合条件的成交总数
+type krakenTrades struct {
+	Trades map[string]krakenFill `json:"trades"`
+	Count  int                   `json:"count"`
+}
+
+// GetFills 使用 TradesHistory 按 ofs 翻页，接口不能按交易对过滤，指定交易对时在本地过滤；
+// FeeAsset 取自交易对的计价币
+func (k *Kraken) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures fills")
+	}
+
+	names, err := k.symbolNames(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	instruments, err := k.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quotes := make(map[string]string, len(instruments))
+	for _, in := range instruments {
+		quotes[in.Symbol] = in.Quote
+	}
+
+	params := map[string]interface{}{}
+	if !since.IsZero() {
+		params["start"] = since.Unix() - 1
+	}
+
+	var fills []types.Fill
+	for offset := 0; ; offset += krakenClosedPage {
+		params["ofs"] = offset
+
+		var resp krakenResponse[krakenTrades]
+		if err := sendRequest(ctx, k, "POST", "/0/private/TradesHistory", params, true, &resp); err != nil {
+			return nil, err
+		}
+		for txid, row := range resp.Result.Trades {
+			if names == nil || names[row.Pair] {
+				fill := row.toFill(txid)
+				fill.FeeAsset = quotes[row.Pair]
+				fills = append(fills, fill)
+			}
+		}
+		if len(resp.Result.Trades) < krakenClosedPage || offset+krakenClosedPage >= resp.Result.Count {
+			break
+		}
+	}
+	return fillHistory(fills, since), nil
+}
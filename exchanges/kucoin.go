@@ -15,7 +15,9 @@ import (
 )
 
 type Kucoin struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewKucoin(config types.ExchangeConfig) *Kucoin {
@@ -27,6 +29,9 @@ func (k *Kucoin) Name() types.ExchangeName {
 }
 
 func (k *Kucoin) GetDefaultBaseURL() string {
+	if k.config.MarketOrSpot() != types.Spot {
+		return "https://api-futures.kucoin.com"
+	}
 	return "https://api.kucoin.com"
 }
 
@@ -42,7 +47,7 @@ func (k *Kucoin) PrepareRequest(method, endpoint string, params map[string]inter
 	}
 
 	body := []byte{}
-	if method == "GET" {
+	if method == "GET" || method == "DELETE" {
 		q := u.Query()
 		for key, value := range params {
 			q.Set(key, fmt.Sprint(value))
@@ -62,10 +67,8 @@ func (k *Kucoin) PrepareRequest(method, endpoint string, params map[string]inter
 
 	if signed {
 		timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
-		message := timestamp + method + endpoint
-		if method != "GET" {
-			message += string(body)
-		}
+		// 签名路径包含查询字符串，GET、DELETE 没有请求体
+		message := timestamp + method + u.RequestURI() + string(body)
 
 		mac := hmac.New(sha256.New, []byte(k.config.APISecret))
 		mac.Write([]byte(message))
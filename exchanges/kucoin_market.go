@@ -0,0 +1,327 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type kucoinResponse[T any] struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+func (r *kucoinResponse[T]) apiError() error {
+	if r.Code != "200000" {
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.Code, Message: r.Msg}
+	}
+	return nil
+}
+
+type kucoinStats struct {
+	Symbol   string      `json:"symbol"`
+	Time     jsonInt     `json:"time"`
+	Buy      jsonDecimal `json:"buy"`
+	Sell     jsonDecimal `json:"sell"`
+	Last     jsonDecimal `json:"last"`
+	Vol      jsonDecimal `json:"vol"`
+	VolValue jsonDecimal `json:"volValue"`
+}
+
+func (k *Kucoin) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return types.Ticker{}, notSupported(k, "futures ticker")
+	}
+
+	var resp kucoinResponse[kucoinStats]
+	if err := sendRequest(ctx, k, "GET", "/api/v1/market/stats", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+
+	return resp.Data.toTicker(), nil
+}
+
+func (t kucoinStats) toTicker() types.Ticker {
+	return types.Ticker{
+		Symbol:      t.Symbol,
+		Last:        t.Last.Decimal,
+		Bid:         t.Buy.Decimal,
+		Ask:         t.Sell.Decimal,
+		Volume:      t.Vol.Decimal,
+		QuoteVolume: t.VolValue.Decimal,
+		Timestamp:   msToTime(int64(t.Time)),
+	}
+}
+
+type kucoinBook struct {
+	Sequence jsonInt         `json:"sequence"`
+	Time     int64           `json:"time"`
+	Bids     [][]jsonDecimal `json:"bids"`
+	Asks     [][]jsonDecimal `json:"asks"`
+}
+
+// GetOrderBook 使用公开的 level2_20 或 level2_100 快照
+func (k *Kucoin) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return types.OrderBook{}, notSupported(k, "futures order book")
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/market/orderbook/level2_%d", nearestDepth(depth, []int{20, 100}))
+
+	var resp kucoinResponse[kucoinBook]
+	if err := sendRequest(ctx, k, "GET", endpoint, map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(resp.Data.Bids),
+		Asks:      levelsFromRows(resp.Data.Asks),
+		Sequence:  int64(resp.Data.Sequence),
+		Timestamp: msToTime(resp.Data.Time),
+	}, depth), nil
+}
+
+var kucoinIntervals = map[types.Interval]string{
+	types.Interval1m: "1min", types.Interval3m: "3min", types.Interval5m: "5min", types.Interval15m: "15min",
+	types.Interval30m: "30min", types.Interval1h: "1hour", types.Interval2h: "2hour", types.Interval4h: "4hour",
+	types.Interval6h: "6hour", types.Interval8h: "8hour", types.Interval12h: "12hour", types.Interval1d: "1day",
+	types.Interval1w: "1week", types.Interval1M: "1month",
+}
+
+// GetCandles 中 KuCoin 返回 [time(秒), open, close, high, low, volume, turnover]，注意收盘价在最高价之前，单次最多 1500 根
+func (k *Kucoin) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures candles")
+	}
+
+	return fetchCandles(ctx, k, kucoinIntervals, k.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol": symbol,
+				"type":   venueInterval,
+			}
+			if !start.IsZero() {
+				params["startAt"] = start.Unix()
+			}
+			if !end.IsZero() {
+				params["endAt"] = end.Unix()
+			}
+
+			var resp kucoinResponse[[][]jsonDecimal]
+			if err := sendRequest(ctx, k, "GET", "/api/v1/market/candles", params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Data))
+			for _, row := range resp.Data {
+				if len(row) < 7 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, unixTime(row[0], time.Second), 1, 3, 4, 2, 5, 6))
+			}
+			return candles, nil
+		})
+}
+
+type kucoinTrade struct {
+	Sequence string      `json:"sequence"`
+	Price    jsonDecimal `json:"price"`
+	Size     jsonDecimal `json:"size"`
+	Side     string      `json:"side"`
+	Time     int64       `json:"time"`
+}
+
+// GetRecentTrades 固定返回最近 100 笔成交，时间为纳秒
+func (k *Kucoin) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures trades")
+	}
+
+	var resp kucoinResponse[[]kucoinTrade]
+	if err := sendRequest(ctx, k, "GET", "/api/v1/market/histories", map[string]interface{}{"symbol": symbol}, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		trades = append(trades, types.Trade{
+			ID:    t.Sequence,
+			Price: t.Price.Decimal,
+			Size:  t.Size.Decimal,
+			Side:  parseSide(t.Side),
+			Time:  time.Unix(0, t.Time),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+type kucoinSymbol struct {
+	Symbol         string      `json:"symbol"`
+	BaseCurrency   string      `json:"baseCurrency"`
+	QuoteCurrency  string      `json:"quoteCurrency"`
+	BaseMinSize    jsonDecimal `json:"baseMinSize"`
+	BaseMaxSize    jsonDecimal `json:"baseMaxSize"`
+	BaseIncrement  jsonDecimal `json:"baseIncrement"`
+	PriceIncrement jsonDecimal `json:"priceIncrement"`
+	MinFunds       jsonDecimal `json:"minFunds"`
+	EnableTrading  bool        `json:"enableTrading"`
+}
+
+func (k *Kucoin) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures instruments")
+	}
+
+	return k.instruments.get(ctx, k.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp kucoinResponse[[]kucoinSymbol]
+		if err := sendRequest(ctx, k, "GET", "/api/v2/symbols", nil, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Data))
+		for _, s := range resp.Data {
+			instruments = append(instruments, types.Instrument{
+				Symbol:      s.Symbol,
+				Base:        s.BaseCurrency,
+				Quote:       s.QuoteCurrency,
+				TickSize:    s.PriceIncrement.Decimal,
+				StepSize:    s.BaseIncrement.Decimal,
+				MinQty:      s.BaseMinSize.Decimal,
+				MaxQty:      s.BaseMaxSize.Decimal,
+				MinNotional: s.MinFunds.Decimal,
+				Status:      tradingStatus(s.EnableTrading),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// kucoinFundingRate 中 value 为当前周期费率，fundingTime 为结算时间，granularity 为结算间隔（毫秒）
+type kucoinFundingRate struct {
+	Symbol      string      `json:"symbol"`
+	Granularity int64       `json:"granularity"`
+	TimePoint   int64       `json:"timePoint"`
+	Value       jsonDecimal `json:"value"`
+	FundingTime int64       `json:"fundingTime"`
+}
+
+// GetFundingRate 的 symbol 为 KuCoin 合约代码，例如 XBTUSDTM
+func (k *Kucoin) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	if err := requirePerpetual(k, k.config.MarketOrSpot()); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	var resp kucoinResponse[kucoinFundingRate]
+	if err := sendRequest(ctx, k, "GET", "/api/v1/funding-rate/"+symbol+"/current", nil, false, &resp); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	f := resp.Data
+	return types.FundingRate{
+		Symbol:          symbol,
+		Rate:            f.Value.Decimal,
+		NextFundingTime: msToTime(f.FundingTime),
+		Interval:        time.Duration(f.Granularity) * time.Millisecond,
+		Timestamp:       msToTime(f.TimePoint),
+	}, nil
+}
+
+// GetFundingHistory 中 KuCoin 要求同时指定 from 和 to，未指定时分别取 limit 个默认间隔之前和当前时间
+func (k *Kucoin) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(k, k.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	from, to := start, end
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		count := limit
+		if count <= 0 {
+			count = 100
+		}
+		from = to.Add(-time.Duration(count) * defaultFundingInterval)
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"from":   from.UnixMilli(),
+		"to":     to.UnixMilli(),
+	}
+
+	var resp kucoinResponse[[]struct {
+		Symbol      string      `json:"symbol"`
+		FundingRate jsonDecimal `json:"fundingRate"`
+		Timepoint   int64       `json:"timepoint"`
+	}]
+	if err := sendRequest(ctx, k, "GET", "/api/v1/contract/funding-rates", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(resp.Data))
+	for _, r := range resp.Data {
+		records = append(records, types.FundingRecord{Symbol: r.Symbol, Rate: r.FundingRate.Decimal, Time: msToTime(r.Timepoint)})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+type kucoinMarkPrice struct {
+	Symbol     string      `json:"symbol"`
+	TimePoint  int64       `json:"timePoint"`
+	Value      jsonDecimal `json:"value"`
+	IndexPrice jsonDecimal `json:"indexPrice"`
+}
+
+func (k *Kucoin) markPrice(ctx context.Context, symbol string) (kucoinMarkPrice, error) {
+	if err := requirePerpetual(k, k.config.MarketOrSpot()); err != nil {
+		return kucoinMarkPrice{}, err
+	}
+
+	var resp kucoinResponse[kucoinMarkPrice]
+	err := sendRequest(ctx, k, "GET", "/api/v1/mark-price/"+symbol+"/current", nil, false, &resp)
+	return resp.Data, err
+}
+
+func (k *Kucoin) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := k.markPrice(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.Value.Decimal, Timestamp: msToTime(p.TimePoint)}, nil
+}
+
+func (k *Kucoin) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := k.markPrice(ctx, symbol)
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return types.ReferencePrice{Symbol: p.Symbol, Price: p.IndexPrice.Decimal, Timestamp: msToTime(p.TimePoint)}, nil
+}
+
+// GetAllTickers 使用 allTickers 接口，时间戳为整个快照的时间
+func (k *Kucoin) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	if k.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(k, "futures ticker")
+	}
+
+	var resp kucoinResponse[struct {
+		Time   jsonInt       `json:"time"`
+		Ticker []kucoinStats `json:"ticker"`
+	}]
+	if err := sendRequest(ctx, k, "GET", "/api/v1/market/allTickers", nil, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Data.Ticker))
+	for _, t := range resp.Data.Ticker {
+		t.Time = resp.Data.Time
+		tickers = append(tickers, t.toTicker())
+	}
+	return keyTickers(ctx, k, tickers)
+}
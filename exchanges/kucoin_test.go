@@ -0,0 +1,58 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKucoin_PrepareRequest(t *testing.T) {
+	kucoin := NewKucoin(types.ExchangeConfig{
+		APIKey:        "test_key",
+		APISecret:     "test_secret",
+		APIPassphrase: "test_passphrase",
+	})
+
+	// KC-API-SIGN = base64(HMAC-SHA256(timestamp + method + 路径和查询字符串 + 请求体))
+	sign := func(timestamp, method, requestURI, body string) string {
+		mac := hmac.New(sha256.New, []byte("test_secret"))
+		mac.Write([]byte(timestamp + method + requestURI + body))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req, err := kucoin.PrepareRequest("GET", "/api/v1/orders", map[string]interface{}{"symbol": "BTC-USDT", "status": "active"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "status=active&symbol=BTC-USDT", req.URL.RawQuery)
+		assert.Equal(t, "test_key", req.Header.Get("KC-API-KEY"))
+		assert.Equal(t, "test_passphrase", req.Header.Get("KC-API-PASSPHRASE"))
+
+		timestamp := req.Header.Get("KC-API-TIMESTAMP")
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, sign(timestamp, "GET", "/api/v1/orders?status=active&symbol=BTC-USDT", ""), req.Header.Get("KC-API-SIGN"))
+	})
+
+	t.Run("DELETE sends params in the query", func(t *testing.T) {
+		req, err := kucoin.PrepareRequest("DELETE", "/api/v1/orders", map[string]interface{}{"symbol": "BTC-USDT"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "symbol=BTC-USDT", req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.Empty(t, body)
+		assert.Equal(t, sign(req.Header.Get("KC-API-TIMESTAMP"), "DELETE", "/api/v1/orders?symbol=BTC-USDT", ""), req.Header.Get("KC-API-SIGN"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := kucoin.PrepareRequest("POST", "/api/v1/orders", map[string]interface{}{"symbol": "BTC-USDT", "size": "0.1"}, true)
+		assert.NoError(t, err)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.JSONEq(t, `{"symbol":"BTC-USDT","size":"0.1"}`, string(body))
+		assert.Equal(t, sign(req.Header.Get("KC-API-TIMESTAMP"), "POST", "/api/v1/orders", string(body)), req.Header.Get("KC-API-SIGN"))
+	})
+}
@@ -0,0 +1,491 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+// newTestServer 按路径返回预置的 JSON 响应
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetTicker(t *testing.T) {
+	tests := []struct {
+		name      string
+		newMarket func(config types.ExchangeConfig) types.MarketData
+		market    types.Market
+		symbol    string
+		responses map[string]string
+		want      types.Ticker
+	}{
+		{
+			name:      "binance",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewBinance(c) },
+			symbol:    "BTCUSDT",
+			responses: map[string]string{
+				"/api/v3/ticker/24hr": `{"symbol":"BTCUSDT","lastPrice":"60000.01","bidPrice":"60000.00","askPrice":"60000.02","volume":"1234.5","quoteVolume":"74070000.1","closeTime":1700000000000}`,
+			},
+			want: types.Ticker{Symbol: "BTCUSDT", Last: dec("60000.01"), Bid: dec("60000"), Ask: dec("60000.02"), Volume: dec("1234.5"), QuoteVolume: dec("74070000.1")},
+		},
+		{
+			name:      "binance usdm",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewBinance(c) },
+			market:    types.USDMFutures,
+			symbol:    "BTCUSDT",
+			responses: map[string]string{
+				"/fapi/v1/ticker/24hr":       `{"symbol":"BTCUSDT","lastPrice":"60000.1","volume":"10","quoteVolume":"600001","closeTime":1700000000000}`,
+				"/fapi/v1/ticker/bookTicker": `{"symbol":"BTCUSDT","bidPrice":"60000.0","askPrice":"60000.2"}`,
+			},
+			want: types.Ticker{Symbol: "BTCUSDT", Last: dec("60000.1"), Bid: dec("60000"), Ask: dec("60000.2"), Volume: dec("10"), QuoteVolume: dec("600001")},
+		},
+		{
+			name:      "okx",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewOKX(c) },
+			symbol:    "BTC-USDT",
+			responses: map[string]string{
+				"/api/v5/market/ticker": `{"code":"0","msg":"","data":[{"instId":"BTC-USDT","last":"60000.1","bidPx":"60000","askPx":"60000.2","vol24h":"10","volCcy24h":"600001","ts":"1700000000000"}]}`,
+			},
+			want: types.Ticker{Symbol: "BTC-USDT", Last: dec("60000.1"), Bid: dec("60000"), Ask: dec("60000.2"), Volume: dec("10"), QuoteVolume: dec("600001")},
+		},
+		{
+			name:      "kraken",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewKraken(c) },
+			symbol:    "XBTUSD",
+			responses: map[string]string{
+				"/0/public/Ticker": `{"error":[],"result":{"XXBTZUSD":{"a":["60000.2","1","1.000"],"b":["60000.0","2","2.000"],"c":["60000.1","0.1"],"v":["5","10"],"p":["60000","60000.5"]}}}`,
+			},
+			want: types.Ticker{Symbol: "XXBTZUSD", Last: dec("60000.1"), Bid: dec("60000"), Ask: dec("60000.2"), Volume: dec("10"), QuoteVolume: dec("600005")},
+		},
+		{
+			name:      "kraken futures",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewKraken(c) },
+			market:    types.USDMFutures,
+			symbol:    "PF_XBTUSD",
+			responses: map[string]string{
+				"/derivatives/api/v3/tickers/PF_XBTUSD": `{"result":"success","serverTime":"2023-11-14T22:13:20.000Z","ticker":{"symbol":"PF_XBTUSD","last":60000.1,"lastTime":"2023-11-14T22:13:19.000Z","bid":60000,"ask":60000.2,"vol24h":10,"volumeQuote":600001}}`,
+			},
+			want: types.Ticker{Symbol: "PF_XBTUSD", Last: dec("60000.1"), Bid: dec("60000"), Ask: dec("60000.2"), Volume: dec("10"), QuoteVolume: dec("600001")},
+		},
+		{
+			name:      "gate",
+			newMarket: func(c types.ExchangeConfig) types.MarketData { return NewGate(c) },
+			symbol:    "BTC_USDT",
+			responses: map[string]string{
+				"/api/v4/spot/tickers": `[{"currency_pair":"BTC_USDT","last":"60000.1","lowest_ask":"60000.2","highest_bid":"60000","base_volume":"10","quote_volume":"600001"}]`,
+			},
+			want: types.Ticker{Symbol: "BTC_USDT", Last: dec("60000.1"), Bid: dec("60000"), Ask: dec("60000.2"), Volume: dec("10"), QuoteVolume: dec("600001")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.responses)
+			md := tt.newMarket(types.ExchangeConfig{BaseURL: server.URL, Market: tt.market})
+
+			ticker, err := md.GetTicker(context.Background(), tt.symbol)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Symbol, ticker.Symbol)
+			assert.True(t, tt.want.Last.Equal(ticker.Last), "last %s", ticker.Last)
+			assert.True(t, tt.want.Bid.Equal(ticker.Bid), "bid %s", ticker.Bid)
+			assert.True(t, tt.want.Ask.Equal(ticker.Ask), "ask %s", ticker.Ask)
+			assert.True(t, tt.want.Volume.Equal(ticker.Volume), "volume %s", ticker.Volume)
+			assert.True(t, tt.want.QuoteVolume.Equal(ticker.QuoteVolume), "quote volume %s", ticker.QuoteVolume)
+		})
+	}
+}
+
+func TestGetTicker_APIError(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v5/market/ticker": `{"code":"51001","msg":"Instrument ID does not exist","data":[]}`,
+	})
+
+	_, err := NewOKX(types.ExchangeConfig{BaseURL: server.URL}).GetTicker(context.Background(), "FOO-BAR")
+	var apiErr *types.APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "51001", apiErr.Code)
+}
+
+func TestGetOrderBook(t *testing.T) {
+	var requested string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`{"code":"200000","data":{"sequence":"3262786978","time":1700000000000,
+			"bids":[["59999","2"],["60000","1"],["59998","3"]],
+			"asks":[["60002","1"],["60001","2"],["60003","3"]]}}`))
+	}))
+	defer server.Close()
+
+	book, err := NewKucoin(types.ExchangeConfig{BaseURL: server.URL}).GetOrderBook(context.Background(), "BTC-USDT", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/v1/market/orderbook/level2_20", requested)
+	assert.Equal(t, int64(3262786978), book.Sequence)
+	assert.Equal(t, int64(1700000000000), book.Timestamp.UnixMilli())
+
+	assert.Len(t, book.Bids, 2)
+	assert.True(t, dec("60000").Equal(book.Bids[0].Price))
+	assert.True(t, dec("59999").Equal(book.Bids[1].Price))
+	assert.Len(t, book.Asks, 2)
+	assert.True(t, dec("60001").Equal(book.Asks[0].Price))
+	assert.True(t, dec("2").Equal(book.Asks[0].Size))
+}
+
+func TestNearestDepth(t *testing.T) {
+	allowed := []int{20, 100}
+	assert.Equal(t, 20, nearestDepth(1, allowed))
+	assert.Equal(t, 100, nearestDepth(21, allowed))
+	assert.Equal(t, 100, nearestDepth(500, allowed))
+	assert.Equal(t, 100, nearestDepth(0, allowed))
+}
+
+func TestGetCandles(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/products/BTC-USD/candles": `[
+			[1700013600,"59900","60400","60100","60300","4"],
+			[1700010000,"59800","60300","60000","60100","3"],
+			[1700006400,"59700","60200","59900","60000","2"],
+			[1700002800,"59600","60100","59800","59900","1"]
+		]`,
+	})
+
+	start := time.Unix(1700002800, 0)
+
+	_, err := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL}).GetCandles(context.Background(), "BTC-USD", types.Interval4h, start, time.Time{}, 0)
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+
+	coinbase := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL, ResampleCandles: true})
+
+	candles, err := coinbase.GetCandles(context.Background(), "BTC-USD", types.Interval1h, start, time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 4)
+	assert.Equal(t, start.Unix(), candles[0].OpenTime.Unix())
+	assert.True(t, dec("59800").Equal(candles[0].Open))
+
+	// 1700002800 位于 4h 周期 1699992000 ~ 1700006400 内，1700006400 开始新的周期
+	candles, err = coinbase.GetCandles(context.Background(), "BTC-USD", types.Interval4h, time.Time{}, time.Time{}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 2)
+	assert.Equal(t, int64(1699992000), candles[0].OpenTime.Unix())
+	assert.True(t, dec("59800").Equal(candles[0].Open))
+	assert.True(t, dec("59900").Equal(candles[0].Close))
+	assert.Equal(t, int64(1700006400), candles[1].OpenTime.Unix())
+	assert.True(t, dec("59900").Equal(candles[1].Open))
+	assert.True(t, dec("60400").Equal(candles[1].High))
+	assert.True(t, dec("59700").Equal(candles[1].Low))
+	assert.True(t, dec("60300").Equal(candles[1].Close))
+	assert.True(t, dec("9").Equal(candles[1].Volume))
+}
+
+func TestGetCandles_OKXHistory(t *testing.T) {
+	row := `{"code":"0","msg":"","data":[["1700000000000","60000","60100","59900","60050","1","60000","60000","1"]]}`
+	server := newTestServer(t, map[string]string{
+		"/api/v5/market/candles":         `{"code":"0","msg":"","data":[]}`,
+		"/api/v5/market/history-candles": row,
+	})
+	okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL})
+
+	// 只指定 end 时也按 end 判断是否超出 candles 接口的范围
+	end := time.UnixMilli(1700000000000)
+	candles, err := okx.GetCandles(context.Background(), "BTC-USDT", types.Interval1m, time.Time{}, end, 10)
+	assert.NoError(t, err)
+	assert.Len(t, candles, 1)
+
+	candles, err = okx.GetCandles(context.Background(), "BTC-USDT", types.Interval1m, time.Time{}, time.Time{}, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, candles)
+}
+
+func TestGetRecentTrades(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/products/BTC-USD/trades": `[
+			{"time":"2023-11-14T22:13:21Z","trade_id":102,"price":"60001","size":"0.2","side":"sell"},
+			{"time":"2023-11-14T22:13:20Z","trade_id":101,"price":"60000","size":"0.1","side":"buy"}
+		]`,
+	})
+
+	trades, err := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL}).GetRecentTrades(context.Background(), "BTC-USD", 10)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+
+	// Coinbase 返回 maker 方向，统一结果为 taker 方向并按时间升序
+	assert.Equal(t, "101", trades[0].ID)
+	assert.Equal(t, types.Sell, trades[0].Side)
+	assert.True(t, dec("0.1").Equal(trades[0].Size))
+	assert.Equal(t, "102", trades[1].ID)
+	assert.Equal(t, types.Buy, trades[1].Side)
+}
+
+func TestGetRecentTrades_GateFutures(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v4/futures/usdt/trades": `[
+			{"id":2,"create_time_ms":1700000001000,"contract":"BTC_USDT","size":-3,"price":"60001"},
+			{"id":1,"create_time_ms":1700000000000,"contract":"BTC_USDT","size":5,"price":"60000"}
+		]`,
+	})
+
+	trades, err := NewGate(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).GetRecentTrades(context.Background(), "BTC_USDT", 10)
+	assert.NoError(t, err)
+	assert.Len(t, trades, 2)
+
+	// 合约的 size 为张数，负数表示 taker 卖出
+	assert.Equal(t, "1", trades[0].ID)
+	assert.Equal(t, types.Buy, trades[0].Side)
+	assert.True(t, dec("5").Equal(trades[0].Size))
+	assert.Equal(t, types.Sell, trades[1].Side)
+	assert.True(t, dec("3").Equal(trades[1].Size))
+}
+
+func TestGetHistoricalTrades(t *testing.T) {
+	var since string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		since = r.URL.Query().Get("since")
+		w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[
+			["60000.0","0.1",1700000000.1234,"b","l","",1001],
+			["60001.0","0.2",1700000001.5,"s","m","",1002]
+		],"last":"1700000001500000000"}}`))
+	}))
+	defer server.Close()
+
+	kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL})
+	req := types.HistoricalTradesRequest{Symbol: "XBTUSD", Since: time.Unix(1700000000, 0), Limit: 2}
+
+	page, err := kraken.GetHistoricalTrades(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1700000000000000000", since)
+	assert.Len(t, page.Trades, 2)
+	assert.Equal(t, "1001", page.Trades[0].ID)
+	assert.Equal(t, types.Buy, page.Trades[0].Side)
+	assert.Equal(t, int64(1700000000123), page.Trades[0].Time.UnixMilli())
+	assert.Equal(t, "1700000001500000000", page.Cursor)
+
+	req.Cursor = page.Cursor
+	req.Limit = 10
+	page, err = kraken.GetHistoricalTrades(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1700000001500000000", since)
+	assert.Empty(t, page.Cursor)
+}
+
+func TestGetHistoricalTrades_BinanceSince(t *testing.T) {
+	since := time.UnixMilli(1700000000000)
+	type aggTrade struct {
+		A int64 `json:"a"`
+		T int64 `json:"T"`
+	}
+	newServer := func(all []aggTrade, requests *int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*requests++
+			q := r.URL.Query()
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			var rows []aggTrade
+			switch {
+			case q.Get("startTime") != "":
+				start, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+				end, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+				for _, trade := range all {
+					if trade.T >= start && trade.T <= end && len(rows) < limit {
+						rows = append(rows, trade)
+					}
+				}
+			case q.Get("fromId") != "":
+				from, _ := strconv.ParseInt(q.Get("fromId"), 10, 64)
+				for _, trade := range all {
+					if trade.A >= from && len(rows) < limit {
+						rows = append(rows, trade)
+					}
+				}
+			default:
+				rows = all[len(all)-limit:]
+			}
+			json.NewEncoder(w).Encode(rows)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("binary search", func(t *testing.T) {
+		// 0~99 早于 since，100~499 不存在，500 起在 since 的 90 天之后
+		var all []aggTrade
+		for id := int64(0); id < 100; id++ {
+			all = append(all, aggTrade{A: id, T: since.Add(-time.Hour).UnixMilli()})
+		}
+		for id := int64(500); id < 2000; id++ {
+			all = append(all, aggTrade{A: id, T: since.Add(90*24*time.Hour).UnixMilli() + id})
+		}
+
+		requests := 0
+		server := newServer(all, &requests)
+		page, err := NewBinance(types.ExchangeConfig{BaseURL: server.URL, RateLimit: -1}).
+			GetHistoricalTrades(context.Background(), types.HistoricalTradesRequest{Symbol: "BTCUSDT", Since: since, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, page.Trades, 10)
+		assert.Equal(t, "500", page.Trades[0].ID)
+		assert.Equal(t, "510", page.Cursor)
+		// 一个时间窗口、一次最新成交、二分查找和最后一页，远少于按小时扫描 90 天所需的 2160 次
+		assert.LessOrEqual(t, requests, 15)
+	})
+
+	t.Run("short window page keeps the cursor", func(t *testing.T) {
+		// 第一个小时只有 3 笔成交，之后一天还有成交
+		var all []aggTrade
+		for id := int64(0); id < 3; id++ {
+			all = append(all, aggTrade{A: id, T: since.Add(time.Minute).UnixMilli()})
+		}
+		for id := int64(3); id < 20; id++ {
+			all = append(all, aggTrade{A: id, T: since.Add(24 * time.Hour).UnixMilli()})
+		}
+
+		requests := 0
+		server := newServer(all, &requests)
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, RateLimit: -1})
+		page, err := binance.GetHistoricalTrades(context.Background(), types.HistoricalTradesRequest{Symbol: "BTCUSDT", Since: since, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, page.Trades, 3)
+		assert.Equal(t, "3", page.Cursor)
+
+		page, err = binance.GetHistoricalTrades(context.Background(), types.HistoricalTradesRequest{Symbol: "BTCUSDT", Cursor: page.Cursor, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, page.Trades, 10)
+		assert.Equal(t, "3", page.Trades[0].ID)
+	})
+}
+
+func TestGetInstruments(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v3/exchangeInfo", r.URL.Path)
+		w.Write([]byte(`{"symbols":[{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[
+			{"filterType":"PRICE_FILTER","minPrice":"0.01","maxPrice":"1000000","tickSize":"0.01"},
+			{"filterType":"LOT_SIZE","minQty":"0.00001","maxQty":"9000","stepSize":"0.00001"},
+			{"filterType":"NOTIONAL","minNotional":"5","applyMinToMarket":true}
+		]},{"symbol":"LUNAUSDT","status":"BREAK","baseAsset":"LUNA","quoteAsset":"USDT","filters":[]}]}`))
+	}))
+	defer server.Close()
+
+	binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+	instruments, err := binance.GetInstruments(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, instruments, 2)
+
+	btc := instruments[0]
+	assert.Equal(t, "BTC", btc.Base)
+	assert.Equal(t, "USDT", btc.Quote)
+	assert.True(t, dec("0.01").Equal(btc.TickSize))
+	assert.True(t, dec("0.00001").Equal(btc.StepSize))
+	assert.True(t, dec("0.00001").Equal(btc.MinQty))
+	assert.True(t, dec("9000").Equal(btc.MaxQty))
+	assert.True(t, dec("5").Equal(btc.MinNotional))
+	assert.True(t, btc.ContractSize.IsZero())
+	assert.Equal(t, types.InstrumentTrading, btc.Status)
+	assert.Equal(t, types.InstrumentHalted, instruments[1].Status)
+
+	// 缓存命中时不再请求，修改返回结果不影响缓存
+	instruments[0].Symbol = "changed"
+	instruments, err = binance.GetInstruments(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", instruments[0].Symbol)
+	assert.Equal(t, 1, requests)
+
+	uncached := NewBinance(types.ExchangeConfig{BaseURL: server.URL, InstrumentTTL: -1})
+	uncached.GetInstruments(context.Background())
+	uncached.GetInstruments(context.Background())
+	assert.Equal(t, 3, requests)
+}
+
+func TestGetInstruments_KrakenFutures(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/derivatives/api/v3/instruments": `{"result":"success","instruments":[
+			{"symbol":"PF_XBTUSD","type":"flexible_futures","base":"XBT","quote":"USD","tradeable":true,"tickSize":0.5,"contractSize":1,"contractValueTradePrecision":4},
+			{"symbol":"PI_XBTUSD","type":"futures_inverse","tradeable":true,"tickSize":0.5,"contractSize":1,"contractValueTradePrecision":0},
+			{"symbol":"FI_XBTUSD_231229","type":"futures_inverse","tradeable":true,"tickSize":0.5,"contractSize":1}]}`,
+	})
+
+	instruments, err := NewKraken(types.ExchangeConfig{BaseURL: server.URL, Market: types.CoinMFutures}).GetInstruments(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, instruments, 1)
+	assert.Equal(t, "PI_XBTUSD", instruments[0].Symbol)
+	assert.Equal(t, "XBT", instruments[0].Base)
+	assert.Equal(t, "USD", instruments[0].Quote)
+	assert.True(t, dec("1").Equal(instruments[0].StepSize))
+
+	instruments, err = NewKraken(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).GetInstruments(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, instruments, 1)
+	assert.Equal(t, "PF_XBTUSD", instruments[0].Symbol)
+	assert.True(t, dec("0.0001").Equal(instruments[0].StepSize))
+	assert.True(t, dec("1").Equal(instruments[0].ContractSize))
+}
+
+func TestGetAllTickers(t *testing.T) {
+	t.Run("bulk", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/fapi/v1/exchangeInfo": `{"symbols":[
+				{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[]},
+				{"symbol":"BTCUSDT_250328","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[]}]}`,
+			"/fapi/v1/ticker/24hr": `[
+				{"symbol":"BTCUSDT","lastPrice":"60000.1","volume":"10","quoteVolume":"600001","closeTime":1700000000000},
+				{"symbol":"BTCUSDT_250328","lastPrice":"61000","volume":"1","quoteVolume":"61000","closeTime":1700000000000},
+				{"symbol":"UNKNOWN","lastPrice":"1","volume":"1","quoteVolume":"1","closeTime":1700000000000}]`,
+			"/fapi/v1/ticker/bookTicker": `[{"symbol":"BTCUSDT","bidPrice":"60000.0","askPrice":"60000.2"}]`,
+		})
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		tickers, err := binance.GetAllTickers(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, tickers, 1)
+
+		btc := tickers[types.NewSymbol("BTC", "USDT")]
+		assert.Equal(t, "BTCUSDT", btc.Symbol)
+		assert.True(t, dec("60000.1").Equal(btc.Last))
+		assert.True(t, dec("60000").Equal(btc.Bid))
+		assert.True(t, dec("60000.2").Equal(btc.Ask))
+	})
+
+	t.Run("batched", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/products": `[
+				{"id":"BTC-USD","base_currency":"BTC","quote_currency":"USD","status":"online"},
+				{"id":"ETH-USD","base_currency":"ETH","quote_currency":"USD","status":"online"},
+				{"id":"OLD-USD","base_currency":"OLD","quote_currency":"USD","status":"delisted"}]`,
+			"/products/BTC-USD/ticker": `{"price":"60000","bid":"59999","ask":"60001","volume":"100","time":"2023-11-14T22:13:20Z"}`,
+			"/products/ETH-USD/ticker": `{"price":"3000","bid":"2999","ask":"3001","volume":"1000","time":"2023-11-14T22:13:20Z"}`,
+		})
+
+		coinbase := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL, RateLimit: -1})
+		tickers, err := coinbase.GetAllTickers(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, tickers, 2)
+		assert.True(t, dec("3000").Equal(tickers[types.NewSymbol("ETH", "USD")].Last))
+		assert.Equal(t, "BTC-USD", tickers[types.NewSymbol("BTC", "USD")].Symbol)
+	})
+}
+
+func TestPrecisionStep(t *testing.T) {
+	assert.True(t, dec("0.01").Equal(precisionStep(2)))
+	assert.True(t, dec("1").Equal(precisionStep(0)))
+	assert.True(t, dec("10").Equal(precisionStep(-1)))
+}
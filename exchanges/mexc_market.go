@@ -0,0 +1,176 @@
+package exchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// MEXC 现货 v3 接口与 Binance 兼容，直接复用 Binance 的响应结构
+
+func (m *MEXC) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return types.Ticker{}, notSupported(m, "contract ticker")
+	}
+
+	var t binanceTicker24hr
+	if err := sendRequest(ctx, m, "GET", "/api/v3/ticker/24hr", map[string]interface{}{"symbol": symbol}, false, &t); err != nil {
+		return types.Ticker{}, err
+	}
+
+	return t.toTicker(types.Spot), nil
+}
+
+func (m *MEXC) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return types.OrderBook{}, notSupported(m, "contract order book")
+	}
+
+	params := map[string]interface{}{
+		"symbol": symbol,
+		"limit":  clampDepth(depth, 5000),
+	}
+
+	var d struct {
+		binanceDepth
+		Timestamp int64 `json:"timestamp"`
+	}
+	if err := sendRequest(ctx, m, "GET", "/api/v3/depth", params, false, &d); err != nil {
+		return types.OrderBook{}, err
+	}
+
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(d.Bids),
+		Asks:      levelsFromRows(d.Asks),
+		Sequence:  d.LastUpdateID,
+		Timestamp: msToTime(d.Timestamp),
+	}, depth), nil
+}
+
+var mexcIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval5m: "5m", types.Interval15m: "15m", types.Interval30m: "30m",
+	types.Interval1h: "60m", types.Interval4h: "4h", types.Interval1d: "1d", types.Interval1w: "1W",
+	types.Interval1M: "1M",
+}
+
+func (m *MEXC) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract candles")
+	}
+
+	return fetchCandles(ctx, m, mexcIntervals, m.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"symbol":   symbol,
+				"interval": venueInterval,
+			}
+			if !start.IsZero() {
+				params["startTime"] = start.UnixMilli()
+			}
+			if !end.IsZero() {
+				params["endTime"] = end.UnixMilli()
+			}
+			if limit > 0 {
+				params["limit"] = clampDepth(limit, 1000)
+			}
+
+			var rows [][]jsonDecimal
+			if err := sendRequest(ctx, m, "GET", "/api/v3/klines", params, false, &rows); err != nil {
+				return nil, err
+			}
+			return binanceKlines(rows, 5, 7), nil
+		})
+}
+
+// GetRecentTrades 中 MEXC 的成交 id 恒为 null，因此 Trade.ID 为空
+func (m *MEXC) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract trades")
+	}
+
+	params := map[string]interface{}{"symbol": symbol}
+	if limit > 0 {
+		params["limit"] = clampDepth(limit, 1000)
+	}
+
+	var rows []binanceTrade
+	if err := sendRequest(ctx, m, "GET", "/api/v3/trades", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, r := range rows {
+		trade := r.toTrade(false)
+		trade.ID = ""
+		trades = append(trades, trade)
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// mexcSymbol 的 status 为 "1" 上线、"2" 暂停、"3" 下线，旧版接口返回 ENABLED
+type mexcSymbol struct {
+	Symbol               string      `json:"symbol"`
+	Status               string      `json:"status"`
+	BaseAsset            string      `json:"baseAsset"`
+	QuoteAsset           string      `json:"quoteAsset"`
+	QuotePrecision       int         `json:"quotePrecision"`
+	BaseAssetPrecision   int         `json:"baseAssetPrecision"`
+	BaseSizePrecision    jsonDecimal `json:"baseSizePrecision"`
+	QuoteAmountPrecision jsonDecimal `json:"quoteAmountPrecision"`
+	MaxQuoteAmount       jsonDecimal `json:"maxQuoteAmount"`
+}
+
+// GetInstruments 中 MEXC 只提供价格小数位数；baseSizePrecision 为最小下单数量，同时作为数量步长
+func (m *MEXC) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract instruments")
+	}
+
+	return m.instruments.get(ctx, m.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var info struct {
+			Symbols []mexcSymbol `json:"symbols"`
+		}
+		if err := sendRequest(ctx, m, "GET", "/api/v3/exchangeInfo", nil, false, &info); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(info.Symbols))
+		for _, s := range info.Symbols {
+			step := s.BaseSizePrecision.Decimal
+			if !step.IsPositive() {
+				step = precisionStep(s.BaseAssetPrecision)
+			}
+
+			instruments = append(instruments, types.Instrument{
+				Symbol:      s.Symbol,
+				Base:        s.BaseAsset,
+				Quote:       s.QuoteAsset,
+				TickSize:    precisionStep(s.QuotePrecision),
+				StepSize:    step,
+				MinQty:      s.BaseSizePrecision.Decimal,
+				MinNotional: s.QuoteAmountPrecision.Decimal,
+				Status:      tradingStatus(s.Status == "1" || s.Status == "ENABLED"),
+			})
+		}
+		return instruments, nil
+	})
+}
+
+func (m *MEXC) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract ticker")
+	}
+
+	var stats []binanceTicker24hr
+	if err := sendRequest(ctx, m, "GET", "/api/v3/ticker/24hr", nil, false, &stats); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(stats))
+	for _, t := range stats {
+		tickers = append(tickers, t.toTicker(types.Spot))
+	}
+	return keyTickers(ctx, m, tickers)
+}
@@ -0,0 +1,295 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// mexcTradeFee 是 tradeFee 接口的响应，与其他 v3 接口不同，结果包在 data 中并带有 code
+type mexcTradeFee struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		MakerCommission jsonDecimal `json:"makerCommission"`
+		TakerCommission jsonDecimal `json:"takerCommission"`
+	} `json:"data"`
+}
+
+func (r *mexcTradeFee) apiError() error {
+	if r.Code != 0 {
+		return &types.APIError{StatusCode: http.StatusOK, Code: strconv.Itoa(r.Code), Message: r.Msg}
+	}
+	return nil
+}
+
+// GetTradingFees 按交易对逐个查询 /api/v3/tradeFee，symbols 为空时查询全部现货交易对
+func (m *MEXC) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract trading fees")
+	}
+
+	if len(symbols) == 0 {
+		var err error
+		if symbols, err = instrumentSymbols(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+	return fanOut(ctx, symbols, func(ctx context.Context, symbol string) (types.TradingFee, error) {
+		var resp mexcTradeFee
+		if err := sendRequest(ctx, m, "GET", "/api/v3/tradeFee", map[string]interface{}{"symbol": symbol}, true, &resp); err != nil {
+			return types.TradingFee{}, err
+		}
+		return types.TradingFee{Symbol: symbol, Maker: resp.Data.MakerCommission.Decimal, Taker: resp.Data.TakerCommission.Decimal}, nil
+	})
+}
+
+// orderParams 把统一的下单请求转换为现货 v3 参数。MEXC 没有 timeInForce 参数，
+// post-only、IOC、FOK 分别使用 LIMIT_MAKER、IMMEDIATE_OR_CANCEL、FILL_OR_KILL 类型；合约交易暂不支持
+func (m *MEXC) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(m, "reduce-only spot orders")
+	}
+	if req.Type.Conditional() {
+		return nil, notSupported(m, "conditional orders")
+	}
+
+	params := map[string]interface{}{
+		"symbol":           req.Symbol,
+		"side":             string(req.Side),
+		"type":             string(req.Type),
+		"newClientOrderId": req.ClientOrderID,
+	}
+	if req.Quantity.IsPositive() {
+		params["quantity"] = req.Quantity.String()
+	}
+	if req.QuoteQuantity.IsPositive() {
+		params["quoteOrderQty"] = req.QuoteQuantity.String()
+	}
+	if req.Type == types.LimitOrder {
+		params["price"] = req.Price.String()
+		switch {
+		case req.PostOnly:
+			params["type"] = "LIMIT_MAKER"
+		case req.TimeInForce == types.IOC:
+			params["type"] = "IMMEDIATE_OR_CANCEL"
+		case req.TimeInForce == types.FOK:
+			params["type"] = "FILL_OR_KILL"
+		}
+	}
+	return params, nil
+}
+
+func (m *MEXC) placer() placer {
+	return placer{t: m, config: m.config, idLength: clientOrderIDLength, place: m.placeOrder}
+}
+
+func (m *MEXC) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return m.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单，batchOrders 只接受同一交易对的订单
+func (m *MEXC) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	return m.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeOrder 中 MEXC 的订单号是字符串，不能复用 Binance 的数字订单号
+func (m *MEXC) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := m.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var ack struct {
+		OrderID string `json:"orderId"`
+	}
+	if err := sendRequest(ctx, m, "POST", "/api/v3/order", params, true, &ack); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, ack.OrderID), nil
+}
+
+func (m *MEXC) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	return replaceOrder(ctx, m, req)
+}
+
+// mexcOrder 是现货 v3 的订单，字段与 Binance 相同，但订单号是字符串
+type mexcOrder struct {
+	Symbol              string      `json:"symbol"`
+	OrderID             string      `json:"orderId"`
+	ClientOrderID       string      `json:"clientOrderId"`
+	OrigClientOrderID   string      `json:"origClientOrderId"`
+	Price               jsonDecimal `json:"price"`
+	OrigQty             jsonDecimal `json:"origQty"`
+	OrigQuoteOrderQty   jsonDecimal `json:"origQuoteOrderQty"`
+	ExecutedQty         jsonDecimal `json:"executedQty"`
+	CummulativeQuoteQty jsonDecimal `json:"cummulativeQuoteQty"`
+	Status              string      `json:"status"`
+	Type                string      `json:"type"`
+	Side                string      `json:"side"`
+	Time                int64       `json:"time"`
+	UpdateTime          int64       `json:"updateTime"`
+}
+
+// mexcRef 返回按订单号或自定义订单号指定订单的参数
+func mexcRef(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"symbol": symbol}
+	if ref.ID != "" {
+		params["orderId"] = ref.ID
+	} else {
+		params["origClientOrderId"] = ref.ClientOrderID
+	}
+	return params
+}
+
+func (m *MEXC) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if m.config.MarketOrSpot() != types.Spot {
+		return notSupported(m, "contract orders")
+	}
+
+	var order mexcOrder
+	return orderNotFound(sendRequest(ctx, m, "DELETE", "/api/v3/order", mexcRef(symbol, ref), true, &order), binanceOrderNotFound)
+}
+
+// CancelOrders 没有按订单号批量撤单的接口，并发地逐个撤单
+func (m *MEXC) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	return cancelOrders(ctx, symbol, refs, 0, nil, m.CancelOrder), nil
+}
+
+// CancelAllOrders 使用 DELETE /api/v3/openOrders，MEXC 要求指定交易对，不指定时返回包装了 ErrNotSupported 的错误
+func (m *MEXC) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	if symbol == "" {
+		return nil, notSupported(m, "cancel all orders without a symbol")
+	}
+
+	var orders []mexcOrder
+	if err := sendRequest(ctx, m, "DELETE", "/api/v3/openOrders", map[string]interface{}{"symbol": symbol}, true, &orders); err != nil {
+		return nil, err
+	}
+	results := make([]types.CancelResult, len(orders))
+	for i, order := range orders {
+		clientOrderID := order.OrigClientOrderID
+		if clientOrderID == "" {
+			clientOrderID = order.ClientOrderID
+		}
+		results[i] = types.CancelResult{Symbol: order.Symbol, OrderID: order.OrderID, ClientOrderID: clientOrderID}
+	}
+	return results, nil
+}
+
+// mexcStatuses 中 PARTIALLY_CANCELED 是部分成交后撤销的订单
+var mexcStatuses = map[string]types.OrderStatus{
+	"NEW":                types.OrderNew,
+	"PARTIALLY_FILLED":   types.OrderPartiallyFilled,
+	"FILLED":             types.OrderFilled,
+	"CANCELED":           types.OrderCanceled,
+	"PARTIALLY_CANCELED": types.OrderCanceled,
+}
+
+func (o mexcOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.OrderID,
+		ClientOrderID:  o.ClientOrderID,
+		Symbol:         o.Symbol,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.GTC,
+		Price:          o.Price.Decimal,
+		Quantity:       o.OrigQty.Decimal,
+		Status:         orderStatus(mexcStatuses, o.Status),
+		FilledQuantity: o.ExecutedQty.Decimal,
+		AveragePrice:   averagePrice(o.CummulativeQuoteQty.Decimal, o.ExecutedQty.Decimal),
+		Raw:            o.Status,
+		CreatedAt:      msToTime(o.Time),
+		UpdatedAt:      msToTime(o.UpdateTime),
+	}
+	switch o.Type {
+	case "MARKET":
+		order.Type, order.TimeInForce, order.Price = types.MarketOrder, "", decimal.Zero
+		order.QuoteQuantity = o.OrigQuoteOrderQty.Decimal
+	case "IMMEDIATE_OR_CANCEL":
+		order.TimeInForce = types.IOC
+	case "FILL_OR_KILL":
+		order.TimeInForce = types.FOK
+	}
+	return order
+}
+
+func (m *MEXC) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if m.config.MarketOrSpot() != types.Spot {
+		return types.Order{}, notSupported(m, "contract orders")
+	}
+
+	var order mexcOrder
+	if err := sendRequest(ctx, m, "GET", "/api/v3/order", mexcRef(symbol, ref), true, &order); err != nil {
+		return types.Order{}, orderNotFound(err, binanceOrderNotFound)
+	}
+	return order.toOrder(), nil
+}
+
+// GetOpenOrders 中 MEXC 要求指定交易对
+func (m *MEXC) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	if symbol == "" {
+		return nil, notSupported(m, "open orders without a symbol")
+	}
+	return m.listOrders(ctx, "/api/v3/openOrders", map[string]interface{}{"symbol": symbol})
+}
+
+// GetOrderHistory 使用 allOrders，要求指定交易对，时间范围最长 7 天，不指定时为最近 24 小时
+func (m *MEXC) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if m.config.MarketOrSpot() != types.Spot {
+		return nil, notSupported(m, "contract orders")
+	}
+	if req.Symbol == "" {
+		return nil, notSupported(m, "order history without a symbol")
+	}
+
+	params := map[string]interface{}{"symbol": req.Symbol, "limit": 1000}
+	if !req.Start.IsZero() {
+		params["startTime"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["endTime"] = req.End.UnixMilli()
+	}
+	orders, err := m.listOrders(ctx, "/api/v3/allOrders", params)
+	if err != nil {
+		return nil, err
+	}
+	return orderHistory(orders, req), nil
+}
+
+func (m *MEXC) listOrders(ctx context.Context, endpoint string, params map[string]interface{}) ([]types.Order, error) {
+	var rows []mexcOrder
+	if err := sendRequest(ctx, m, "GET", endpoint, params, true, &rows); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(rows))
+	for i, row := range rows {
+		orders[i] = row.toOrder()
+	}
+	return orders, nil
+}
@@ -4,16 +4,21 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/hedeqiang/cryptoexchange/types"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type MEXC struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewMEXC(config types.ExchangeConfig) *MEXC {
@@ -25,6 +30,9 @@ func (m *MEXC) Name() types.ExchangeName {
 }
 
 func (m *MEXC) GetDefaultBaseURL() string {
+	if m.config.MarketOrSpot() != types.Spot {
+		return "https://contract.mexc.com"
+	}
 	return "https://api.mexc.com"
 }
 
@@ -39,6 +47,10 @@ func (m *MEXC) PrepareRequest(method, endpoint string, params map[string]interfa
 		return nil, err
 	}
 
+	if m.config.MarketOrSpot() != types.Spot {
+		return m.prepareContractRequest(method, u, params, signed)
+	}
+
 	q := u.Query()
 	for k, v := range params {
 		q.Set(k, fmt.Sprint(v))
@@ -66,3 +78,48 @@ func (m *MEXC) PrepareRequest(method, endpoint string, params map[string]interfa
 
 	return req, nil
 }
+
+// prepareContractRequest 处理 MEXC 合约接口，签名串为 accessKey + timestamp + 参数，
+// GET/DELETE 的参数是排序后的查询串，POST 的参数是 JSON 请求体
+func (m *MEXC) prepareContractRequest(method string, u *url.URL, params map[string]interface{}, signed bool) (*http.Request, error) {
+	var paramString string
+	if method == "GET" || method == "DELETE" {
+		q := u.Query()
+		for k, v := range params {
+			q.Set(k, fmt.Sprint(v))
+		}
+		u.RawQuery = q.Encode()
+		paramString = u.RawQuery
+	} else if params != nil {
+		body, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		paramString = string(body)
+	}
+
+	var body io.Reader
+	if method != "GET" && method != "DELETE" {
+		body = strings.NewReader(paramString)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		timestamp := strconv.FormatInt(time.Now().UnixNano()/1e6, 10)
+
+		mac := hmac.New(sha256.New, []byte(m.config.APISecret))
+		mac.Write([]byte(m.config.APIKey + timestamp + paramString))
+
+		req.Header.Set("ApiKey", m.config.APIKey)
+		req.Header.Set("Request-Time", timestamp)
+		req.Header.Set("Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
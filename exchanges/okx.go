@@ -5,7 +5,6 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"github.com/hedeqiang/cryptoexchange/types"
 	"net/http"
@@ -14,7 +13,9 @@ import (
 )
 
 type OKX struct {
-	config types.ExchangeConfig
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
 }
 
 func NewOKX(config types.ExchangeConfig) *OKX {
@@ -48,7 +49,7 @@ func (o *OKX) PrepareRequest(method, endpoint string, params map[string]interfac
 		}
 		u.RawQuery = q.Encode()
 	} else {
-		body, err = json.Marshal(params)
+		body, err = jsonBody(params)
 		if err != nil {
 			return nil, err
 		}
@@ -61,7 +62,8 @@ func (o *OKX) PrepareRequest(method, endpoint string, params map[string]interfac
 
 	if signed {
 		timestamp := time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
-		message := timestamp + method + endpoint
+		// GET 请求的签名路径包含查询字符串
+		message := timestamp + method + u.RequestURI()
 		if method != "GET" {
 			message += string(body)
 		}
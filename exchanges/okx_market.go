@@ -0,0 +1,439 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type okxResponse[T any] struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data T      `json:"data"`
+}
+
+func (r *okxResponse[T]) apiError() error {
+	if r.Code != "0" {
+		return &types.APIError{StatusCode: http.StatusOK, Code: r.Code, Message: r.Msg}
+	}
+	return nil
+}
+
+type okxTicker struct {
+	InstID    string      `json:"instId"`
+	Last      jsonDecimal `json:"last"`
+	BidPx     jsonDecimal `json:"bidPx"`
+	AskPx     jsonDecimal `json:"askPx"`
+	Vol24h    jsonDecimal `json:"vol24h"`
+	VolCcy24h jsonDecimal `json:"volCcy24h"`
+	Ts        jsonInt     `json:"ts"`
+}
+
+// GetTicker 的 symbol 为 OKX instId，例如 BTC-USDT 或 BTC-USDT-SWAP
+func (o *OKX) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	var resp okxResponse[[]okxTicker]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/ticker", map[string]interface{}{"instId": symbol}, false, &resp); err != nil {
+		return types.Ticker{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.Ticker{}, fmt.Errorf("okx ticker %s not found", symbol)
+	}
+
+	return resp.Data[0].toTicker(o.config.MarketOrSpot()), nil
+}
+
+func (t okxTicker) toTicker(market types.Market) types.Ticker {
+	ticker := types.Ticker{
+		Symbol:      t.InstID,
+		Last:        t.Last.Decimal,
+		Bid:         t.BidPx.Decimal,
+		Ask:         t.AskPx.Decimal,
+		Volume:      t.Vol24h.Decimal,
+		QuoteVolume: t.VolCcy24h.Decimal,
+		Timestamp:   msToTime(int64(t.Ts)),
+	}
+
+	// 衍生品的 vol24h 是合约张数，volCcy24h 是基础币数量，成交额按最新价估算
+	if market != types.Spot {
+		ticker.Volume = t.VolCcy24h.Decimal
+		ticker.QuoteVolume = t.VolCcy24h.Decimal.Mul(t.Last.Decimal)
+	}
+	return ticker
+}
+
+type okxBook struct {
+	Asks  [][]jsonDecimal `json:"asks"`
+	Bids  [][]jsonDecimal `json:"bids"`
+	Ts    jsonInt         `json:"ts"`
+	SeqID int64           `json:"seqId"`
+}
+
+func (o *OKX) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	params := map[string]interface{}{
+		"instId": symbol,
+		"sz":     clampDepth(depth, 400),
+	}
+
+	var resp okxResponse[[]okxBook]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/books", params, false, &resp); err != nil {
+		return types.OrderBook{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.OrderBook{}, fmt.Errorf("okx order book %s not found", symbol)
+	}
+
+	b := resp.Data[0]
+	return finalizeOrderBook(types.OrderBook{
+		Symbol:    symbol,
+		Bids:      levelsFromRows(b.Bids),
+		Asks:      levelsFromRows(b.Asks),
+		Sequence:  b.SeqID,
+		Timestamp: msToTime(int64(b.Ts)),
+	}, depth), nil
+}
+
+// OKX 的 6H 及以上周期默认按北京时间对齐，这里使用 utc 版本
+var okxIntervals = map[types.Interval]string{
+	types.Interval1m: "1m", types.Interval3m: "3m", types.Interval5m: "5m", types.Interval15m: "15m",
+	types.Interval30m: "30m", types.Interval1h: "1H", types.Interval2h: "2H", types.Interval4h: "4H",
+	types.Interval6h: "6Hutc", types.Interval12h: "12Hutc", types.Interval1d: "1Dutc", types.Interval3d: "3Dutc",
+	types.Interval1w: "1Wutc", types.Interval1M: "1Mutc",
+}
+
+// okxRecentCandles 是 /market/candles 能返回的最近 K 线数量，更早的数据需要使用 history-candles
+const okxRecentCandles = 1440
+
+// GetCandles 中 OKX 单次最多返回 300 根（history-candles 为 100 根），按时间倒序返回后再统一排序
+func (o *OKX) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, o, okxIntervals, o.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			// 按请求中最早的时间选择接口，未指定 start 时取 end
+			oldest := start
+			if oldest.IsZero() {
+				oldest = end
+			}
+			endpoint, maxLimit := "/api/v5/market/candles", 300
+			if !oldest.IsZero() && time.Since(oldest) > okxRecentCandles*interval.Duration() {
+				endpoint, maxLimit = "/api/v5/market/history-candles", 100
+			}
+
+			params := map[string]interface{}{
+				"instId": symbol,
+				"bar":    venueInterval,
+				"limit":  clampDepth(limit, maxLimit),
+			}
+			// after 返回早于该时间的数据，before 返回晚于该时间的数据
+			if !end.IsZero() {
+				params["after"] = end.UnixMilli() + 1
+			}
+			if !start.IsZero() {
+				params["before"] = start.UnixMilli() - 1
+			}
+
+			var resp okxResponse[[][]jsonDecimal]
+			if err := sendRequest(ctx, o, "GET", endpoint, params, false, &resp); err != nil {
+				return nil, err
+			}
+
+			// [ts, o, h, l, c, vol, volCcy, volCcyQuote, confirm]，衍生品的 vol 为合约张数
+			volumeIndex := 5
+			if o.config.MarketOrSpot() != types.Spot {
+				volumeIndex = 6
+			}
+
+			candles := make([]types.Candle, 0, len(resp.Data))
+			for _, row := range resp.Data {
+				if len(row) < 8 {
+					continue
+				}
+				candles = append(candles, candleFromRow(row, msToTime(row[0].IntPart()), 1, 2, 3, 4, volumeIndex, 7))
+			}
+			return candles, nil
+		})
+}
+
+type okxTrade struct {
+	TradeID string      `json:"tradeId"`
+	Px      jsonDecimal `json:"px"`
+	Sz      jsonDecimal `json:"sz"`
+	Side    string      `json:"side"`
+	Ts      jsonInt     `json:"ts"`
+}
+
+func (t okxTrade) toTrade() types.Trade {
+	return types.Trade{
+		ID:    t.TradeID,
+		Price: t.Px.Decimal,
+		Size:  t.Sz.Decimal,
+		Side:  parseSide(t.Side),
+		Time:  msToTime(int64(t.Ts)),
+	}
+}
+
+// GetRecentTrades 单次最多返回 500 笔，衍生品的 Size 为合约张数
+func (o *OKX) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	params := map[string]interface{}{"instId": symbol}
+	if limit > 0 {
+		params["limit"] = clampDepth(limit, 500)
+	}
+
+	var resp okxResponse[[]okxTrade]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/trades", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		trades = append(trades, t.toTrade())
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// GetHistoricalTrades 通过 history-trades 按 tradeId 由新到旧翻页，游标为本页最早的 tradeId。
+// 指定 Since 时丢弃早于 Since 的成交，并在越过 Since 后结束翻页。
+func (o *OKX) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	limit := clampDepth(req.Limit, 100)
+	params := map[string]interface{}{
+		"instId": req.Symbol,
+		"type":   "1",
+		"limit":  limit,
+	}
+	if req.Cursor != "" {
+		params["after"] = req.Cursor
+	}
+
+	var resp okxResponse[[]okxTrade]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/history-trades", params, false, &resp); err != nil {
+		return types.TradePage{}, err
+	}
+
+	page := types.TradePage{Trades: make([]types.Trade, 0, len(resp.Data))}
+	reachedSince := false
+	for _, t := range resp.Data {
+		trade := t.toTrade()
+		if !req.Since.IsZero() && trade.Time.Before(req.Since) {
+			reachedSince = true
+			continue
+		}
+		page.Trades = append(page.Trades, trade)
+	}
+	sortTrades(page.Trades, 0)
+
+	if len(resp.Data) == limit && !reachedSince {
+		page.Cursor = resp.Data[len(resp.Data)-1].TradeID
+	}
+	return page, nil
+}
+
+type okxInstrument struct {
+	InstID   string      `json:"instId"`
+	Uly      string      `json:"uly"`
+	BaseCcy  string      `json:"baseCcy"`
+	QuoteCcy string      `json:"quoteCcy"`
+	CtType   string      `json:"ctType"`
+	CtVal    jsonDecimal `json:"ctVal"`
+	TickSz   jsonDecimal `json:"tickSz"`
+	LotSz    jsonDecimal `json:"lotSz"`
+	MinSz    jsonDecimal `json:"minSz"`
+	MaxLmtSz jsonDecimal `json:"maxLmtSz"`
+	State    string      `json:"state"`
+}
+
+func okxStatus(state string) types.InstrumentStatus {
+	switch state {
+	case "live":
+		return types.InstrumentTrading
+	case "preopen":
+		return types.InstrumentPreTrading
+	default:
+		return types.InstrumentHalted
+	}
+}
+
+// GetInstruments 中合约只返回永续合约，U 本位与币本位按 ctType 区分；合约的数量单位为张，ContractSize 为 ctVal
+func (o *OKX) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	market := o.config.MarketOrSpot()
+	instType, ctType := "SPOT", ""
+	switch market {
+	case types.USDMFutures:
+		instType, ctType = "SWAP", "linear"
+	case types.CoinMFutures:
+		instType, ctType = "SWAP", "inverse"
+	case types.Options:
+		return nil, notSupported(o, "options instruments")
+	}
+
+	return o.instruments.get(ctx, o.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var resp okxResponse[[]okxInstrument]
+		if err := sendRequest(ctx, o, "GET", "/api/v5/public/instruments", map[string]interface{}{"instType": instType}, false, &resp); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(resp.Data))
+		for _, i := range resp.Data {
+			if ctType != "" && i.CtType != ctType {
+				continue
+			}
+
+			inst := types.Instrument{
+				Symbol:   i.InstID,
+				Base:     i.BaseCcy,
+				Quote:    i.QuoteCcy,
+				TickSize: i.TickSz.Decimal,
+				StepSize: i.LotSz.Decimal,
+				MinQty:   i.MinSz.Decimal,
+				MaxQty:   i.MaxLmtSz.Decimal,
+				Status:   okxStatus(i.State),
+			}
+			// 合约的 baseCcy、quoteCcy 为空，从标的指数（如 BTC-USDT）中解析
+			if ctType != "" {
+				inst.Base, inst.Quote, _ = strings.Cut(i.Uly, "-")
+				inst.ContractSize = i.CtVal.Decimal
+			}
+			instruments = append(instruments, inst)
+		}
+		return instruments, nil
+	})
+}
+
+// okxFundingRate 中 fundingTime 是当前费率的结算时间，nextFundingTime 是下一期的结算时间
+type okxFundingRate struct {
+	InstID          string      `json:"instId"`
+	FundingRate     jsonDecimal `json:"fundingRate"`
+	FundingTime     jsonInt     `json:"fundingTime"`
+	NextFundingTime jsonInt     `json:"nextFundingTime"`
+	Ts              jsonInt     `json:"ts"`
+}
+
+// GetFundingRate 的 symbol 为永续合约 instId，例如 BTC-USDT-SWAP
+func (o *OKX) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	if err := requirePerpetual(o, o.config.MarketOrSpot()); err != nil {
+		return types.FundingRate{}, err
+	}
+
+	var resp okxResponse[[]okxFundingRate]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/public/funding-rate", map[string]interface{}{"instId": symbol}, false, &resp); err != nil {
+		return types.FundingRate{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.FundingRate{}, fmt.Errorf("okx funding rate %s not found", symbol)
+	}
+
+	f := resp.Data[0]
+	rate := types.FundingRate{
+		Symbol:          f.InstID,
+		Rate:            f.FundingRate.Decimal,
+		NextFundingTime: msToTime(int64(f.FundingTime)),
+		Timestamp:       msToTime(int64(f.Ts)),
+	}
+	if f.NextFundingTime > f.FundingTime {
+		rate.Interval = time.Duration(f.NextFundingTime-f.FundingTime) * time.Millisecond
+	}
+	return rate, nil
+}
+
+// GetFundingHistory 单次最多返回 100 条，after 返回早于该时间的数据，before 返回晚于该时间的数据
+func (o *OKX) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	if err := requirePerpetual(o, o.config.MarketOrSpot()); err != nil {
+		return nil, err
+	}
+
+	params := map[string]interface{}{
+		"instId": symbol,
+		"limit":  clampDepth(limit, 100),
+	}
+	if !end.IsZero() {
+		params["after"] = end.UnixMilli() + 1
+	}
+	if !start.IsZero() {
+		params["before"] = start.UnixMilli() - 1
+	}
+
+	var resp okxResponse[[]struct {
+		InstID      string      `json:"instId"`
+		FundingRate jsonDecimal `json:"fundingRate"`
+		FundingTime jsonInt     `json:"fundingTime"`
+	}]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/public/funding-rate-history", params, false, &resp); err != nil {
+		return nil, err
+	}
+
+	records := make([]types.FundingRecord, 0, len(resp.Data))
+	for _, r := range resp.Data {
+		records = append(records, types.FundingRecord{Symbol: r.InstID, Rate: r.FundingRate.Decimal, Time: msToTime(int64(r.FundingTime))})
+	}
+	return trimFunding(records, start, end, limit), nil
+}
+
+func (o *OKX) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	if err := requirePerpetual(o, o.config.MarketOrSpot()); err != nil {
+		return types.ReferencePrice{}, err
+	}
+
+	params := map[string]interface{}{
+		"instType": "SWAP",
+		"instId":   symbol,
+	}
+
+	var resp okxResponse[[]struct {
+		InstID string      `json:"instId"`
+		MarkPx jsonDecimal `json:"markPx"`
+		Ts     jsonInt     `json:"ts"`
+	}]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/public/mark-price", params, false, &resp); err != nil {
+		return types.ReferencePrice{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.ReferencePrice{}, fmt.Errorf("okx mark price %s not found", symbol)
+	}
+
+	return types.ReferencePrice{Symbol: resp.Data[0].InstID, Price: resp.Data[0].MarkPx.Decimal, Timestamp: msToTime(int64(resp.Data[0].Ts))}, nil
+}
+
+// GetIndexPrice 查询合约标的指数，BTC-USDT-SWAP 对应的指数为 BTC-USDT
+func (o *OKX) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	if err := requirePerpetual(o, o.config.MarketOrSpot()); err != nil {
+		return types.ReferencePrice{}, err
+	}
+
+	var resp okxResponse[[]struct {
+		IdxPx jsonDecimal `json:"idxPx"`
+		Ts    jsonInt     `json:"ts"`
+	}]
+	index := strings.TrimSuffix(symbol, "-SWAP")
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/index-tickers", map[string]interface{}{"instId": index}, false, &resp); err != nil {
+		return types.ReferencePrice{}, err
+	}
+	if len(resp.Data) == 0 {
+		return types.ReferencePrice{}, fmt.Errorf("okx index price %s not found", index)
+	}
+
+	return types.ReferencePrice{Symbol: symbol, Price: resp.Data[0].IdxPx.Decimal, Timestamp: msToTime(int64(resp.Data[0].Ts))}, nil
+}
+
+// GetAllTickers 中合约只返回永续合约，U 本位与币本位按交易对元数据区分
+func (o *OKX) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	market := o.config.MarketOrSpot()
+	instType := "SPOT"
+	switch market {
+	case types.USDMFutures, types.CoinMFutures:
+		instType = "SWAP"
+	case types.Options:
+		return nil, notSupported(o, "options tickers")
+	}
+
+	var resp okxResponse[[]okxTicker]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/market/tickers", map[string]interface{}{"instType": instType}, false, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]types.Ticker, 0, len(resp.Data))
+	for _, t := range resp.Data {
+		tickers = append(tickers, t.toTicker(market))
+	}
+	return keyTickers(ctx, o, tickers)
+}
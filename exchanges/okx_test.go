@@ -0,0 +1,49 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOKX_PrepareRequest(t *testing.T) {
+	okx := NewOKX(types.ExchangeConfig{
+		APIKey:        "test_key",
+		APISecret:     "test_secret",
+		APIPassphrase: "test_passphrase",
+	})
+
+	// OK-ACCESS-SIGN = base64(HMAC-SHA256(timestamp + method + 路径和查询字符串 + 请求体))
+	sign := func(timestamp, method, requestURI, body string) string {
+		mac := hmac.New(sha256.New, []byte("test_secret"))
+		mac.Write([]byte(timestamp + method + requestURI + body))
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("GET", func(t *testing.T) {
+		req, err := okx.PrepareRequest("GET", "/api/v5/account/trade-fee", map[string]interface{}{"instType": "SPOT", "instId": "BTC-USDT"}, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "instId=BTC-USDT&instType=SPOT", req.URL.RawQuery)
+		assert.Equal(t, "test_key", req.Header.Get("OK-ACCESS-KEY"))
+		assert.Equal(t, "test_passphrase", req.Header.Get("OK-ACCESS-PASSPHRASE"))
+
+		timestamp := req.Header.Get("OK-ACCESS-TIMESTAMP")
+		assert.NotEmpty(t, timestamp)
+		assert.Equal(t, sign(timestamp, "GET", "/api/v5/account/trade-fee?instId=BTC-USDT&instType=SPOT", ""), req.Header.Get("OK-ACCESS-SIGN"))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		req, err := okx.PrepareRequest("POST", "/api/v5/trade/order", map[string]interface{}{"instId": "BTC-USDT", "sz": "0.1"}, true)
+		assert.NoError(t, err)
+		assert.Empty(t, req.URL.RawQuery)
+
+		body, _ := io.ReadAll(req.Body)
+		assert.JSONEq(t, `{"instId":"BTC-USDT","sz":"0.1"}`, string(body))
+		assert.Equal(t, sign(req.Header.Get("OK-ACCESS-TIMESTAMP"), "POST", "/api/v5/trade/order", string(body)), req.Header.Get("OK-ACCESS-SIGN"))
+	})
+}
@@ -0,0 +1,815 @@
+package exchanges
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+type okxTradeFee struct {
+	Maker  jsonDecimal `json:"maker"`
+	Taker  jsonDecimal `json:"taker"`
+	MakerU jsonDecimal `json:"makerU"`
+	TakerU jsonDecimal `json:"takerU"`
+}
+
+// toFee 中 OKX 以负数表示收取手续费、正数表示返佣，这里取反；U 本位合约使用 makerU/takerU
+func (f okxTradeFee) toFee(symbol string, market types.Market) types.TradingFee {
+	maker, taker := f.Maker.Decimal, f.Taker.Decimal
+	if market == types.USDMFutures {
+		maker, taker = f.MakerU.Decimal, f.TakerU.Decimal
+	}
+	return types.TradingFee{Symbol: symbol, Maker: maker.Neg(), Taker: taker.Neg()}
+}
+
+func (o *OKX) tradeFee(ctx context.Context, params map[string]interface{}) (okxTradeFee, error) {
+	var resp okxResponse[[]okxTradeFee]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/account/trade-fee", params, true, &resp); err != nil {
+		return okxTradeFee{}, err
+	}
+	if len(resp.Data) == 0 {
+		return okxTradeFee{}, fmt.Errorf("okx trade fee %v not found", params)
+	}
+	return resp.Data[0], nil
+}
+
+// GetTradingFees 中现货按交易对逐个查询；合约费率取决于账户等级，统一适用于全部永续合约
+func (o *OKX) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	market := o.config.MarketOrSpot()
+	switch market {
+	case types.Options:
+		return nil, notSupported(o, "options trading fees")
+	case types.USDMFutures, types.CoinMFutures:
+		fee, err := o.tradeFee(ctx, map[string]interface{}{"instType": "SWAP"})
+		if err != nil {
+			return nil, err
+		}
+		f := fee.toFee("", market)
+		return uniformFees(ctx, o, symbols, f.Maker, f.Taker)
+	}
+
+	if len(symbols) == 0 {
+		fee, err := o.tradeFee(ctx, map[string]interface{}{"instType": "SPOT"})
+		if err != nil {
+			return nil, err
+		}
+		f := fee.toFee("", market)
+		return uniformFees(ctx, o, symbols, f.Maker, f.Taker)
+	}
+
+	return fanOut(ctx, symbols, func(ctx context.Context, symbol string) (types.TradingFee, error) {
+		fee, err := o.tradeFee(ctx, map[string]interface{}{"instType": "SPOT", "instId": symbol})
+		if err != nil {
+			return types.TradingFee{}, err
+		}
+		return fee.toFee(symbol, market), nil
+	})
+}
+
+// okxOrderAck 是下单、撤单等接口中每个订单的处理结果，sCode 非 0 表示该订单失败
+type okxOrderAck struct {
+	OrdID       string `json:"ordId"`
+	ClOrdID     string `json:"clOrdId"`
+	AlgoID      string `json:"algoId"`
+	AlgoClOrdID string `json:"algoClOrdId"`
+	SCode       string `json:"sCode"`
+	SMsg        string `json:"sMsg"`
+}
+
+func (a okxOrderAck) err() error {
+	if a.SCode != "" && a.SCode != "0" {
+		return &types.APIError{StatusCode: http.StatusOK, Code: a.SCode, Message: a.SMsg}
+	}
+	return nil
+}
+
+// okxAckResponse 中 code 为 1 或 2 表示全部或部分订单失败，具体原因在每个订单的 sCode 中，由调用方逐个检查
+type okxAckResponse struct {
+	okxResponse[[]okxOrderAck]
+}
+
+func (r *okxAckResponse) apiError() error {
+	if len(r.Data) > 0 {
+		return nil
+	}
+	return r.okxResponse.apiError()
+}
+
+// first 返回单个订单请求的结果，订单失败时返回其 sCode 对应的错误
+func (r *okxAckResponse) first() (okxOrderAck, error) {
+	if len(r.Data) == 0 {
+		return okxOrderAck{}, fmt.Errorf("okx returned no order result")
+	}
+	ack := r.Data[0]
+	return ack, ack.err()
+}
+
+// at 返回批量请求中第 i 个订单的结果，结果顺序与请求一致
+func (r *okxAckResponse) at(i int) (okxOrderAck, error) {
+	if i >= len(r.Data) {
+		return okxOrderAck{}, errMissingResult
+	}
+	ack := r.Data[i]
+	return ack, ack.err()
+}
+
+// tdMode 返回下单的交易模式：现货为非保证金交易，合约使用全仓
+func (o *OKX) tdMode() string {
+	if o.config.MarketOrSpot() == types.Spot {
+		return "cash"
+	}
+	return "cross"
+}
+
+// orderParams 把统一的下单请求转换为 OKX 参数，post-only、IOC、FOK 通过 ordType 表达
+func (o *OKX) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	market := o.config.MarketOrSpot()
+	if market == types.Options {
+		return nil, notSupported(o, "options orders")
+	}
+
+	ordType := strings.ToLower(string(req.Type))
+	if req.Type == types.LimitOrder {
+		switch {
+		case req.PostOnly:
+			ordType = "post_only"
+		case req.TimeInForce == types.IOC:
+			ordType = "ioc"
+		case req.TimeInForce == types.FOK:
+			ordType = "fok"
+		}
+	}
+
+	if req.Type.Conditional() {
+		return nil, notSupported(o, "conditional orders on the order endpoint")
+	}
+
+	params := map[string]interface{}{
+		"instId":  req.Symbol,
+		"tdMode":  o.tdMode(),
+		"side":    lowerSide(req.Side),
+		"ordType": ordType,
+		"sz":      req.Quantity.String(),
+	}
+	if req.Type == types.LimitOrder {
+		params["px"] = req.Price.String()
+	}
+	if req.ClientOrderID != "" {
+		params["clOrdId"] = req.ClientOrderID
+	}
+
+	if market == types.Spot {
+		if req.ReduceOnly {
+			return nil, notSupported(o, "reduce-only spot orders")
+		}
+		// 现货市价买单默认按计价币下单，需要明确数量的单位
+		if req.Type == types.MarketOrder {
+			params["tgtCcy"] = "base_ccy"
+			if req.QuoteQuantity.IsPositive() {
+				params["sz"] = req.QuoteQuantity.String()
+				params["tgtCcy"] = "quote_ccy"
+			}
+		}
+		return params, nil
+	}
+
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(o, "swap orders by quote quantity")
+	}
+	if req.ReduceOnly {
+		params["reduceOnly"] = true
+	}
+	return params, nil
+}
+
+// okxBatchSize 是 batch-orders 与 cancel-batch-orders 一次最多处理的订单数
+const okxBatchSize = 20
+
+func (o *OKX) placer() placer {
+	return placer{t: o, config: o.config, idLength: clientOrderIDLength, uniqueIDs: true, place: o.placeOrder, batch: o.placeBatch, batchSize: okxBatchSize}
+}
+
+// PlaceOrder 中合约按全仓模式下单，数量单位为张
+func (o *OKX) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return o.placer().placeOrder(ctx, req)
+}
+
+func (o *OKX) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return o.placer().placeOrders(ctx, reqs), nil
+}
+
+// placeBatch 通过 batch-orders 提交一批订单，请求体是订单参数组成的数组
+func (o *OKX) placeBatch(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return buildBatch(ctx, reqs, o.orderParams, func(ctx context.Context, items []map[string]interface{}, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+		var resp okxAckResponse
+		if err := sendRequest(ctx, o, "POST", "/api/v5/trade/batch-orders", map[string]interface{}{bodyParam: items}, true, &resp); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.OrderResult, len(reqs))
+		for i, req := range reqs {
+			ack, err := resp.at(i)
+			if err != nil {
+				results[i].Err = err
+				continue
+			}
+			results[i].Order = orderFromRequest(req, ack.OrdID)
+		}
+		return results, nil
+	})
+}
+
+func (o *OKX) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if req.Type.Conditional() {
+		return o.placeAlgo(ctx, req)
+	}
+
+	params, err := o.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp okxAckResponse
+	if err := sendRequest(ctx, o, "POST", "/api/v5/trade/order", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	ack, err := resp.first()
+	if err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, ack.OrdID), nil
+}
+
+// algoParams 把条件单转换为 order-algo 参数：止损、止盈单是 conditional，OCO 是 oco。
+// OKX 的止盈部分也是触发单，OCO 的止盈部分在价格触及 Price 时以 Price 挂限价单；委托价为 -1 表示市价
+func (o *OKX) algoParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if o.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(o, "options orders")
+	}
+
+	params := map[string]interface{}{
+		"instId":  req.Symbol,
+		"tdMode":  o.tdMode(),
+		"side":    lowerSide(req.Side),
+		"ordType": "conditional",
+		"sz":      req.Quantity.String(),
+	}
+	if req.ClientOrderID != "" {
+		params["algoClOrdId"] = req.ClientOrderID
+	}
+
+	orderPx := "-1"
+	if req.Type.Priced() {
+		orderPx = req.Price.String()
+	}
+	switch req.Type {
+	case types.StopMarketOrder, types.StopLimitOrder:
+		params["slTriggerPx"], params["slOrdPx"] = req.TriggerPrice.String(), orderPx
+	case types.TakeProfitMarketOrder, types.TakeProfitLimitOrder:
+		params["tpTriggerPx"], params["tpOrdPx"] = req.TriggerPrice.String(), orderPx
+	case types.OCOOrder:
+		params["ordType"] = "oco"
+		params["tpTriggerPx"], params["tpOrdPx"] = req.Price.String(), req.Price.String()
+		params["slTriggerPx"], params["slOrdPx"] = req.TriggerPrice.String(), "-1"
+		if req.StopLimitPrice.IsPositive() {
+			params["slOrdPx"] = req.StopLimitPrice.String()
+		}
+	}
+
+	if o.config.MarketOrSpot() == types.Spot {
+		if req.ReduceOnly {
+			return nil, notSupported(o, "reduce-only spot orders")
+		}
+		params["tgtCcy"] = "base_ccy"
+		if req.QuoteQuantity.IsPositive() {
+			params["sz"], params["tgtCcy"] = req.QuoteQuantity.String(), "quote_ccy"
+		}
+		return params, nil
+	}
+
+	if req.QuoteQuantity.IsPositive() {
+		return nil, notSupported(o, "swap orders by quote quantity")
+	}
+	if req.ReduceOnly {
+		params["reduceOnly"] = true
+	}
+	return params, nil
+}
+
+// placeAlgo 通过 order-algo 下条件单，返回的订单号是 algoId，自定义订单号是 algoClOrdId
+func (o *OKX) placeAlgo(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := o.algoParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var resp okxAckResponse
+	if err := sendRequest(ctx, o, "POST", "/api/v5/trade/order-algo", params, true, &resp); err != nil {
+		return types.Order{}, err
+	}
+	ack, err := resp.first()
+	if err != nil {
+		return types.Order{}, err
+	}
+	order := orderFromRequest(req, ack.AlgoID)
+	order.TriggerPrice = req.TriggerPrice
+	return order, nil
+}
+
+// okxAlgoOrder 是条件单，触发后以 ordId 的订单成交；actualSz 是触发后实际下单的数量
+type okxAlgoOrder struct {
+	InstID      string      `json:"instId"`
+	AlgoID      string      `json:"algoId"`
+	AlgoClOrdID string      `json:"algoClOrdId"`
+	Side        string      `json:"side"`
+	OrdType     string      `json:"ordType"`
+	Sz          jsonDecimal `json:"sz"`
+	State       string      `json:"state"`
+	SlTriggerPx jsonDecimal `json:"slTriggerPx"`
+	SlOrdPx     jsonDecimal `json:"slOrdPx"`
+	TpTriggerPx jsonDecimal `json:"tpTriggerPx"`
+	TpOrdPx     jsonDecimal `json:"tpOrdPx"`
+	ActualSz    jsonDecimal `json:"actualSz"`
+	ReduceOnly  string      `json:"reduceOnly"`
+	CTime       jsonInt     `json:"cTime"`
+	UTime       jsonInt     `json:"uTime"`
+}
+
+// okxAlgoStatuses 中 effective 表示已触发并下单，条件单本身就此结束，成交情况需查询触发后的订单
+var okxAlgoStatuses = map[string]types.OrderStatus{
+	"live":                types.OrderNew,
+	"pause":               types.OrderNew,
+	"partially_effective": types.OrderPartiallyFilled,
+	"effective":           types.OrderFilled,
+	"canceled":            types.OrderCanceled,
+	"order_failed":        types.OrderRejected,
+}
+
+func (o okxAlgoOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:            o.AlgoID,
+		ClientOrderID: o.AlgoClOrdID,
+		Symbol:        o.InstID,
+		Side:          parseSide(o.Side),
+		Quantity:      o.Sz.Decimal,
+		ReduceOnly:    o.ReduceOnly == "true",
+
+		Status:         orderStatus(okxAlgoStatuses, o.State),
+		FilledQuantity: o.ActualSz.Decimal,
+		Raw:            o.State,
+		CreatedAt:      msToTime(int64(o.CTime)),
+		UpdatedAt:      msToTime(int64(o.UTime)),
+	}
+
+	// 委托价 -1 表示触发后按市价下单
+	priced := func(px jsonDecimal) bool { return px.IsPositive() }
+	switch {
+	case o.OrdType == "oco":
+		order.Type, order.Price, order.TriggerPrice = types.OCOOrder, o.TpOrdPx.Decimal, o.SlTriggerPx.Decimal
+	case o.SlTriggerPx.IsPositive():
+		order.Type, order.TriggerPrice = types.StopMarketOrder, o.SlTriggerPx.Decimal
+		if priced(o.SlOrdPx) {
+			order.Type, order.Price = types.StopLimitOrder, o.SlOrdPx.Decimal
+		}
+	default:
+		order.Type, order.TriggerPrice = types.TakeProfitMarketOrder, o.TpTriggerPx.Decimal
+		if priced(o.TpOrdPx) {
+			order.Type, order.Price = types.TakeProfitLimitOrder, o.TpOrdPx.Decimal
+		}
+	}
+	if order.Type.Priced() {
+		order.TimeInForce = types.GTC
+	}
+	return order
+}
+
+// algoRefParams 返回按 algoId 或 algoClOrdId 定位条件单的参数
+func (o *OKX) algoRefParams(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"instId": symbol}
+	if ref.ID != "" {
+		params["algoId"] = ref.ID
+	} else {
+		params["algoClOrdId"] = ref.ClientOrderID
+	}
+	return params
+}
+
+// getAlgo 查询条件单
+func (o *OKX) getAlgo(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	params := o.algoRefParams(symbol, ref)
+	delete(params, "instId")
+
+	var resp okxResponse[[]okxAlgoOrder]
+	if err := sendRequest(ctx, o, "GET", "/api/v5/trade/order-algo", params, true, &resp); err != nil {
+		return types.Order{}, orderNotFound(err, okxOrderNotFound, okxAlgoNotFound)
+	}
+	if len(resp.Data) == 0 {
+		return types.Order{}, fmt.Errorf("okx algo order %s: %w", symbol, types.ErrOrderNotFound)
+	}
+	return resp.Data[0].toOrder(), nil
+}
+
+// okxAlgoNotFound 是条件单不存在时的错误码，okxCancelFailed 是撤单时订单不存在或已结束的错误码
+const (
+	okxAlgoNotFound = "51000"
+	okxCancelFailed = "51400"
+)
+
+// okxOrder 是订单查询接口返回的订单，市价单的 px 为空，tgtCcy 为 quote_ccy 时 sz 是计价币金额；
+// fee 为负数表示收取的手续费
+type okxOrder struct {
+	InstID     string      `json:"instId"`
+	OrdID      string      `json:"ordId"`
+	ClOrdID    string      `json:"clOrdId"`
+	Px         jsonDecimal `json:"px"`
+	Sz         jsonDecimal `json:"sz"`
+	Side       string      `json:"side"`
+	OrdType    string      `json:"ordType"`
+	TgtCcy     string      `json:"tgtCcy"`
+	ReduceOnly string      `json:"reduceOnly"`
+	State      string      `json:"state"`
+	AccFillSz  jsonDecimal `json:"accFillSz"`
+	AvgPx      jsonDecimal `json:"avgPx"`
+	Fee        jsonDecimal `json:"fee"`
+	FeeCcy     string      `json:"feeCcy"`
+	CTime      jsonInt     `json:"cTime"`
+	UTime      jsonInt     `json:"uTime"`
+}
+
+var okxStatuses = map[string]types.OrderStatus{
+	"live":             types.OrderNew,
+	"partially_filled": types.OrderPartiallyFilled,
+	"filled":           types.OrderFilled,
+	"canceled":         types.OrderCanceled,
+	"mmp_canceled":     types.OrderCanceled,
+}
+
+// okxOrderNotFound 是订单不存在时的错误码
+const okxOrderNotFound = "51603"
+
+func (o okxOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:            o.OrdID,
+		ClientOrderID: o.ClOrdID,
+		Symbol:        o.InstID,
+		Side:          parseSide(o.Side),
+		Type:          types.LimitOrder,
+		TimeInForce:   types.GTC,
+		Price:         o.Px.Decimal,
+		Quantity:      o.Sz.Decimal,
+		ReduceOnly:    o.ReduceOnly == "true",
+
+		Status:         orderStatus(okxStatuses, o.State),
+		FilledQuantity: o.AccFillSz.Decimal,
+		AveragePrice:   o.AvgPx.Decimal,
+		Fee:            o.Fee.Neg(),
+		FeeAsset:       o.FeeCcy,
+		Raw:            o.State,
+		CreatedAt:      msToTime(int64(o.CTime)),
+		UpdatedAt:      msToTime(int64(o.UTime)),
+	}
+
+	switch o.OrdType {
+	case "market":
+		order.Type, order.TimeInForce = types.MarketOrder, ""
+		if o.TgtCcy == "quote_ccy" {
+			order.Quantity, order.QuoteQuantity = decimal.Zero, o.Sz.Decimal
+		}
+	case "ioc":
+		order.TimeInForce = types.IOC
+	case "fok":
+		order.TimeInForce = types.FOK
+	}
+	return order
+}
+
+// refParams 返回按订单号或自定义订单号定位订单的参数
+func (o *OKX) refParams(symbol string, ref types.OrderRef) map[string]interface{} {
+	params := map[string]interface{}{"instId": symbol}
+	if ref.ID != "" {
+		params["ordId"] = ref.ID
+	} else {
+		params["clOrdId"] = ref.ClientOrderID
+	}
+	return params
+}
+
+// CancelOrder 在普通订单中找不到订单时改为按条件单撤销
+func (o *OKX) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	err := o.cancelOrder(ctx, symbol, ref)
+	if err == nil || !errors.Is(orderNotFound(err, okxCancelFailed, okxOrderNotFound), types.ErrOrderNotFound) {
+		return err
+	}
+
+	var algoResp okxAckResponse
+	if algoErr := sendRequest(ctx, o, "POST", "/api/v5/trade/cancel-algos", map[string]interface{}{bodyParam: []map[string]interface{}{o.algoRefParams(symbol, ref)}}, true, &algoResp); algoErr != nil {
+		return err
+	}
+	if _, algoErr := algoResp.first(); algoErr != nil {
+		return err
+	}
+	return nil
+}
+
+// AmendOrder 使用 amend-order 原地修改订单，订单号不变
+func (o *OKX) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	params := o.refParams(req.Symbol, req.Ref)
+	if req.Price.IsPositive() {
+		params["newPx"] = req.Price.String()
+	}
+	if req.Quantity.IsPositive() {
+		params["newSz"] = req.Quantity.String()
+	}
+
+	var resp okxAckResponse
+	if err := sendRequest(ctx, o, "POST", "/api/v5/trade/amend-order", params, true, &resp); err != nil {
+		return types.AmendResult{}, err
+	}
+	ack, err := resp.first()
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	return types.AmendResult{Order: amendedOrder(req, ack.OrdID, ack.ClOrdID)}, nil
+}
+
+// cancelOrder 撤销普通订单
+func (o *OKX) cancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	var resp okxAckResponse
+	if err := sendRequest(ctx, o, "POST", "/api/v5/trade/cancel-order", o.refParams(symbol, ref), true, &resp); err != nil {
+		return err
+	}
+	_, err := resp.first()
+	return err
+}
+
+// CancelOrders 通过 cancel-batch-orders 批量撤单
+func (o *OKX) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, okxBatchSize, func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error) {
+		items := make([]map[string]interface{}, len(refs))
+		for i, ref := range refs {
+			items[i] = o.refParams(symbol, ref)
+		}
+
+		var resp okxAckResponse
+		if err := sendRequest(ctx, o, "POST", "/api/v5/trade/cancel-batch-orders", map[string]interface{}{bodyParam: items}, true, &resp); err != nil {
+			return nil, err
+		}
+
+		results := make([]types.CancelResult, len(refs))
+		for i, ref := range refs {
+			_, err := resp.at(i)
+			results[i] = refResult(symbol, ref, err)
+		}
+		return results, nil
+	}, nil), nil
+}
+
+// listOrders 按 ordId 向前翻页读取 endpoint 的订单，limit 为 0 时读取全部
+func (o *OKX) listOrders(ctx context.Context, endpoint string, params map[string]interface{}, limit int) ([]types.Order, error) {
+	params["instType"] = "SPOT"
+	if o.config.MarketOrSpot() != types.Spot {
+		params["instType"] = "SWAP"
+	}
+	params["limit"] = 100
+
+	var orders []types.Order
+	for limit <= 0 || len(orders) < limit {
+		var resp okxResponse[[]okxOrder]
+		if err := sendRequest(ctx, o, "GET", endpoint, params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, row := range resp.Data {
+			orders = append(orders, row.toOrder())
+		}
+		if len(resp.Data) < 100 {
+			break
+		}
+		params["after"] = resp.Data[len(resp.Data)-1].OrdID
+	}
+	return orders, nil
+}
+
+// openOrders 分页读取当前挂单，symbol 为空时返回当前产品线的全部挂单
+func (o *OKX) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{}
+	if symbol != "" {
+		params["instId"] = symbol
+	}
+
+	orders, err := o.listOrders(ctx, "/api/v5/trade/orders-pending", params, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbol == "" {
+		return o.marketOrders(ctx, orders)
+	}
+	return orders, nil
+}
+
+// marketOrders 过滤出当前产品线的订单：永续合约的订单同时包含 U 本位与币本位
+func (o *OKX) marketOrders(ctx context.Context, orders []types.Order) ([]types.Order, error) {
+	if o.config.MarketOrSpot() == types.Spot {
+		return orders, nil
+	}
+
+	known, err := o.marketSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := orders[:0]
+	for _, order := range orders {
+		if known[order.Symbol] {
+			filtered = append(filtered, order)
+		}
+	}
+	return filtered, nil
+}
+
+// marketSymbols 返回当前产品线的全部交易对，用于从 SWAP 的结果中过滤出 U 本位或币本位合约
+func (o *OKX) marketSymbols(ctx context.Context) (map[string]bool, error) {
+	symbols, err := instrumentSymbols(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(symbols))
+	for _, s := range symbols {
+		known[s] = true
+	}
+	return known, nil
+}
+
+// CancelAllOrders 列出挂单后逐个撤销；OKX 的 mass-cancel 只适用于期权
+func (o *OKX) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	if o.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(o, "options orders")
+	}
+
+	orders, err := o.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+		return o.cancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+	}), nil
+}
+
+func (o *OKX) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	var resp okxResponse[[]okxOrder]
+	err := sendRequest(ctx, o, "GET", "/api/v5/trade/order", o.refParams(symbol, ref), true, &resp)
+	switch {
+	case err == nil && len(resp.Data) > 0:
+		return resp.Data[0].toOrder(), nil
+	case err == nil:
+		err = fmt.Errorf("okx order %s: %w", symbol, types.ErrOrderNotFound)
+	default:
+		err = orderNotFound(err, okxOrderNotFound)
+	}
+	if !errors.Is(err, types.ErrOrderNotFound) {
+		return types.Order{}, err
+	}
+
+	// 条件单有独立的订单号，普通订单中找不到时再查询条件单
+	if order, algoErr := o.getAlgo(ctx, symbol, ref); !errors.Is(algoErr, types.ErrOrderNotFound) {
+		return order, algoErr
+	}
+	return types.Order{}, err
+}
+
+func (o *OKX) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	if o.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(o, "options orders")
+	}
+	return o.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 orders-history，只能查询最近 7 天内结束的订单
+func (o *OKX) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	if o.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(o, "options orders")
+	}
+
+	params := map[string]interface{}{}
+	if req.Symbol != "" {
+		params["instId"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["begin"] = req.Start.UnixMilli()
+	}
+	if !req.End.IsZero() {
+		params["end"] = req.End.UnixMilli()
+	}
+
+	orders, err := o.listOrders(ctx, "/api/v5/trade/orders-history", params, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	if req.Symbol == "" {
+		if orders, err = o.marketOrders(ctx, orders); err != nil {
+			return nil, err
+		}
+	}
+	return orderHistory(orders, req), nil
+}
+
+// okxFill 是 fills-history 的成交，fee 为负数表示扣除的手续费，正数表示返佣；execType 为 M 表示 maker
+type okxFill struct {
+	InstID   string      `json:"instId"`
+	TradeID  string      `json:"tradeId"`
+	OrdID    string      `json:"ordId"`
+	ClOrdID  string      `json:"clOrdId"`
+	BillID   string      `json:"billId"`
+	Side     string      `json:"side"`
+	FillPx   jsonDecimal `json:"fillPx"`
+	FillSz   jsonDecimal `json:"fillSz"`
+	Fee      jsonDecimal `json:"fee"`
+	FeeCcy   string      `json:"feeCcy"`
+	ExecType string      `json:"execType"`
+	Ts       jsonInt     `json:"ts"`
+}
+
+func (f okxFill) toFill() types.Fill {
+	return types.Fill{
+		Symbol:        f.InstID,
+		OrderID:       f.OrdID,
+		ClientOrderID: f.ClOrdID,
+		TradeID:       f.TradeID,
+		Side:          parseSide(f.Side),
+		Price:         f.FillPx.Decimal,
+		Quantity:      f.FillSz.Decimal,
+		Fee:           f.Fee.Neg(),
+		FeeAsset:      f.FeeCcy,
+		Liquidity:     liquidity(f.ExecType == "M"),
+		Time:          msToTime(int64(f.Ts)),
+	}
+}
+
+// okxFillPage 是 fills-history 每页的最大数量
+const okxFillPage = 100
+
+// GetFills 使用 fills-history 从最新的成交按 billId 向前翻页，直到 since；接口只保留最近 3 个月的成交
+func (o *OKX) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	if o.config.MarketOrSpot() == types.Options {
+		return nil, notSupported(o, "options fills")
+	}
+
+	params := map[string]interface{}{"instType": "SPOT", "limit": okxFillPage}
+	if o.config.MarketOrSpot() != types.Spot {
+		params["instType"] = "SWAP"
+	}
+	if symbol != "" {
+		params["instId"] = symbol
+	}
+	if !since.IsZero() {
+		params["begin"] = since.UnixMilli()
+	}
+
+	var fills []types.Fill
+	for {
+		var resp okxResponse[[]okxFill]
+		if err := sendRequest(ctx, o, "GET", "/api/v5/trade/fills-history", params, true, &resp); err != nil {
+			return nil, err
+		}
+		for _, row := range resp.Data {
+			fills = append(fills, row.toFill())
+		}
+		if len(resp.Data) < okxFillPage {
+			break
+		}
+		params["after"] = resp.Data[len(resp.Data)-1].BillID
+	}
+
+	if symbol == "" && o.config.MarketOrSpot() != types.Spot {
+		known, err := o.marketSymbols(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filtered := fills[:0]
+		for _, fill := range fills {
+			if known[fill.Symbol] {
+				filtered = append(filtered, fill)
+			}
+		}
+		fills = filtered
+	}
+	return fillHistory(fills, since), nil
+}
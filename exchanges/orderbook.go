@@ -0,0 +1,55 @@
+package exchanges
+
+import (
+	"sort"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// nearestDepth 返回 allowed 中不小于 depth 的最小值，depth 超出范围或 <= 0 时返回最大值。allowed 需升序排列。
+func nearestDepth(depth int, allowed []int) int {
+	if depth > 0 {
+		for _, d := range allowed {
+			if d >= depth {
+				return d
+			}
+		}
+	}
+	return allowed[len(allowed)-1]
+}
+
+// clampDepth 用于接受任意档位但有上限的交易所
+func clampDepth(depth, max int) int {
+	if depth <= 0 || depth > max {
+		return max
+	}
+	return depth
+}
+
+// levelsFromRows 解析 [[价格, 数量, ...], ...] 形式的档位
+func levelsFromRows(rows [][]jsonDecimal) []types.PriceLevel {
+	levels := make([]types.PriceLevel, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		levels = append(levels, types.PriceLevel{Price: row[0].Decimal, Size: row[1].Decimal})
+	}
+	return levels
+}
+
+// finalizeOrderBook 对买卖盘排序并截取到 depth 档
+func finalizeOrderBook(book types.OrderBook, depth int) types.OrderBook {
+	sort.SliceStable(book.Bids, func(i, j int) bool { return book.Bids[i].Price.GreaterThan(book.Bids[j].Price) })
+	sort.SliceStable(book.Asks, func(i, j int) bool { return book.Asks[i].Price.LessThan(book.Asks[j].Price) })
+
+	if depth > 0 {
+		if len(book.Bids) > depth {
+			book.Bids = book.Bids[:depth]
+		}
+		if len(book.Asks) > depth {
+			book.Asks = book.Asks[:depth]
+		}
+	}
+	return book
+}
@@ -0,0 +1,594 @@
+package exchanges
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// timeInForce 返回限价单的有效方式，未指定时为 GTC；市价单返回空字符串
+func timeInForce(req types.OrderRequest) types.TimeInForce {
+	if req.Type != types.LimitOrder {
+		return ""
+	}
+	if req.TimeInForce == "" {
+		return types.GTC
+	}
+	return req.TimeInForce
+}
+
+// orderFromRequest 用请求字段填充只返回订单号的下单结果
+func orderFromRequest(req types.OrderRequest, id string) types.Order {
+	order := types.Order{
+		ID:            id,
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		TimeInForce:   timeInForce(req),
+		Quantity:      req.Quantity,
+		QuoteQuantity: req.QuoteQuantity,
+		ReduceOnly:    req.ReduceOnly,
+	}
+	if req.Type == types.LimitOrder {
+		order.Price = req.Price
+	}
+	return order
+}
+
+// lowerSide 返回小写的方向，多数交易所使用 buy/sell
+func lowerSide(side types.Side) string {
+	return strings.ToLower(string(side))
+}
+
+// clientOrderIDLength 是自动生成的自定义订单号长度，32 位十六进制符合 Binance、OKX、Bybit、KuCoin 的格式，
+// 也是 Kraken 接受的不带连字符的 UUID 格式
+const clientOrderIDLength = 32
+
+// newClientOrderID 生成 length 位十六进制的随机订单号，length 应为偶数
+func newClientOrderID(length int) string {
+	b := make([]byte, length/2)
+	if _, err := rand.Read(b); err != nil {
+		panic("cryptoexchange: generate client order id: " + err.Error())
+	}
+	return hex.EncodeToString(b)
+}
+
+// placeAttempts 是下单结果不确定时的最多下单次数，只用于拒绝重复自定义订单号的交易所
+const placeAttempts = 3
+
+// lookupTimeout 是调用方的 ctx 已结束时查询订单使用的超时
+const lookupTimeout = 10 * time.Second
+
+// lookupDelay 是下单结果不确定时查询订单前的等待时间，给交易所留出处理订单的时间
+var lookupDelay = 500 * time.Millisecond
+
+// trader 是支持统一下单接口并提供交易对元数据的交易所
+type trader interface {
+	types.Trading
+	instrumentSource
+}
+
+// placer 描述一个交易所的下单方式
+type placer struct {
+	t      trader
+	config types.ExchangeConfig
+	// idLength 是自动生成的自定义订单号长度
+	idLength int
+	// uniqueIDs 表示交易所拒绝与已有订单重复的自定义订单号（Binance、OKX、Bybit、Kraken），
+	// 下单结果不确定且查询确认订单不存在时可以用同一个订单号重试
+	uniqueIDs bool
+	// place 提交单个已校验的订单
+	place func(ctx context.Context, req types.OrderRequest) (types.Order, error)
+	// batch 是原生的批量下单，返回与 reqs 等长的结果；为 nil 时并发地逐个下单
+	batch     func(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error)
+	batchSize int
+}
+
+// placeOrder 准备并提交单个订单
+func (p placer) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	req, err := p.prepare(ctx, req)
+	if err != nil {
+		return types.Order{}, err
+	}
+	return p.submit(ctx, req)
+}
+
+// prepare 校验请求，按交易对元数据检查并取整，在缺少自定义订单号时按 idLength 生成一个
+func (p placer) prepare(ctx context.Context, req types.OrderRequest) (types.OrderRequest, error) {
+	if err := req.Validate(); err != nil {
+		return req, err
+	}
+	if !p.config.SkipOrderChecks {
+		var err error
+		if req, err = checkOrder(ctx, p.t, req, p.config.OrderRounding); err != nil {
+			return req, err
+		}
+	}
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = newClientOrderID(p.idLength)
+	}
+	return req, nil
+}
+
+// submit 提交已准备好的订单。超时、网络错误或 5xx 响应时无法确定订单是否已提交，先按自定义订单号查询，查到则返回该订单。
+// 交易所拒绝重复的订单号时，确认订单不存在后用同一个订单号重试，最多 placeAttempts 次；
+// Gate 的 text、KuCoin 的 clientOid 不做去重，这些交易所查询不到时返回包装了 ErrOrderStatusUnknown 的错误，不自动重新下单
+func (p placer) submit(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	var err error
+	for attempt := 0; attempt < placeAttempts; attempt++ {
+		var order types.Order
+		order, err = p.place(ctx, req)
+		switch {
+		case err == nil:
+			return order, nil
+		case !ambiguous(err) && attempt > 0:
+			// 重试被拒绝可能是因为前一次请求其实已经下单，交易所认为订单号重复
+			if order, lookupErr := lookupOrder(ctx, p.t, req); lookupErr == nil {
+				return order, nil
+			}
+			return types.Order{}, err
+		case !ambiguous(err):
+			return order, err
+		}
+
+		order, found, resolveErr := p.resolve(ctx, req, err)
+		if found || resolveErr != nil {
+			return order, resolveErr
+		}
+	}
+	return types.Order{}, fmt.Errorf("order %s: %w after %d attempts: %w", req.ClientOrderID, types.ErrOrderStatusUnknown, placeAttempts, err)
+}
+
+// resolve 在下单结果不确定时按自定义订单号查询订单，查到时 found 为 true。交易所拒绝重复的订单号、确认订单不存在
+// 且调用方的 ctx 仍有效时 found 为 false 且没有错误，表示可以重试；其余情况返回包装了 ErrOrderStatusUnknown 和原始错误的错误
+func (p placer) resolve(ctx context.Context, req types.OrderRequest, placeErr error) (order types.Order, found bool, err error) {
+	order, lookupErr := lookupOrder(ctx, p.t, req)
+	switch {
+	case lookupErr == nil:
+		return order, true, nil
+	case p.uniqueIDs && errors.Is(lookupErr, types.ErrOrderNotFound) && ctx.Err() == nil:
+		return types.Order{}, false, nil
+	}
+	return types.Order{}, false, fmt.Errorf("order %s: %w: %w; lookup failed: %w", req.ClientOrderID, types.ErrOrderStatusUnknown, placeErr, lookupErr)
+}
+
+// placeOrders 逐个准备请求，未通过校验的订单直接记为失败；其余普通订单按 batchSize 分批调用 batch，
+// 条件单以及 batch 为 nil 时的全部订单并发地逐个提交。结果顺序与 reqs 一致
+func (p placer) placeOrders(ctx context.Context, reqs []types.OrderRequest) []types.OrderResult {
+	results := make([]types.OrderResult, len(reqs))
+	var (
+		batched, single           []types.OrderRequest
+		batchedIndex, singleIndex []int
+	)
+	for i, req := range reqs {
+		req, err := p.prepare(ctx, req)
+		switch {
+		case err != nil:
+			results[i].Err = err
+		case p.batch == nil || req.Type.Conditional():
+			single, singleIndex = append(single, req), append(singleIndex, i)
+		default:
+			batched, batchedIndex = append(batched, req), append(batchedIndex, i)
+		}
+	}
+
+	for start := 0; start < len(batched); start += p.batchSize {
+		end := min(start+p.batchSize, len(batched))
+		for i, result := range p.placeChunk(ctx, batched[start:end]) {
+			results[batchedIndex[start+i]] = result
+		}
+	}
+	forEach(len(single), func(i int) {
+		order, err := p.submit(ctx, single[i])
+		results[singleIndex[i]] = types.OrderResult{Order: order, Err: err}
+	})
+	return results
+}
+
+// placeChunk 调用一次批量下单接口。整批请求的结果不确定时逐个按自定义订单号查询，确认未下单的订单按 submit 的规则单独重试，
+// 不能重试的记为 ErrOrderStatusUnknown；
+// 整批被拒绝时每个订单都记为该错误
+func (p placer) placeChunk(ctx context.Context, reqs []types.OrderRequest) []types.OrderResult {
+	if err := ctx.Err(); err != nil {
+		return failedOrders(len(reqs), err)
+	}
+
+	results, err := p.batch(ctx, reqs)
+	if err == nil {
+		return results
+	}
+	if !ambiguous(err) {
+		return failedOrders(len(reqs), err)
+	}
+
+	results = make([]types.OrderResult, len(reqs))
+	forEach(len(reqs), func(i int) {
+		order, found, resolveErr := p.resolve(ctx, reqs[i], err)
+		if !found && resolveErr == nil {
+			order, resolveErr = p.submit(ctx, reqs[i])
+		}
+		results[i] = types.OrderResult{Order: order, Err: resolveErr}
+	})
+	return results
+}
+
+// buildBatch 用 build 转换每个订单的参数，转换失败的订单直接记为失败，其余订单交给 send 一次提交；
+// send 的结果与传入的请求一一对应
+func buildBatch[P any](ctx context.Context, reqs []types.OrderRequest, build func(req types.OrderRequest) (P, error),
+	send func(ctx context.Context, items []P, reqs []types.OrderRequest) ([]types.OrderResult, error),
+) ([]types.OrderResult, error) {
+	results := make([]types.OrderResult, len(reqs))
+	var (
+		items []P
+		sent  []types.OrderRequest
+		index []int
+	)
+	for i, req := range reqs {
+		item, err := build(req)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		items = append(items, item)
+		sent = append(sent, req)
+		index = append(index, i)
+	}
+	if len(items) == 0 {
+		return results, nil
+	}
+
+	placed, err := send(ctx, items, sent)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range placed {
+		results[index[i]] = result
+	}
+	return results, nil
+}
+
+// failedOrders 返回 n 个都记为 err 的下单结果
+func failedOrders(n int, err error) []types.OrderResult {
+	results := make([]types.OrderResult, n)
+	for i := range results {
+		results[i].Err = err
+	}
+	return results
+}
+
+// errMissingResult 表示批量接口的响应中缺少某个订单的结果
+var errMissingResult = errors.New("order missing from batch response")
+
+// jsonList 把字符串序列化为 JSON 数组，用于以 JSON 字符串传递列表的参数
+func jsonList(values []string) string {
+	b, _ := json.Marshal(values)
+	return string(b)
+}
+
+// batchError 把批量接口中单个订单的错误码和消息转换为 APIError
+func batchError(code, message string) error {
+	return &types.APIError{StatusCode: 200, Code: code, Message: message}
+}
+
+// amendedOrder 用修改请求填充只返回订单号的修改结果
+func amendedOrder(req types.AmendRequest, id, clientOrderID string) types.Order {
+	return types.Order{ID: id, ClientOrderID: clientOrderID, Symbol: req.Symbol, Type: types.LimitOrder, Price: req.Price, Quantity: req.Quantity}
+}
+
+// amendable 确认订单是仍在挂单中的限价单
+func amendable(order types.Order) error {
+	if order.Type != types.LimitOrder {
+		return fmt.Errorf("%w: only limit orders can be amended", types.ErrInvalidOrder)
+	}
+	if order.Status.Final() {
+		return fmt.Errorf("%w: order %s is already %s", types.ErrInvalidOrder, order.ID, order.Status)
+	}
+	return nil
+}
+
+// replaceOrder 用撤单再下单模拟修改订单：撤销原订单后按撤单时的剩余数量和新价格下一个新订单。
+// 原订单已撤销但没有下出新订单时返回原订单和包装了 types.ErrAmendIncomplete 的错误
+func replaceOrder(ctx context.Context, t types.Trading, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	orig, err := t.GetOrder(ctx, req.Symbol, req.Ref)
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	if err := amendable(orig); err != nil {
+		return types.AmendResult{}, err
+	}
+
+	ref := types.OrderRef{ID: orig.ID}
+	if err := t.CancelOrder(ctx, req.Symbol, ref); err != nil {
+		return types.AmendResult{}, err
+	}
+	// 查询到撤销之间可能有新的成交，按撤销后的成交数量计算剩余数量
+	if canceled, err := t.GetOrder(ctx, req.Symbol, ref); err == nil {
+		orig = canceled
+	}
+
+	price, quantity := req.Price, req.Quantity
+	if price.IsZero() {
+		price = orig.Price
+	}
+	if quantity.IsZero() {
+		quantity = orig.Quantity
+	}
+
+	result := types.AmendResult{Order: orig, Emulated: true}
+	remaining := quantity.Sub(orig.FilledQuantity)
+	if !remaining.IsPositive() {
+		return result, fmt.Errorf("%w: order %s filled %s of %s before it was canceled", types.ErrAmendIncomplete, orig.ID, orig.FilledQuantity, quantity)
+	}
+
+	order, err := t.PlaceOrder(ctx, types.OrderRequest{
+		Symbol:        req.Symbol,
+		Side:          orig.Side,
+		Type:          types.LimitOrder,
+		TimeInForce:   orig.TimeInForce,
+		Price:         price,
+		Quantity:      remaining,
+		PostOnly:      req.PostOnly,
+		ReduceOnly:    orig.ReduceOnly,
+		ClientOrderID: req.NewClientOrderID,
+	})
+	if err != nil {
+		return result, fmt.Errorf("%w: order %s: %w", types.ErrAmendIncomplete, orig.ID, err)
+	}
+	result.Order, result.Replaced = order, true
+	return result, nil
+}
+
+// checkOrder 按缓存的交易对元数据取整并校验订单；元数据中没有该交易对（如 Kraken 的 altname）或
+// 当前产品线不提供元数据时原样返回，由交易所校验
+func checkOrder(ctx context.Context, e instrumentSource, req types.OrderRequest, mode types.RoundingMode) (types.OrderRequest, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if errors.Is(err, types.ErrNotSupported) {
+		return req, nil
+	}
+	if err != nil {
+		return req, err
+	}
+
+	for _, inst := range instruments {
+		if inst.Symbol == req.Symbol {
+			return inst.PrepareOrder(req, mode)
+		}
+	}
+	return req, nil
+}
+
+// lookupOrder 等待 lookupDelay 后按自定义订单号查询订单，调用方的 ctx 已结束时改用独立的短超时
+func lookupOrder(ctx context.Context, t types.Trading, req types.OrderRequest) (types.Order, error) {
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), lookupTimeout)
+		defer cancel()
+	}
+
+	select {
+	case <-time.After(lookupDelay):
+	case <-ctx.Done():
+		return types.Order{}, ctx.Err()
+	}
+	return t.GetOrder(ctx, req.Symbol, types.OrderRef{ClientOrderID: req.ClientOrderID})
+}
+
+// ambiguous 判断下单错误是否意味着结果不确定：请求可能已到达交易所，但没有收到明确的响应
+func ambiguous(err error) bool {
+	var apiErr *types.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isInteger 判断数量是否为整数，用于只接受整数张数的合约
+func isInteger(d decimal.Decimal) bool {
+	return d.Equal(d.Truncate(0))
+}
+
+// cancelEach 并发撤销 orders 中的每个订单，单个订单失败不影响其余订单，结果顺序与 orders 一致
+func cancelEach(ctx context.Context, orders []types.Order, cancel func(ctx context.Context, order types.Order) error) []types.CancelResult {
+	results := make([]types.CancelResult, len(orders))
+	forEach(len(orders), func(i int) {
+		results[i] = cancelResult(orders[i], cancel(ctx, orders[i]))
+	})
+	return results
+}
+
+// cancelResult 用订单字段填充单个订单的撤单结果
+func cancelResult(order types.Order, err error) types.CancelResult {
+	return types.CancelResult{Symbol: order.Symbol, OrderID: order.ID, ClientOrderID: order.ClientOrderID, Err: err}
+}
+
+// cancelBySymbol 对订单涉及的每个交易对调用原生的撤销全部挂单接口，
+// 某个交易对的请求失败时，该交易对的订单都记为失败
+func cancelBySymbol(ctx context.Context, orders []types.Order, cancel func(ctx context.Context, symbol string) ([]types.CancelResult, error)) []types.CancelResult {
+	var (
+		results []types.CancelResult
+		seen    = make(map[string]bool)
+	)
+	for _, order := range orders {
+		if seen[order.Symbol] {
+			continue
+		}
+		seen[order.Symbol] = true
+
+		canceled, err := cancel(ctx, order.Symbol)
+		if err != nil {
+			for _, o := range orders {
+				if o.Symbol == order.Symbol {
+					results = append(results, cancelResult(o, err))
+				}
+			}
+			continue
+		}
+		results = append(results, canceled...)
+	}
+	return results
+}
+
+// cancelOrders 按 batchSize 分批调用 batch 撤销 refs 指定的订单，batch 为 nil 时并发地逐个调用 cancel；
+// 未通过校验的 ref 直接记为失败，某一批请求失败时该批订单都记为该错误。结果顺序与 refs 一致
+func cancelOrders(ctx context.Context, symbol string, refs []types.OrderRef, batchSize int,
+	batch func(ctx context.Context, refs []types.OrderRef) ([]types.CancelResult, error),
+	cancel func(ctx context.Context, symbol string, ref types.OrderRef) error,
+) []types.CancelResult {
+	results := make([]types.CancelResult, len(refs))
+	var (
+		valid []types.OrderRef
+		index []int
+	)
+	for i, ref := range refs {
+		if err := ref.Validate(); err != nil {
+			results[i] = refResult(symbol, ref, err)
+			continue
+		}
+		valid = append(valid, ref)
+		index = append(index, i)
+	}
+
+	if batch == nil {
+		forEach(len(valid), func(i int) {
+			results[index[i]] = refResult(symbol, valid[i], cancel(ctx, symbol, valid[i]))
+		})
+		return results
+	}
+
+	for start := 0; start < len(valid); start += batchSize {
+		end := min(start+batchSize, len(valid))
+		chunk, err := batch(ctx, valid[start:end])
+		for i, ref := range valid[start:end] {
+			if err != nil {
+				results[index[start+i]] = refResult(symbol, ref, err)
+			} else {
+				results[index[start+i]] = chunk[i]
+			}
+		}
+	}
+	return results
+}
+
+// refResult 用 ref 填充单个订单的撤单结果
+func refResult(symbol string, ref types.OrderRef, err error) types.CancelResult {
+	return types.CancelResult{Symbol: symbol, OrderID: ref.ID, ClientOrderID: ref.ClientOrderID, Err: err}
+}
+
+// triggerType 由触发方向推断条件单类型：止损买单与止盈卖单在价格上涨时触发，priced 表示触发后挂限价单
+func triggerType(side types.Side, rising, priced bool) types.OrderType {
+	stop := (side == types.Buy) == rising
+	switch {
+	case stop && priced:
+		return types.StopLimitOrder
+	case stop:
+		return types.StopMarketOrder
+	case priced:
+		return types.TakeProfitLimitOrder
+	default:
+		return types.TakeProfitMarketOrder
+	}
+}
+
+// orderStatus 按 statuses 映射交易所的订单状态，无法识别时返回 OrderStatusUnknown
+func orderStatus(statuses map[string]types.OrderStatus, raw string) types.OrderStatus {
+	if status, ok := statuses[raw]; ok {
+		return status
+	}
+	return types.OrderStatusUnknown
+}
+
+// openStatus 根据成交数量区分未成交与部分成交的挂单
+func openStatus(filled decimal.Decimal) types.OrderStatus {
+	if filled.IsPositive() {
+		return types.OrderPartiallyFilled
+	}
+	return types.OrderNew
+}
+
+// averagePrice 用成交金额除以成交数量计算均价，没有成交时为零
+func averagePrice(quote, filled decimal.Decimal) decimal.Decimal {
+	if !filled.IsPositive() {
+		return decimal.Zero
+	}
+	return quote.Div(filled)
+}
+
+// orderNotFound 在交易所返回的错误码、消息或响应体包含 markers 之一时，把错误包装为 types.ErrOrderNotFound
+func orderNotFound(err error, markers ...string) error {
+	var apiErr *types.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	for _, marker := range markers {
+		if apiErr.Code == marker || strings.Contains(apiErr.Message, marker) || strings.Contains(apiErr.Body, marker) {
+			return fmt.Errorf("%w: %w", types.ErrOrderNotFound, err)
+		}
+	}
+	return err
+}
+
+// orderHistory 只保留已结束且在 [Start, End) 内创建的订单，按创建时间升序排列，超过 Limit 时保留最新的
+func orderHistory(orders []types.Order, req types.OrderHistoryRequest) []types.Order {
+	result := make([]types.Order, 0, len(orders))
+	for _, order := range orders {
+		if !order.Status.Final() {
+			continue
+		}
+		if !req.Start.IsZero() && order.CreatedAt.Before(req.Start) {
+			continue
+		}
+		if !req.End.IsZero() && !order.CreatedAt.Before(req.End) {
+			continue
+		}
+		result = append(result, order)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	if req.Limit > 0 && len(result) > req.Limit {
+		return result[len(result)-req.Limit:]
+	}
+	return result
+}
+
+// fillHistory 去掉 since 之前的成交并按时间升序排列，翻页边界上重复返回的成交只保留一笔
+func fillHistory(fills []types.Fill, since time.Time) []types.Fill {
+	seen := make(map[string]bool, len(fills))
+	result := make([]types.Fill, 0, len(fills))
+	for _, fill := range fills {
+		key := fill.Symbol + "/" + fill.TradeID + "/" + fill.OrderID
+		if seen[key] || (!since.IsZero() && fill.Time.Before(since)) {
+			continue
+		}
+		seen[key] = true
+		result = append(result, fill)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.Before(result[j].Time) })
+	return result
+}
+
+// liquidity 把是否为 maker 转换为 types.Liquidity
+func liquidity(maker bool) types.Liquidity {
+	if maker {
+		return types.Maker
+	}
+	return types.Taker
+}
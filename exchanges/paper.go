@@ -0,0 +1,615 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// PaperConfig 配置模拟交易
+type PaperConfig struct {
+	// Balances 是各资产的初始可用余额，以资产代码为键，与交易对元数据中的 Base、Quote 一致
+	Balances map[string]decimal.Decimal
+	// MakerFee、TakerFee 是手续费率，小数形式；手续费一律以计价币收取
+	MakerFee decimal.Decimal
+	TakerFee decimal.Decimal
+	// Latency 是下单、撤单生效前的等待时间，用来模拟网络往返与撮合延迟，期间行情可能已经变化
+	Latency time.Duration
+	// FillRatio 是每次撮合时最多能成交的数量占订单簿对应档位数量的比例，模拟与其他订单的竞争和部分成交；
+	// 零值表示 1，即可以吃掉整档
+	FillRatio decimal.Decimal
+	// PollInterval 是 Run 检查挂单能否成交的间隔，零值为 1 秒
+	PollInterval time.Duration
+	// OrderRounding 决定下单前如何把价格、数量对齐到交易对的最小变动单位，与 ExchangeConfig.OrderRounding 相同
+	OrderRounding types.RoundingMode
+}
+
+// paperBookDepth 是撮合时读取的订单簿档位数
+const paperBookDepth = 50
+
+// PaperExchange 是模拟交易的交易所：行情、历史成交、永续合约和交易对转换接口透传给真实交易所，下单、撤单、订单与成交查询、余额都在本地处理，
+// 不会发出签名请求。下单时按实时订单簿吃单，限价单的剩余部分成为挂单，由 Run 在对手价穿过挂单价格时按挂单价成交。
+// 余额按现货计算：买单冻结计价币，卖单冻结基础币
+type PaperExchange struct {
+	types.MarketData
+	exchange types.Exchange
+	config   PaperConfig
+
+	mu       sync.Mutex
+	balances map[string]*types.Balance
+	orders   []*paperOrder
+	fills    []types.Fill
+}
+
+// paperOrder 是模拟的订单。locked 是订单仍冻结的余额，买单以计价币计、卖单以基础币计；
+// budget 是按计价币金额下的市价买单还能花费的金额（不含手续费）
+type paperOrder struct {
+	types.Order
+	base, quote string
+	step        decimal.Decimal
+	locked      decimal.Decimal
+	budget      decimal.Decimal
+}
+
+// remaining 返回未成交的数量。按计价币金额下的市价单 Quantity 为零，match 改用 budget 限制成交数量
+func (o *paperOrder) remaining() decimal.Decimal {
+	return o.Quantity.Sub(o.FilledQuantity)
+}
+
+// NewPaperExchange 用真实交易所的行情创建模拟交易所，exchange 必须实现 types.MarketData
+func NewPaperExchange(exchange types.Exchange, config PaperConfig) (*PaperExchange, error) {
+	market, ok := exchange.(types.MarketData)
+	if !ok {
+		return nil, notSupported(exchange, "paper trading without market data")
+	}
+
+	e := &PaperExchange{MarketData: market, exchange: exchange, config: config, balances: make(map[string]*types.Balance)}
+	for asset, amount := range config.Balances {
+		e.balance(asset).Free = amount
+	}
+	return e, nil
+}
+
+func (e *PaperExchange) Name() types.ExchangeName {
+	return e.exchange.Name()
+}
+
+func (e *PaperExchange) GetDefaultBaseURL() string {
+	return e.exchange.GetDefaultBaseURL()
+}
+
+// PrepareRequest 只放行公开请求，签名请求会动用真实账户，直接拒绝
+func (e *PaperExchange) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	if signed {
+		return nil, notSupported(e, "signed requests in paper trading")
+	}
+	return e.exchange.PrepareRequest(method, endpoint, params, signed)
+}
+
+// balance 返回资产的余额记录，不存在时创建；调用方需持有 mu
+func (e *PaperExchange) balance(asset string) *types.Balance {
+	b, ok := e.balances[asset]
+	if !ok {
+		b = &types.Balance{Asset: asset}
+		e.balances[asset] = b
+	}
+	return b
+}
+
+// fillRatio 返回每档可成交的比例
+func (e *PaperExchange) fillRatio() decimal.Decimal {
+	if e.config.FillRatio.IsPositive() {
+		return e.config.FillRatio
+	}
+	return decimal.NewFromInt(1)
+}
+
+// wait 等待 Latency，ctx 先结束时返回它的错误
+func (e *PaperExchange) wait(ctx context.Context) error {
+	if e.config.Latency <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-time.After(e.config.Latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// instrument 返回交易对元数据，模拟交易需要它的 Base、Quote 记账
+func (e *PaperExchange) instrument(ctx context.Context, symbol string) (types.Instrument, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if err != nil {
+		return types.Instrument{}, err
+	}
+	for _, inst := range instruments {
+		if inst.Symbol == symbol {
+			return inst, nil
+		}
+	}
+	return types.Instrument{}, symbolNotFound(e, symbol)
+}
+
+// PlaceOrder 按下单时的实时订单簿吃单。限价 GTC 单的剩余部分成为挂单，其余订单未成交的部分以 EXPIRED 结束，
+// 不能全部成交的 FOK 订单不成交；
+// 会立即成交的只挂单（post-only）订单以 REJECTED 结束。余额不足时返回包装了 ErrInsufficientBalance 的错误
+func (e *PaperExchange) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if err := req.Validate(); err != nil {
+		return types.Order{}, err
+	}
+	if req.Type.Conditional() {
+		return types.Order{}, notSupported(e, "conditional orders in paper trading")
+	}
+	if req.ReduceOnly {
+		return types.Order{}, notSupported(e, "reduce-only orders in paper trading")
+	}
+	if req.Side == types.Sell && req.QuoteQuantity.IsPositive() {
+		return types.Order{}, notSupported(e, "sell orders by quote quantity in paper trading")
+	}
+
+	inst, err := e.instrument(ctx, req.Symbol)
+	if err != nil {
+		return types.Order{}, err
+	}
+	if req, err = inst.PrepareOrder(req, e.config.OrderRounding); err != nil {
+		return types.Order{}, err
+	}
+	if req.ClientOrderID == "" {
+		req.ClientOrderID = newClientOrderID(clientOrderIDLength)
+	}
+
+	if err := e.wait(ctx); err != nil {
+		return types.Order{}, err
+	}
+	book, err := e.GetOrderBook(ctx, req.Symbol, paperBookDepth)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, o := range e.orders {
+		if o.ClientOrderID == req.ClientOrderID {
+			return types.Order{}, fmt.Errorf("%w: duplicate client order ID %s", types.ErrInvalidOrder, req.ClientOrderID)
+		}
+	}
+
+	now := time.Now()
+	o := &paperOrder{Order: orderFromRequest(req, strconv.Itoa(len(e.orders)+1)), base: inst.Base, quote: inst.Quote, step: inst.StepSize}
+	o.Status, o.FeeAsset, o.CreatedAt, o.UpdatedAt = types.OrderNew, inst.Quote, now, now
+
+	depth := book.Asks
+	if req.Side == types.Sell {
+		depth = book.Bids
+	}
+
+	if req.PostOnly && len(depth) > 0 && crosses(o.Side, o.Price, depth[0].Price) {
+		o.Status = types.OrderRejected
+		e.orders = append(e.orders, o)
+		return o.Order, nil
+	}
+	if err := e.reserve(o, depth); err != nil {
+		return types.Order{}, err
+	}
+	e.orders = append(e.orders, o)
+
+	// FOK 订单不能全部成交时不成交任何数量
+	if _, complete := e.match(o, append([]types.PriceLevel(nil), depth...)); !complete && timeInForce(req) == types.FOK {
+		e.finish(o, types.OrderExpired, now)
+		return o.Order, nil
+	}
+	complete := e.take(o, depth, types.Taker, now)
+	switch {
+	case complete:
+		e.finish(o, types.OrderFilled, now)
+	case o.Type != types.LimitOrder || timeInForce(req) != types.GTC:
+		e.finish(o, types.OrderExpired, now)
+	}
+	return o.Order, nil
+}
+
+// crosses 判断 side 方向、价格为 price 的限价单能否与对手价 level 成交
+func crosses(side types.Side, price, level decimal.Decimal) bool {
+	if side == types.Buy {
+		return level.LessThanOrEqual(price)
+	}
+	return level.GreaterThanOrEqual(price)
+}
+
+// reserve 冻结订单所需的余额：卖单冻结数量，限价买单按价格和较高的费率冻结，
+// 按金额的市价买单冻结该金额，按数量的市价买单按吃单的预估成本冻结
+func (e *PaperExchange) reserve(o *paperOrder, depth []types.PriceLevel) error {
+	asset, amount := o.base, o.Quantity
+	if o.Side == types.Buy {
+		taker := decimal.NewFromInt(1).Add(e.config.TakerFee)
+		asset = o.quote
+		switch {
+		case o.Type == types.LimitOrder:
+			amount = o.Price.Mul(o.Quantity).Mul(decimal.NewFromInt(1).Add(decimal.Max(e.config.MakerFee, e.config.TakerFee)))
+		case o.QuoteQuantity.IsPositive():
+			amount, o.budget = o.QuoteQuantity, o.QuoteQuantity.Div(taker)
+		default:
+			fills, _ := e.match(o, append([]types.PriceLevel(nil), depth...))
+			amount = decimal.Zero
+			for _, f := range fills {
+				amount = amount.Add(f.Price.Mul(f.Size))
+			}
+			amount = amount.Mul(taker)
+		}
+	}
+
+	b := e.balance(asset)
+	if b.Free.LessThan(amount) {
+		return fmt.Errorf("paper %s: %s available, %s required: %w", asset, b.Free, amount, types.ErrInsufficientBalance)
+	}
+	b.Free, b.Locked, o.locked = b.Free.Sub(amount), b.Locked.Add(amount), amount
+	return nil
+}
+
+// match 计算订单与对手盘 levels 的成交，并从 levels 中扣除已成交的数量，每档最多成交其数量的 FillRatio。
+// 返回的 complete 表示订单已全部成交；按金额的市价买单在金额不足以吃满某一档时视为全部成交
+func (e *PaperExchange) match(o *paperOrder, levels []types.PriceLevel) (fills []types.PriceLevel, complete bool) {
+	byQuote := o.QuoteQuantity.IsPositive()
+	remaining, budget := o.remaining(), o.budget
+	for i := range levels {
+		level := &levels[i]
+		if o.Type == types.LimitOrder && !crosses(o.Side, o.Price, level.Price) {
+			break
+		}
+
+		available := level.Size.Mul(e.fillRatio())
+		size := decimal.Min(available, remaining)
+		if byQuote {
+			size = decimal.Min(available, budget.Div(level.Price))
+			if o.step.IsPositive() {
+				size = size.Div(o.step).Floor().Mul(o.step)
+			}
+			complete = size.LessThan(available)
+		} else {
+			complete = size.Equal(remaining)
+		}
+
+		if size.IsPositive() {
+			fills = append(fills, types.PriceLevel{Price: level.Price, Size: size})
+			level.Size = level.Size.Sub(size)
+			remaining, budget = remaining.Sub(size), budget.Sub(size.Mul(level.Price))
+		}
+		if complete || !size.IsPositive() {
+			break
+		}
+	}
+	return fills, complete
+}
+
+// take 让订单与对手盘 levels 成交，taker 按对手盘价格成交，maker 按订单价格成交；返回订单是否已全部成交
+func (e *PaperExchange) take(o *paperOrder, levels []types.PriceLevel, liquidity types.Liquidity, now time.Time) bool {
+	fills, complete := e.match(o, levels)
+	for _, f := range fills {
+		price := f.Price
+		if liquidity == types.Maker {
+			price = o.Price
+		}
+		e.fill(o, price, f.Size, liquidity, now)
+	}
+	return complete
+}
+
+// fill 记录一笔成交并结算余额，手续费以计价币收取
+func (e *PaperExchange) fill(o *paperOrder, price, quantity decimal.Decimal, liquidity types.Liquidity, now time.Time) {
+	rate := e.config.TakerFee
+	if liquidity == types.Maker {
+		rate = e.config.MakerFee
+	}
+	notional := price.Mul(quantity)
+	fee := notional.Mul(rate)
+
+	base, quote := e.balance(o.base), e.balance(o.quote)
+	if o.Side == types.Buy {
+		spent := notional.Add(fee)
+		o.locked, quote.Locked = o.locked.Sub(spent), quote.Locked.Sub(spent)
+		base.Free = base.Free.Add(quantity)
+		if o.budget.IsPositive() {
+			o.budget = o.budget.Sub(notional)
+		}
+	} else {
+		o.locked, base.Locked = o.locked.Sub(quantity), base.Locked.Sub(quantity)
+		quote.Free = quote.Free.Add(notional.Sub(fee))
+	}
+
+	filled := o.FilledQuantity.Add(quantity)
+	o.AveragePrice = o.AveragePrice.Mul(o.FilledQuantity).Add(notional).Div(filled)
+	o.FilledQuantity, o.Fee, o.Status, o.UpdatedAt = filled, o.Fee.Add(fee), types.OrderPartiallyFilled, now
+
+	e.fills = append(e.fills, types.Fill{
+		Symbol:        o.Symbol,
+		OrderID:       o.ID,
+		ClientOrderID: o.ClientOrderID,
+		TradeID:       strconv.Itoa(len(e.fills) + 1),
+		Side:          o.Side,
+		Price:         price,
+		Quantity:      quantity,
+		Fee:           fee,
+		FeeAsset:      o.quote,
+		Liquidity:     liquidity,
+		Time:          now,
+	})
+}
+
+// finish 以 status 结束订单并解冻剩余的余额
+func (e *PaperExchange) finish(o *paperOrder, status types.OrderStatus, now time.Time) {
+	asset := o.base
+	if o.Side == types.Buy {
+		asset = o.quote
+	}
+	b := e.balance(asset)
+	b.Free, b.Locked = b.Free.Add(o.locked), b.Locked.Sub(o.locked)
+	o.locked, o.Status, o.UpdatedAt = decimal.Zero, status, now
+}
+
+// PlaceOrders 并发地逐个下单
+func (e *PaperExchange) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	results := make([]types.OrderResult, len(reqs))
+	forEach(len(reqs), func(i int) {
+		results[i].Order, results[i].Err = e.PlaceOrder(ctx, reqs[i])
+	})
+	return results, nil
+}
+
+// AmendOrder 用撤单再下单模拟修改订单
+func (e *PaperExchange) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if err := req.Validate(); err != nil {
+		return types.AmendResult{}, err
+	}
+	return replaceOrder(ctx, e, req)
+}
+
+// find 按 ref 查找订单；调用方需持有 mu
+func (e *PaperExchange) find(symbol string, ref types.OrderRef) (*paperOrder, error) {
+	for _, o := range e.orders {
+		if o.Symbol == symbol && ((ref.ID != "" && o.ID == ref.ID) || (ref.ID == "" && o.ClientOrderID == ref.ClientOrderID)) {
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("paper order %s: %w", symbol, types.ErrOrderNotFound)
+}
+
+// CancelOrder 撤销挂单，已结束的订单按不存在处理
+func (e *PaperExchange) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+	if err := e.wait(ctx); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, err := e.find(symbol, ref)
+	if err != nil {
+		return err
+	}
+	if o.Status.Final() {
+		return fmt.Errorf("paper order %s is %s: %w", o.ID, o.Status, types.ErrOrderNotFound)
+	}
+	e.finish(o, types.OrderCanceled, time.Now())
+	return nil
+}
+
+func (e *PaperExchange) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, 0, nil, e.CancelOrder), nil
+}
+
+func (e *PaperExchange) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	open, err := e.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelEach(ctx, open, func(ctx context.Context, order types.Order) error {
+		return e.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+	}), nil
+}
+
+func (e *PaperExchange) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	o, err := e.find(symbol, ref)
+	if err != nil {
+		return types.Order{}, err
+	}
+	return o.Order, nil
+}
+
+func (e *PaperExchange) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var orders []types.Order
+	for _, o := range e.orders {
+		if !o.Status.Final() && (symbol == "" || o.Symbol == symbol) {
+			orders = append(orders, o.Order)
+		}
+	}
+	return orders, nil
+}
+
+func (e *PaperExchange) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var orders []types.Order
+	for _, o := range e.orders {
+		if req.Symbol == "" || o.Symbol == req.Symbol {
+			orders = append(orders, o.Order)
+		}
+	}
+	return orderHistory(orders, req), nil
+}
+
+func (e *PaperExchange) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var fills []types.Fill
+	for _, f := range e.fills {
+		if symbol == "" || f.Symbol == symbol {
+			fills = append(fills, f)
+		}
+	}
+	return fillHistory(fills, since), nil
+}
+
+// GetBalances 返回模拟账户中余额不为零的资产
+func (e *PaperExchange) GetBalances(ctx context.Context) (map[string]types.Balance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	balances := make(map[string]types.Balance, len(e.balances))
+	for asset, b := range e.balances {
+		if !b.Total().IsZero() {
+			balances[asset] = *b
+		}
+	}
+	return balances, nil
+}
+
+// GetTradingFees 返回配置的费率
+func (e *PaperExchange) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	return uniformFees(ctx, e, symbols, e.config.MakerFee, e.config.TakerFee)
+}
+
+// GetHistoricalTrades 透传给真实交易所，未实现 types.HistoricalTrades 时返回包装了 ErrNotSupported 的错误
+func (e *PaperExchange) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	trades, ok := e.exchange.(types.HistoricalTrades)
+	if !ok {
+		return types.TradePage{}, notSupported(e, "historical trades")
+	}
+	return trades.GetHistoricalTrades(ctx, req)
+}
+
+// perpetuals 返回真实交易所的永续合约接口，未实现时返回包装了 ErrNotSupported 的错误
+func (e *PaperExchange) perpetuals() (types.Perpetuals, error) {
+	perpetuals, ok := e.exchange.(types.Perpetuals)
+	if !ok {
+		return nil, notSupported(e, "perpetuals")
+	}
+	return perpetuals, nil
+}
+
+// GetFundingRate 透传给真实交易所
+func (e *PaperExchange) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	perpetuals, err := e.perpetuals()
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+	return perpetuals.GetFundingRate(ctx, symbol)
+}
+
+// GetFundingHistory 透传给真实交易所
+func (e *PaperExchange) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	perpetuals, err := e.perpetuals()
+	if err != nil {
+		return nil, err
+	}
+	return perpetuals.GetFundingHistory(ctx, symbol, start, end, limit)
+}
+
+// GetMarkPrice 透传给真实交易所
+func (e *PaperExchange) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	perpetuals, err := e.perpetuals()
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return perpetuals.GetMarkPrice(ctx, symbol)
+}
+
+// GetIndexPrice 透传给真实交易所
+func (e *PaperExchange) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	perpetuals, err := e.perpetuals()
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return perpetuals.GetIndexPrice(ctx, symbol)
+}
+
+// NativeSymbol 透传给真实交易所，未实现 types.SymbolMapper 时返回包装了 ErrNotSupported 的错误
+func (e *PaperExchange) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	mapper, ok := e.exchange.(types.SymbolMapper)
+	if !ok {
+		return "", notSupported(e, "symbol mapping")
+	}
+	return mapper.NativeSymbol(ctx, symbol)
+}
+
+// CanonicalSymbol 透传给真实交易所，未实现 types.SymbolMapper 时返回包装了 ErrNotSupported 的错误
+func (e *PaperExchange) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	mapper, ok := e.exchange.(types.SymbolMapper)
+	if !ok {
+		return types.Symbol{}, notSupported(e, "symbol mapping")
+	}
+	return mapper.CanonicalSymbol(ctx, native)
+}
+
+// Run 每隔 PollInterval 撮合一次挂单，直到 ctx 结束
+func (e *PaperExchange) Run(ctx context.Context) error {
+	interval := e.config.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			e.matchResting(ctx)
+		}
+	}
+}
+
+// matchResting 按交易对读取一次订单簿，对手价穿过挂单价格时按挂单价以 maker 成交，先下的挂单先成交；
+// 订单簿读取失败的交易对留到下一轮
+func (e *PaperExchange) matchResting(ctx context.Context) {
+	e.mu.Lock()
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, o := range e.orders {
+		if !o.Status.Final() && !seen[o.Symbol] {
+			seen[o.Symbol] = true
+			symbols = append(symbols, o.Symbol)
+		}
+	}
+	e.mu.Unlock()
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		book, err := e.GetOrderBook(ctx, symbol, paperBookDepth)
+		if err != nil {
+			continue
+		}
+
+		e.mu.Lock()
+		now := time.Now()
+		for _, o := range e.orders {
+			if o.Symbol != symbol || o.Status.Final() {
+				continue
+			}
+			levels := book.Asks
+			if o.Side == types.Sell {
+				levels = book.Bids
+			}
+			if e.take(o, levels, types.Maker, now) {
+				e.finish(o, types.OrderFilled, now)
+			}
+		}
+		e.mu.Unlock()
+	}
+}
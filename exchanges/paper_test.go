@@ -0,0 +1,175 @@
+package exchanges
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// paperMarket 返回固定的交易对元数据和可修改的订单簿，用于测试模拟交易
+type paperMarket struct {
+	types.MarketData
+	mu   sync.Mutex
+	book types.OrderBook
+}
+
+func (m *paperMarket) Name() types.ExchangeName  { return types.Binance }
+func (m *paperMarket) GetDefaultBaseURL() string { return "http://127.0.0.1:0" }
+
+func (m *paperMarket) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	return http.NewRequest(method, m.GetDefaultBaseURL()+endpoint, nil)
+}
+
+func (m *paperMarket) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return []types.Instrument{{Symbol: "BTCUSDT", Base: "BTC", Quote: "USDT", TickSize: dec("0.5"), StepSize: dec("0.01"), Status: types.InstrumentTrading}}, nil
+}
+
+func (m *paperMarket) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book := m.book
+	book.Bids = append([]types.PriceLevel(nil), m.book.Bids...)
+	book.Asks = append([]types.PriceLevel(nil), m.book.Asks...)
+	return book, nil
+}
+
+func levels(pairs ...string) []types.PriceLevel {
+	var result []types.PriceLevel
+	for i := 0; i < len(pairs); i += 2 {
+		result = append(result, types.PriceLevel{Price: dec(pairs[i]), Size: dec(pairs[i+1])})
+	}
+	return result
+}
+
+func newPaper(t *testing.T, market *paperMarket, config PaperConfig) *PaperExchange {
+	paper, err := NewPaperExchange(market, config)
+	assert.NoError(t, err)
+	return paper
+}
+
+func TestPaperExchange(t *testing.T) {
+	usdt := map[string]decimal.Decimal{"USDT": dec("1000")}
+
+	t.Run("market order walks the book", func(t *testing.T) {
+		market := &paperMarket{book: types.OrderBook{Asks: levels("100", "1", "101", "2")}}
+		paper := newPaper(t, market, PaperConfig{Balances: usdt, TakerFee: dec("0.001"), FillRatio: dec("0.5")})
+
+		order, err := paper.PlaceOrder(context.Background(), types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder, Quantity: dec("1.5")})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderFilled, order.Status)
+		// 每档只能吃掉一半：100 上 0.5，101 上 1
+		assert.Equal(t, "1.5", order.FilledQuantity.String())
+		assert.Equal(t, "0.151", order.Fee.String())
+
+		fills, err := paper.GetFills(context.Background(), "BTCUSDT", order.CreatedAt)
+		assert.NoError(t, err)
+		assert.Len(t, fills, 2)
+		assert.Equal(t, types.Taker, fills[0].Liquidity)
+		assert.Equal(t, "USDT", fills[0].FeeAsset)
+
+		balances, err := paper.GetBalances(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "1.5", balances["BTC"].Free.String())
+		assert.Equal(t, "848.849", balances["USDT"].Free.String())
+		assert.True(t, balances["USDT"].Locked.IsZero())
+	})
+
+	t.Run("market buy by quote amount", func(t *testing.T) {
+		market := &paperMarket{book: types.OrderBook{Asks: levels("50", "1", "60", "5")}}
+		paper := newPaper(t, market, PaperConfig{Balances: usdt})
+
+		order, err := paper.PlaceOrder(context.Background(), types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("100")})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderFilled, order.Status)
+		assert.Equal(t, "1.83", order.FilledQuantity.String())
+
+		balances, _ := paper.GetBalances(context.Background())
+		assert.Equal(t, "900.2", balances["USDT"].Free.String())
+	})
+
+	t.Run("resting limit order fills as maker", func(t *testing.T) {
+		market := &paperMarket{book: types.OrderBook{Bids: levels("98", "1"), Asks: levels("100", "1")}}
+		paper := newPaper(t, market, PaperConfig{Balances: usdt, MakerFee: dec("0.001")})
+
+		order, err := paper.PlaceOrder(context.Background(), types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("99")})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderNew, order.Status)
+		balances, _ := paper.GetBalances(context.Background())
+		assert.Equal(t, "99.099", balances["USDT"].Locked.String())
+
+		market.book.Asks = levels("98.5", "0.4")
+		paper.matchResting(context.Background())
+		order, err = paper.GetOrder(context.Background(), "BTCUSDT", types.OrderRef{ID: order.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderPartiallyFilled, order.Status)
+		assert.Equal(t, "0.4", order.FilledQuantity.String())
+		assert.Equal(t, "99", order.AveragePrice.String())
+
+		assert.NoError(t, paper.CancelOrder(context.Background(), "BTCUSDT", types.OrderRef{ClientOrderID: order.ClientOrderID}))
+		balances, _ = paper.GetBalances(context.Background())
+		// 以挂单价 99 成交 0.4，手续费 0.0396，其余冻结资金退回
+		assert.Equal(t, "960.3604", balances["USDT"].Free.String())
+		assert.True(t, balances["USDT"].Locked.IsZero())
+
+		err = paper.CancelOrder(context.Background(), "BTCUSDT", types.OrderRef{ID: order.ID})
+		assert.ErrorIs(t, err, types.ErrOrderNotFound)
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		market := &paperMarket{book: types.OrderBook{Bids: levels("98", "1"), Asks: levels("100", "1")}}
+		paper := newPaper(t, market, PaperConfig{Balances: usdt})
+
+		order, err := paper.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"), PostOnly: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderRejected, order.Status)
+
+		order, err = paper.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("2"), Price: dec("100"), TimeInForce: types.FOK,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, types.OrderExpired, order.Status)
+		assert.True(t, order.FilledQuantity.IsZero())
+
+		_, err = paper.PlaceOrder(context.Background(), types.OrderRequest{Symbol: "BTCUSDT", Side: types.Sell, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("101")})
+		assert.ErrorIs(t, err, types.ErrInsufficientBalance)
+
+		_, err = paper.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTCUSDT", Side: types.Sell, Type: types.StopMarketOrder, Quantity: dec("1"), TriggerPrice: dec("90"),
+		})
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+
+		_, err = paper.PrepareRequest("GET", "/api/v3/account", nil, true)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("forwards optional market interfaces", func(t *testing.T) {
+		market := &historyMarket{paperMarket: &paperMarket{}}
+		paper, err := NewPaperExchange(market, PaperConfig{})
+		assert.NoError(t, err)
+
+		page, err := paper.GetHistoricalTrades(context.Background(), types.HistoricalTradesRequest{Symbol: "BTCUSDT"})
+		assert.NoError(t, err)
+		assert.Equal(t, "next", page.Cursor)
+
+		_, err = paper.GetFundingRate(context.Background(), "BTCUSDT")
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+		_, err = paper.NativeSymbol(context.Background(), types.Symbol{Base: "BTC", Quote: "USDT"})
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+}
+
+// historyMarket 在 paperMarket 之上实现 types.HistoricalTrades，用于测试模拟交易所的透传
+type historyMarket struct {
+	*paperMarket
+}
+
+func (m *historyMarket) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	return types.TradePage{Cursor: "next"}, nil
+}
@@ -0,0 +1,117 @@
+package exchanges
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// rateLimit 是令牌桶参数：每秒补充 rate 个令牌，最多累积 burst 个
+type rateLimit struct {
+	rate  float64
+	burst int
+}
+
+// defaultRateLimits 按各交易所公开文档中较严格的限制设置，留出余量给同一 IP 下的其他请求
+var defaultRateLimits = map[types.ExchangeName]rateLimit{
+	types.Binance:     {rate: 20, burst: 20},
+	types.BinanceUS:   {rate: 20, burst: 20},
+	types.OKX:         {rate: 10, burst: 10},
+	types.Bitget:      {rate: 10, burst: 10},
+	types.Kucoin:      {rate: 10, burst: 10},
+	types.MEXC:        {rate: 10, burst: 10},
+	types.Gate:        {rate: 10, burst: 10},
+	types.Kraken:      {rate: 1, burst: 3},
+	types.Bybit:       {rate: 10, burst: 10},
+	types.Huobi:       {rate: 10, burst: 10},
+	types.Coinbase:    {rate: 10, burst: 10},
+	types.BTSE:        {rate: 10, burst: 10},
+	types.Gemini:      {rate: 2, burst: 5},
+	types.Upbit:       {rate: 10, burst: 10},
+	types.CryptoCom:   {rate: 10, burst: 10},
+	types.BitMart:     {rate: 5, burst: 5},
+	types.Hyperliquid: {rate: 5, burst: 5},
+}
+
+// rateLimiter 是令牌桶限速器，由各交易所结构体内嵌为字段，第一次使用时按配置初始化
+type rateLimiter struct {
+	once   sync.Once
+	mu     sync.Mutex
+	limit  rateLimit
+	tokens float64
+	last   time.Time
+}
+
+// throttled 由带有限速器的交易所实现，sendRequest 在发出请求前等待令牌
+type throttled interface {
+	throttle() *rateLimiter
+}
+
+// init 根据配置的每秒请求数初始化限速器：零值使用交易所默认值，负数不限速
+func (l *rateLimiter) init(name types.ExchangeName, perSecond float64) *rateLimiter {
+	l.once.Do(func() {
+		switch {
+		case perSecond > 0:
+			l.limit = rateLimit{rate: perSecond, burst: int(math.Max(1, math.Ceil(perSecond)))}
+		case perSecond == 0:
+			l.limit = defaultRateLimits[name]
+		}
+		l.tokens = float64(l.limit.burst)
+		l.last = time.Now()
+	})
+	return l
+}
+
+// wait 取走一个令牌，令牌不足时等待补充或直到 ctx 结束
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.limit.rate <= 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(float64(l.limit.burst), l.tokens+now.Sub(l.last).Seconds()*l.limit.rate)
+	l.last = now
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.limit.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (b *Binance) throttle() *rateLimiter     { return b.limiter.init(b.Name(), b.config.RateLimit) }
+func (o *OKX) throttle() *rateLimiter         { return o.limiter.init(o.Name(), o.config.RateLimit) }
+func (b *Bitget) throttle() *rateLimiter      { return b.limiter.init(b.Name(), b.config.RateLimit) }
+func (k *Kucoin) throttle() *rateLimiter      { return k.limiter.init(k.Name(), k.config.RateLimit) }
+func (m *MEXC) throttle() *rateLimiter        { return m.limiter.init(m.Name(), m.config.RateLimit) }
+func (g *Gate) throttle() *rateLimiter        { return g.limiter.init(g.Name(), g.config.RateLimit) }
+func (k *Kraken) throttle() *rateLimiter      { return k.limiter.init(k.Name(), k.config.RateLimit) }
+func (b *Bybit) throttle() *rateLimiter       { return b.limiter.init(b.Name(), b.config.RateLimit) }
+func (h *Huobi) throttle() *rateLimiter       { return h.limiter.init(h.Name(), h.config.RateLimit) }
+func (c *Coinbase) throttle() *rateLimiter    { return c.limiter.init(c.Name(), c.config.RateLimit) }
+func (b *BTSE) throttle() *rateLimiter        { return b.limiter.init(b.Name(), b.config.RateLimit) }
+func (g *Gemini) throttle() *rateLimiter      { return g.limiter.init(g.Name(), g.config.RateLimit) }
+func (u *Upbit) throttle() *rateLimiter       { return u.limiter.init(u.Name(), u.config.RateLimit) }
+func (c *CryptoCom) throttle() *rateLimiter   { return c.limiter.init(c.Name(), c.config.RateLimit) }
+func (b *BitMart) throttle() *rateLimiter     { return b.limiter.init(b.Name(), b.config.RateLimit) }
+func (h *Hyperliquid) throttle() *rateLimiter { return h.limiter.init(h.Name(), h.config.RateLimit) }
@@ -0,0 +1,34 @@
+package exchanges
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var limiter rateLimiter
+	limiter.init(types.Binance, 20)
+
+	began := time.Now()
+	for i := 0; i < 22; i++ {
+		assert.NoError(t, limiter.wait(context.Background()))
+	}
+	// 20 个突发令牌用完后，再取两个需要约 100ms
+	assert.GreaterOrEqual(t, time.Since(began), 80*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.wait(ctx), context.Canceled)
+
+	var unlimited rateLimiter
+	unlimited.init(types.Kraken, -1)
+	began = time.Now()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, unlimited.wait(context.Background()))
+	}
+	assert.Less(t, time.Since(began), 50*time.Millisecond)
+}
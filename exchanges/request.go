@@ -0,0 +1,175 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// httpClient 由统一接口共享。Timeout 限制单个请求的总耗时，避免调用方传入没有截止时间的 context 时
+// 在卡住的连接上无限等待；Transport 另外限制建连、TLS 握手和等待响应头的时间
+var httpClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConnsPerHost:   16,
+	},
+}
+
+// apiResult 由各交易所的响应外层结构实现，用于识别 HTTP 200 中携带的业务错误
+type apiResult interface {
+	apiError() error
+}
+
+// sendRequest 按交易所限速等待后通过 PrepareRequest 构造请求，并把 JSON 响应解析到 result
+func sendRequest(ctx context.Context, e types.Exchange, method, endpoint string, params map[string]interface{}, signed bool, result interface{}) error {
+	if t, ok := e.(throttled); ok {
+		if err := t.throttle().wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	req, err := e.PrepareRequest(method, endpoint, params, signed)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &types.APIError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %s", err.Error())
+	}
+
+	if r, ok := result.(apiResult); ok {
+		if err := r.apiError(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bodyParam 是 params 中代表整个请求体的键，用于请求体为 JSON 数组的批量接口
+const bodyParam = "_body"
+
+// jsonBody 把 params 序列化为请求体，含 bodyParam 时只序列化它的值
+func jsonBody(params map[string]interface{}) ([]byte, error) {
+	if v, ok := params[bodyParam]; ok {
+		return json.Marshal(v)
+	}
+	return json.Marshal(params)
+}
+
+// notSupported 返回包装了 types.ErrNotSupported 的错误
+func notSupported(e types.Exchange, feature string) error {
+	return fmt.Errorf("%s %s: %w", e.Name(), feature, types.ErrNotSupported)
+}
+
+// jsonDecimal 兼容交易所返回的字符串、数字、空字符串和 null
+type jsonDecimal struct {
+	decimal.Decimal
+}
+
+func (d *jsonDecimal) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		d.Decimal = decimal.Zero
+		return nil
+	}
+
+	v, err := decimal.NewFromString(s)
+	if err != nil {
+		return err
+	}
+	d.Decimal = v
+	return nil
+}
+
+// jsonInt 兼容以字符串或数字返回的整数，例如毫秒时间戳
+type jsonInt int64
+
+func (i *jsonInt) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = jsonInt(v)
+	return nil
+}
+
+func msToTime(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
+// unmarshalFirst 解析可能是对象也可能是数组的响应，数组时取第一个元素
+func unmarshalFirst(raw json.RawMessage, v interface{}) error {
+	trimmed := strings.TrimSpace(string(raw))
+	if !strings.HasPrefix(trimmed, "[") {
+		return json.Unmarshal(raw, v)
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		return fmt.Errorf("empty response")
+	}
+	return json.Unmarshal(list[0], v)
+}
+
+func decimalFromDuration(d time.Duration) decimal.Decimal {
+	return decimal.NewFromInt(int64(d))
+}
+
+// nonceClock 生成严格递增的毫秒时间戳，同一毫秒内的多个请求依次加一；Gemini、Hyperliquid 拒绝重复或不递增的 nonce
+type nonceClock struct {
+	last atomic.Int64
+}
+
+func (c *nonceClock) next() int64 {
+	for {
+		last := c.last.Load()
+		nonce := max(time.Now().UnixMilli(), last+1)
+		if c.last.CompareAndSwap(last, nonce) {
+			return nonce
+		}
+	}
+}
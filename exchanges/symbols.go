@@ -0,0 +1,233 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// instrumentSource 是可以列出交易对元数据的交易所，标准交易对与原生交易对的转换依赖这些元数据
+type instrumentSource interface {
+	types.Exchange
+	GetInstruments(ctx context.Context) ([]types.Instrument, error)
+}
+
+func symbolNotFound(e types.Exchange, symbol string) error {
+	return fmt.Errorf("%s %s: %w", e.Name(), symbol, types.ErrSymbolNotFound)
+}
+
+// instrumentSymbol 返回交易对元数据对应的标准 Symbol
+func instrumentSymbol(inst types.Instrument) types.Symbol {
+	return types.NewSymbol(inst.Base, inst.Quote)
+}
+
+// preferInstrument 在多个交易对对应同一个 Symbol 时（例如永续与交割合约）决定是否用 a 替换 b：
+// 优先可交易的，其次名称更短的，永续合约的名称通常最短
+func preferInstrument(a, b types.Instrument) bool {
+	if (a.Status == types.InstrumentTrading) != (b.Status == types.InstrumentTrading) {
+		return a.Status == types.InstrumentTrading
+	}
+	if len(a.Symbol) != len(b.Symbol) {
+		return len(a.Symbol) < len(b.Symbol)
+	}
+	return a.Symbol < b.Symbol
+}
+
+func nativeSymbol(ctx context.Context, e instrumentSource, symbol types.Symbol) (string, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	symbol = types.NewSymbol(symbol.Base, symbol.Quote)
+	var best *types.Instrument
+	for i := range instruments {
+		if instrumentSymbol(instruments[i]) != symbol {
+			continue
+		}
+		if best == nil || preferInstrument(instruments[i], *best) {
+			best = &instruments[i]
+		}
+	}
+	if best == nil {
+		return "", symbolNotFound(e, symbol.String())
+	}
+	return best.Symbol, nil
+}
+
+// compactSymbol 去掉分隔符并转为大写，用于匹配 XBTUSD、btcusdt 这类交易所也接受的写法
+func compactSymbol(s string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", "_", "", "/", "").Replace(s))
+}
+
+// canonicalSymbol 依次按原生名称、忽略大小写、去掉分隔符后的币种拼接匹配交易对
+func canonicalSymbol(ctx context.Context, e instrumentSource, native string) (types.Symbol, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if err != nil {
+		return types.Symbol{}, err
+	}
+
+	for _, inst := range instruments {
+		if inst.Symbol == native {
+			return instrumentSymbol(inst), nil
+		}
+	}
+	for _, inst := range instruments {
+		if strings.EqualFold(inst.Symbol, native) {
+			return instrumentSymbol(inst), nil
+		}
+	}
+
+	compact := compactSymbol(native)
+	for _, inst := range instruments {
+		canonical := instrumentSymbol(inst)
+		if compact == compactSymbol(inst.Base+inst.Quote) || compact == canonical.Base+canonical.Quote {
+			return canonical, nil
+		}
+	}
+	return types.Symbol{}, symbolNotFound(e, native)
+}
+
+func (b *Binance) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, b, symbol)
+}
+
+func (b *Binance) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, b, native)
+}
+
+func (o *OKX) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, o, symbol)
+}
+
+func (o *OKX) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, o, native)
+}
+
+func (b *Bitget) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, b, symbol)
+}
+
+func (b *Bitget) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, b, native)
+}
+
+func (k *Kucoin) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, k, symbol)
+}
+
+func (k *Kucoin) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, k, native)
+}
+
+func (m *MEXC) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, m, symbol)
+}
+
+func (m *MEXC) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, m, native)
+}
+
+func (g *Gate) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, g, symbol)
+}
+
+func (g *Gate) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, g, native)
+}
+
+// NativeSymbol 返回 AssetPairs 的 key（如 XXBTZUSD）；CanonicalSymbol 也接受 XBTUSD 这类 altname
+func (k *Kraken) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, k, symbol)
+}
+
+func (k *Kraken) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, k, native)
+}
+
+func (b *Bybit) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, b, symbol)
+}
+
+func (b *Bybit) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, b, native)
+}
+
+// NativeSymbol 返回小写的 HTX 交易对，例如 btcusdt
+func (h *Huobi) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, h, symbol)
+}
+
+func (h *Huobi) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, h, native)
+}
+
+func (c *Coinbase) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, c, symbol)
+}
+
+func (c *Coinbase) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, c, native)
+}
+
+func (b *BTSE) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, b, symbol)
+}
+
+func (b *BTSE) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, b, native)
+}
+
+// geminiQuotes 是 Gemini 使用的计价币，以 USD 结尾的较长代码排在前面，避免 btcgusd 被拆成 BTCG/USD
+var geminiQuotes = []string{"RLUSD", "GUSD", "USDT", "USDC", "USD", "EUR", "GBP", "SGD", "DAI", "BTC", "ETH"}
+
+// NativeSymbol 中 Gemini 没有交易对列表接口，直接按其小写拼接的命名规则生成，例如 btcusd
+func (g *Gemini) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	symbol = types.NewSymbol(symbol.Base, symbol.Quote)
+	return strings.ToLower(symbol.Base + symbol.Quote), nil
+}
+
+// CanonicalSymbol 按已知计价币后缀拆分 Gemini 交易对
+func (g *Gemini) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	upper := strings.ToUpper(native)
+	for _, quote := range geminiQuotes {
+		if base, ok := strings.CutSuffix(upper, quote); ok && base != "" {
+			return types.NewSymbol(base, quote), nil
+		}
+	}
+	return types.Symbol{}, symbolNotFound(g, native)
+}
+
+func (u *Upbit) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, u, symbol)
+}
+
+func (u *Upbit) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, u, native)
+}
+
+func (c *CryptoCom) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, c, symbol)
+}
+
+func (c *CryptoCom) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, c, native)
+}
+
+func (b *BitMart) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, b, symbol)
+}
+
+func (b *BitMart) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, b, native)
+}
+
+func (h *Hyperliquid) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	return nativeSymbol(ctx, h, symbol)
+}
+
+func (h *Hyperliquid) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	return canonicalSymbol(ctx, h, native)
+}
@@ -0,0 +1,89 @@
+package exchanges
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKrakenSymbolMapping(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/0/public/AssetPairs": `{"error":[],"result":{
+			"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD","pair_decimals":1,"lot_decimals":8,"ordermin":"0.0001","costmin":"0.5","tick_size":"0.1","status":"online"},
+			"XDGUSD":{"altname":"XDGUSD","wsname":"XDG/USD","base":"XXDG","quote":"ZUSD","pair_decimals":7,"lot_decimals":8,"ordermin":"50","status":"online"}
+		}}`,
+	})
+	kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL})
+	ctx := context.Background()
+
+	native, err := kraken.NativeSymbol(ctx, types.Symbol{Base: "BTC", Quote: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, "XXBTZUSD", native)
+
+	native, err = kraken.NativeSymbol(ctx, types.NewSymbol("doge", "usd"))
+	assert.NoError(t, err)
+	assert.Equal(t, "XDGUSD", native)
+
+	for _, s := range []string{"XXBTZUSD", "XBTUSD", "xbtusd", "BTCUSD"} {
+		symbol, err := kraken.CanonicalSymbol(ctx, s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, types.Symbol{Base: "BTC", Quote: "USD"}, symbol, s)
+	}
+
+	_, err = kraken.NativeSymbol(ctx, types.NewSymbol("ETH", "USD"))
+	assert.ErrorIs(t, err, types.ErrSymbolNotFound)
+}
+
+func TestNativeSymbol_PrefersPerpetual(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/fapi/v1/exchangeInfo": `{"symbols":[
+			{"symbol":"BTCUSDT_250328","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[]},
+			{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[]},
+			{"symbol":"BTCUSDT_240329","status":"SETTLING","baseAsset":"BTC","quoteAsset":"USDT","filters":[]}
+		]}`,
+	})
+
+	native, err := NewBinance(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures}).NativeSymbol(context.Background(), types.NewSymbol("BTC", "USDT"))
+	assert.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", native)
+}
+
+func TestHuobiSymbolMapping(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/v1/common/symbols": `{"status":"ok","data":[{"symbol":"btcusdt","base-currency":"btc","quote-currency":"usdt","price-precision":2,"amount-precision":6,"state":"online"}]}`,
+	})
+	huobi := NewHuobi(types.ExchangeConfig{BaseURL: server.URL})
+
+	native, err := huobi.NativeSymbol(context.Background(), types.NewSymbol("BTC", "USDT"))
+	assert.NoError(t, err)
+	assert.Equal(t, "btcusdt", native)
+
+	symbol, err := huobi.CanonicalSymbol(context.Background(), "BTCUSDT")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC/USDT", symbol.String())
+}
+
+func TestGeminiSymbolMapping(t *testing.T) {
+	gemini := NewGemini(types.ExchangeConfig{})
+
+	native, err := gemini.NativeSymbol(context.Background(), types.NewSymbol("XBT", "USD"))
+	assert.NoError(t, err)
+	assert.Equal(t, "btcusd", native)
+
+	symbol, err := gemini.CanonicalSymbol(context.Background(), "ethgusd")
+	assert.NoError(t, err)
+	assert.Equal(t, types.Symbol{Base: "ETH", Quote: "GUSD"}, symbol)
+}
+
+func TestParseSymbol(t *testing.T) {
+	for _, s := range []string{"BTC/USDT", "btc-usdt", "BTC_USDT"} {
+		symbol, err := types.ParseSymbol(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, types.Symbol{Base: "BTC", Quote: "USDT"}, symbol)
+	}
+
+	_, err := types.ParseSymbol("BTCUSDT")
+	assert.Error(t, err)
+}
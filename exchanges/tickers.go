@@ -0,0 +1,65 @@
+package exchanges
+
+import (
+	"context"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// symbolIndex 返回原生交易对到标准 Symbol 的映射，多个交易对对应同一 Symbol 时只保留 preferInstrument 选中的那个。
+// tradingOnly 为 true 时跳过不可交易的交易对
+func symbolIndex(instruments []types.Instrument, tradingOnly bool) map[string]types.Symbol {
+	best := make(map[types.Symbol]types.Instrument, len(instruments))
+	for _, inst := range instruments {
+		if tradingOnly && inst.Status != types.InstrumentTrading {
+			continue
+		}
+		symbol := instrumentSymbol(inst)
+		if prev, ok := best[symbol]; ok && !preferInstrument(inst, prev) {
+			continue
+		}
+		best[symbol] = inst
+	}
+
+	index := make(map[string]types.Symbol, len(best))
+	for symbol, inst := range best {
+		index[inst.Symbol] = symbol
+	}
+	return index
+}
+
+// keyTickers 把批量接口返回的行情按标准 Symbol 建立索引，交易对元数据中没有的行情被忽略
+func keyTickers(ctx context.Context, e instrumentSource, tickers []types.Ticker) (map[types.Symbol]types.Ticker, error) {
+	instruments, err := e.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := symbolIndex(instruments, false)
+	result := make(map[types.Symbol]types.Ticker, len(index))
+	for _, t := range tickers {
+		if symbol, ok := index[t.Symbol]; ok {
+			result[symbol] = t
+		}
+	}
+	return result, nil
+}
+
+// batchTickers 用于没有批量行情接口的交易所：在限速内逐个查询 symbols 中的交易对，任一请求失败即停止
+func batchTickers(ctx context.Context, symbols map[string]types.Symbol, get func(ctx context.Context, symbol string) (types.Ticker, error)) (map[types.Symbol]types.Ticker, error) {
+	natives := make([]string, 0, len(symbols))
+	for native := range symbols {
+		natives = append(natives, native)
+	}
+
+	byNative, err := fanOut(ctx, natives, get)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[types.Symbol]types.Ticker, len(byNative))
+	for native, t := range byNative {
+		result[symbols[native]] = t
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package exchanges
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// parseSide 把交易所的方向字符串转换为 types.Side，无法识别时返回空字符串
+func parseSide(s string) types.Side {
+	switch strings.ToLower(s) {
+	case "buy", "b", "bid":
+		return types.Buy
+	case "sell", "s", "a", "ask":
+		return types.Sell
+	}
+	return ""
+}
+
+// opposite 返回相反方向，用于只提供 maker 方向的交易所
+func opposite(side types.Side) types.Side {
+	switch side {
+	case types.Buy:
+		return types.Sell
+	case types.Sell:
+		return types.Buy
+	}
+	return side
+}
+
+// sortTrades 按时间升序排列，并在 limit > 0 时保留最近的 limit 笔
+func sortTrades(trades []types.Trade, limit int) []types.Trade {
+	sort.SliceStable(trades, func(i, j int) bool { return trades[i].Time.Before(trades[j].Time) })
+	if limit > 0 && len(trades) > limit {
+		return trades[len(trades)-limit:]
+	}
+	return trades
+}
@@ -0,0 +1,2053 @@
+package exchanges
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTradingFees(t *testing.T) {
+	t.Run("kraken percent", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/0/private/TradeVolume": `{"error":[],"result":{"currency":"ZUSD","volume":"0",
+				"fees":{"XXBTZUSD":{"fee":"0.2600"}},"fees_maker":{"XXBTZUSD":{"fee":"0.1600"}}}}`,
+		})
+
+		kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL, APIKey: "key", APISecret: "c2VjcmV0"})
+		fees, err := kraken.GetTradingFees(context.Background(), "XBTUSD")
+		assert.NoError(t, err)
+		assert.True(t, dec("0.0016").Equal(fees["XXBTZUSD"].Maker))
+		assert.True(t, dec("0.0026").Equal(fees["XXBTZUSD"].Taker))
+	})
+
+	t.Run("okx negated", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v5/account/trade-fee": `{"code":"0","msg":"","data":[{"instType":"SPOT","maker":"-0.0008","taker":"-0.001","makerU":"","takerU":""}]}`,
+		})
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL})
+		fees, err := okx.GetTradingFees(context.Background(), "BTC-USDT", "ETH-USDT")
+		assert.NoError(t, err)
+		assert.Len(t, fees, 2)
+		assert.True(t, dec("0.0008").Equal(fees["ETH-USDT"].Maker))
+		assert.True(t, dec("0.001").Equal(fees["BTC-USDT"].Taker))
+	})
+
+	t.Run("bybit missing symbol", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/v5/account/fee-rate": `{"retCode":0,"retMsg":"OK","result":{"list":[{"symbol":"BTCUSDT","makerFeeRate":"0.001","takerFeeRate":"0.001"}]}}`,
+		})
+
+		bybit := NewBybit(types.ExchangeConfig{BaseURL: server.URL})
+		_, err := bybit.GetTradingFees(context.Background(), "BTCUSDT", "ETHUSDT")
+		assert.ErrorIs(t, err, types.ErrSymbolNotFound)
+	})
+
+	t.Run("bitget per symbol", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v2/common/trade-rate": `{"code":"00000","msg":"success","data":{"makerFeeRate":"0.0002","takerFeeRate":"0.0006"}}`,
+		})
+
+		bitget := NewBitget(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		fees, err := bitget.GetTradingFees(context.Background(), "BTCUSDT", "ETHUSDT")
+		assert.NoError(t, err)
+		assert.Len(t, fees, 2)
+		assert.True(t, dec("0.0002").Equal(fees["ETHUSDT"].Maker))
+		assert.True(t, dec("0.0006").Equal(fees["BTCUSDT"].Taker))
+	})
+
+	t.Run("mexc numeric commission", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v3/tradeFee": `{"data":{"makerCommission":0,"takerCommission":0.0005},"code":0,"msg":"success","timestamp":1700000000000}`,
+		})
+
+		mexc := NewMEXC(types.ExchangeConfig{BaseURL: server.URL})
+		fees, err := mexc.GetTradingFees(context.Background(), "BTCUSDT")
+		assert.NoError(t, err)
+		assert.True(t, fees["BTCUSDT"].Maker.IsZero())
+		assert.True(t, dec("0.0005").Equal(fees["BTCUSDT"].Taker))
+	})
+
+	t.Run("htx actual rates", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/v2/reference/transact-fee-rate": `{"code":200,"success":true,"data":[
+				{"symbol":"btcusdt","makerFeeRate":"0.002","takerFeeRate":"0.002","actualMakerRate":"0.0018","actualTakerRate":"0.0019"}]}`,
+		})
+
+		huobi := NewHuobi(types.ExchangeConfig{BaseURL: server.URL})
+		fees, err := huobi.GetTradingFees(context.Background(), "btcusdt")
+		assert.NoError(t, err)
+		assert.True(t, dec("0.0018").Equal(fees["btcusdt"].Maker))
+		assert.True(t, dec("0.0019").Equal(fees["btcusdt"].Taker))
+	})
+
+	t.Run("htx v2 error", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/v2/reference/transact-fee-rate": `{"code":1002,"message":"unauthorized"}`,
+		})
+
+		huobi := NewHuobi(types.ExchangeConfig{BaseURL: server.URL})
+		_, err := huobi.GetTradingFees(context.Background(), "btcusdt")
+		var apiErr *types.APIError
+		assert.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, "1002", apiErr.Code)
+	})
+
+	t.Run("coinbase account tier", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/fees": `{"maker_fee_rate":"0.0040","taker_fee_rate":"0.0060","usd_volume":"1000.00"}`,
+		})
+
+		coinbase := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL, APIKey: "key", APISecret: "c2VjcmV0"})
+		fees, err := coinbase.GetTradingFees(context.Background(), "BTC-USD", "ETH-USD")
+		assert.NoError(t, err)
+		assert.Len(t, fees, 2)
+		assert.True(t, dec("0.004").Equal(fees["ETH-USD"].Maker))
+		assert.True(t, dec("0.006").Equal(fees["BTC-USD"].Taker))
+	})
+}
+
+func TestOrderRequestValidate(t *testing.T) {
+	limit := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100")}
+	assert.NoError(t, limit.Validate())
+
+	tests := map[string]func(r *types.OrderRequest){
+		"missing symbol":       func(r *types.OrderRequest) { r.Symbol = "" },
+		"unknown side":         func(r *types.OrderRequest) { r.Side = "HOLD" },
+		"limit without price":  func(r *types.OrderRequest) { r.Price = dec("0") },
+		"limit by quote":       func(r *types.OrderRequest) { r.QuoteQuantity = dec("10") },
+		"post-only with ioc":   func(r *types.OrderRequest) { r.PostOnly, r.TimeInForce = true, types.IOC },
+		"market with both qty": func(r *types.OrderRequest) { r.Type, r.QuoteQuantity = types.MarketOrder, dec("10") },
+		"post-only market":     func(r *types.OrderRequest) { r.Type, r.PostOnly = types.MarketOrder, true },
+		"stop without trigger": func(r *types.OrderRequest) { r.Type = types.StopLimitOrder },
+		"limit with trigger":   func(r *types.OrderRequest) { r.TriggerPrice = dec("90") },
+		"post-only stop": func(r *types.OrderRequest) {
+			r.Type, r.TriggerPrice, r.PostOnly = types.StopLimitOrder, dec("90"), true
+		},
+		"oco buy above stop": func(r *types.OrderRequest) { r.Type, r.TriggerPrice = types.OCOOrder, dec("90") },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := limit
+			mutate(&req)
+			assert.ErrorIs(t, req.Validate(), types.ErrInvalidOrder)
+		})
+	}
+}
+
+func TestOrderParams(t *testing.T) {
+	postOnly := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Sell, Type: types.LimitOrder, Quantity: dec("2"), Price: dec("100"), PostOnly: true}
+	marketBuy := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("50")}
+
+	t.Run("binance", func(t *testing.T) {
+		spot := NewBinance(types.ExchangeConfig{})
+		params, err := spot.orderParams(postOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, "LIMIT_MAKER", params["type"])
+		assert.NotContains(t, params, "timeInForce")
+
+		params, err = spot.orderParams(marketBuy)
+		assert.NoError(t, err)
+		assert.Equal(t, "50", params["quoteOrderQty"])
+
+		futures := NewBinance(types.ExchangeConfig{Market: types.USDMFutures})
+		params, err = futures.orderParams(postOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, "GTX", params["timeInForce"])
+		_, err = futures.orderParams(marketBuy)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("okx", func(t *testing.T) {
+		okx := NewOKX(types.ExchangeConfig{})
+		params, err := okx.orderParams(postOnly)
+		assert.NoError(t, err)
+		assert.Equal(t, "post_only", params["ordType"])
+		assert.Equal(t, "cash", params["tdMode"])
+
+		params, err = okx.orderParams(marketBuy)
+		assert.NoError(t, err)
+		assert.Equal(t, "quote_ccy", params["tgtCcy"])
+		assert.Equal(t, "50", params["sz"])
+	})
+
+	t.Run("gate futures", func(t *testing.T) {
+		gate := NewGate(types.ExchangeConfig{Market: types.USDMFutures})
+		req := types.OrderRequest{Symbol: "BTC_USDT", Side: types.Sell, Type: types.MarketOrder, Quantity: dec("3"), ReduceOnly: true, ClientOrderID: "abc"}
+		_, params, err := gate.orderParams(req)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-3), params["size"])
+		assert.Equal(t, "0", params["price"])
+		assert.Equal(t, "ioc", params["tif"])
+		assert.Equal(t, "t-abc", params["text"])
+
+		req.Quantity = dec("1.5")
+		_, _, err = gate.orderParams(req)
+		assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	})
+
+	t.Run("kraken fok", func(t *testing.T) {
+		kraken := NewKraken(types.ExchangeConfig{})
+		req := postOnly
+		req.PostOnly, req.TimeInForce = false, types.FOK
+		_, err := kraken.orderParams(req)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("bitget", func(t *testing.T) {
+		spot := NewBitget(types.ExchangeConfig{})
+		params, err := spot.orderParams(context.Background(), marketBuy)
+		assert.NoError(t, err)
+		assert.Equal(t, "market", params["orderType"])
+		assert.Equal(t, "50", params["size"])
+
+		futures := NewBitget(types.ExchangeConfig{Market: types.USDMFutures})
+		req := postOnly
+		req.ReduceOnly = true
+		params, err = futures.orderParams(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "post_only", params["force"])
+		assert.Equal(t, "USDT-FUTURES", params["productType"])
+		assert.Equal(t, "USDT", params["marginCoin"])
+		assert.Equal(t, "YES", params["reduceOnly"])
+	})
+
+	t.Run("htx order types", func(t *testing.T) {
+		assert.Equal(t, "sell-limit-maker", huobiOrderType(postOnly))
+		assert.Equal(t, "buy-market", huobiOrderType(marketBuy))
+		req := postOnly
+		req.PostOnly, req.TimeInForce = false, types.FOK
+		assert.Equal(t, "sell-limit-fok", huobiOrderType(req))
+	})
+
+	t.Run("upbit market buy by price", func(t *testing.T) {
+		upbit := NewUpbit(types.ExchangeConfig{})
+		params, err := upbit.orderParams(marketBuy)
+		assert.NoError(t, err)
+		assert.Equal(t, "bid", params["side"])
+		assert.Equal(t, "price", params["ord_type"])
+		assert.Equal(t, "50", params["price"])
+		assert.NotContains(t, params, "volume")
+
+		_, err = upbit.orderParams(postOnly)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("cryptocom exec inst", func(t *testing.T) {
+		cryptoCom := NewCryptoCom(types.ExchangeConfig{Market: types.USDMFutures})
+		req := postOnly
+		req.ReduceOnly = true
+		params, err := cryptoCom.orderParams(req)
+		assert.NoError(t, err)
+		assert.Equal(t, "GOOD_TILL_CANCEL", params["time_in_force"])
+		assert.Equal(t, []string{"POST_ONLY", "REDUCE_ONLY"}, params["exec_inst"])
+	})
+
+	t.Run("unsupported order types", func(t *testing.T) {
+		fok := postOnly
+		fok.PostOnly, fok.TimeInForce = false, types.FOK
+		_, err := NewBitMart(types.ExchangeConfig{}).orderParams(fok)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+		_, err = NewGemini(types.ExchangeConfig{}).orderParams(marketBuy)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+		_, err = NewBTSE(types.ExchangeConfig{}).orderParams(marketBuy)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+		_, err = NewMEXC(types.ExchangeConfig{Market: types.USDMFutures}).orderParams(postOnly)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+}
+
+func TestPlaceOrder(t *testing.T) {
+	t.Run("binance", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v3/order": `{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"my-id","transactTime":1700000000000,
+				"price":"100.00","origQty":"2.00","origQuoteOrderQty":"0","timeInForce":"GTC","type":"LIMIT_MAKER","side":"SELL"}`,
+		})
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		order, err := binance.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTCUSDT", Side: types.Sell, Type: types.LimitOrder, Quantity: dec("2"), Price: dec("100"), PostOnly: true, ClientOrderID: "my-id",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "28", order.ID)
+		assert.Equal(t, "my-id", order.ClientOrderID)
+		assert.Equal(t, types.LimitOrder, order.Type)
+		assert.Equal(t, types.GTC, order.TimeInForce)
+		assert.True(t, dec("2").Equal(order.Quantity))
+	})
+
+	t.Run("kucoin generates client id", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v1/orders": `{"code":"200000","data":{"orderId":"5bd6e9286d99522a52e458de"}}`,
+		})
+
+		kucoin := NewKucoin(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		order, err := kucoin.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC-USDT", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("10"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "5bd6e9286d99522a52e458de", order.ID)
+		assert.Len(t, order.ClientOrderID, 32)
+	})
+
+	t.Run("okx rejected", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v5/trade/order": `{"code":"1","msg":"Operation failed.","data":[{"ordId":"","clOrdId":"","sCode":"51008","sMsg":"Insufficient balance"}]}`,
+		})
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		_, err := okx.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"),
+		})
+		assert.ErrorContains(t, err, "Insufficient balance")
+	})
+
+	t.Run("invalid request is not sent", func(t *testing.T) {
+		bybit := NewBybit(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0"})
+		_, err := bybit.PlaceOrder(context.Background(), types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder})
+		assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	})
+
+	t.Run("unsupported order type is not sent", func(t *testing.T) {
+		gemini := NewGemini(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0", SkipOrderChecks: true})
+		_, err := gemini.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "btcusd", Side: types.Buy, Type: types.MarketOrder, Quantity: dec("1"),
+		})
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("htx looks up spot account", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/v1/account/accounts":   `{"status":"ok","data":[{"id":100009,"type":"margin"},{"id":100010,"type":"spot"}]}`,
+			"/v1/order/orders/place": `{"status":"ok","data":"59378"}`,
+		})
+
+		huobi := NewHuobi(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		order, err := huobi.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "btcusdt", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "59378", order.ID)
+		assert.Equal(t, "100010", huobi.accountID)
+	})
+
+	t.Run("btse rejected status", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v3.2/order": `[{"status":8,"symbol":"BTC-USDT","orderID":"","clOrderID":"my-id","message":"insufficient balance"}]`,
+		})
+
+		btse := NewBTSE(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		_, err := btse.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"), ClientOrderID: "my-id",
+		})
+		assert.ErrorContains(t, err, "insufficient balance")
+	})
+
+	t.Run("hyperliquid batch", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/info": `{"universe":[{"name":"BTC","szDecimals":5},{"name":"ETH","szDecimals":4}]}`,
+			"/exchange": `{"status":"ok","response":{"type":"order","data":{"statuses":[
+				{"resting":{"oid":77738308}},{"filled":{"totalSz":"0.02","avgPx":"1891.4","oid":77747314}},{"error":"Order must have minimum value of $10."}]}}}`,
+		})
+
+		hyperliquid := NewHyperliquid(types.ExchangeConfig{
+			BaseURL: server.URL, Market: types.USDMFutures, SkipOrderChecks: true,
+			PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+		})
+		results, err := hyperliquid.PlaceOrders(context.Background(), []types.OrderRequest{
+			{Symbol: "BTC", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.001"), Price: dec("60000")},
+			{Symbol: "ETH", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.02"), Price: dec("1900"), TimeInForce: types.IOC},
+			{Symbol: "ETH", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.001"), Price: dec("1900")},
+			{Symbol: "ETH", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("1900"), ClientOrderID: "my-id"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "77738308", results[0].Order.ID)
+		assert.Len(t, results[0].Order.ClientOrderID, 34)
+		assert.Equal(t, types.OrderFilled, results[1].Order.Status)
+		assert.True(t, dec("1891.4").Equal(results[1].Order.AveragePrice))
+		assert.ErrorContains(t, results[2].Err, "minimum value")
+		assert.ErrorIs(t, results[3].Err, types.ErrInvalidOrder)
+	})
+}
+
+func TestCancelOrder(t *testing.T) {
+	okx := NewOKX(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0"})
+	err := okx.CancelOrder(context.Background(), "BTC-USDT", types.OrderRef{})
+	assert.ErrorIs(t, err, types.ErrInvalidOrder)
+
+	server := newTestServer(t, map[string]string{
+		"/api/v1/order/client-order/my-id": `{"code":"200000","data":{"cancelledOrderId":"5bd6e9286d99522a52e458de","clientOid":"my-id"}}`,
+	})
+	kucoin := NewKucoin(types.ExchangeConfig{BaseURL: server.URL})
+	assert.NoError(t, kucoin.CancelOrder(context.Background(), "BTC-USDT", types.OrderRef{ClientOrderID: "my-id"}))
+
+	server = newTestServer(t, map[string]string{
+		"/v1/order/orders/submitCancelClientOrder": `{"status":"ok","data":0}`,
+		"/api/v3.2/order":                          `[{"status":16,"symbol":"BTC-USDT","orderID":"1","message":"ORDER_NOT_FOUND"}]`,
+	})
+	htx := NewHuobi(types.ExchangeConfig{BaseURL: server.URL})
+	err = htx.CancelOrder(context.Background(), "btcusdt", types.OrderRef{ClientOrderID: "my-id"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+
+	btse := NewBTSE(types.ExchangeConfig{BaseURL: server.URL})
+	err = btse.CancelOrder(context.Background(), "BTC-USDT", types.OrderRef{ID: "1"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+	assert.ErrorContains(t, err, "ORDER_NOT_FOUND")
+}
+
+func TestCancelAllOrders(t *testing.T) {
+	t.Run("binance spot native with oco", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v3/openOrders": `[
+				{"symbol":"BTCUSDT","orderId":11,"clientOrderId":"a","price":"100","origQty":"1","type":"LIMIT","side":"BUY"},
+				{"orderListId":5,"contingencyType":"OCO","orderReports":[
+					{"symbol":"BTCUSDT","orderId":12,"clientOrderId":"b","type":"STOP_LOSS_LIMIT","side":"SELL"},
+					{"symbol":"BTCUSDT","orderId":13,"clientOrderId":"c","type":"LIMIT_MAKER","side":"SELL"}]}]`,
+		})
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := binance.CancelAllOrders(context.Background(), "BTCUSDT")
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, "13", results[2].OrderID)
+		assert.NoError(t, results[2].Err)
+	})
+
+	t.Run("okx emulated with partial failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v5/trade/orders-pending":
+				w.Write([]byte(`{"code":"0","msg":"","data":[
+					{"instId":"BTC-USDT","ordId":"1","px":"100","sz":"1","side":"buy","ordType":"limit"},
+					{"instId":"BTC-USDT","ordId":"2","px":"101","sz":"1","side":"buy","ordType":"limit"}]}`))
+			case "/api/v5/trade/cancel-order":
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				if body["ordId"] == "2" {
+					w.Write([]byte(`{"code":"1","msg":"","data":[{"ordId":"2","sCode":"51400","sMsg":"Order cancellation failed"}]}`))
+					return
+				}
+				w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"1","sCode":"0","sMsg":""}]}`))
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := okx.CancelAllOrders(context.Background(), "BTC-USDT")
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.NoError(t, results[0].Err)
+		assert.ErrorContains(t, results[1].Err, "Order cancellation failed")
+	})
+
+	t.Run("kraken cancel all from snapshot", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/0/private/OpenOrders": `{"error":[],"result":{"open":{
+				"OQCLML-BW3P3-BUCMWZ":{"opentm":1688666559.8974,"vol":"1.0","descr":{"pair":"XBTUSD","type":"buy","ordertype":"limit","price":"30000"}}}}}`,
+			"/0/private/CancelAll": `{"error":[],"result":{"count":1}}`,
+		})
+
+		kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL, APISecret: "c2VjcmV0"})
+		results, err := kraken.CancelAllOrders(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, []types.CancelResult{{Symbol: "XBTUSD", OrderID: "OQCLML-BW3P3-BUCMWZ"}}, results)
+	})
+
+	t.Run("coinbase native", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{"/orders": `["a1","b2"]`})
+
+		coinbase := NewCoinbase(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := coinbase.CancelAllOrders(context.Background(), "BTC-USD")
+		assert.NoError(t, err)
+		assert.Equal(t, []types.CancelResult{{Symbol: "BTC-USD", OrderID: "a1"}, {Symbol: "BTC-USD", OrderID: "b2"}}, results)
+	})
+
+	t.Run("cryptocom cancel all from snapshot", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/private/get-open-orders": `{"code":0,"result":{"data":[
+				{"order_id":"19848525","client_oid":"a","instrument_name":"BTC_USD","order_type":"LIMIT","side":"BUY","quantity":"1","limit_price":"100","status":"ACTIVE"}]}}`,
+			"/private/cancel-all-orders": `{"code":0}`,
+		})
+
+		cryptoCom := NewCryptoCom(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := cryptoCom.CancelAllOrders(context.Background(), "BTC_USD")
+		assert.NoError(t, err)
+		assert.Equal(t, []types.CancelResult{{Symbol: "BTC_USD", OrderID: "19848525", ClientOrderID: "a"}}, results)
+	})
+
+	t.Run("hyperliquid batch cancel", func(t *testing.T) {
+		var cancels []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]json.RawMessage
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			switch string(body["type"]) {
+			case `"meta"`:
+				w.Write([]byte(`{"universe":[{"name":"BTC","szDecimals":5},{"name":"ETH","szDecimals":4}]}`))
+			case `"frontendOpenOrders"`:
+				w.Write([]byte(`[
+					{"coin":"ETH","side":"B","limitPx":"1900","sz":"0.5","origSz":"1","oid":2,"tif":"Gtc","timestamp":1700000000000},
+					{"coin":"@107","side":"A","limitPx":"20","sz":"1","origSz":"1","oid":3,"timestamp":1700000000000},
+					{"coin":"ETH","side":"A","limitPx":"2100","sz":"1","origSz":"1","oid":4,"cloid":"0x00000000000000000000000000000004","timestamp":1700000000000}]`))
+			default:
+				cancels = append(cancels, string(body["action"]))
+				w.Write([]byte(`{"status":"ok","response":{"type":"cancel","data":{"statuses":[
+					"success",{"error":"Order was never placed, already canceled, or filled. asset=1"}]}}}`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		hyperliquid := NewHyperliquid(types.ExchangeConfig{
+			BaseURL: server.URL, Market: types.USDMFutures,
+			PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+		})
+		results, err := hyperliquid.CancelAllOrders(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{`{"type":"cancel","cancels":[{"a":1,"o":2},{"a":1,"o":4}]}`}, cancels)
+		assert.Len(t, results, 2)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "0x00000000000000000000000000000004", results[1].ClientOrderID)
+		assert.ErrorIs(t, results[1].Err, types.ErrOrderNotFound)
+	})
+
+	t.Run("gate spot per pair", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v4/spot/open_orders": `[{"currency_pair":"BTC_USDT","total":1,"orders":[{"id":"7","currency_pair":"BTC_USDT","type":"limit","side":"buy","amount":"1","price":"100"}]},
+				{"currency_pair":"ETH_USDT","total":1,"orders":[{"id":"8","currency_pair":"ETH_USDT","type":"limit","side":"sell","amount":"1","price":"10"}]}]`,
+			"/api/v4/spot/orders": `[{"id":"7","currency_pair":"BTC_USDT","type":"limit","side":"buy","amount":"1","price":"100"}]`,
+		})
+
+		gate := NewGate(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := gate.CancelAllOrders(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "BTC_USDT", results[0].Symbol)
+	})
+}
+
+func TestGetOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		newTrader func(config types.ExchangeConfig) types.Trading
+		market    types.Market
+		symbol    string
+		ref       types.OrderRef
+		responses map[string]string
+		want      types.Order
+	}{
+		{
+			name:      "binance partially filled",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewBinance(c) },
+			symbol:    "BTCUSDT",
+			ref:       types.OrderRef{ID: "28"},
+			responses: map[string]string{
+				"/api/v3/order": `{"symbol":"BTCUSDT","orderId":28,"clientOrderId":"a","price":"100","origQty":"2","executedQty":"1",
+					"cummulativeQuoteQty":"99","status":"PARTIALLY_FILLED","timeInForce":"GTC","type":"LIMIT","side":"BUY","time":1700000000000,"updateTime":1700000001000}`,
+			},
+			want: types.Order{
+				ID: "28", ClientOrderID: "a", Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("2"), Status: types.OrderPartiallyFilled, FilledQuantity: dec("1"), AveragePrice: dec("99"),
+				Raw: "PARTIALLY_FILLED", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "okx live with fee",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewOKX(c) },
+			symbol:    "BTC-USDT",
+			ref:       types.OrderRef{ClientOrderID: "b"},
+			responses: map[string]string{
+				"/api/v5/trade/order": `{"code":"0","msg":"","data":[{"instId":"BTC-USDT","ordId":"1","clOrdId":"b","px":"100","sz":"2","side":"sell",
+					"ordType":"post_only","state":"filled","accFillSz":"2","avgPx":"100","fee":"-0.2","feeCcy":"USDT","cTime":"1700000000000","uTime":"1700000001000"}]}`,
+			},
+			want: types.Order{
+				ID: "1", ClientOrderID: "b", Symbol: "BTC-USDT", Side: types.Sell, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("2"), Status: types.OrderFilled, FilledQuantity: dec("2"), AveragePrice: dec("100"),
+				Fee: dec("0.2"), FeeAsset: "USDT", Raw: "filled", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "kucoin canceled after partial fill",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewKucoin(c) },
+			symbol:    "BTC-USDT",
+			ref:       types.OrderRef{ID: "k1"},
+			responses: map[string]string{
+				"/api/v1/orders/k1": `{"code":"200000","data":{"id":"k1","clientOid":"c","symbol":"BTC-USDT","type":"limit","side":"buy","price":"10",
+					"size":"4","funds":"0","dealSize":"1","dealFunds":"10","fee":"0.01","feeCurrency":"USDT","timeInForce":"GTC",
+					"isActive":false,"cancelExist":true,"createdAt":1700000000000}}`,
+			},
+			want: types.Order{
+				ID: "k1", ClientOrderID: "c", Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("10"), Quantity: dec("4"), QuoteQuantity: dec("0"), Status: types.OrderCanceled, FilledQuantity: dec("1"),
+				AveragePrice: dec("10"), Fee: dec("0.01"), FeeAsset: "USDT", Raw: "done", CreatedAt: msToTime(1700000000000),
+			},
+		},
+		{
+			name:      "gate futures ioc expired",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewGate(c) },
+			market:    types.USDMFutures,
+			symbol:    "BTC_USDT",
+			ref:       types.OrderRef{ID: "42"},
+			responses: map[string]string{
+				"/api/v4/futures/usdt/orders/42": `{"id":42,"contract":"BTC_USDT","size":-5,"left":-2,"price":"100","fill_price":"100.5","tif":"ioc",
+					"status":"finished","finish_as":"ioc","create_time":1700000000,"finish_time":1700000001}`,
+			},
+			want: types.Order{
+				ID: "42", Symbol: "BTC_USDT", Side: types.Sell, Type: types.LimitOrder, TimeInForce: types.IOC,
+				Price: dec("100"), Quantity: dec("5"), Status: types.OrderExpired, FilledQuantity: dec("3"), AveragePrice: dec("100.5"),
+				Raw: "finished", CreatedAt: time.Unix(1700000000, 0).UTC(), UpdatedAt: time.Unix(1700000001, 0).UTC(),
+			},
+		},
+		{
+			name:      "htx submitted by client id",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewHuobi(c) },
+			symbol:    "btcusdt",
+			ref:       types.OrderRef{ClientOrderID: "h"},
+			responses: map[string]string{
+				"/v1/order/orders/getClientOrder": `{"status":"ok","data":{"id":59378,"client-order-id":"h","symbol":"btcusdt","type":"buy-limit",
+					"price":"100","amount":"2","state":"submitted","field-amount":"0","field-cash-amount":"0","field-fees":"0","created-at":1700000000000}}`,
+			},
+			want: types.Order{
+				ID: "59378", ClientOrderID: "h", Symbol: "btcusdt", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("2"), Status: types.OrderNew, Raw: "submitted", CreatedAt: msToTime(1700000000000),
+			},
+		},
+		{
+			name:      "bitget spot market buy by quote",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewBitget(c) },
+			symbol:    "BTCUSDT",
+			ref:       types.OrderRef{ID: "1"},
+			responses: map[string]string{
+				"/api/v2/spot/trade/orderInfo": `{"code":"00000","msg":"success","data":[{"symbol":"BTCUSDT","orderId":"1","clientOid":"g","price":"0","size":"50",
+					"orderType":"market","side":"buy","status":"filled","priceAvg":"25000","baseVolume":"0.002","cTime":"1700000000000","uTime":"1700000001000"}]}`,
+			},
+			want: types.Order{
+				ID: "1", ClientOrderID: "g", Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("50"),
+				Status: types.OrderFilled, FilledQuantity: dec("0.002"), AveragePrice: dec("25000"),
+				Raw: "filled", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "mexc partially canceled ioc",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewMEXC(c) },
+			symbol:    "MXUSDT",
+			ref:       types.OrderRef{ID: "C02__443"},
+			responses: map[string]string{
+				"/api/v3/order": `{"symbol":"MXUSDT","orderId":"C02__443","clientOrderId":"m","price":"3","origQty":"10","executedQty":"4","cummulativeQuoteQty":"12",
+					"status":"PARTIALLY_CANCELED","type":"IMMEDIATE_OR_CANCEL","side":"SELL","time":1700000000000,"updateTime":1700000001000}`,
+			},
+			want: types.Order{
+				ID: "C02__443", ClientOrderID: "m", Symbol: "MXUSDT", Side: types.Sell, Type: types.LimitOrder, TimeInForce: types.IOC,
+				Price: dec("3"), Quantity: dec("10"), Status: types.OrderCanceled, FilledQuantity: dec("4"), AveragePrice: dec("3"),
+				Raw: "PARTIALLY_CANCELED", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "coinbase done and canceled",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewCoinbase(c) },
+			symbol:    "BTC-USD",
+			ref:       types.OrderRef{ClientOrderID: "c1"},
+			responses: map[string]string{
+				"/orders/client:c1": `{"id":"d1","client_oid":"c1","product_id":"BTC-USD","side":"buy","type":"limit","time_in_force":"GTC","price":"100","size":"2",
+					"filled_size":"0.5","executed_value":"50","fill_fees":"0.25","status":"done","done_reason":"canceled",
+					"created_at":"2023-11-14T22:13:20Z","done_at":"2023-11-14T22:13:21Z"}`,
+			},
+			want: types.Order{
+				ID: "d1", ClientOrderID: "c1", Symbol: "BTC-USD", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("2"), Status: types.OrderCanceled, FilledQuantity: dec("0.5"), AveragePrice: dec("100"),
+				Fee: dec("0.25"), FeeAsset: "USD", Raw: "done", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "btse filled",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewBTSE(c) },
+			symbol:    "BTC-USDT",
+			ref:       types.OrderRef{ID: "bt1"},
+			responses: map[string]string{
+				"/api/v3.2/order": `{"orderID":"bt1","clOrderID":"x","symbol":"BTC-USDT","orderType":76,"side":"BUY","price":"100","size":"1",
+					"filledSize":"1","avgFillPrice":"99.5","status":4,"timeInForce":"GTC","timestamp":1700000000000}`,
+			},
+			want: types.Order{
+				ID: "bt1", ClientOrderID: "x", Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("1"), Status: types.OrderFilled, FilledQuantity: dec("1"), AveragePrice: dec("99.5"),
+				Raw: "4", CreatedAt: msToTime(1700000000000),
+			},
+		},
+		{
+			name:      "gemini live maker-or-cancel",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewGemini(c) },
+			symbol:    "btcusd",
+			ref:       types.OrderRef{ID: "44"},
+			responses: map[string]string{
+				"/v1/order/status": `{"order_id":"44","client_order_id":"gm","symbol":"btcusd","side":"buy","type":"exchange limit","options":["maker-or-cancel"],
+					"price":"100","original_amount":"1","executed_amount":"0","is_live":true,"is_cancelled":false,"timestampms":1700000000000}`,
+			},
+			want: types.Order{
+				ID: "44", ClientOrderID: "gm", Symbol: "btcusd", Side: types.Buy, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("1"), Status: types.OrderNew, CreatedAt: msToTime(1700000000000),
+			},
+		},
+		{
+			name:      "upbit market buy by price",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewUpbit(c) },
+			symbol:    "KRW-BTC",
+			ref:       types.OrderRef{ID: "u1"},
+			responses: map[string]string{
+				"/v1/order": `{"uuid":"u1","identifier":"i1","market":"KRW-BTC","side":"bid","ord_type":"price","price":"10000","state":"cancel",
+					"executed_volume":"0.0002","executed_funds":"9990","paid_fee":"4.995","created_at":"2023-11-15T07:13:20+09:00"}`,
+			},
+			want: types.Order{
+				ID: "u1", ClientOrderID: "i1", Symbol: "KRW-BTC", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("10000"),
+				Status: types.OrderCanceled, FilledQuantity: dec("0.0002"), AveragePrice: dec("49950000"), Fee: dec("4.995"), FeeAsset: "KRW",
+				Raw: "cancel", CreatedAt: msToTime(1700000000000),
+			},
+		},
+		{
+			name:      "cryptocom active reduce-only",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewCryptoCom(c) },
+			market:    types.USDMFutures,
+			symbol:    "BTCUSD-PERP",
+			ref:       types.OrderRef{ClientOrderID: "cc"},
+			responses: map[string]string{
+				"/private/get-order-detail": `{"code":0,"result":{"order_id":"1","client_oid":"cc","instrument_name":"BTCUSD-PERP","order_type":"LIMIT","side":"SELL",
+					"time_in_force":"GOOD_TILL_CANCEL","exec_inst":["REDUCE_ONLY"],"quantity":"2","limit_price":"100","avg_price":"100","cumulative_quantity":"1",
+					"cumulative_fee":"0.01","fee_instrument_name":"USD","status":"ACTIVE","create_time":1700000000000,"update_time":1700000001000}}`,
+			},
+			want: types.Order{
+				ID: "1", ClientOrderID: "cc", Symbol: "BTCUSD-PERP", Side: types.Sell, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("2"), ReduceOnly: true, Status: types.OrderPartiallyFilled, FilledQuantity: dec("1"),
+				AveragePrice: dec("100"), Fee: dec("0.01"), FeeAsset: "USD", Raw: "ACTIVE",
+				CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name:      "bitmart by client order id",
+			newTrader: func(c types.ExchangeConfig) types.Trading { return NewBitMart(c) },
+			symbol:    "BTC_USDT",
+			ref:       types.OrderRef{ClientOrderID: "bm"},
+			responses: map[string]string{
+				"/spot/v4/query/client-order": `{"code":1000,"message":"OK","data":{"orderId":"b1","clientOrderId":"bm","symbol":"BTC_USDT","side":"sell",
+					"type":"limit_maker","state":"filled","price":"100","priceAvg":"100","size":"1","filledSize":"1","createTime":1700000000000,"updateTime":1700000001000}}`,
+			},
+			want: types.Order{
+				ID: "b1", ClientOrderID: "bm", Symbol: "BTC_USDT", Side: types.Sell, Type: types.LimitOrder, TimeInForce: types.GTC,
+				Price: dec("100"), Quantity: dec("1"), Status: types.OrderFilled, FilledQuantity: dec("1"), AveragePrice: dec("100"),
+				Raw: "filled", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+		{
+			name: "hyperliquid filled ioc",
+			newTrader: func(c types.ExchangeConfig) types.Trading {
+				c.PrivateKey = "0x0123456789012345678901234567890123456789012345678901234567890123"
+				return NewHyperliquid(c)
+			},
+			market: types.USDMFutures,
+			symbol: "ETH",
+			ref:    types.OrderRef{ID: "7"},
+			responses: map[string]string{
+				"/info": `{"status":"order","order":{"order":{"coin":"ETH","side":"A","limitPx":"2000","sz":"0","origSz":"1","oid":7,
+					"cloid":"0x00000000000000000000000000000007","tif":"Ioc","timestamp":1700000000000},"status":"filled","statusTimestamp":1700000001000}}`,
+			},
+			want: types.Order{
+				ID: "7", ClientOrderID: "0x00000000000000000000000000000007", Symbol: "ETH", Side: types.Sell, Type: types.LimitOrder,
+				TimeInForce: types.IOC, Price: dec("2000"), Quantity: dec("1"), Status: types.OrderFilled, FilledQuantity: dec("1"),
+				Raw: "filled", CreatedAt: msToTime(1700000000000), UpdatedAt: msToTime(1700000001000),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.responses)
+			trader := tt.newTrader(types.ExchangeConfig{BaseURL: server.URL, Market: tt.market})
+
+			got, err := trader.GetOrder(context.Background(), tt.symbol, tt.ref)
+			assert.NoError(t, err)
+			assertOrder(t, tt.want, got)
+		})
+	}
+}
+
+// assertOrder 逐字段比较订单，decimal 按数值比较
+func assertOrder(t *testing.T, want, got types.Order) {
+	t.Helper()
+	for name, pair := range map[string][2]decimal.Decimal{
+		"Price":          {want.Price, got.Price},
+		"Quantity":       {want.Quantity, got.Quantity},
+		"QuoteQuantity":  {want.QuoteQuantity, got.QuoteQuantity},
+		"FilledQuantity": {want.FilledQuantity, got.FilledQuantity},
+		"AveragePrice":   {want.AveragePrice, got.AveragePrice},
+		"Fee":            {want.Fee, got.Fee},
+	} {
+		assert.True(t, pair[0].Equal(pair[1]), "%s: want %s, got %s", name, pair[0], pair[1])
+	}
+
+	want.Price, want.Quantity, want.QuoteQuantity, want.FilledQuantity, want.AveragePrice, want.Fee = got.Price, got.Quantity, got.QuoteQuantity, got.FilledQuantity, got.AveragePrice, got.Fee
+	assert.True(t, want.CreatedAt.Equal(got.CreatedAt), "CreatedAt: want %s, got %s", want.CreatedAt, got.CreatedAt)
+	assert.True(t, want.UpdatedAt.Equal(got.UpdatedAt), "UpdatedAt: want %s, got %s", want.UpdatedAt, got.UpdatedAt)
+	want.CreatedAt, want.UpdatedAt = got.CreatedAt, got.UpdatedAt
+	assert.Equal(t, want, got)
+}
+
+func TestGetOrderNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+	}))
+	t.Cleanup(server.Close)
+
+	binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+	_, err := binance.GetOrder(context.Background(), "BTCUSDT", types.OrderRef{ClientOrderID: "missing"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+
+	var apiErr *types.APIError
+	assert.ErrorAs(t, err, &apiErr)
+
+	bybitServer := newTestServer(t, map[string]string{
+		"/v5/order/realtime": `{"retCode":0,"retMsg":"OK","result":{"list":[]}}`,
+		"/v5/order/history":  `{"retCode":0,"retMsg":"OK","result":{"list":[]}}`,
+	})
+	bybit := NewBybit(types.ExchangeConfig{BaseURL: bybitServer.URL})
+	_, err = bybit.GetOrder(context.Background(), "BTCUSDT", types.OrderRef{ID: "1"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+
+	emptyServer := newTestServer(t, map[string]string{
+		"/api/v2/spot/trade/orderInfo": `{"code":"00000","msg":"success","data":[]}`,
+		"/v1/order/status":             `[]`,
+		"/info":                        `{"status":"unknownOid"}`,
+	})
+	bitget := NewBitget(types.ExchangeConfig{BaseURL: emptyServer.URL})
+	_, err = bitget.GetOrder(context.Background(), "BTCUSDT", types.OrderRef{ID: "1"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+
+	gemini := NewGemini(types.ExchangeConfig{BaseURL: emptyServer.URL})
+	_, err = gemini.GetOrder(context.Background(), "btcusd", types.OrderRef{ClientOrderID: "missing"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+
+	hyperliquid := NewHyperliquid(types.ExchangeConfig{
+		BaseURL:    emptyServer.URL,
+		PrivateKey: "0x0123456789012345678901234567890123456789012345678901234567890123",
+	})
+	_, err = hyperliquid.GetOrder(context.Background(), "ETH", types.OrderRef{ID: "1"})
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+}
+
+func TestGetOrderHistory(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/0/private/ClosedOrders": `{"error":[],"result":{"count":3,"closed":{
+			"A":{"status":"closed","opentm":1700000000,"vol":"1","vol_exec":"1","cost":"100","fee":"0.26","price":"100","descr":{"pair":"XBTUSD","type":"buy","ordertype":"limit","price":"100"}},
+			"B":{"status":"canceled","opentm":1700000100,"vol":"1","vol_exec":"0","descr":{"pair":"XBTUSD","type":"sell","ordertype":"limit","price":"110"}},
+			"C":{"status":"archived","opentm":1700000200,"vol":"1","vol_exec":"0","descr":{"pair":"XBTUSD","type":"sell","ordertype":"limit","price":"120"}}}}}`,
+	})
+
+	kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL, APISecret: "c2VjcmV0"})
+	orders, err := kraken.GetOrderHistory(context.Background(), types.OrderHistoryRequest{})
+	assert.NoError(t, err)
+	// 无法识别的状态不属于已结束的订单
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, "A", orders[0].ID)
+		assert.Equal(t, types.OrderFilled, orders[0].Status)
+		assert.Equal(t, types.OrderCanceled, orders[1].Status)
+	}
+
+	gate := NewGate(types.ExchangeConfig{})
+	_, err = gate.GetOrderHistory(context.Background(), types.OrderHistoryRequest{})
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+
+	mexc := NewMEXC(types.ExchangeConfig{})
+	_, err = mexc.GetOrderHistory(context.Background(), types.OrderHistoryRequest{})
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+
+	btse := NewBTSE(types.ExchangeConfig{})
+	_, err = btse.GetOrderHistory(context.Background(), types.OrderHistoryRequest{Symbol: "BTC-USDT"})
+	assert.ErrorIs(t, err, types.ErrNotSupported)
+
+	var pages []string
+	htxServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/order/history", r.URL.Path)
+		pages = append(pages, r.URL.Query().Get("start-time"))
+		if len(pages) == 1 {
+			w.Write([]byte(`{"status":"ok","next-time":1700000100000,"data":[
+				{"id":1,"symbol":"btcusdt","type":"sell-limit","price":"110","amount":"1","state":"partial-canceled","filled-amount":"0.5","filled-cash-amount":"55","created-at":1700000050000}]}`))
+			return
+		}
+		w.Write([]byte(`{"status":"ok","data":[
+			{"id":2,"symbol":"btcusdt","type":"buy-market","price":"0","amount":"100","state":"filled","filled-amount":"1","filled-cash-amount":"100","created-at":1700000150000}]}`))
+	}))
+	t.Cleanup(htxServer.Close)
+
+	htx := NewHuobi(types.ExchangeConfig{BaseURL: htxServer.URL})
+	orders, err = htx.GetOrderHistory(context.Background(), types.OrderHistoryRequest{Start: msToTime(1700000000000)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1700000000000", "1700000100000"}, pages)
+	if assert.Len(t, orders, 2) {
+		assert.Equal(t, types.OrderCanceled, orders[0].Status)
+		assert.True(t, dec("110").Equal(orders[0].AveragePrice))
+		assert.Equal(t, types.MarketOrder, orders[1].Type)
+		assert.True(t, dec("100").Equal(orders[1].QuoteQuantity))
+	}
+}
+
+func TestPlaceOrderLooksUpBeforeRetry(t *testing.T) {
+	defer func(d time.Duration) { lookupDelay = d }(lookupDelay)
+	lookupDelay = 0
+
+	req := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100")}
+	orderJSON := func(clientID string) string {
+		return `{"symbol":"BTCUSDT","orderId":7,"clientOrderId":"` + clientID + `","price":"100","origQty":"1","status":"NEW","type":"LIMIT","side":"BUY","timeInForce":"GTC"}`
+	}
+
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		// placed 写入失败响应并返回订单是否已被交易所接受，delay 是接受订单后响应前的延迟
+		placed func(attempt int, w http.ResponseWriter) bool
+		delay  time.Duration
+		// duplicate 表示重试被交易所以订单号重复拒绝
+		duplicate bool
+		wantPosts int
+		wantErr   bool
+	}{
+		{
+			name: "found after server error",
+			placed: func(attempt int, w http.ResponseWriter) bool {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return true
+			},
+			wantPosts: 1,
+		},
+		{
+			name: "retried when not placed",
+			placed: func(attempt int, w http.ResponseWriter) bool {
+				if attempt == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return false
+				}
+				return true
+			},
+			wantPosts: 2,
+		},
+		{
+			name: "duplicate rejection after a hidden placement",
+			placed: func(attempt int, w http.ResponseWriter) bool {
+				if attempt == 1 {
+					// 第一次查询时订单还不可见
+					w.WriteHeader(http.StatusGatewayTimeout)
+					return false
+				}
+				return true
+			},
+			duplicate: true,
+			wantPosts: 2,
+		},
+		{
+			name: "gives up after bounded retries",
+			placed: func(attempt int, w http.ResponseWriter) bool {
+				w.WriteHeader(http.StatusBadGateway)
+				return false
+			},
+			wantPosts: placeAttempts,
+			wantErr:   true,
+		},
+		{
+			name:      "found after caller timeout",
+			timeout:   50 * time.Millisecond,
+			placed:    func(attempt int, w http.ResponseWriter) bool { return true },
+			delay:     200 * time.Millisecond,
+			wantPosts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu        sync.Mutex
+				posts     int
+				placed    bool
+				clientIDs = map[string]bool{}
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if r.Method == http.MethodGet {
+					mu.Lock()
+					found := placed
+					mu.Unlock()
+					if !found {
+						w.WriteHeader(http.StatusBadRequest)
+						w.Write([]byte(`{"code":-2013,"msg":"Order does not exist."}`))
+						return
+					}
+					w.Write([]byte(orderJSON(q.Get("origClientOrderId"))))
+					return
+				}
+
+				mu.Lock()
+				posts++
+				attempt := posts
+				clientIDs[q.Get("newClientOrderId")] = true
+				placed = tt.placed(attempt, w)
+				accepted := placed
+				mu.Unlock()
+
+				time.Sleep(tt.delay)
+				switch {
+				case accepted && attempt > 1 && tt.duplicate:
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte(`{"code":-2010,"msg":"Duplicate order sent."}`))
+				case accepted && attempt > 1:
+					w.Write([]byte(orderJSON(q.Get("newClientOrderId"))))
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+
+			binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+			order, err := binance.PlaceOrder(ctx, req)
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.wantPosts, posts)
+			// 重试使用同一个自定义订单号
+			assert.Len(t, clientIDs, 1)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, types.ErrOrderStatusUnknown)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "7", order.ID)
+			assert.True(t, clientIDs[order.ClientOrderID])
+		})
+	}
+}
+
+func TestPlaceOrderNotRetriedWithoutUniqueIDs(t *testing.T) {
+	defer func(d time.Duration) { lookupDelay = d }(lookupDelay)
+	lookupDelay = 0
+
+	// KuCoin 不拒绝重复的 clientOid，查询不到时不重新下单
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			posts++
+			w.WriteHeader(http.StatusBadGateway)
+		case strings.HasPrefix(r.URL.Path, "/api/v1/order/client-order/"):
+			w.Write([]byte(`{"code":"400100","msg":"order not exist"}`))
+		default:
+			w.Write([]byte(`{"code":"200000","data":[]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kucoin := NewKucoin(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+	_, err := kucoin.PlaceOrder(context.Background(), types.OrderRequest{
+		Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"),
+	})
+	assert.ErrorIs(t, err, types.ErrOrderStatusUnknown)
+	assert.ErrorIs(t, err, types.ErrOrderNotFound)
+	assert.Equal(t, 1, posts)
+}
+
+func TestPlaceOrderGeneratesClientOrderID(t *testing.T) {
+	gate := NewGate(types.ExchangeConfig{})
+	id := newClientOrderID(gateTextLength)
+	assert.Len(t, gateText(id), 30)
+
+	req := types.OrderRequest{Symbol: "BTC_USDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("1"), ClientOrderID: id}
+	_, params, err := gate.orderParams(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "t-"+id, params["text"])
+}
+
+func TestPrepareOrder(t *testing.T) {
+	inst := types.Instrument{
+		Symbol: "BTCUSDT", TickSize: dec("0.1"), StepSize: dec("0.001"),
+		MinQty: dec("0.001"), MaxQty: dec("100"), MinNotional: dec("5"), Status: types.InstrumentTrading,
+	}
+	buy := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.0125"), Price: dec("1000.05")}
+	sell := buy
+	sell.Side = types.Sell
+
+	tests := []struct {
+		name      string
+		req       types.OrderRequest
+		mode      types.RoundingMode
+		wantPrice string
+		wantQty   string
+		wantErr   string
+	}{
+		{name: "exact rejects off-grid price", req: buy, mode: types.RoundExact, wantErr: "price 1000.05: not a multiple of 0.1"},
+		{name: "down", req: buy, mode: types.RoundDown, wantPrice: "1000", wantQty: "0.012"},
+		{name: "up", req: buy, mode: types.RoundUp, wantPrice: "1000.1", wantQty: "0.013"},
+		{name: "nearest", req: buy, mode: types.RoundNearest, wantPrice: "1000.1", wantQty: "0.013"},
+		{name: "passive buy", req: buy, mode: types.RoundPassive, wantPrice: "1000", wantQty: "0.012"},
+		{name: "passive sell", req: sell, mode: types.RoundPassive, wantPrice: "1000.1", wantQty: "0.012"},
+		{
+			name: "below min quantity", mode: types.RoundDown, wantErr: "quantity 0 is below the minimum 0.001",
+			req: types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.0004"), Price: dec("1000")},
+		},
+		{
+			name: "below min notional", mode: types.RoundDown, wantErr: "notional 4 is below the minimum 5",
+			req: types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.004"), Price: dec("1000")},
+		},
+		{
+			name: "market by quote below min notional", wantErr: "notional 1 is below the minimum 5",
+			req: types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.MarketOrder, QuoteQuantity: dec("1")},
+		},
+		{
+			name: "above max quantity", wantErr: "quantity 101 is above the maximum 100",
+			req: types.OrderRequest{Symbol: "BTCUSDT", Side: types.Sell, Type: types.MarketOrder, Quantity: dec("101")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inst.PrepareOrder(tt.req, tt.mode)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, types.ErrInvalidOrder)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, dec(tt.wantPrice).Equal(got.Price), "price %s", got.Price)
+			assert.True(t, dec(tt.wantQty).Equal(got.Quantity), "quantity %s", got.Quantity)
+		})
+	}
+
+	halted := inst
+	halted.Status = types.InstrumentHalted
+	_, err := halted.PrepareOrder(types.OrderRequest{Symbol: "BTCUSDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("1000")}, types.RoundDown)
+	assert.ErrorContains(t, err, "is not trading")
+
+	// U 本位合约按张计数量，下单金额为 价格×张数×ContractSize
+	usdm := types.Instrument{Symbol: "BTC-USDT-SWAP", TickSize: dec("0.1"), StepSize: dec("1"), MinQty: dec("1"), MinNotional: dec("5"), ContractSize: dec("0.01")}
+	_, err = usdm.PrepareOrder(types.OrderRequest{Symbol: "BTC-USDT-SWAP", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("4"), Price: dec("100")}, types.RoundDown)
+	assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	assert.ErrorContains(t, err, "notional 4 is below the minimum 5")
+	_, err = usdm.PrepareOrder(types.OrderRequest{Symbol: "BTC-USDT-SWAP", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("5"), Price: dec("100")}, types.RoundDown)
+	assert.NoError(t, err)
+}
+
+func TestPlaceOrderChecksInstrument(t *testing.T) {
+	var placed map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v5/public/instruments":
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT","baseCcy":"BTC","quoteCcy":"USDT","tickSz":"0.1","lotSz":"0.0001","minSz":"0.001","state":"live"}]}`))
+		case "/api/v5/trade/order":
+			json.NewDecoder(r.Body).Decode(&placed)
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"9","clOrdId":"x","sCode":"0","sMsg":""}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL, OrderRounding: types.RoundPassive})
+	order, err := okx.PlaceOrder(context.Background(), types.OrderRequest{
+		Symbol: "BTC-USDT", Side: types.Sell, Type: types.LimitOrder, Quantity: dec("0.00123"), Price: dec("60000.01"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "60000.1", placed["px"])
+	assert.Equal(t, "0.0012", placed["sz"])
+	assert.True(t, dec("0.0012").Equal(order.Quantity))
+
+	// 低于最小数量时不发送下单请求
+	placed = nil
+	_, err = okx.PlaceOrder(context.Background(), types.OrderRequest{
+		Symbol: "BTC-USDT", Side: types.Buy, Type: types.LimitOrder, Quantity: dec("0.0005"), Price: dec("60000"),
+	})
+	assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	assert.Nil(t, placed)
+}
+
+func TestPlaceOrders(t *testing.T) {
+	limit := func(symbol, clientID string) types.OrderRequest {
+		return types.OrderRequest{Symbol: symbol, Side: types.Buy, Type: types.LimitOrder, Quantity: dec("1"), Price: dec("100"), ClientOrderID: clientID}
+	}
+
+	t.Run("okx chunks to batch size", func(t *testing.T) {
+		var (
+			mu     sync.Mutex
+			chunks []int
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v5/trade/batch-orders", r.URL.Path)
+			var items []map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&items))
+			mu.Lock()
+			chunks = append(chunks, len(items))
+			mu.Unlock()
+
+			acks := make([]map[string]string, len(items))
+			for i, item := range items {
+				acks[i] = map[string]string{"ordId": "id-" + item["clOrdId"].(string), "clOrdId": item["clOrdId"].(string), "sCode": "0"}
+				if item["clOrdId"] == "c3" {
+					acks[i]["sCode"], acks[i]["sMsg"] = "51008", "Insufficient balance"
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": "2", "msg": "", "data": acks})
+		}))
+		t.Cleanup(server.Close)
+
+		reqs := make([]types.OrderRequest, 25)
+		for i := range reqs {
+			reqs[i] = limit("BTC-USDT", "c"+strconv.Itoa(i))
+		}
+		reqs[1].Quantity = decimal.Zero
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		results, err := okx.PlaceOrders(context.Background(), reqs)
+		assert.NoError(t, err)
+		assert.Len(t, results, 25)
+		assert.Equal(t, []int{20, 4}, chunks)
+
+		assert.ErrorIs(t, results[1].Err, types.ErrInvalidOrder)
+		assert.ErrorContains(t, results[3].Err, "Insufficient balance")
+		assert.NoError(t, results[24].Err)
+		assert.Equal(t, "id-c24", results[24].Order.ID)
+		assert.Equal(t, "c24", results[24].Order.ClientOrderID)
+	})
+
+	t.Run("bybit per-order errors", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/v5/order/create-batch": `{"retCode":0,"retMsg":"OK","result":{"list":[
+				{"category":"linear","symbol":"BTCUSDT","orderId":"1","orderLinkId":"a"},
+				{"category":"linear","symbol":"BTCUSDT","orderId":"","orderLinkId":"b"}]},
+				"retExtInfo":{"list":[{"code":0,"msg":"OK"},{"code":10001,"msg":"Qty invalid"}]}}`,
+		})
+
+		bybit := NewBybit(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures, SkipOrderChecks: true})
+		results, err := bybit.PlaceOrders(context.Background(), []types.OrderRequest{limit("BTCUSDT", "a"), limit("BTCUSDT", "b")})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", results[0].Order.ID)
+		assert.ErrorContains(t, results[1].Err, "Qty invalid")
+	})
+
+	t.Run("kraken places each order", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/0/private/AddOrder": `{"error":[],"result":{"descr":{"order":"buy 1 XBTUSD @ limit 100"},"txid":["OUF4EM-FRGI2-MQMWZD"]}}`,
+		})
+
+		kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		results, err := kraken.PlaceOrders(context.Background(), []types.OrderRequest{limit("XBTUSD", "a"), limit("XBTUSD", "b"), limit("XBTUSD", "c")})
+		assert.NoError(t, err)
+		for i, id := range []string{"a", "b", "c"} {
+			assert.NoError(t, results[i].Err)
+			assert.Equal(t, "OUF4EM-FRGI2-MQMWZD", results[i].Order.ID)
+			assert.Equal(t, id, results[i].Order.ClientOrderID)
+		}
+	})
+
+	t.Run("okx looks up orders after ambiguous batch", func(t *testing.T) {
+		defer func(d time.Duration) { lookupDelay = d }(lookupDelay)
+		lookupDelay = 0
+
+		var (
+			mu     sync.Mutex
+			single []string
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/v5/trade/batch-orders":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case r.Method == http.MethodGet && r.URL.Query().Get("clOrdId") == "a":
+				w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT","ordId":"1","clOrdId":"a","state":"live","side":"buy","ordType":"limit","px":"100","sz":"1"}]}`))
+			case r.Method == http.MethodGet:
+				w.Write([]byte(`{"code":"51603","msg":"Order does not exist","data":[]}`))
+			default:
+				var item map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&item)
+				mu.Lock()
+				single = append(single, item["clOrdId"].(string))
+				mu.Unlock()
+				w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"2","clOrdId":"b","sCode":"0"}]}`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		results, err := okx.PlaceOrders(context.Background(), []types.OrderRequest{limit("BTC-USDT", "a"), limit("BTC-USDT", "b")})
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "1", results[0].Order.ID)
+		assert.NoError(t, results[1].Err)
+		assert.Equal(t, "2", results[1].Order.ID)
+		// 只有确认未下单的订单被单独提交
+		assert.Equal(t, []string{"b"}, single)
+	})
+}
+
+func TestCancelOrders(t *testing.T) {
+	t.Run("binance futures splits id kinds", func(t *testing.T) {
+		var lists []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/fapi/v1/batchOrders", r.URL.Path)
+			q := r.URL.Query()
+			if ids := q.Get("orderIdList"); ids != "" {
+				lists = append(lists, ids)
+				w.Write([]byte(`[{"symbol":"BTCUSDT","orderId":1,"status":"CANCELED"},{"code":-2011,"msg":"Unknown order sent."}]`))
+				return
+			}
+			lists = append(lists, q.Get("origClientOrderIdList"))
+			w.Write([]byte(`[{"symbol":"BTCUSDT","orderId":3,"clientOrderId":"c","status":"CANCELED"}]`))
+		}))
+		t.Cleanup(server.Close)
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		results, err := binance.CancelOrders(context.Background(), "BTCUSDT", []types.OrderRef{{ID: "1"}, {ClientOrderID: "c"}, {ID: "2"}, {}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"[1,2]", `["c"]`}, lists)
+
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "c", results[1].ClientOrderID)
+		assert.NoError(t, results[1].Err)
+		assert.ErrorContains(t, results[2].Err, "Unknown order sent.")
+		assert.Equal(t, "2", results[2].OrderID)
+		assert.ErrorIs(t, results[3].Err, types.ErrInvalidOrder)
+	})
+
+	t.Run("bitget spot batch failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v2/spot/trade/batch-cancel-order", r.URL.Path)
+			var body struct {
+				Symbol    string              `json:"symbol"`
+				OrderList []map[string]string `json:"orderList"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []map[string]string{{"orderId": "1"}, {"clientOid": "b"}}, body.OrderList)
+			w.Write([]byte(`{"code":"00000","msg":"success","data":{"successList":[{"orderId":"1","clientOid":"a"}],
+				"failureList":[{"orderId":"","clientOid":"b","errorCode":"43001","errorMsg":"The order does not exist"}]}}`))
+		}))
+		t.Cleanup(server.Close)
+
+		bitget := NewBitget(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := bitget.CancelOrders(context.Background(), "BTCUSDT", []types.OrderRef{{ID: "1"}, {ClientOrderID: "b"}})
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, types.ErrOrderNotFound)
+	})
+
+	t.Run("gate spot", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v4/spot/cancel_batch_orders", r.URL.Path)
+			var items []map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&items))
+			assert.Equal(t, []map[string]string{{"currency_pair": "BTC_USDT", "id": "1"}, {"currency_pair": "BTC_USDT", "id": "t-my-id"}}, items)
+			w.Write([]byte(`[{"currency_pair":"BTC_USDT","id":"1","succeeded":true},
+				{"currency_pair":"BTC_USDT","id":"t-my-id","succeeded":false,"label":"ORDER_NOT_FOUND","message":"Order not found"}]`))
+		}))
+		t.Cleanup(server.Close)
+
+		gate := NewGate(types.ExchangeConfig{BaseURL: server.URL})
+		results, err := gate.CancelOrders(context.Background(), "BTC_USDT", []types.OrderRef{{ID: "1"}, {ClientOrderID: "my-id"}})
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.ErrorContains(t, results[1].Err, "ORDER_NOT_FOUND")
+	})
+}
+
+func TestAmendOrder(t *testing.T) {
+	t.Run("invalid request", func(t *testing.T) {
+		okx := NewOKX(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0"})
+		_, err := okx.AmendOrder(context.Background(), types.AmendRequest{Symbol: "BTC-USDT", Ref: types.OrderRef{ID: "1"}})
+		assert.ErrorIs(t, err, types.ErrInvalidOrder)
+		_, err = okx.AmendOrder(context.Background(), types.AmendRequest{Symbol: "BTC-USDT", Price: dec("1")})
+		assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	})
+
+	t.Run("okx amends in place", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v5/trade/amend-order", r.URL.Path)
+			var params map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+			assert.Equal(t, map[string]interface{}{"instId": "BTC-USDT", "clOrdId": "a", "newPx": "101"}, params)
+			w.Write([]byte(`{"code":"0","msg":"","data":[{"ordId":"1","clOrdId":"a","sCode":"0","sMsg":""}]}`))
+		}))
+		t.Cleanup(server.Close)
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL})
+		result, err := okx.AmendOrder(context.Background(), types.AmendRequest{Symbol: "BTC-USDT", Ref: types.OrderRef{ClientOrderID: "a"}, Price: dec("101")})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", result.Order.ID)
+		assert.False(t, result.Replaced)
+		assert.False(t, result.Emulated)
+	})
+
+	t.Run("binance spot cancel-replace", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			switch r.URL.Path {
+			case "/api/v3/order":
+				w.Write([]byte(`{"symbol":"BTCUSDT","orderId":7,"clientOrderId":"a","price":"100","origQty":"2","executedQty":"0.5",
+					"status":"PARTIALLY_FILLED","type":"LIMIT","side":"BUY","timeInForce":"GTC"}`))
+			case "/api/v3/order/cancelReplace":
+				assert.Equal(t, "7", q.Get("cancelOrderId"))
+				assert.Equal(t, "STOP_ON_FAILURE", q.Get("cancelReplaceMode"))
+				assert.Equal(t, "1.5", q.Get("quantity"))
+				assert.Equal(t, "101", q.Get("price"))
+				assert.Equal(t, "b", q.Get("newClientOrderId"))
+				w.Write([]byte(`{"cancelResult":"SUCCESS","newOrderResult":"SUCCESS","newOrderResponse":{"symbol":"BTCUSDT","orderId":8,
+					"clientOrderId":"b","price":"101","origQty":"1.5","status":"NEW","type":"LIMIT","side":"BUY","timeInForce":"GTC"}}`))
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+		result, err := binance.AmendOrder(context.Background(), types.AmendRequest{
+			Symbol: "BTCUSDT", Ref: types.OrderRef{ClientOrderID: "a"}, Price: dec("101"), NewClientOrderID: "b",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "8", result.Order.ID)
+		assert.True(t, result.Replaced)
+		assert.False(t, result.Emulated)
+	})
+
+	// kucoinServer 模拟撤单后成交数量变为 filledAtCancel 的订单，并记录重新下单的参数
+	kucoinServer := func(t *testing.T, filledAtCancel string) (*httptest.Server, *map[string]interface{}) {
+		var (
+			mu       sync.Mutex
+			canceled bool
+			placed   map[string]interface{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case r.Method == http.MethodGet && r.URL.Path == "/api/v1/orders/k1":
+				active, filled := "true", "1"
+				if canceled {
+					active, filled = "false", filledAtCancel
+				}
+				w.Write([]byte(`{"code":"200000","data":{"id":"k1","symbol":"BTC-USDT","type":"limit","side":"buy","price":"10","size":"4",
+					"dealSize":"` + filled + `","timeInForce":"GTC","isActive":` + active + `,"cancelExist":` + strconv.FormatBool(canceled) + `}}`))
+			case r.Method == http.MethodDelete && r.URL.Path == "/api/v1/orders/k1":
+				canceled = true
+				w.Write([]byte(`{"code":"200000","data":{"cancelledOrderIds":["k1"]}}`))
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v1/orders":
+				json.NewDecoder(r.Body).Decode(&placed)
+				w.Write([]byte(`{"code":"200000","data":{"orderId":"k2"}}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, &placed
+	}
+
+	t.Run("bitget spot cancel-replace", func(t *testing.T) {
+		var replaced map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v2/spot/trade/orderInfo":
+				w.Write([]byte(`{"code":"00000","msg":"success","data":[{"symbol":"BTCUSDT","orderId":"1","clientOid":"a","price":"100","size":"3",
+					"orderType":"limit","side":"sell","force":"gtc","status":"partially_filled","baseVolume":"1"}]}`))
+			case "/api/v2/spot/trade/cancel-replace-order":
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&replaced))
+				w.Write([]byte(`{"code":"00000","msg":"success","data":{"orderId":"2","clientOid":"b","success":"success","msg":""}}`))
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		bitget := NewBitget(types.ExchangeConfig{BaseURL: server.URL})
+		result, err := bitget.AmendOrder(context.Background(), types.AmendRequest{
+			Symbol: "BTCUSDT", Ref: types.OrderRef{ClientOrderID: "a"}, Price: dec("101"), NewClientOrderID: "b",
+		})
+		assert.NoError(t, err)
+		// 已成交 1，新订单按剩余的 2 下单
+		assert.Equal(t, map[string]interface{}{"symbol": "BTCUSDT", "orderId": "1", "newClientOid": "b", "price": "101", "size": "2"}, replaced)
+		assert.True(t, result.Replaced)
+		assert.False(t, result.Emulated)
+		assert.Equal(t, "2", result.Order.ID)
+		assert.Equal(t, types.Sell, result.Order.Side)
+	})
+
+	t.Run("bitget futures modify-order", func(t *testing.T) {
+		var modified map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v2/mix/order/detail":
+				assert.Equal(t, "USDT-FUTURES", r.URL.Query().Get("productType"))
+				w.Write([]byte(`{"code":"00000","msg":"success","data":{"symbol":"BTCUSDT","orderId":"1","clientOid":"a","price":"100","size":"3",
+					"orderType":"limit","side":"buy","force":"gtc","state":"live","baseVolume":"0"}}`))
+			case "/api/v2/mix/order/modify-order":
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&modified))
+				w.Write([]byte(`{"code":"00000","msg":"success","data":{"orderId":"3","clientOid":"c"}}`))
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		bitget := NewBitget(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		result, err := bitget.AmendOrder(context.Background(), types.AmendRequest{
+			Symbol: "BTCUSDT", Ref: types.OrderRef{ID: "1"}, Quantity: dec("5"), NewClientOrderID: "c",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"symbol": "BTCUSDT", "productType": "USDT-FUTURES", "marginCoin": "USDT", "orderId": "1",
+			"newClientOid": "c", "newPrice": "100", "newSize": "5",
+		}, modified)
+		assert.True(t, result.Replaced)
+		assert.Equal(t, "3", result.Order.ID)
+		assert.True(t, dec("5").Equal(result.Order.Quantity))
+	})
+
+	t.Run("mexc emulates with cancel and place", func(t *testing.T) {
+		var placed url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/order", r.URL.Path)
+			switch r.Method {
+			case http.MethodGet:
+				w.Write([]byte(`{"symbol":"MXUSDT","orderId":"m1","price":"3","origQty":"10","executedQty":"4","status":"PARTIALLY_FILLED","type":"LIMIT","side":"BUY"}`))
+			case http.MethodDelete:
+				w.Write([]byte(`{"symbol":"MXUSDT","orderId":"m1","status":"CANCELED"}`))
+			case http.MethodPost:
+				placed = r.URL.Query()
+				w.Write([]byte(`{"orderId":"m2"}`))
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		mexc := NewMEXC(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		result, err := mexc.AmendOrder(context.Background(), types.AmendRequest{Symbol: "MXUSDT", Ref: types.OrderRef{ID: "m1"}, Price: dec("2.9")})
+		assert.NoError(t, err)
+		assert.True(t, result.Emulated)
+		assert.True(t, result.Replaced)
+		assert.Equal(t, "m2", result.Order.ID)
+		assert.Equal(t, "6", placed.Get("quantity"))
+		assert.Equal(t, "2.9", placed.Get("price"))
+	})
+
+	t.Run("hyperliquid checks the new cloid before cancelling", func(t *testing.T) {
+		hyperliquid := NewHyperliquid(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0"})
+		_, err := hyperliquid.AmendOrder(context.Background(), types.AmendRequest{
+			Symbol: "ETH", Ref: types.OrderRef{ID: "1"}, Price: dec("2000"), NewClientOrderID: "my-id",
+		})
+		assert.ErrorIs(t, err, types.ErrInvalidOrder)
+	})
+
+	t.Run("kucoin emulates with cancel and place", func(t *testing.T) {
+		server, placed := kucoinServer(t, "2")
+		kucoin := NewKucoin(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		result, err := kucoin.AmendOrder(context.Background(), types.AmendRequest{Symbol: "BTC-USDT", Ref: types.OrderRef{ID: "k1"}, Price: dec("11")})
+		assert.NoError(t, err)
+		assert.True(t, result.Emulated)
+		assert.True(t, result.Replaced)
+		assert.Equal(t, "k2", result.Order.ID)
+		// 撤单时已成交 2，剩余 2 按新价格重新下单
+		assert.Equal(t, "2", (*placed)["size"])
+		assert.Equal(t, "11", (*placed)["price"])
+	})
+
+	t.Run("kucoin filled before cancel", func(t *testing.T) {
+		server, placed := kucoinServer(t, "4")
+		kucoin := NewKucoin(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		result, err := kucoin.AmendOrder(context.Background(), types.AmendRequest{Symbol: "BTC-USDT", Ref: types.OrderRef{ID: "k1"}, Price: dec("11")})
+		assert.ErrorIs(t, err, types.ErrAmendIncomplete)
+		assert.False(t, result.Replaced)
+		assert.Equal(t, "k1", result.Order.ID)
+		assert.Nil(t, *placed)
+	})
+}
+
+func TestConditionalOrders(t *testing.T) {
+	stopSell := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Sell, Type: types.StopLimitOrder, Quantity: dec("1"), Price: dec("89"), TriggerPrice: dec("90")}
+
+	t.Run("trigger direction", func(t *testing.T) {
+		assert.NoError(t, stopSell.Validate())
+		assert.False(t, stopSell.TriggersOnRise())
+		assert.False(t, stopSell.Triggered(dec("91")))
+		assert.True(t, stopSell.Triggered(dec("90")))
+
+		takeProfit := stopSell
+		takeProfit.Type = types.TakeProfitMarketOrder
+		takeProfit.Price = dec("0")
+		assert.True(t, takeProfit.TriggersOnRise())
+		assert.Equal(t, types.MarketOrder, takeProfit.TriggerOrder().Type)
+
+		oco := types.OrderRequest{Symbol: "BTCUSDT", Side: types.Sell, Type: types.OCOOrder, Quantity: dec("1"), Price: dec("110"), TriggerPrice: dec("90")}
+		assert.NoError(t, oco.Validate())
+		stop := oco.TriggerOrder()
+		assert.Equal(t, types.MarketOrder, stop.Type)
+		assert.True(t, stop.TriggerPrice.IsZero())
+	})
+
+	t.Run("venue params", func(t *testing.T) {
+		params, err := NewBinance(types.ExchangeConfig{}).orderParams(stopSell)
+		assert.NoError(t, err)
+		assert.Equal(t, "STOP_LOSS_LIMIT", params["type"])
+		assert.Equal(t, "90", params["stopPrice"])
+
+		params, err = NewBybit(types.ExchangeConfig{}).orderParams(stopSell)
+		assert.NoError(t, err)
+		assert.Equal(t, "Limit", params["orderType"])
+		assert.Equal(t, 2, params["triggerDirection"])
+		assert.Equal(t, "StopOrder", params["orderFilter"])
+
+		params, err = NewKraken(types.ExchangeConfig{}).orderParams(stopSell)
+		assert.NoError(t, err)
+		assert.Equal(t, "stop-loss-limit", params["ordertype"])
+		assert.Equal(t, "90", params["price"])
+		assert.Equal(t, "89", params["price2"])
+
+		_, _, err = NewGate(types.ExchangeConfig{}).orderParams(stopSell)
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	t.Run("binance oco", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v3/orderList/oco", r.URL.Path)
+			q := r.URL.Query()
+			assert.Equal(t, "LIMIT_MAKER", q.Get("aboveType"))
+			assert.Equal(t, "110", q.Get("abovePrice"))
+			assert.Equal(t, "STOP_LOSS", q.Get("belowType"))
+			assert.Equal(t, "90", q.Get("belowStopPrice"))
+			w.Write([]byte(`{"orderReports":[{"symbol":"BTCUSDT","orderId":1,"clientOrderId":"x","origQty":"1","status":"NEW","type":"STOP_LOSS","side":"SELL"},
+				{"symbol":"BTCUSDT","orderId":2,"clientOrderId":"a","price":"110","origQty":"1","status":"NEW","type":"LIMIT_MAKER","side":"SELL"}]}`))
+		}))
+		t.Cleanup(server.Close)
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		order, err := binance.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTCUSDT", Side: types.Sell, Type: types.OCOOrder, Quantity: dec("1"), Price: dec("110"), TriggerPrice: dec("90"), ClientOrderID: "a",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "2", order.ID)
+		assert.Equal(t, types.OCOOrder, order.Type)
+	})
+
+	t.Run("okx algo order", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPost && r.URL.Path == "/api/v5/trade/order-algo":
+				var params map[string]interface{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+				assert.Equal(t, "conditional", params["ordType"])
+				assert.Equal(t, "90", params["slTriggerPx"])
+				assert.Equal(t, "89", params["slOrdPx"])
+				w.Write([]byte(`{"code":"0","msg":"","data":[{"algoId":"9","algoClOrdId":"a","sCode":"0","sMsg":""}]}`))
+			case r.URL.Path == "/api/v5/trade/order":
+				w.Write([]byte(`{"code":"51603","msg":"Order does not exist","data":[]}`))
+			case r.URL.Path == "/api/v5/trade/order-algo":
+				assert.Equal(t, "9", r.URL.Query().Get("algoId"))
+				w.Write([]byte(`{"code":"0","msg":"","data":[{"instId":"BTC-USDT","algoId":"9","algoClOrdId":"a","side":"sell","ordType":"conditional",
+					"sz":"1","state":"live","slTriggerPx":"90","slOrdPx":"89"}]}`))
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL, SkipOrderChecks: true})
+		req := stopSell
+		req.Symbol, req.ClientOrderID = "BTC-USDT", "a"
+		order, err := okx.PlaceOrder(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, "9", order.ID)
+
+		order, err = okx.GetOrder(context.Background(), "BTC-USDT", types.OrderRef{ID: "9"})
+		assert.NoError(t, err)
+		assert.Equal(t, types.StopLimitOrder, order.Type)
+		assert.Equal(t, types.OrderNew, order.Status)
+		assert.True(t, dec("90").Equal(order.TriggerPrice))
+	})
+}
+
+// fakeVenue 记录下单与撤单，并按 last 返回行情，用于测试条件单模拟器；placeErrs 与 cancelErrs 依次作为之后下单、撤单的错误
+type fakeVenue struct {
+	types.Trading
+	types.MarketData
+	mu         sync.Mutex
+	last       decimal.Decimal
+	placed     []types.OrderRequest
+	canceled   []types.OrderRef
+	filled     decimal.Decimal
+	placeErrs  []error
+	cancelErrs []error
+}
+
+// nextErr 取出 errs 中的第一个错误
+func nextErr(errs *[]error) error {
+	if len(*errs) == 0 {
+		return nil
+	}
+	err := (*errs)[0]
+	*errs = (*errs)[1:]
+	return err
+}
+
+func (f *fakeVenue) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	if req.Type.Conditional() {
+		return types.Order{}, notSupported(NewGate(types.ExchangeConfig{}), "conditional orders")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := nextErr(&f.placeErrs); err != nil {
+		return types.Order{}, err
+	}
+	f.placed = append(f.placed, req)
+	return types.Order{ID: strconv.Itoa(len(f.placed)), ClientOrderID: req.ClientOrderID, Symbol: req.Symbol, Type: req.Type, Status: types.OrderNew}, nil
+}
+
+func (f *fakeVenue) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := nextErr(&f.cancelErrs); err != nil {
+		return err
+	}
+	f.canceled = append(f.canceled, ref)
+	return nil
+}
+
+func (f *fakeVenue) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	status := types.OrderNew
+	if len(f.canceled) > 0 {
+		status = types.OrderCanceled
+	}
+	return types.Order{ID: ref.ID, Symbol: symbol, Status: status, FilledQuantity: f.filled}, nil
+}
+
+func (f *fakeVenue) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return types.Ticker{Symbol: symbol, Last: f.last}, nil
+}
+
+func TestConditionalEmulator(t *testing.T) {
+	t.Run("stop triggers market order", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		var triggered []types.Order
+		emulator.OnTrigger = func(req types.OrderRequest, order types.Order, err error) {
+			assert.NoError(t, err)
+			triggered = append(triggered, order)
+		}
+
+		req := types.OrderRequest{Symbol: "BTC_USDT", Side: types.Sell, Type: types.StopMarketOrder, Quantity: dec("1"), TriggerPrice: dec("90")}
+		order, err := emulator.PlaceOrder(context.Background(), req)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, order.ClientOrderID)
+		assert.Equal(t, types.OrderNew, order.Status)
+
+		emulator.check(context.Background())
+		assert.Empty(t, venue.placed)
+
+		venue.last = dec("89.5")
+		emulator.check(context.Background())
+		assert.Len(t, triggered, 1)
+		assert.Equal(t, types.MarketOrder, venue.placed[0].Type)
+		// 调用方没有指定自定义订单号时由交易所生成，之后仍可用模拟器的订单号查询
+		assert.Empty(t, venue.placed[0].ClientOrderID)
+		got, err := emulator.GetOrder(context.Background(), "BTC_USDT", types.OrderRef{ClientOrderID: order.ClientOrderID})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", got.ID)
+	})
+
+	t.Run("cancel pending order", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		order, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Buy, Type: types.StopMarketOrder, Quantity: dec("1"), TriggerPrice: dec("110"), ClientOrderID: "s1",
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, emulator.CancelOrder(context.Background(), "BTC_USDT", types.OrderRef{ClientOrderID: order.ClientOrderID}))
+
+		venue.last = dec("120")
+		emulator.check(context.Background())
+		assert.Empty(t, venue.placed)
+		assert.Empty(t, venue.canceled)
+	})
+
+	t.Run("oco cancels limit leg", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100"), filled: dec("0.4")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		_, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Sell, Type: types.OCOOrder, Quantity: dec("1"), Price: dec("110"), TriggerPrice: dec("90"), ClientOrderID: "o1",
+		})
+		assert.NoError(t, err)
+		assert.Len(t, venue.placed, 1)
+		assert.Equal(t, types.LimitOrder, venue.placed[0].Type)
+
+		venue.last = dec("90")
+		emulator.check(context.Background())
+		assert.Equal(t, []types.OrderRef{{ID: "1"}}, venue.canceled)
+		assert.Len(t, venue.placed, 2)
+		// 限价单已成交 0.4，止损单按剩余数量下单并沿用调用方的订单号
+		assert.Equal(t, "0.6", venue.placed[1].Quantity.String())
+		assert.Equal(t, types.MarketOrder, venue.placed[1].Type)
+		assert.Equal(t, "o1", venue.placed[1].ClientOrderID)
+	})
+
+	t.Run("retries on next tick after server error", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		var errs []error
+		emulator.OnTrigger = func(req types.OrderRequest, order types.Order, err error) { errs = append(errs, err) }
+
+		_, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Sell, Type: types.StopMarketOrder, Quantity: dec("1"), TriggerPrice: dec("90"), ClientOrderID: "s1",
+		})
+		assert.NoError(t, err)
+
+		venue.last = dec("89")
+		venue.placeErrs = []error{&types.APIError{StatusCode: http.StatusServiceUnavailable}}
+		emulator.check(context.Background())
+		assert.Empty(t, venue.placed)
+		assert.Empty(t, errs)
+		// 条件单放回等待队列，仍可按模拟器的订单号查询
+		order, err := emulator.GetOrder(context.Background(), "BTC_USDT", types.OrderRef{ClientOrderID: "s1"})
+		assert.NoError(t, err)
+		assert.Equal(t, "EMULATED", order.Raw)
+
+		emulator.check(context.Background())
+		assert.Len(t, venue.placed, 1)
+		assert.Equal(t, []error{nil}, errs)
+	})
+
+	t.Run("oco retries limit cancel and does not cancel twice", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		_, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Sell, Type: types.OCOOrder, Quantity: dec("1"), Price: dec("110"), TriggerPrice: dec("90"), ClientOrderID: "o1",
+		})
+		assert.NoError(t, err)
+
+		venue.last = dec("90")
+		venue.cancelErrs = []error{context.DeadlineExceeded}
+		emulator.check(context.Background())
+		assert.Empty(t, venue.canceled)
+		assert.Len(t, venue.placed, 1)
+
+		venue.placeErrs = []error{&types.APIError{StatusCode: http.StatusTooManyRequests}}
+		emulator.check(context.Background())
+		assert.Len(t, venue.canceled, 1)
+		assert.Len(t, venue.placed, 1)
+
+		emulator.check(context.Background())
+		assert.Len(t, venue.canceled, 1)
+		assert.Len(t, venue.placed, 2)
+		assert.Equal(t, "1", venue.placed[1].Quantity.String())
+	})
+
+	t.Run("rejected order is not retried", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		var errs []error
+		emulator.OnTrigger = func(req types.OrderRequest, order types.Order, err error) { errs = append(errs, err) }
+		_, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Sell, Type: types.StopMarketOrder, Quantity: dec("1"), TriggerPrice: dec("90"),
+		})
+		assert.NoError(t, err)
+
+		venue.last = dec("89")
+		venue.placeErrs = []error{types.ErrInsufficientBalance}
+		emulator.check(context.Background())
+		emulator.check(context.Background())
+		assert.Empty(t, venue.placed)
+		assert.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], types.ErrInsufficientBalance)
+	})
+
+	t.Run("forgets finished venue orders", func(t *testing.T) {
+		venue := &fakeVenue{last: dec("100")}
+		emulator := NewConditionalEmulator(venue, venue, time.Second)
+		_, err := emulator.PlaceOrder(context.Background(), types.OrderRequest{
+			Symbol: "BTC_USDT", Side: types.Sell, Type: types.StopLimitOrder, Quantity: dec("1"), Price: dec("89"), TriggerPrice: dec("90"), ClientOrderID: "s1",
+		})
+		assert.NoError(t, err)
+
+		venue.last = dec("89")
+		emulator.check(context.Background())
+		assert.Len(t, emulator.triggered, 1)
+
+		// 交易所订单仍在挂单时保留记录，结束后下一轮移除
+		emulator.check(context.Background())
+		assert.Len(t, emulator.triggered, 1)
+		venue.canceled = append(venue.canceled, types.OrderRef{ID: "1"})
+		emulator.check(context.Background())
+		assert.Empty(t, emulator.triggered)
+	})
+}
+
+func TestGetFills(t *testing.T) {
+	t.Run("okx pages back with billId", func(t *testing.T) {
+		var afters []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v5/trade/fills-history", r.URL.Path)
+			q := r.URL.Query()
+			assert.Equal(t, "SPOT", q.Get("instType"))
+			assert.Equal(t, "1700000000000", q.Get("begin"))
+			afters = append(afters, q.Get("after"))
+
+			// 第一页返回满页的 100 笔，第二页返回 1 笔
+			n, first := 100, 200
+			if q.Get("after") != "" {
+				n, first = 1, 100
+			}
+			rows := make([]string, 0, n)
+			for i := 0; i < n; i++ {
+				id := strconv.Itoa(first - i)
+				rows = append(rows, `{"instId":"BTC-USDT","tradeId":"`+id+`","ordId":"o`+id+`","billId":"b`+id+`","side":"buy",
+					"fillPx":"100","fillSz":"0.1","fee":"-0.0001","feeCcy":"BTC","execType":"M","ts":"`+strconv.Itoa(1700000000000+first-i)+`"}`)
+			}
+			w.Write([]byte(`{"code":"0","msg":"","data":[` + strings.Join(rows, ",") + `]}`))
+		}))
+		t.Cleanup(server.Close)
+
+		okx := NewOKX(types.ExchangeConfig{BaseURL: server.URL})
+		fills, err := okx.GetFills(context.Background(), "", time.UnixMilli(1700000000000))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"", "b101"}, afters)
+		assert.Len(t, fills, 101)
+		assert.Equal(t, "100", fills[0].TradeID)
+		assert.Equal(t, "0.0001", fills[0].Fee.String())
+		assert.Equal(t, "BTC", fills[0].FeeAsset)
+		assert.Equal(t, types.Maker, fills[0].Liquidity)
+	})
+
+	t.Run("binance requires a symbol", func(t *testing.T) {
+		binance := NewBinance(types.ExchangeConfig{BaseURL: "http://127.0.0.1:0"})
+		_, err := binance.GetFills(context.Background(), "", time.Time{})
+		assert.ErrorIs(t, err, types.ErrNotSupported)
+	})
+
+	// binanceFills 模拟 myTrades/userTrades：按 fromId 或 [startTime, endTime] 返回 times 中的成交，成交 ID 为下标，
+	// 时间范围超过 window 时返回空页；queries 记录每次请求使用的 fromId 或 startTime
+	binanceFills := func(t *testing.T, path string, window time.Duration, times []time.Time) (*httptest.Server, *[]string) {
+		var queries []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, path, r.URL.Path)
+			q := r.URL.Query()
+			limit, _ := strconv.Atoi(q.Get("limit"))
+			match := func(id int, ts time.Time) bool { return false }
+			if q.Get("fromId") != "" {
+				queries = append(queries, "fromId="+q.Get("fromId"))
+				from, _ := strconv.Atoi(q.Get("fromId"))
+				match = func(id int, ts time.Time) bool { return id >= from }
+			} else {
+				queries = append(queries, "startTime="+q.Get("startTime"))
+				start, _ := strconv.ParseInt(q.Get("startTime"), 10, 64)
+				end, _ := strconv.ParseInt(q.Get("endTime"), 10, 64)
+				if end-start < window.Milliseconds() {
+					match = func(id int, ts time.Time) bool { return ts.UnixMilli() >= start && ts.UnixMilli() <= end }
+				}
+			}
+
+			rows := []string{}
+			for id, ts := range times {
+				if match(id, ts) && len(rows) < limit {
+					rows = append(rows, `{"symbol":"BTCUSDT","id":`+strconv.Itoa(id)+`,"orderId":9,"side":"SELL","price":"100","qty":"2",
+						"commission":"0.04","commissionAsset":"USDT","time":`+strconv.FormatInt(ts.UnixMilli(), 10)+`,"isBuyer":false,"maker":false}`)
+				}
+			}
+			w.Write([]byte("[" + strings.Join(rows, ",") + "]"))
+		}))
+		t.Cleanup(server.Close)
+		return server, &queries
+	}
+	ms := func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) }
+
+	t.Run("binance futures skips empty windows", func(t *testing.T) {
+		since := time.Now().Add(-20 * 24 * time.Hour)
+		server, queries := binanceFills(t, "/fapi/v1/userTrades", 7*24*time.Hour, []time.Time{since.Add(-time.Hour), since.Add(8 * 24 * time.Hour)})
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		fills, err := binance.GetFills(context.Background(), "BTCUSDT", since)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"startTime=" + ms(since), "startTime=" + ms(since.Add(7*24*time.Hour)), "fromId=2"}, *queries)
+		assert.Len(t, fills, 1)
+		assert.Equal(t, types.Sell, fills[0].Side)
+		assert.Equal(t, types.Taker, fills[0].Liquidity)
+		assert.Equal(t, "USDT", fills[0].FeeAsset)
+	})
+
+	t.Run("binance spot walks 24 hour windows", func(t *testing.T) {
+		since := time.Now().Add(-5 * 24 * time.Hour)
+		server, queries := binanceFills(t, "/api/v3/myTrades", 24*time.Hour, []time.Time{since.Add(50 * time.Hour)})
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+		fills, err := binance.GetFills(context.Background(), "BTCUSDT", since)
+		assert.NoError(t, err)
+		assert.Len(t, *queries, 4)
+		assert.Equal(t, "startTime="+ms(since.Add(48*time.Hour)), (*queries)[2])
+		assert.Len(t, fills, 1)
+		assert.Equal(t, since.Add(50*time.Hour).UnixMilli(), fills[0].Time.UnixMilli())
+	})
+
+	t.Run("binance spot pages past the first window", func(t *testing.T) {
+		// 第 2 天和第 20 天都有成交，第一个非空窗口之后仍需继续翻页
+		since := time.Now().Add(-30 * 24 * time.Hour)
+		var times []time.Time
+		for i := 0; i < 10; i++ {
+			times = append(times, since.Add(36*time.Hour+time.Duration(i)*time.Minute))
+		}
+		for i := 0; i < 5; i++ {
+			times = append(times, since.Add(20*24*time.Hour+time.Duration(i)*time.Minute))
+		}
+		server, queries := binanceFills(t, "/api/v3/myTrades", 24*time.Hour, times)
+
+		binance := NewBinance(types.ExchangeConfig{BaseURL: server.URL})
+		fills, err := binance.GetFills(context.Background(), "BTCUSDT", since)
+		assert.NoError(t, err)
+		assert.Len(t, fills, 15)
+		assert.Equal(t, "14", fills[14].TradeID)
+		assert.Equal(t, "fromId=10", (*queries)[len(*queries)-1])
+	})
+
+	t.Run("gate futures", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/api/v4/futures/usdt/my_trades_timerange": `[{"trade_id":"7","contract":"BTC_USDT","order_id":"3","size":-4,"price":"100",
+				"role":"maker","fee":"-0.01","create_time":1700000000.5}]`,
+		})
+		gate := NewGate(types.ExchangeConfig{BaseURL: server.URL, Market: types.USDMFutures})
+		fills, err := gate.GetFills(context.Background(), "BTC_USDT", time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+		assert.Equal(t, types.Sell, fills[0].Side)
+		assert.Equal(t, "4", fills[0].Quantity.String())
+		assert.Equal(t, "-0.01", fills[0].Fee.String())
+		assert.Equal(t, "USDT", fills[0].FeeAsset)
+		assert.Equal(t, types.Maker, fills[0].Liquidity)
+	})
+
+	t.Run("kraken filters by pair", func(t *testing.T) {
+		server := newTestServer(t, map[string]string{
+			"/0/public/AssetPairs": `{"error":[],"result":{"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD",
+				"pair_decimals":1,"lot_decimals":8,"status":"online"}}}`,
+			"/0/private/TradesHistory": `{"error":[],"result":{"count":2,"trades":{
+				"T1":{"ordertxid":"O1","pair":"XXBTZUSD","time":1700000001.5,"type":"buy","price":"100","fee":"0.26","vol":"1","maker":true},
+				"T2":{"ordertxid":"O2","pair":"XETHZUSD","time":1700000002,"type":"sell","price":"10","fee":"0.1","vol":"1","maker":false}}}}`,
+		})
+		kraken := NewKraken(types.ExchangeConfig{BaseURL: server.URL, APIKey: "key", APISecret: "c2VjcmV0"})
+		fills, err := kraken.GetFills(context.Background(), "XBTUSD", time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, fills, 1)
+		assert.Equal(t, "T1", fills[0].TradeID)
+		assert.Equal(t, "O1", fills[0].OrderID)
+		assert.Equal(t, "USD", fills[0].FeeAsset)
+		assert.Equal(t, types.Maker, fills[0].Liquidity)
+	})
+}
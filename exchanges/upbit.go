@@ -0,0 +1,160 @@
+package exchanges
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type Upbit struct {
+	config      types.ExchangeConfig
+	instruments instrumentCache
+	limiter     rateLimiter
+}
+
+func NewUpbit(config types.ExchangeConfig) *Upbit {
+	return &Upbit{config: config}
+}
+
+func (u *Upbit) Name() types.ExchangeName {
+	return types.Upbit
+}
+
+func (u *Upbit) GetDefaultBaseURL() string {
+	return "https://api.upbit.com"
+}
+
+func (u *Upbit) PrepareRequest(method, endpoint string, params map[string]interface{}, signed bool) (*http.Request, error) {
+	baseURL := u.config.BaseURL
+	if baseURL == "" {
+		baseURL = u.GetDefaultBaseURL()
+	}
+
+	reqURL, err := url.Parse(baseURL + endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	rawQuery, queryString := u.buildQueryString(params)
+
+	var body []byte
+	if method == "POST" {
+		body, err = json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		reqURL.RawQuery = rawQuery
+	}
+
+	req, err := http.NewRequest(method, reqURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if signed {
+		token, err := u.generateToken(queryString)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// buildQueryString 按 key 排序生成查询串，数组参数展开为 key[]=v1&key[]=v2。
+// 返回的第一个值用于 URL，第二个是未转义的版本，用于计算 query_hash。
+func (u *Upbit) buildQueryString(params map[string]interface{}) (string, string) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var escaped, unescaped []string
+	for _, k := range keys {
+		for _, pair := range upbitQueryPairs(k, params[k]) {
+			escaped = append(escaped, url.QueryEscape(pair[0])+"="+url.QueryEscape(pair[1]))
+			unescaped = append(unescaped, pair[0]+"="+pair[1])
+		}
+	}
+
+	return strings.Join(escaped, "&"), strings.Join(unescaped, "&")
+}
+
+func upbitQueryPairs(key string, value interface{}) [][2]string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return [][2]string{{key, fmt.Sprint(value)}}
+	}
+
+	if !strings.HasSuffix(key, "[]") {
+		key += "[]"
+	}
+
+	pairs := make([][2]string, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		pairs = append(pairs, [2]string{key, fmt.Sprint(v.Index(i).Interface())})
+	}
+	return pairs
+}
+
+func (u *Upbit) generateToken(queryString string) (string, error) {
+	nonce, err := newUUID()
+	if err != nil {
+		return "", err
+	}
+
+	claims := map[string]interface{}{
+		"access_key": u.config.APIKey,
+		"nonce":      nonce,
+	}
+	if queryString != "" {
+		hash := sha512.Sum512([]byte(queryString))
+		claims["query_hash"] = hex.EncodeToString(hash[:])
+		claims["query_hash_alg"] = "SHA512"
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(u.config.APISecret))
+	mac.Write([]byte(signingInput))
+	signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return signingInput + "." + signature, nil
+}
+
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,255 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type upbitTicker struct {
+	Market            string      `json:"market"`
+	TradePrice        jsonDecimal `json:"trade_price"`
+	AccTradeVolume24h jsonDecimal `json:"acc_trade_volume_24h"`
+	AccTradePrice24h  jsonDecimal `json:"acc_trade_price_24h"`
+	Timestamp         int64       `json:"timestamp"`
+}
+
+type upbitOrderbook struct {
+	Market         string               `json:"market"`
+	Timestamp      int64                `json:"timestamp"`
+	OrderbookUnits []upbitOrderbookUnit `json:"orderbook_units"`
+}
+
+type upbitOrderbookUnit struct {
+	AskPrice jsonDecimal `json:"ask_price"`
+	BidPrice jsonDecimal `json:"bid_price"`
+	AskSize  jsonDecimal `json:"ask_size"`
+	BidSize  jsonDecimal `json:"bid_size"`
+}
+
+// upbitBatchSize 是一次 ticker、orderbook 请求携带的 market 数量上限
+const upbitBatchSize = 100
+
+// GetTicker 的 symbol 为 Upbit market，例如 KRW-BTC；ticker 接口不含买卖盘，需额外查询 orderbook
+func (u *Upbit) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	tickers, err := u.tickers(ctx, []string{symbol})
+	if err != nil {
+		return types.Ticker{}, err
+	}
+	if len(tickers) == 0 {
+		return types.Ticker{}, fmt.Errorf("upbit ticker %s not found", symbol)
+	}
+	return tickers[0], nil
+}
+
+// tickers 用一次 ticker 和一次 orderbook 请求查询多个 market 的行情
+func (u *Upbit) tickers(ctx context.Context, markets []string) ([]types.Ticker, error) {
+	params := map[string]interface{}{"markets": strings.Join(markets, ",")}
+
+	var rows []upbitTicker
+	if err := sendRequest(ctx, u, "GET", "/v1/ticker", params, false, &rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var books []upbitOrderbook
+	if err := sendRequest(ctx, u, "GET", "/v1/orderbook", params, false, &books); err != nil {
+		return nil, err
+	}
+	top := make(map[string]upbitOrderbookUnit, len(books))
+	for _, book := range books {
+		if len(book.OrderbookUnits) > 0 {
+			top[book.Market] = book.OrderbookUnits[0]
+		}
+	}
+
+	tickers := make([]types.Ticker, 0, len(rows))
+	for _, t := range rows {
+		ticker := types.Ticker{
+			Symbol:      t.Market,
+			Last:        t.TradePrice.Decimal,
+			Volume:      t.AccTradeVolume24h.Decimal,
+			QuoteVolume: t.AccTradePrice24h.Decimal,
+			Timestamp:   msToTime(t.Timestamp),
+		}
+		if unit, ok := top[t.Market]; ok {
+			ticker.Bid = unit.BidPrice.Decimal
+			ticker.Ask = unit.AskPrice.Decimal
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+// GetOrderBook 返回 Upbit 默认的 15 档，更深的 depth 也只能得到 15 档
+func (u *Upbit) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	var books []upbitOrderbook
+	if err := sendRequest(ctx, u, "GET", "/v1/orderbook", map[string]interface{}{"markets": symbol}, false, &books); err != nil {
+		return types.OrderBook{}, err
+	}
+	if len(books) == 0 {
+		return types.OrderBook{}, fmt.Errorf("upbit order book %s not found", symbol)
+	}
+
+	book := types.OrderBook{
+		Symbol:    books[0].Market,
+		Timestamp: msToTime(books[0].Timestamp),
+	}
+	for _, unit := range books[0].OrderbookUnits {
+		book.Bids = append(book.Bids, types.PriceLevel{Price: unit.BidPrice.Decimal, Size: unit.BidSize.Decimal})
+		book.Asks = append(book.Asks, types.PriceLevel{Price: unit.AskPrice.Decimal, Size: unit.AskSize.Decimal})
+	}
+
+	return finalizeOrderBook(book, depth), nil
+}
+
+// Upbit 的分钟 K 线路径为 minutes/{unit}，其余为 days、weeks、months
+var upbitIntervals = map[types.Interval]string{
+	types.Interval1m: "minutes/1", types.Interval3m: "minutes/3", types.Interval5m: "minutes/5",
+	types.Interval15m: "minutes/15", types.Interval30m: "minutes/30", types.Interval1h: "minutes/60",
+	types.Interval4h: "minutes/240", types.Interval1d: "days", types.Interval1w: "weeks", types.Interval1M: "months",
+}
+
+type upbitCandle struct {
+	CandleDateTimeUTC    string      `json:"candle_date_time_utc"`
+	OpeningPrice         jsonDecimal `json:"opening_price"`
+	HighPrice            jsonDecimal `json:"high_price"`
+	LowPrice             jsonDecimal `json:"low_price"`
+	TradePrice           jsonDecimal `json:"trade_price"`
+	CandleAccTradeVolume jsonDecimal `json:"candle_acc_trade_volume"`
+	CandleAccTradePrice  jsonDecimal `json:"candle_acc_trade_price"`
+}
+
+// GetCandles 中 Upbit 只支持 to 往前查询，单次最多 200 根，start 在本地过滤
+func (u *Upbit) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	return fetchCandles(ctx, u, upbitIntervals, u.config.ResampleCandles, interval, start, end, limit,
+		func(ctx context.Context, interval types.Interval, venueInterval string, start, end time.Time, limit int) ([]types.Candle, error) {
+			params := map[string]interface{}{
+				"market": symbol,
+				"count":  clampDepth(limit, 200),
+			}
+			if !end.IsZero() {
+				// to 不包含自身，加 1 秒使 end 所在的 K 线也被返回
+				params["to"] = end.Add(time.Second).UTC().Format("2006-01-02T15:04:05Z")
+			}
+
+			var rows []upbitCandle
+			if err := sendRequest(ctx, u, "GET", "/v1/candles/"+venueInterval, params, false, &rows); err != nil {
+				return nil, err
+			}
+
+			candles := make([]types.Candle, 0, len(rows))
+			for _, r := range rows {
+				openTime, err := time.Parse("2006-01-02T15:04:05", r.CandleDateTimeUTC)
+				if err != nil {
+					return nil, err
+				}
+				candles = append(candles, types.Candle{
+					OpenTime:    openTime,
+					Open:        r.OpeningPrice.Decimal,
+					High:        r.HighPrice.Decimal,
+					Low:         r.LowPrice.Decimal,
+					Close:       r.TradePrice.Decimal,
+					Volume:      r.CandleAccTradeVolume.Decimal,
+					QuoteVolume: r.CandleAccTradePrice.Decimal,
+				})
+			}
+			return candles, nil
+		})
+}
+
+// upbitTick 的 ask_bid 为 taker 方向：ASK 表示卖出成交，BID 表示买入成交
+type upbitTick struct {
+	TradePrice   jsonDecimal `json:"trade_price"`
+	TradeVolume  jsonDecimal `json:"trade_volume"`
+	AskBid       string      `json:"ask_bid"`
+	SequentialID int64       `json:"sequential_id"`
+	Timestamp    int64       `json:"timestamp"`
+}
+
+// GetRecentTrades 单次最多返回 500 笔
+func (u *Upbit) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	params := map[string]interface{}{
+		"market": symbol,
+		"count":  clampDepth(limit, 500),
+	}
+
+	var rows []upbitTick
+	if err := sendRequest(ctx, u, "GET", "/v1/trades/ticks", params, false, &rows); err != nil {
+		return nil, err
+	}
+
+	trades := make([]types.Trade, 0, len(rows))
+	for _, t := range rows {
+		trades = append(trades, types.Trade{
+			ID:    strconv.FormatInt(t.SequentialID, 10),
+			Price: t.TradePrice.Decimal,
+			Size:  t.TradeVolume.Decimal,
+			Side:  parseSide(t.AskBid),
+			Time:  msToTime(t.Timestamp),
+		})
+	}
+	return sortTrades(trades, limit), nil
+}
+
+// upbitMarket 的 market 为 KRW-BTC 形式，计价币在前
+type upbitMarket struct {
+	Market        string `json:"market"`
+	MarketWarning string `json:"market_warning"`
+}
+
+// GetInstruments 中 Upbit 的价格步长随价格区间变化、数量不限制步长，因此只返回交易对和状态
+func (u *Upbit) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	return u.instruments.get(ctx, u.config.InstrumentTTL, func(ctx context.Context) ([]types.Instrument, error) {
+		var markets []upbitMarket
+		if err := sendRequest(ctx, u, "GET", "/v1/market/all", nil, false, &markets); err != nil {
+			return nil, err
+		}
+
+		instruments := make([]types.Instrument, 0, len(markets))
+		for _, m := range markets {
+			quote, base, _ := strings.Cut(m.Market, "-")
+			instruments = append(instruments, types.Instrument{
+				Symbol: m.Market,
+				Base:   base,
+				Quote:  quote,
+				Status: types.InstrumentTrading,
+			})
+		}
+		return instruments, nil
+	})
+}
+
+// GetAllTickers 把全部 market 按 upbitBatchSize 分组，每组一次 ticker 和一次 orderbook 请求
+func (u *Upbit) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	instruments, err := u.GetInstruments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	index := symbolIndex(instruments, true)
+	markets := make([]string, 0, len(index))
+	for market := range index {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+
+	result := make(map[types.Symbol]types.Ticker, len(markets))
+	for start := 0; start < len(markets); start += upbitBatchSize {
+		tickers, err := u.tickers(ctx, markets[start:min(start+upbitBatchSize, len(markets))])
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tickers {
+			result[index[t.Symbol]] = t
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package exchanges
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpbit_PrepareRequest(t *testing.T) {
+	upbit := NewUpbit(types.ExchangeConfig{
+		APIKey:    "test_key",
+		APISecret: "test_secret",
+	})
+
+	params := map[string]interface{}{
+		"market":  "KRW-BTC",
+		"uuids[]": []string{"9ca023a5-851b-4fec-9f0a-48cd83c2eaae", "2d2f6d5e-4b8f-44f1-a15d-7e4b5a6b3c2f"},
+	}
+
+	req, err := upbit.PrepareRequest("GET", "/v1/orders/uuids", params, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "market=KRW-BTC&uuids%5B%5D=9ca023a5-851b-4fec-9f0a-48cd83c2eaae&uuids%5B%5D=2d2f6d5e-4b8f-44f1-a15d-7e4b5a6b3c2f", req.URL.RawQuery)
+
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	parts := strings.Split(token, ".")
+	assert.Len(t, parts, 3)
+
+	mac := hmac.New(sha256.New, []byte("test_secret"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2])
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+
+	var claims map[string]string
+	assert.NoError(t, json.Unmarshal(payload, &claims))
+
+	hash := sha512.Sum512([]byte("market=KRW-BTC&uuids[]=9ca023a5-851b-4fec-9f0a-48cd83c2eaae&uuids[]=2d2f6d5e-4b8f-44f1-a15d-7e4b5a6b3c2f"))
+	assert.Equal(t, "test_key", claims["access_key"])
+	assert.Equal(t, hex.EncodeToString(hash[:]), claims["query_hash"])
+	assert.Equal(t, "SHA512", claims["query_hash_alg"])
+	assert.Len(t, claims["nonce"], 36)
+}
@@ -0,0 +1,267 @@
+package exchanges
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+	"github.com/shopspring/decimal"
+)
+
+// upbitSide 把统一的方向转换为 Upbit 的 bid/ask
+func upbitSide(side types.Side) string {
+	if side == types.Buy {
+		return "bid"
+	}
+	return "ask"
+}
+
+// orderParams 把统一的下单请求转换为 /v1/orders 参数。市价买单的 ord_type 为 price，只能按计价币金额下单；
+// 市价卖单的 ord_type 为 market，按基础币数量下单。Upbit 不支持 post-only 和条件单
+func (u *Upbit) orderParams(req types.OrderRequest) (map[string]interface{}, error) {
+	if req.Type.Conditional() {
+		return nil, notSupported(u, "conditional orders")
+	}
+	if req.PostOnly {
+		return nil, notSupported(u, "post-only orders")
+	}
+	if req.ReduceOnly {
+		return nil, notSupported(u, "reduce-only spot orders")
+	}
+
+	params := map[string]interface{}{
+		"market":     req.Symbol,
+		"side":       upbitSide(req.Side),
+		"identifier": req.ClientOrderID,
+	}
+	if req.Type == types.LimitOrder {
+		params["ord_type"] = "limit"
+		params["volume"] = req.Quantity.String()
+		params["price"] = req.Price.String()
+		if tif := timeInForce(req); tif != types.GTC {
+			params["time_in_force"] = strings.ToLower(string(tif))
+		}
+		return params, nil
+	}
+
+	switch {
+	case req.Side == types.Buy && !req.QuoteQuantity.IsPositive():
+		return nil, notSupported(u, "market buys by base quantity")
+	case req.Side == types.Sell && req.QuoteQuantity.IsPositive():
+		return nil, notSupported(u, "market sells by quote quantity")
+	case req.Side == types.Buy:
+		params["ord_type"] = "price"
+		params["price"] = req.QuoteQuantity.String()
+	default:
+		params["ord_type"] = "market"
+		params["volume"] = req.Quantity.String()
+	}
+	return params, nil
+}
+
+func (u *Upbit) placer() placer {
+	return placer{t: u, config: u.config, idLength: clientOrderIDLength, place: u.placeOrder}
+}
+
+// PlaceOrder 的 symbol 为 Upbit 市场代码，例如 KRW-BTC
+func (u *Upbit) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	return u.placer().placeOrder(ctx, req)
+}
+
+// PlaceOrders 并发地逐个下单，Upbit 没有批量下单接口
+func (u *Upbit) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	return u.placer().placeOrders(ctx, reqs), nil
+}
+
+func (u *Upbit) placeOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	params, err := u.orderParams(req)
+	if err != nil {
+		return types.Order{}, err
+	}
+
+	var ack struct {
+		UUID string `json:"uuid"`
+	}
+	if err := sendRequest(ctx, u, "POST", "/v1/orders", params, true, &ack); err != nil {
+		return types.Order{}, err
+	}
+	return orderFromRequest(req, ack.UUID), nil
+}
+
+// AmendOrder 通过撤单再下单实现。Upbit 没有 post-only 订单，PostOnly 在撤单前就拒绝，避免撤单后无法下出新订单
+func (u *Upbit) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	if req.PostOnly {
+		return types.AmendResult{}, notSupported(u, "post-only orders")
+	}
+	return replaceOrder(ctx, u, req)
+}
+
+// upbitOrderNotFound 是订单不存在时错误响应中的 error.name
+const upbitOrderNotFound = "order_not_found"
+
+// upbitPageSize 是订单列表每页的最大数量
+const upbitPageSize = 100
+
+// upbitOrder 是订单接口的订单。ord_type 为 price 的市价买单用 price 表示计价币金额，为 market 的市价卖单只有 volume；
+// state 中 wait、watch 是挂单，done 是全部成交，cancel 是已撤销（可能部分成交）
+type upbitOrder struct {
+	UUID           string      `json:"uuid"`
+	Identifier     string      `json:"identifier"`
+	Market         string      `json:"market"`
+	Side           string      `json:"side"`
+	OrdType        string      `json:"ord_type"`
+	Price          jsonDecimal `json:"price"`
+	Volume         jsonDecimal `json:"volume"`
+	ExecutedVolume jsonDecimal `json:"executed_volume"`
+	ExecutedFunds  jsonDecimal `json:"executed_funds"`
+	PaidFee        jsonDecimal `json:"paid_fee"`
+	State          string      `json:"state"`
+	TimeInForce    string      `json:"time_in_force"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+var upbitStatuses = map[string]types.OrderStatus{
+	"done":   types.OrderFilled,
+	"cancel": types.OrderCanceled,
+}
+
+func (o upbitOrder) toOrder() types.Order {
+	order := types.Order{
+		ID:             o.UUID,
+		ClientOrderID:  o.Identifier,
+		Symbol:         o.Market,
+		Side:           parseSide(o.Side),
+		Type:           types.LimitOrder,
+		TimeInForce:    types.GTC,
+		Price:          o.Price.Decimal,
+		Quantity:       o.Volume.Decimal,
+		Status:         orderStatus(upbitStatuses, o.State),
+		FilledQuantity: o.ExecutedVolume.Decimal,
+		AveragePrice:   averagePrice(o.ExecutedFunds.Decimal, o.ExecutedVolume.Decimal),
+		Fee:            o.PaidFee.Decimal,
+		Raw:            o.State,
+		CreatedAt:      o.CreatedAt,
+	}
+	if o.State == "wait" || o.State == "watch" {
+		order.Status = openStatus(o.ExecutedVolume.Decimal)
+	}
+	if o.TimeInForce != "" {
+		order.TimeInForce = types.TimeInForce(strings.ToUpper(o.TimeInForce))
+	}
+
+	// 手续费以计价币支付，市场代码的格式为 计价币-基础币
+	order.FeeAsset, _, _ = strings.Cut(o.Market, "-")
+	switch o.OrdType {
+	case "price":
+		order.Type, order.TimeInForce = types.MarketOrder, ""
+		order.QuoteQuantity, order.Price = o.Price.Decimal, decimal.Zero
+	case "market":
+		order.Type, order.TimeInForce = types.MarketOrder, ""
+	}
+	return order
+}
+
+// upbitRef 返回按 uuid 或 identifier 指定订单的参数
+func upbitRef(ref types.OrderRef) map[string]interface{} {
+	if ref.ID != "" {
+		return map[string]interface{}{"uuid": ref.ID}
+	}
+	return map[string]interface{}{"identifier": ref.ClientOrderID}
+}
+
+func (u *Upbit) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	if err := ref.Validate(); err != nil {
+		return err
+	}
+
+	var order upbitOrder
+	return orderNotFound(sendRequest(ctx, u, "DELETE", "/v1/order", upbitRef(ref), true, &order), upbitOrderNotFound)
+}
+
+// CancelOrders 并发地逐个撤单
+func (u *Upbit) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	return cancelOrders(ctx, symbol, refs, 0, nil, u.CancelOrder), nil
+}
+
+// CancelAllOrders 列出挂单后逐个撤销
+func (u *Upbit) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	orders, err := u.openOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return cancelEach(ctx, orders, func(ctx context.Context, order types.Order) error {
+		return u.CancelOrder(ctx, order.Symbol, types.OrderRef{ID: order.ID})
+	}), nil
+}
+
+// listOrders 按 page 翻页读取 endpoint 的订单，limit 为 0 时读取全部
+func (u *Upbit) listOrders(ctx context.Context, endpoint string, params map[string]interface{}, limit int) ([]types.Order, error) {
+	params["limit"] = upbitPageSize
+	var orders []types.Order
+	for page := 1; limit <= 0 || len(orders) < limit; page++ {
+		params["page"] = page
+		var rows []upbitOrder
+		if err := sendRequest(ctx, u, "GET", endpoint, params, true, &rows); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			orders = append(orders, row.toOrder())
+		}
+		if len(rows) < upbitPageSize {
+			break
+		}
+	}
+	return orders, nil
+}
+
+func (u *Upbit) openOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	params := map[string]interface{}{"states": []string{"wait", "watch"}}
+	if symbol != "" {
+		params["market"] = symbol
+	}
+	return u.listOrders(ctx, "/v1/orders/open", params, 0)
+}
+
+// upbitClosedLimit 是 /v1/orders/closed 一次返回的最大订单数
+const upbitClosedLimit = 1000
+
+func (u *Upbit) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	if err := ref.Validate(); err != nil {
+		return types.Order{}, err
+	}
+
+	var order upbitOrder
+	if err := sendRequest(ctx, u, "GET", "/v1/order", upbitRef(ref), true, &order); err != nil {
+		return types.Order{}, orderNotFound(err, upbitOrderNotFound)
+	}
+	return order.toOrder(), nil
+}
+
+func (u *Upbit) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	return u.openOrders(ctx, symbol)
+}
+
+// GetOrderHistory 使用 /v1/orders/closed，时间范围最长 7 天，只返回最新的 1000 个订单
+func (u *Upbit) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	params := map[string]interface{}{"states": []string{"done", "cancel"}, "limit": upbitClosedLimit}
+	if req.Symbol != "" {
+		params["market"] = req.Symbol
+	}
+	if !req.Start.IsZero() {
+		params["start_time"] = req.Start.Format(time.RFC3339)
+	}
+	if !req.End.IsZero() {
+		params["end_time"] = req.End.Format(time.RFC3339)
+	}
+
+	var rows []upbitOrder
+	if err := sendRequest(ctx, u, "GET", "/v1/orders/closed", params, true, &rows); err != nil {
+		return nil, err
+	}
+	orders := make([]types.Order, len(rows))
+	for i, row := range rows {
+		orders[i] = row.toOrder()
+	}
+	return orderHistory(orders, req), nil
+}
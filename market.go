@@ -0,0 +1,136 @@
+package cryptoexchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+func (c *CryptoExchangeClient) marketData() (types.MarketData, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	md, ok := c.exchange.(types.MarketData)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "market data is not supported"}
+	}
+	return md, nil
+}
+
+// GetTicker 获取统一格式的行情，symbol 使用交易所原生格式
+func (c *CryptoExchangeClient) GetTicker(ctx context.Context, symbol string) (types.Ticker, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return types.Ticker{}, err
+	}
+	return md.GetTicker(ctx, symbol)
+}
+
+// GetOrderBook 获取订单簿快照，depth 会映射到交易所允许的最接近档位
+func (c *CryptoExchangeClient) GetOrderBook(ctx context.Context, symbol string, depth int) (types.OrderBook, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return types.OrderBook{}, err
+	}
+	return md.GetOrderBook(ctx, symbol, depth)
+}
+
+// GetCandles 获取 K 线，interval 使用统一的 types.Interval
+func (c *CryptoExchangeClient) GetCandles(ctx context.Context, symbol string, interval types.Interval, start, end time.Time, limit int) ([]types.Candle, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return nil, err
+	}
+	return md.GetCandles(ctx, symbol, interval, start, end, limit)
+}
+
+// GetRecentTrades 获取最近的公开成交，Side 为 taker 方向
+func (c *CryptoExchangeClient) GetRecentTrades(ctx context.Context, symbol string, limit int) ([]types.Trade, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return nil, err
+	}
+	return md.GetRecentTrades(ctx, symbol, limit)
+}
+
+// GetHistoricalTrades 分页获取历史成交，把返回的 Cursor 填回请求即可获取下一页
+func (c *CryptoExchangeClient) GetHistoricalTrades(ctx context.Context, req types.HistoricalTradesRequest) (types.TradePage, error) {
+	if c.exchange == nil {
+		return types.TradePage{}, &ExchangeError{Message: "no exchange added"}
+	}
+
+	ht, ok := c.exchange.(types.HistoricalTrades)
+	if !ok {
+		return types.TradePage{}, &ExchangeError{Exchange: c.exchange.Name(), Message: "historical trades are not supported"}
+	}
+	return ht.GetHistoricalTrades(ctx, req)
+}
+
+// GetInstruments 获取当前产品线全部交易对的精度与下单限制，结果按 ExchangeConfig.InstrumentTTL 缓存
+func (c *CryptoExchangeClient) GetInstruments(ctx context.Context) ([]types.Instrument, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return nil, err
+	}
+	return md.GetInstruments(ctx)
+}
+
+// GetAllTickers 一次获取当前产品线全部交易对的行情，以标准 Symbol 为键；
+// 没有批量接口的交易所会在限速内逐个查询
+func (c *CryptoExchangeClient) GetAllTickers(ctx context.Context) (map[types.Symbol]types.Ticker, error) {
+	md, err := c.marketData()
+	if err != nil {
+		return nil, err
+	}
+	return md.GetAllTickers(ctx)
+}
+
+func (c *CryptoExchangeClient) perpetuals() (types.Perpetuals, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	p, ok := c.exchange.(types.Perpetuals)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "perpetuals are not supported"}
+	}
+	return p, nil
+}
+
+// GetFundingRate 获取永续合约当前周期的资金费率、结算时间和结算间隔
+func (c *CryptoExchangeClient) GetFundingRate(ctx context.Context, symbol string) (types.FundingRate, error) {
+	p, err := c.perpetuals()
+	if err != nil {
+		return types.FundingRate{}, err
+	}
+	return p.GetFundingRate(ctx, symbol)
+}
+
+// GetFundingHistory 获取已结算的历史资金费率，按时间升序排列
+func (c *CryptoExchangeClient) GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]types.FundingRecord, error) {
+	p, err := c.perpetuals()
+	if err != nil {
+		return nil, err
+	}
+	return p.GetFundingHistory(ctx, symbol, start, end, limit)
+}
+
+// GetMarkPrice 获取永续合约的标记价格
+func (c *CryptoExchangeClient) GetMarkPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := c.perpetuals()
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return p.GetMarkPrice(ctx, symbol)
+}
+
+// GetIndexPrice 获取永续合约的指数价格
+func (c *CryptoExchangeClient) GetIndexPrice(ctx context.Context, symbol string) (types.ReferencePrice, error) {
+	p, err := c.perpetuals()
+	if err != nil {
+		return types.ReferencePrice{}, err
+	}
+	return p.GetIndexPrice(ctx, symbol)
+}
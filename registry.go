@@ -0,0 +1,57 @@
+package cryptoexchange
+
+import (
+	"github.com/hedeqiang/cryptoexchange/exchanges"
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+type exchangeFactory struct {
+	newExchange func(config types.ExchangeConfig) types.Exchange
+	markets     []types.Market
+}
+
+var (
+	allMarkets  = []types.Market{types.Spot, types.USDMFutures, types.CoinMFutures, types.Options}
+	spotOnly    = []types.Market{types.Spot}
+	spotAndUSDM = []types.Market{types.Spot, types.USDMFutures}
+	spotAndPerp = []types.Market{types.Spot, types.USDMFutures, types.CoinMFutures}
+)
+
+// registry 记录每个交易所的构造函数以及它支持的产品线
+var registry = map[types.ExchangeName]exchangeFactory{
+	types.Binance:     {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBinance(c) }, allMarkets},
+	types.BinanceUS:   {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBinanceUS(c) }, spotOnly},
+	types.OKX:         {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewOKX(c) }, spotAndPerp},
+	types.Bitget:      {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBitget(c) }, spotAndPerp},
+	types.Kucoin:      {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewKucoin(c) }, spotAndPerp},
+	types.MEXC:        {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewMEXC(c) }, spotAndPerp},
+	types.Gate:        {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewGate(c) }, spotAndPerp},
+	types.Kraken:      {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewKraken(c) }, spotAndPerp},
+	types.Bybit:       {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBybit(c) }, spotAndPerp},
+	types.Huobi:       {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewHuobi(c) }, spotAndPerp},
+	types.Coinbase:    {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewCoinbase(c) }, spotOnly},
+	types.BTSE:        {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBTSE(c) }, spotAndUSDM},
+	types.Gemini:      {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewGemini(c) }, spotOnly},
+	types.Upbit:       {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewUpbit(c) }, spotOnly},
+	types.CryptoCom:   {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewCryptoCom(c) }, spotAndUSDM},
+	types.BitMart:     {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewBitMart(c) }, spotAndUSDM},
+	types.Hyperliquid: {func(c types.ExchangeConfig) types.Exchange { return exchanges.NewHyperliquid(c) }, spotAndUSDM},
+}
+
+// SupportedMarkets 返回交易所支持的产品线，未注册的交易所返回 nil
+func SupportedMarkets(name types.ExchangeName) []types.Market {
+	factory, ok := registry[name]
+	if !ok {
+		return nil
+	}
+	return factory.markets
+}
+
+func (f exchangeFactory) supports(market types.Market) bool {
+	for _, m := range f.markets {
+		if m == market {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package cryptoexchange
+
+import (
+	"context"
+
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+func (c *CryptoExchangeClient) symbolMapper() (types.SymbolMapper, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	m, ok := c.exchange.(types.SymbolMapper)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "symbol mapping is not supported"}
+	}
+	return m, nil
+}
+
+// NativeSymbol 把标准交易对转换为当前交易所和产品线的原生交易对，例如 BTC/USDT -> BTC-USDT-SWAP
+func (c *CryptoExchangeClient) NativeSymbol(ctx context.Context, symbol types.Symbol) (string, error) {
+	m, err := c.symbolMapper()
+	if err != nil {
+		return "", err
+	}
+	return m.NativeSymbol(ctx, symbol)
+}
+
+// CanonicalSymbol 把原生交易对转换为标准交易对，例如 XXBTZUSD -> BTC/USD
+func (c *CryptoExchangeClient) CanonicalSymbol(ctx context.Context, native string) (types.Symbol, error) {
+	m, err := c.symbolMapper()
+	if err != nil {
+		return types.Symbol{}, err
+	}
+	return m.CanonicalSymbol(ctx, native)
+}
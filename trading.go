@@ -0,0 +1,160 @@
+package cryptoexchange
+
+import (
+	"context"
+	"time"
+
+	"github.com/hedeqiang/cryptoexchange/exchanges"
+	"github.com/hedeqiang/cryptoexchange/types"
+)
+
+// GetTradingFees 获取账户在 symbols 上的 maker/taker 费率，symbols 为空时返回当前产品线的全部交易对
+func (c *CryptoExchangeClient) GetTradingFees(ctx context.Context, symbols ...string) (map[string]types.TradingFee, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	tf, ok := c.exchange.(types.TradingFees)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "trading fees are not supported"}
+	}
+	return tf.GetTradingFees(ctx, symbols...)
+}
+
+// PlaceOrder 校验并提交订单，参数组合不合法时返回包装了 types.ErrInvalidOrder 的错误
+func (c *CryptoExchangeClient) PlaceOrder(ctx context.Context, req types.OrderRequest) (types.Order, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return types.Order{}, err
+	}
+	return tr.PlaceOrder(ctx, req)
+}
+
+// PlaceOrders 批量下单，交易所支持时按其上限分批调用批量接口，否则并发地逐个下单；
+// 结果与 reqs 一一对应，单个订单的错误记录在对应的 OrderResult 中
+func (c *CryptoExchangeClient) PlaceOrders(ctx context.Context, reqs []types.OrderRequest) ([]types.OrderResult, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return nil, err
+	}
+	return tr.PlaceOrders(ctx, reqs)
+}
+
+// AmendOrder 修改订单的价格或数量。交易所原生支持时原地修改或在一次请求中撤单重下；
+// 否则用撤单再下单模拟，结果的 Emulated 为 true，原订单已撤销但新订单失败时返回包装了 types.ErrAmendIncomplete 的错误
+func (c *CryptoExchangeClient) AmendOrder(ctx context.Context, req types.AmendRequest) (types.AmendResult, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return types.AmendResult{}, err
+	}
+	return tr.AmendOrder(ctx, req)
+}
+
+// CancelOrder 撤销 symbol 上由 ref 指定的订单，ref 的 ID 与 ClientOrderID 至少填一个
+func (c *CryptoExchangeClient) CancelOrder(ctx context.Context, symbol string, ref types.OrderRef) error {
+	tr, err := c.trading()
+	if err != nil {
+		return err
+	}
+	return tr.CancelOrder(ctx, symbol, ref)
+}
+
+// CancelOrders 批量撤销 symbol 上由 refs 指定的订单，结果与 refs 一一对应
+func (c *CryptoExchangeClient) CancelOrders(ctx context.Context, symbol string, refs []types.OrderRef) ([]types.CancelResult, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return nil, err
+	}
+	return tr.CancelOrders(ctx, symbol, refs)
+}
+
+// CancelAllOrders 撤销 symbol 上的全部挂单，symbol 为空时撤销当前产品线的全部挂单，
+// 每个订单的结果记录在返回的 CancelResult 中
+func (c *CryptoExchangeClient) CancelAllOrders(ctx context.Context, symbol string) ([]types.CancelResult, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return nil, err
+	}
+	return tr.CancelAllOrders(ctx, symbol)
+}
+
+// GetOrder 查询 symbol 上由 ref 指定的订单，订单不存在时返回包装了 types.ErrOrderNotFound 的错误
+func (c *CryptoExchangeClient) GetOrder(ctx context.Context, symbol string, ref types.OrderRef) (types.Order, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return types.Order{}, err
+	}
+	return tr.GetOrder(ctx, symbol, ref)
+}
+
+// GetOpenOrders 返回 symbol 上的挂单，symbol 为空时返回当前产品线的全部挂单
+func (c *CryptoExchangeClient) GetOpenOrders(ctx context.Context, symbol string) ([]types.Order, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetOpenOrders(ctx, symbol)
+}
+
+// GetOrderHistory 返回已结束的订单，按创建时间升序排列
+func (c *CryptoExchangeClient) GetOrderHistory(ctx context.Context, req types.OrderHistoryRequest) ([]types.Order, error) {
+	tr, err := c.trading()
+	if err != nil {
+		return nil, err
+	}
+	return tr.GetOrderHistory(ctx, req)
+}
+
+// GetFills 获取 symbol 上 since 之后的成交记录，自动翻页并按时间升序排列；symbol 为空时返回当前产品线的全部交易对
+func (c *CryptoExchangeClient) GetFills(ctx context.Context, symbol string, since time.Time) ([]types.Fill, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	f, ok := c.exchange.(types.Fills)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "fills are not supported"}
+	}
+	return f.GetFills(ctx, symbol, since)
+}
+
+// GetBalances 获取账户中余额不为零的资产，以资产代码为键
+func (c *CryptoExchangeClient) GetBalances(ctx context.Context) (map[string]types.Balance, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	b, ok := c.exchange.(types.Balances)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "balances are not supported"}
+	}
+	return b.GetBalances(ctx)
+}
+
+// UsePaperTrading 把当前交易所换成模拟交易：行情仍来自真实交易所，下单、撤单、成交和余额在本地模拟，
+// 调用代码无需修改。返回的 PaperExchange 需要运行 Run 才会撮合挂单
+func (c *CryptoExchangeClient) UsePaperTrading(config exchanges.PaperConfig) (*exchanges.PaperExchange, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	paper, err := exchanges.NewPaperExchange(c.exchange, config)
+	if err != nil {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: err.Error()}
+	}
+	c.exchange = paper
+	return paper, nil
+}
+
+// trading 返回支持统一下单接口的交易所
+func (c *CryptoExchangeClient) trading() (types.Trading, error) {
+	if c.exchange == nil {
+		return nil, &ExchangeError{Message: "no exchange added"}
+	}
+
+	tr, ok := c.exchange.(types.Trading)
+	if !ok {
+		return nil, &ExchangeError{Exchange: c.exchange.Name(), Message: "trading is not supported"}
+	}
+	return tr, nil
+}
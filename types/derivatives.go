@@ -0,0 +1,41 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FundingRate 是永续合约当前周期的资金费率，Rate 为小数形式（0.0001 即 0.01%）
+type FundingRate struct {
+	Symbol          string
+	Rate            decimal.Decimal
+	NextFundingTime time.Time     // 当前费率的结算时间
+	Interval        time.Duration // 结算间隔，交易所未提供时为零值
+	Timestamp       time.Time
+}
+
+// FundingRecord 是一次已结算的资金费率
+type FundingRecord struct {
+	Symbol string
+	Rate   decimal.Decimal
+	Time   time.Time
+}
+
+// ReferencePrice 是标记价格或指数价格
+type ReferencePrice struct {
+	Symbol    string
+	Price     decimal.Decimal
+	Timestamp time.Time
+}
+
+// Perpetuals 由提供永续合约的交易所实现，仅在 USDMFutures、CoinMFutures 产品线下可用，
+// 其他产品线返回包装了 ErrNotSupported 的错误
+type Perpetuals interface {
+	GetFundingRate(ctx context.Context, symbol string) (FundingRate, error)
+	// GetFundingHistory 返回按时间升序排列的历史资金费率，start、end 为零值时不限制，limit <= 0 时使用交易所默认数量
+	GetFundingHistory(ctx context.Context, symbol string, start, end time.Time, limit int) ([]FundingRecord, error)
+	GetMarkPrice(ctx context.Context, symbol string) (ReferencePrice, error)
+	GetIndexPrice(ctx context.Context, symbol string) (ReferencePrice, error)
+}
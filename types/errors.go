@@ -0,0 +1,43 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotSupported 表示交易所或当前产品线不支持该功能
+var ErrNotSupported = errors.New("not supported")
+
+// ErrSymbolNotFound 表示交易所当前产品线没有对应的交易对
+var ErrSymbolNotFound = errors.New("symbol not found")
+
+// ErrInvalidOrder 表示订单参数在本地校验时不合法，没有发送到交易所
+var ErrInvalidOrder = errors.New("invalid order")
+
+// ErrOrderNotFound 表示交易所找不到指定的订单
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrOrderStatusUnknown 表示下单请求超时或失败后按自定义订单号也查不到订单，订单可能已提交也可能没有；
+// 调用方可以稍后用同一个 ClientOrderID 查询，确认不存在后再重新下单
+var ErrOrderStatusUnknown = errors.New("order status unknown")
+
+// ErrInsufficientBalance 表示可用余额不足以冻结订单所需的资金
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+// ErrAmendIncomplete 表示撤单重下模拟修改订单时原订单已撤销，但没有下出新订单
+var ErrAmendIncomplete = errors.New("order canceled but not replaced")
+
+// APIError 表示交易所返回的错误，既包括非 200 的 HTTP 状态，也包括 200 响应中的业务错误码
+type APIError struct {
+	StatusCode int
+	Body       string
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("API request failed with status %d, code %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Body)
+}
@@ -0,0 +1,191 @@
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Ticker 是统一后的行情快照，交易所未提供的字段保持零值
+type Ticker struct {
+	Symbol      string
+	Last        decimal.Decimal
+	Bid         decimal.Decimal
+	Ask         decimal.Decimal
+	Volume      decimal.Decimal // 24 小时成交量，以基础币计
+	QuoteVolume decimal.Decimal // 24 小时成交额，以计价币计
+	Timestamp   time.Time       // 交易所行情时间，交易所不返回时为零值
+}
+
+// PriceLevel 是订单簿中的一档价格
+type PriceLevel struct {
+	Price decimal.Decimal
+	Size  decimal.Decimal
+}
+
+// OrderBook 是订单簿快照，Bids 按价格从高到低排序，Asks 按价格从低到高排序
+type OrderBook struct {
+	Symbol    string
+	Bids      []PriceLevel
+	Asks      []PriceLevel
+	Sequence  int64     // 交易所的序列号或更新 ID，不提供时为 0
+	Timestamp time.Time // 交易所快照时间，交易所不返回时为零值
+}
+
+// Interval 是统一的 K 线周期
+type Interval string
+
+const (
+	Interval1m  Interval = "1m"
+	Interval3m  Interval = "3m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval30m Interval = "30m"
+	Interval1h  Interval = "1h"
+	Interval2h  Interval = "2h"
+	Interval4h  Interval = "4h"
+	Interval6h  Interval = "6h"
+	Interval8h  Interval = "8h"
+	Interval12h Interval = "12h"
+	Interval1d  Interval = "1d"
+	Interval3d  Interval = "3d"
+	Interval1w  Interval = "1w"
+	Interval1M  Interval = "1M"
+)
+
+var intervalDurations = map[Interval]time.Duration{
+	Interval1m:  time.Minute,
+	Interval3m:  3 * time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval30m: 30 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval2h:  2 * time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval6h:  6 * time.Hour,
+	Interval8h:  8 * time.Hour,
+	Interval12h: 12 * time.Hour,
+	Interval1d:  24 * time.Hour,
+	Interval3d:  3 * 24 * time.Hour,
+	Interval1w:  7 * 24 * time.Hour,
+	Interval1M:  30 * 24 * time.Hour,
+}
+
+// Duration 返回周期长度，1M 按 30 天近似；未知周期返回 0
+func (i Interval) Duration() time.Duration {
+	return intervalDurations[i]
+}
+
+// Valid 判断是否为已定义的周期
+func (i Interval) Valid() bool {
+	_, ok := intervalDurations[i]
+	return ok
+}
+
+// Truncate 返回 t 所在周期的起始时间（UTC）：周线从周一开始，月线按自然月，其余按 Unix 纪元对齐
+func (i Interval) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	switch i {
+	case Interval1M:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case Interval1w:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+
+	d := int64(i.Duration() / time.Second)
+	if d == 0 {
+		return t
+	}
+	unix := t.Unix()
+	return time.Unix(unix-((unix%d)+d)%d, 0).UTC()
+}
+
+// Candle 是一根 K 线，OpenTime 为周期起始时间
+type Candle struct {
+	OpenTime    time.Time
+	Open        decimal.Decimal
+	High        decimal.Decimal
+	Low         decimal.Decimal
+	Close       decimal.Decimal
+	Volume      decimal.Decimal // 以基础币计
+	QuoteVolume decimal.Decimal // 以计价币计，交易所不提供时为零值
+}
+
+// Side 表示买卖方向
+type Side string
+
+const (
+	Buy  Side = "BUY"
+	Sell Side = "SELL"
+)
+
+// Trade 是一笔公开成交，Side 为主动成交（taker）一方的方向。
+// 合约市场中部分交易所的 Size 为合约张数。
+type Trade struct {
+	ID    string
+	Price decimal.Decimal
+	Size  decimal.Decimal
+	Side  Side
+	Time  time.Time
+}
+
+// InstrumentStatus 是交易对的交易状态
+type InstrumentStatus string
+
+const (
+	InstrumentTrading    InstrumentStatus = "TRADING"
+	InstrumentPreTrading InstrumentStatus = "PRE_TRADING" // 已上架但尚未开放交易
+	InstrumentHalted     InstrumentStatus = "HALTED"      // 暂停、只允许撤单或已下架
+)
+
+// Instrument 是交易对的元数据，数值字段为零值表示交易所未提供该限制
+type Instrument struct {
+	Symbol       string // 交易所原生格式
+	Base         string // 交易所返回的基础币名称，未做别名转换
+	Quote        string
+	TickSize     decimal.Decimal // 价格最小变动单位
+	StepSize     decimal.Decimal // 数量最小变动单位
+	MinQty       decimal.Decimal
+	MaxQty       decimal.Decimal
+	MinNotional  decimal.Decimal // 最小下单金额，以计价币计
+	ContractSize decimal.Decimal // 每张合约对应的基础币（反向合约为计价币）数量，现货为零值
+	Status       InstrumentStatus
+}
+
+// MarketData 是各交易所统一的公开行情接口，symbol 使用交易所原生格式
+type MarketData interface {
+	GetTicker(ctx context.Context, symbol string) (Ticker, error)
+	// GetOrderBook 的 depth 会映射到交易所允许的最接近档位，返回结果最多 depth 档；depth <= 0 时使用交易所支持的最大档位
+	GetOrderBook(ctx context.Context, symbol string, depth int) (OrderBook, error)
+	// GetCandles 返回按 OpenTime 升序排列的 K 线；start、end 为零值时不限制，limit <= 0 时使用交易所默认数量。
+	// 交易所不支持的周期返回包装了 ErrNotSupported 的错误，开启 ExchangeConfig.ResampleCandles 后改为由更细的周期合成。
+	GetCandles(ctx context.Context, symbol string, interval Interval, start, end time.Time, limit int) ([]Candle, error)
+	// GetRecentTrades 返回按时间升序排列的最近成交，limit <= 0 时使用交易所默认数量
+	GetRecentTrades(ctx context.Context, symbol string, limit int) ([]Trade, error)
+	// GetInstruments 返回当前产品线的全部交易对，结果按 ExchangeConfig.InstrumentTTL 缓存
+	GetInstruments(ctx context.Context) ([]Instrument, error)
+	// GetAllTickers 返回当前产品线全部交易对的行情，以标准 Symbol 为键，Ticker.Symbol 仍为原生交易对
+	GetAllTickers(ctx context.Context) (map[Symbol]Ticker, error)
+}
+
+// HistoricalTradesRequest 描述历史成交的分页查询
+type HistoricalTradesRequest struct {
+	Symbol string
+	Since  time.Time // 只返回该时间之后的成交
+	Cursor string    // 上一页返回的 TradePage.Cursor，首页为空
+	Limit  int
+}
+
+// TradePage 是一页按时间升序排列的历史成交，Cursor 为空表示没有更多数据
+type TradePage struct {
+	Trades []Trade
+	Cursor string
+}
+
+// HistoricalTrades 由支持历史成交分页的交易所实现。分页方向取决于交易所：
+// Binance、Kraken 从 Since 向后翻页，OKX、Coinbase 从最新成交向前翻页直到 Since。
+type HistoricalTrades interface {
+	GetHistoricalTrades(ctx context.Context, req HistoricalTradesRequest) (TradePage, error)
+}
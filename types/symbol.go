@@ -0,0 +1,53 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Symbol 是与交易所无关的交易对表示，Base、Quote 为大写的标准资产代码
+type Symbol struct {
+	Base  string
+	Quote string
+}
+
+// NewSymbol 创建 Symbol，并把资产代码转换为标准形式
+func NewSymbol(base, quote string) Symbol {
+	return Symbol{Base: CanonicalAsset(base), Quote: CanonicalAsset(quote)}
+}
+
+// ParseSymbol 解析 BTC/USDT、BTC-USDT 或 BTC_USDT 形式的交易对
+func ParseSymbol(s string) (Symbol, error) {
+	for _, sep := range []string{"/", "-", "_"} {
+		if base, quote, ok := strings.Cut(s, sep); ok && base != "" && quote != "" {
+			return NewSymbol(base, quote), nil
+		}
+	}
+	return Symbol{}, fmt.Errorf("invalid symbol %q, expected BASE/QUOTE", s)
+}
+
+func (s Symbol) String() string {
+	return s.Base + "/" + s.Quote
+}
+
+// AssetAliases 把交易所使用的非标准资产代码映射到通用代码
+var AssetAliases = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// CanonicalAsset 返回大写并经过别名转换的资产代码，例如 xbt -> BTC
+func CanonicalAsset(asset string) string {
+	asset = strings.ToUpper(strings.TrimSpace(asset))
+	if alias, ok := AssetAliases[asset]; ok {
+		return alias
+	}
+	return asset
+}
+
+// SymbolMapper 在标准 Symbol 与交易所原生交易对之间转换，找不到时返回包装了 ErrSymbolNotFound 的错误
+type SymbolMapper interface {
+	NativeSymbol(ctx context.Context, symbol Symbol) (string, error)
+	CanonicalSymbol(ctx context.Context, native string) (Symbol, error)
+}
@@ -0,0 +1,508 @@
+package types
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TradingFee 是账户在某个交易对上的手续费率，小数形式（0.001 即 0.1%），负数表示返佣
+type TradingFee struct {
+	Symbol string
+	Maker  decimal.Decimal
+	Taker  decimal.Decimal
+}
+
+// TradingFees 由支持查询账户手续费率的交易所实现，需要配置 API Key
+type TradingFees interface {
+	// GetTradingFees 返回 symbols 的 maker/taker 费率，以原生交易对为键；symbols 为空时返回当前产品线的全部交易对
+	GetTradingFees(ctx context.Context, symbols ...string) (map[string]TradingFee, error)
+}
+
+// OrderType 是订单类型
+type OrderType string
+
+const (
+	LimitOrder  OrderType = "LIMIT"
+	MarketOrder OrderType = "MARKET"
+	// 条件单在最新价触及 TriggerPrice 后按市价或 Price 下单。止损单在价格向不利方向触及时触发
+	// （买单上涨、卖单下跌），止盈单在价格向有利方向触及时触发
+	StopMarketOrder       OrderType = "STOP_MARKET"
+	StopLimitOrder        OrderType = "STOP_LIMIT"
+	TakeProfitMarketOrder OrderType = "TAKE_PROFIT_MARKET"
+	TakeProfitLimitOrder  OrderType = "TAKE_PROFIT_LIMIT"
+	// OCOOrder 同时挂出 Price 上的限价止盈单和 TriggerPrice 上的止损单，一个成交或触发后另一个被撤销；
+	// StopLimitPrice 为零时止损单按市价成交
+	OCOOrder OrderType = "OCO"
+)
+
+// Conditional 判断订单是否需要 TriggerPrice
+func (t OrderType) Conditional() bool {
+	switch t {
+	case StopMarketOrder, StopLimitOrder, TakeProfitMarketOrder, TakeProfitLimitOrder, OCOOrder:
+		return true
+	}
+	return false
+}
+
+// Priced 判断订单是否按 Price 挂限价单：限价单、限价条件单和 OCO 的止盈部分
+func (t OrderType) Priced() bool {
+	switch t {
+	case LimitOrder, StopLimitOrder, TakeProfitLimitOrder, OCOOrder:
+		return true
+	}
+	return false
+}
+
+// Stop 判断订单是否是止损单
+func (t OrderType) Stop() bool {
+	return t == StopMarketOrder || t == StopLimitOrder
+}
+
+// TimeInForce 是限价单的有效方式
+type TimeInForce string
+
+const (
+	GTC TimeInForce = "GTC" // 一直有效直到取消
+	IOC TimeInForce = "IOC" // 立即成交，未成交部分取消
+	FOK TimeInForce = "FOK" // 全部成交，否则全部取消
+)
+
+// OrderStatus 是统一的订单状态
+type OrderStatus string
+
+const (
+	OrderNew             OrderStatus = "NEW"
+	OrderPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderFilled          OrderStatus = "FILLED"
+	OrderCanceled        OrderStatus = "CANCELED"
+	OrderRejected        OrderStatus = "REJECTED"
+	OrderExpired         OrderStatus = "EXPIRED"
+	// OrderStatusUnknown 表示无法识别的交易所状态，原始值保存在 Order.Raw 中
+	OrderStatusUnknown OrderStatus = "UNKNOWN"
+)
+
+// Final 判断订单是否已结束，已结束的订单不会再成交
+func (s OrderStatus) Final() bool {
+	switch s {
+	case OrderFilled, OrderCanceled, OrderRejected, OrderExpired:
+		return true
+	}
+	return false
+}
+
+// OrderRequest 描述一笔新订单，Symbol 为交易所原生交易对
+type OrderRequest struct {
+	Symbol string
+	Side   Side
+	Type   OrderType
+	// Quantity 是基础币数量，合约为交易所的数量单位（通常是张数）
+	Quantity decimal.Decimal
+	// QuoteQuantity 是按计价币金额下的市价单，与 Quantity 二选一
+	QuoteQuantity decimal.Decimal
+	// Price 是限价单价格，市价单忽略
+	Price decimal.Decimal
+	// TriggerPrice 是条件单的触发价
+	TriggerPrice decimal.Decimal
+	// StopLimitPrice 是 OCO 中止损单触发后的限价，为零时按市价成交
+	StopLimitPrice decimal.Decimal
+	// TimeInForce 为空时限价单按 GTC 处理
+	TimeInForce TimeInForce
+	// PostOnly 的限价单在会立即成交时被交易所拒绝或取消
+	PostOnly bool
+	// ReduceOnly 只减少合约持仓，现货不支持
+	ReduceOnly bool
+	// ClientOrderID 是自定义订单号，需要符合交易所的格式要求
+	ClientOrderID string
+}
+
+// Validate 在发送前检查参数组合，返回包装了 ErrInvalidOrder 的错误
+func (r OrderRequest) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidOrder, fmt.Sprintf(format, args...))
+	}
+
+	if r.Symbol == "" {
+		return invalid("symbol is required")
+	}
+	if r.Side != Buy && r.Side != Sell {
+		return invalid("unknown side %q", r.Side)
+	}
+	switch r.TimeInForce {
+	case "", GTC, IOC, FOK:
+	default:
+		return invalid("unknown time in force %q", r.TimeInForce)
+	}
+
+	if r.PostOnly && r.Type != LimitOrder {
+		return invalid("post-only is only supported for limit orders")
+	}
+	if r.Type.Conditional() != r.TriggerPrice.IsPositive() {
+		return invalid("trigger price must be positive for conditional orders and zero otherwise")
+	}
+	if !r.StopLimitPrice.IsZero() && (r.Type != OCOOrder || r.StopLimitPrice.IsNegative()) {
+		return invalid("stop limit price is only supported as a positive price on OCO orders")
+	}
+
+	switch r.Type {
+	case LimitOrder, StopLimitOrder, TakeProfitLimitOrder, OCOOrder:
+		if !r.Price.IsPositive() {
+			return invalid("%s order needs a positive price", r.Type)
+		}
+		if !r.Quantity.IsPositive() {
+			return invalid("%s order needs a positive quantity", r.Type)
+		}
+		if !r.QuoteQuantity.IsZero() {
+			return invalid("quote quantity is only supported for market orders")
+		}
+		if r.PostOnly && r.TimeInForce != "" && r.TimeInForce != GTC {
+			return invalid("post-only cannot be combined with %s", r.TimeInForce)
+		}
+	case MarketOrder, StopMarketOrder, TakeProfitMarketOrder:
+		if r.Quantity.IsPositive() == r.QuoteQuantity.IsPositive() {
+			return invalid("market order needs exactly one of quantity and quote quantity")
+		}
+	default:
+		return invalid("unknown order type %q", r.Type)
+	}
+
+	// OCO 的止盈价必须比触发价更有利：卖单高于触发价，买单低于触发价
+	if r.Type == OCOOrder && (r.Side == Sell) != r.Price.GreaterThan(r.TriggerPrice) {
+		return invalid("OCO %s take-profit price %s is on the wrong side of the stop trigger %s", r.Side, r.Price, r.TriggerPrice)
+	}
+	return nil
+}
+
+// Triggered 判断最新价 last 是否触发了条件单，OCO 判断的是其中的止损单
+func (r OrderRequest) Triggered(last decimal.Decimal) bool {
+	if !r.Type.Conditional() || !last.IsPositive() {
+		return false
+	}
+	if r.TriggersOnRise() {
+		return last.GreaterThanOrEqual(r.TriggerPrice)
+	}
+	return last.LessThanOrEqual(r.TriggerPrice)
+}
+
+// TriggersOnRise 判断条件单是否在价格上涨到触发价时触发：止损买单与止盈卖单在上涨时触发，
+// 止损卖单与止盈买单在下跌时触发，OCO 按其中的止损单判断
+func (r OrderRequest) TriggersOnRise() bool {
+	return (r.Side == Buy) == (r.Type.Stop() || r.Type == OCOOrder)
+}
+
+// TriggerOrder 返回条件单触发后提交的普通订单，OCO 返回其中的止损单
+func (r OrderRequest) TriggerOrder() OrderRequest {
+	order := r
+	order.TriggerPrice, order.StopLimitPrice = decimal.Zero, decimal.Zero
+	switch r.Type {
+	case StopMarketOrder, TakeProfitMarketOrder:
+		order.Type = MarketOrder
+	case StopLimitOrder, TakeProfitLimitOrder:
+		order.Type = LimitOrder
+	case OCOOrder:
+		order.Type, order.Price = MarketOrder, decimal.Zero
+		if r.StopLimitPrice.IsPositive() {
+			order.Type, order.Price = LimitOrder, r.StopLimitPrice
+		}
+	}
+	return order
+}
+
+// RoundingMode 是下单前把价格、数量对齐到最小变动单位的方式
+type RoundingMode string
+
+const (
+	// RoundExact 不取整，价格或数量不是最小变动单位的整数倍时拒绝订单
+	RoundExact   RoundingMode = ""
+	RoundDown    RoundingMode = "DOWN"
+	RoundUp      RoundingMode = "UP"
+	RoundNearest RoundingMode = "NEAREST"
+	// RoundPassive 让订单不比请求更激进：数量向下取整，买单价格向下、卖单价格向上取整
+	RoundPassive RoundingMode = "PASSIVE"
+)
+
+// PrepareOrder 按交易对的 TickSize、StepSize 取整价格和数量，并检查状态、最小/最大数量与最小下单金额，
+// 不满足时返回包装了 ErrInvalidOrder 的错误。ContractSize 大于零时数量以张计，下单金额按 价格×张数×ContractSize 计算；
+// 反向合约的交易所不提供最小下单金额。按数量下的市价单没有价格，不检查最小下单金额
+func (i Instrument) PrepareOrder(req OrderRequest, mode RoundingMode) (OrderRequest, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s %s", ErrInvalidOrder, i.Symbol, fmt.Sprintf(format, args...))
+	}
+
+	if i.Status != "" && i.Status != InstrumentTrading {
+		return req, invalid("is not trading (%s)", i.Status)
+	}
+
+	// 被动取整时，买单价格向下、卖单价格向上；触发价按同一方向取整
+	up := req.Side == Sell
+	roundPrice := func(name string, price *decimal.Decimal) error {
+		v, err := roundStep(*price, i.TickSize, mode, up)
+		if err != nil {
+			return invalid("%s %s: %v", name, *price, err)
+		}
+		if !v.IsPositive() {
+			return invalid("%s rounds to zero with tick size %s", name, i.TickSize)
+		}
+		*price = v
+		return nil
+	}
+
+	var err error
+	if req.Type.Priced() {
+		if err = roundPrice("price", &req.Price); err != nil {
+			return req, err
+		}
+	}
+	if req.TriggerPrice.IsPositive() {
+		if err = roundPrice("trigger price", &req.TriggerPrice); err != nil {
+			return req, err
+		}
+	}
+	if req.StopLimitPrice.IsPositive() {
+		if err = roundPrice("stop limit price", &req.StopLimitPrice); err != nil {
+			return req, err
+		}
+	}
+
+	if req.Quantity.IsPositive() {
+		qty := req.Quantity
+		if req.Quantity, err = roundStep(req.Quantity, i.StepSize, mode, false); err != nil {
+			return req, invalid("quantity %s: %v", qty, err)
+		}
+		if i.MinQty.IsPositive() && req.Quantity.LessThan(i.MinQty) {
+			return req, invalid("quantity %s is below the minimum %s", req.Quantity, i.MinQty)
+		}
+		if !req.Quantity.IsPositive() {
+			return req, invalid("quantity %s rounds to zero with step size %s", qty, i.StepSize)
+		}
+		if i.MaxQty.IsPositive() && req.Quantity.GreaterThan(i.MaxQty) {
+			return req, invalid("quantity %s is above the maximum %s", req.Quantity, i.MaxQty)
+		}
+	}
+
+	if i.MinNotional.IsPositive() {
+		notional := req.QuoteQuantity
+		if req.Type.Priced() {
+			notional = req.Quantity.Mul(req.Price)
+			if i.ContractSize.IsPositive() {
+				notional = notional.Mul(i.ContractSize)
+			}
+		}
+		if notional.IsPositive() && notional.LessThan(i.MinNotional) {
+			return req, invalid("notional %s is below the minimum %s", notional, i.MinNotional)
+		}
+	}
+	return req, nil
+}
+
+// roundStep 把 v 对齐到 step 的整数倍；RoundPassive 时 up 决定取整方向，RoundExact 时不对齐返回错误
+func roundStep(v, step decimal.Decimal, mode RoundingMode, up bool) (decimal.Decimal, error) {
+	if !step.IsPositive() {
+		return v, nil
+	}
+	rem := v.Mod(step)
+	if rem.IsZero() {
+		return v, nil
+	}
+
+	floor := v.Sub(rem)
+	switch mode {
+	case RoundExact:
+		return v, fmt.Errorf("not a multiple of %s", step)
+	case RoundDown:
+		return floor, nil
+	case RoundUp:
+		return floor.Add(step), nil
+	case RoundNearest:
+		if rem.Mul(decimal.NewFromInt(2)).GreaterThanOrEqual(step) {
+			return floor.Add(step), nil
+		}
+		return floor, nil
+	case RoundPassive:
+		if up {
+			return floor.Add(step), nil
+		}
+		return floor, nil
+	}
+	return v, fmt.Errorf("unknown rounding mode %q", mode)
+}
+
+// Order 是交易所返回的订单，PlaceOrder 只保证 ID 和请求中的字段，其余字段取决于交易所的响应
+type Order struct {
+	ID            string
+	ClientOrderID string
+	Symbol        string
+	Side          Side
+	Type          OrderType
+	TimeInForce   TimeInForce
+	Price         decimal.Decimal
+	// TriggerPrice 是条件单的触发价，普通订单为零
+	TriggerPrice  decimal.Decimal
+	Quantity      decimal.Decimal
+	QuoteQuantity decimal.Decimal
+	ReduceOnly    bool
+	// Status 在交易所没有返回状态时（如部分交易所的下单确认）为空
+	Status         OrderStatus
+	FilledQuantity decimal.Decimal
+	// AveragePrice 是成交均价，没有成交时为零
+	AveragePrice decimal.Decimal
+	// Fee 是已支付的手续费，负数表示返佣；交易所的订单接口不返回手续费时为零
+	Fee      decimal.Decimal
+	FeeAsset string
+	// Raw 是交易所的原始状态字符串
+	Raw       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrderHistoryRequest 描述历史订单查询，Start、End 为零值时不限制，Limit <= 0 时使用交易所默认数量
+type OrderHistoryRequest struct {
+	// Symbol 为空时查询当前产品线的全部交易对，部分交易所要求必填
+	Symbol string
+	Start  time.Time
+	End    time.Time
+	Limit  int
+}
+
+// OrderRef 指定一个已有订单，ID 与 ClientOrderID 至少填一个，都填时使用 ID
+type OrderRef struct {
+	ID            string
+	ClientOrderID string
+}
+
+// Validate 检查是否指定了订单，返回包装了 ErrInvalidOrder 的错误
+func (r OrderRef) Validate() error {
+	if r.ID == "" && r.ClientOrderID == "" {
+		return fmt.Errorf("%w: order id or client order id is required", ErrInvalidOrder)
+	}
+	return nil
+}
+
+// CancelResult 是批量撤单时单个订单的结果，Err 为 nil 表示已撤销；
+// 交易所没有返回的字段为空，例如 KuCoin 只返回订单号
+type CancelResult struct {
+	Symbol        string
+	OrderID       string
+	ClientOrderID string
+	Err           error
+}
+
+// AmendRequest 修改 Symbol 上由 Ref 指定的限价单，Price 与 Quantity 为零时不修改，至少指定一个
+type AmendRequest struct {
+	Symbol string
+	Ref    OrderRef
+	Price  decimal.Decimal
+	// Quantity 是修改后的订单总数量，包含已成交的部分
+	Quantity decimal.Decimal
+	// NewClientOrderID 是原订单被新订单代替时新订单的自定义订单号，为空时自动生成
+	NewClientOrderID string
+	// PostOnly 指定撤单重下模拟时新订单是否只做 maker，订单查询接口不一定返回该属性
+	PostOnly bool
+}
+
+func (r AmendRequest) Validate() error {
+	if r.Symbol == "" {
+		return fmt.Errorf("%w: symbol is required", ErrInvalidOrder)
+	}
+	if err := r.Ref.Validate(); err != nil {
+		return err
+	}
+	if r.Price.IsNegative() || r.Quantity.IsNegative() {
+		return fmt.Errorf("%w: price and quantity cannot be negative", ErrInvalidOrder)
+	}
+	if r.Price.IsZero() && r.Quantity.IsZero() {
+		return fmt.Errorf("%w: amend needs a new price or quantity", ErrInvalidOrder)
+	}
+	return nil
+}
+
+// AmendResult 是修改订单的结果
+type AmendResult struct {
+	// Order 是修改后的订单，交易所只返回订单号时其余字段来自请求
+	Order Order
+	// Replaced 表示原订单被撤销并由订单号不同的新订单代替，新订单失去原来的排队位置
+	Replaced bool
+	// Emulated 表示交易所不支持修改订单，改为先撤单再下单。两步之间原订单可能成交，
+	// 新订单按撤单时的剩余数量下单
+	Emulated bool
+}
+
+// OrderResult 是批量下单时单个订单的结果，Err 为 nil 表示已下单
+type OrderResult struct {
+	Order Order
+	Err   error
+}
+
+// Trading 由支持统一下单接口的交易所实现，需要配置 API Key
+type Trading interface {
+	// PlaceOrder 校验并提交订单，返回交易所分配的订单号
+	PlaceOrder(ctx context.Context, req OrderRequest) (Order, error)
+	// PlaceOrders 批量下单，结果与 reqs 一一对应。交易所支持时按其上限分批调用批量接口，否则并发地逐个下单；
+	// 返回的错误表示整个操作失败，单个订单的失败记录在对应的 OrderResult 中
+	PlaceOrders(ctx context.Context, reqs []OrderRequest) ([]OrderResult, error)
+	// AmendOrder 修改订单的价格或数量，优先使用交易所原生的修改接口以尽量保留排队位置
+	AmendOrder(ctx context.Context, req AmendRequest) (AmendResult, error)
+	// CancelOrder 撤销 symbol 上由 ref 指定的订单
+	CancelOrder(ctx context.Context, symbol string, ref OrderRef) error
+	// CancelOrders 批量撤销 symbol 上由 refs 指定的订单，结果与 refs 一一对应
+	CancelOrders(ctx context.Context, symbol string, refs []OrderRef) ([]CancelResult, error)
+	// CancelAllOrders 撤销 symbol 上的全部挂单，symbol 为空时撤销当前产品线的全部挂单。
+	// 返回的错误表示整个操作失败，单个订单的失败记录在对应的 CancelResult 中
+	CancelAllOrders(ctx context.Context, symbol string) ([]CancelResult, error)
+	// GetOrder 查询 symbol 上由 ref 指定的订单，订单不存在时返回包装了 ErrOrderNotFound 的错误
+	GetOrder(ctx context.Context, symbol string, ref OrderRef) (Order, error)
+	// GetOpenOrders 返回 symbol 上的挂单，symbol 为空时返回当前产品线的全部挂单
+	GetOpenOrders(ctx context.Context, symbol string) ([]Order, error)
+	// GetOrderHistory 返回已结束的订单，按 CreatedAt 升序排列，超过 Limit 时保留最新的订单
+	GetOrderHistory(ctx context.Context, req OrderHistoryRequest) ([]Order, error)
+}
+
+// Liquidity 表示成交时订单是挂单方（maker）还是吃单方（taker）
+type Liquidity string
+
+const (
+	Maker Liquidity = "MAKER"
+	Taker Liquidity = "TAKER"
+)
+
+// Fill 是账户的一笔成交，Fee 以 FeeAsset 计，负数表示返佣；交易所不返回成交方角色时 Liquidity 为空
+type Fill struct {
+	Symbol        string
+	OrderID       string
+	ClientOrderID string
+	TradeID       string
+	Side          Side
+	Price         decimal.Decimal
+	Quantity      decimal.Decimal
+	Fee           decimal.Decimal
+	FeeAsset      string
+	Liquidity     Liquidity
+	Time          time.Time
+}
+
+// Fills 由支持查询账户成交记录的交易所实现，需要配置 API Key
+type Fills interface {
+	// GetFills 自动翻页返回 symbol 上 since 之后的成交，按 Time 升序排列。since 为零值时返回交易所保留的全部成交，
+	// symbol 为空时返回当前产品线的全部交易对，要求指定交易对的交易所返回包装了 ErrNotSupported 的错误
+	GetFills(ctx context.Context, symbol string, since time.Time) ([]Fill, error)
+}
+
+// Balance 是账户中某个资产的余额，Free 为可用部分，Locked 为挂单冻结的部分
+type Balance struct {
+	Asset  string
+	Free   decimal.Decimal
+	Locked decimal.Decimal
+}
+
+// Total 返回可用与冻结余额之和
+func (b Balance) Total() decimal.Decimal {
+	return b.Free.Add(b.Locked)
+}
+
+// Balances 由支持查询账户余额的交易所实现
+type Balances interface {
+	// GetBalances 返回余额不为零的资产，以资产代码为键
+	GetBalances(ctx context.Context) (map[string]Balance, error)
+}
@@ -2,22 +2,39 @@ package types
 
 import (
 	"net/http"
+	"time"
 )
 
 type ExchangeName string
 
 const (
-	Binance  ExchangeName = "BINANCE"
-	OKX      ExchangeName = "OKX"
-	Bitget   ExchangeName = "BITGET"
-	Kucoin   ExchangeName = "KUCOIN"
-	MEXC     ExchangeName = "MEXC"
-	Gate     ExchangeName = "GATE"
-	Kraken   ExchangeName = "KRAKEN"
-	Bybit    ExchangeName = "BYBIT"
-	Huobi    ExchangeName = "HUOBI"
-	Coinbase ExchangeName = "COINBASE"
-	BTSE     ExchangeName = "BTSE"
+	Binance     ExchangeName = "BINANCE"
+	OKX         ExchangeName = "OKX"
+	Bitget      ExchangeName = "BITGET"
+	Kucoin      ExchangeName = "KUCOIN"
+	MEXC        ExchangeName = "MEXC"
+	Gate        ExchangeName = "GATE"
+	Kraken      ExchangeName = "KRAKEN"
+	Bybit       ExchangeName = "BYBIT"
+	Huobi       ExchangeName = "HUOBI"
+	Coinbase    ExchangeName = "COINBASE"
+	BTSE        ExchangeName = "BTSE"
+	Gemini      ExchangeName = "GEMINI"
+	Upbit       ExchangeName = "UPBIT"
+	CryptoCom   ExchangeName = "CRYPTOCOM"
+	BitMart     ExchangeName = "BITMART"
+	Hyperliquid ExchangeName = "HYPERLIQUID"
+	BinanceUS   ExchangeName = "BINANCE_US"
+)
+
+// Market 区分同一交易所下的不同产品线，它们通常使用不同的域名、路径前缀或签名方式
+type Market string
+
+const (
+	Spot         Market = "SPOT"
+	USDMFutures  Market = "USDM_FUTURES"
+	CoinMFutures Market = "COINM_FUTURES"
+	Options      Market = "OPTIONS"
 )
 
 type ExchangeConfig struct {
@@ -25,6 +42,33 @@ type ExchangeConfig struct {
 	APISecret     string
 	BaseURL       string
 	APIPassphrase string
+	APIMemo       string
+	PrivateKey    string
+	Sandbox       bool
+	// Market 为空时按现货处理
+	Market Market
+	// ResampleCandles 为 true 时，交易所不支持的 K 线周期由更细的周期聚合得到
+	ResampleCandles bool
+	// InstrumentTTL 是交易对元数据的缓存时间，零值使用 DefaultInstrumentTTL，负数表示不缓存
+	InstrumentTTL time.Duration
+	// RateLimit 是每秒允许的请求数，零值使用交易所的默认限速，负数表示不限速。
+	// 限速作用于统一接口发出的请求，每个交易所实例单独计数
+	RateLimit float64
+	// OrderRounding 决定下单前如何把价格、数量对齐到交易对的最小变动单位，零值表示不取整、不对齐时拒绝
+	OrderRounding RoundingMode
+	// SkipOrderChecks 为 true 时下单前不按交易对元数据校验和取整
+	SkipOrderChecks bool
+}
+
+// DefaultInstrumentTTL 是交易对元数据的默认缓存时间
+const DefaultInstrumentTTL = time.Hour
+
+// MarketOrSpot 返回配置的产品线，未设置时为现货
+func (c ExchangeConfig) MarketOrSpot() Market {
+	if c.Market == "" {
+		return Spot
+	}
+	return c.Market
 }
 
 type Exchange interface {